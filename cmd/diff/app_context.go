@@ -16,10 +16,16 @@ import (
 type AppContext struct {
 	K8sClients k8.Clients
 	XpClients  xp.Clients
+
+	// Server is the API server URL of the cluster the clients talk to, and ObservedDir the
+	// directory of exported resources they serve instead. Only one is set.
+	Server      string
+	ObservedDir string
 }
 
-// NewAppContext creates a new AppContext with initialized clients.
-func NewAppContext(config *rest.Config, logger logging.Logger) (*AppContext, error) {
+// NewAppContext creates a new AppContext with initialized clients. XRDs and
+// CRDs fetched from the cluster are persisted to cache, which may be nil.
+func NewAppContext(config *rest.Config, cache *core.DiskCache, retry k8.RetryPolicy, logger logging.Logger) (*AppContext, error) {
 	coreClients, err := core.NewClients(config)
 	if err != nil {
 		// error is already well-decorated
@@ -28,14 +34,54 @@ func NewAppContext(config *rest.Config, logger logging.Logger) (*AppContext, err
 
 	tc := k8.NewTypeConverter(coreClients, logger)
 
+	// Under --as, Forbidden errors name the impersonated identity.
+	identity := k8.ImpersonatedIdentity(config.Impersonate)
+
 	k8c := k8.Clients{
-		Type:     tc,
-		Apply:    k8.NewApplyClient(coreClients, tc, logger),
-		Resource: k8.NewResourceClient(coreClients, tc, logger),
-		Schema:   k8.NewSchemaClient(coreClients, tc, logger),
+		Type:  tc,
+		Apply: k8.NewImpersonatedApplyClient(k8.NewApplyClient(coreClients, tc, logger), identity),
+		Resource: k8.NewImpersonatedResourceClient(
+			k8.NewRetryingResourceClient(k8.NewResourceClient(coreClients, tc, logger), retry, logger), identity),
+		Schema: k8.NewImpersonatedSchemaClient(
+			k8.NewRetryingSchemaClient(k8.NewSchemaClient(coreClients, tc, cache, logger), retry, logger), identity),
 	}
 
-	defClient := xp.NewDefinitionClient(k8c.Resource, logger)
+	appCtx := newAppContext(k8c, xp.NewResourceTreeClient(coreClients.Tree, logger), cache, logger)
+	appCtx.Server = config.Host
+
+	return appCtx, nil
+}
+
+// NewObservedAppContext creates an AppContext whose clients serve the resources
+// in dir, as exported from a cluster, instead of talking to one. No REST config
+// is needed.
+func NewObservedAppContext(dir string, logger logging.Logger) (*AppContext, error) {
+	resources, err := LoadObservedResources(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	oc, err := k8.NewObservedClient(dir, resources, logger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load observed resources from %q", dir)
+	}
+
+	k8c := k8.Clients{
+		Type:     oc,
+		Apply:    oc,
+		Resource: oc,
+		Schema:   k8.NewObservedSchemaClient(oc.CRDs(), oc, logger),
+	}
+
+	appCtx := newAppContext(k8c, xp.NewObservedResourceTreeClient(oc, logger), nil, logger)
+	appCtx.ObservedDir = dir
+
+	return appCtx, nil
+}
+
+// newAppContext builds the Crossplane clients on top of the given Kubernetes clients.
+func newAppContext(k8c k8.Clients, tree xp.ResourceTreeClient, cache *core.DiskCache, logger logging.Logger) *AppContext {
+	defClient := xp.NewDefinitionClient(k8c.Resource, cache, logger)
 
 	xpc := xp.Clients{
 		Composition:  xp.NewCompositionClient(k8c.Resource, defClient, logger),
@@ -43,13 +89,13 @@ func NewAppContext(config *rest.Config, logger logging.Logger) (*AppContext, err
 		Definition:   defClient,
 		Environment:  xp.NewEnvironmentClient(k8c.Resource, logger),
 		Function:     xp.NewFunctionClient(k8c.Resource, logger),
-		ResourceTree: xp.NewResourceTreeClient(coreClients.Tree, logger),
+		ResourceTree: tree,
 	}
 
 	return &AppContext{
 		K8sClients: k8c,
 		XpClients:  xpc,
-	}, nil
+	}
 }
 
 // Initialize initializes all clients.
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// DiskCache persists objects fetched from a cluster as JSON files under a
+// directory keyed by the cluster's API server URL, so later runs against the
+// same cluster can skip fetching them again while they're fresh. A nil
+// *DiskCache is a valid, disabled cache: Load always misses and Store and
+// Clear do nothing.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewDiskCache returns a cache for the cluster at host, stored under root.
+// Entries older than ttl are treated as missing.
+func NewDiskCache(root, host string, ttl time.Duration) *DiskCache {
+	sum := sha256.Sum256([]byte(host))
+
+	return &DiskCache{
+		dir: filepath.Join(root, hex.EncodeToString(sum[:8])),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Load decodes the named entry into out. It returns false if the entry is
+// missing, older than the cache's TTL, or can't be decoded.
+func (c *DiskCache) Load(name string, out any) bool {
+	if c == nil {
+		return false
+	}
+
+	path := c.path(name)
+
+	info, err := os.Stat(path)
+	if err != nil || c.now().Sub(info.ModTime()) >= c.ttl {
+		return false
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from the cache dir and a fixed entry name.
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(data, out) == nil
+}
+
+// Store writes v as the named entry. The entry is written to a temporary file
+// and renamed into place, so concurrent runs never read a partial entry.
+func (c *DiskCache) Store(name string, v any) error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "cannot encode cache entry %s", name)
+	}
+
+	path := c.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.Wrapf(err, "cannot create cache directory for %s", name)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "cannot write cache entry %s", name)
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return errors.Wrapf(err, "cannot write cache entry %s", name)
+	}
+
+	return nil
+}
+
+// Clear removes every entry cached for the cluster.
+func (c *DiskCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+
+	return errors.Wrap(os.RemoveAll(c.dir), "cannot clear cache")
+}
+
+func (c *DiskCache) path(name string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(name)+".json")
+}
@@ -42,6 +42,11 @@ type Clients struct {
 
 // NewClients initializes a bundle of built-in kube clients using the given rest config.
 func NewClients(config *rest.Config) (*Clients, error) {
+	// Route server warnings through a handler that lets callers capture them per request
+	// (see WithWarningRecorder). Copy so the caller's config is left untouched.
+	config = rest.CopyConfig(config)
+	config.WarningHandlerWithContext = ContextWarningHandler{}
+
 	// These three clients underlie all of our client wrapper interfaces.
 	dynClient, err := makeDynamicClient(config)
 	if err != nil {
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// warningRecorderKey is the context key under which a WarningRecorder is stored.
+type warningRecorderKey struct{}
+
+// WarningRecorder collects distinct warnings, such as the API server warnings
+// returned for requests made with a context from WithWarningRecorder. It is
+// safe for concurrent use.
+type WarningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// WithWarningRecorder returns a context that records the warnings of any
+// request made with it, and the recorder that collects them.
+func WithWarningRecorder(ctx context.Context) (context.Context, *WarningRecorder) {
+	r := &WarningRecorder{}
+	return context.WithValue(ctx, warningRecorderKey{}, r), r
+}
+
+// Warnings returns the distinct warnings recorded so far, in the order they
+// were first seen.
+func (r *WarningRecorder) Warnings() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.warnings)
+}
+
+// Record records a warning, unless it was recorded before.
+func (r *WarningRecorder) Record(text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.Contains(r.warnings, text) {
+		r.warnings = append(r.warnings, text)
+	}
+}
+
+// ContextWarningHandler hands warnings to the WarningRecorder in the request
+// context, if any, and otherwise logs them the way client-go does by default.
+type ContextWarningHandler struct{}
+
+// HandleWarningHeaderWithContext implements rest.WarningHandlerWithContext.
+func (ContextWarningHandler) HandleWarningHeaderWithContext(ctx context.Context, code int, agent, text string) {
+	// 299 is the only warning code the API server sends; client-go ignores the rest.
+	if r, ok := ctx.Value(warningRecorderKey{}).(*WarningRecorder); ok && code == 299 && text != "" {
+		r.Record(text)
+		return
+	}
+
+	rest.WarningLogger{}.HandleWarningHeaderWithContext(ctx, code, agent, text)
+}
@@ -31,6 +31,8 @@ const (
 	CompositionRevisionKind = "CompositionRevision"
 	// FunctionKind is the kind for Crossplane Functions.
 	FunctionKind = "Function"
+	// EnvironmentConfigKind is the kind for EnvironmentConfigs.
+	EnvironmentConfigKind = "EnvironmentConfig"
 
 	// updatePolicyAutomatic is the Automatic compositionUpdatePolicy value (also the default when
 	// unset), mirroring Crossplane's CompositionUpdatePolicy.
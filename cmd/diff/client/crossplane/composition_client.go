@@ -3,7 +3,11 @@ package crossplane
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
@@ -28,12 +32,39 @@ type CompositionClient interface {
 	// FindMatchingComposition finds a composition that matches the given XR or claim
 	FindMatchingComposition(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error)
 
+	// FindMatchingCompositionAsOf finds the composition that matches the given XR or claim, as it was
+	// defined by the CompositionRevision that was current at the given time.
+	FindMatchingCompositionAsOf(ctx context.Context, res *un.Unstructured, asOf time.Time) (*apiextensionsv1.Composition, error)
+
+	// FindMatchingCompositionForRevision finds the composition that matches the given XR or claim, as it
+	// is defined by the named CompositionRevision regardless of the resource's update policy. The
+	// revision must belong to the matched composition.
+	FindMatchingCompositionForRevision(ctx context.Context, res *un.Unstructured, revisionName string) (*apiextensionsv1.Composition, error)
+
+	// FindMatchingCompositionAtLatestRevision finds the composition that matches the given XR or claim, as
+	// it is defined by its latest CompositionRevision regardless of the resource's update policy,
+	// revision selector or pinned revision.
+	FindMatchingCompositionAtLatestRevision(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error)
+
+	// FindMatchingCompositionSkippingMissingRevisions finds the composition that matches the given XR or
+	// claim like FindMatchingComposition, except that a pinned CompositionRevision that no longer exists
+	// is replaced by the latest revision instead of failing.
+	FindMatchingCompositionSkippingMissingRevisions(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error)
+
+	// ExplainCompositionSelection reports which composition and revision the given XR or claim would be
+	// rendered against, and why, without rendering anything.
+	ExplainCompositionSelection(ctx context.Context, res *un.Unstructured) (*dtypes.CompositionSelection, error)
+
 	// ListCompositions lists all compositions in the cluster
 	ListCompositions(ctx context.Context) ([]*apiextensionsv1.Composition, error)
 
 	// GetComposition gets a composition by name
 	GetComposition(ctx context.Context, name string) (*apiextensionsv1.Composition, error)
 
+	// AddLocalCompositions caches compositions supplied from files, replacing any cluster
+	// composition of the same name. Selection considers them like any other cached composition.
+	AddLocalCompositions(comps []*apiextensionsv1.Composition)
+
 	// FindComposites locates composites (XRs and Claims) that reference a composition.
 	// `comp` is taken as the user-supplied unstructured Composition (typically loaded from a YAML
 	// file); the client converts to a typed *apiextensionsv1.Composition internally only when
@@ -65,9 +96,10 @@ type DefaultCompositionClient struct {
 	revisionClient   CompositionRevisionClient
 	logger           logging.Logger
 
-	// Cache of compositions
-	compositions map[string]*apiextensionsv1.Composition
-	gvks         []schema.GroupVersionKind
+	// Cache of compositions, guarded by compositionsMutex
+	compositionsMutex sync.RWMutex
+	compositions      map[string]*apiextensionsv1.Composition
+	gvks              []schema.GroupVersionKind
 }
 
 // NewCompositionClient creates a new DefaultCompositionClient.
@@ -104,11 +136,13 @@ func (c *DefaultCompositionClient) Initialize(ctx context.Context) error {
 	}
 
 	// Store in cache
+	c.compositionsMutex.Lock()
 	for _, comp := range comps {
 		c.compositions[comp.GetName()] = comp
 	}
+	c.compositionsMutex.Unlock()
 
-	c.logger.Debug("Composition client initialized", "compositionsCount", len(c.compositions))
+	c.logger.Debug("Composition client initialized", "compositionsCount", len(comps))
 
 	return nil
 }
@@ -159,7 +193,11 @@ func (c *DefaultCompositionClient) ListCompositions(ctx context.Context) ([]*api
 // GetComposition gets a composition by name.
 func (c *DefaultCompositionClient) GetComposition(ctx context.Context, name string) (*apiextensionsv1.Composition, error) {
 	// Check cache first
-	if comp, ok := c.compositions[name]; ok {
+	c.compositionsMutex.RLock()
+	comp, ok := c.compositions[name]
+	c.compositionsMutex.RUnlock()
+
+	if ok {
 		return comp, nil
 	}
 
@@ -176,17 +214,43 @@ func (c *DefaultCompositionClient) GetComposition(ctx context.Context, name stri
 	}
 
 	// Convert to typed # TODO:  troublesome because typed has a version
-	comp := &apiextensionsv1.Composition{}
+	comp = &apiextensionsv1.Composition{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unComp.Object, comp); err != nil {
 		return nil, errors.Wrap(err, "cannot convert unstructured to Composition")
 	}
 
 	// Update cache
+	c.compositionsMutex.Lock()
 	c.compositions[name] = comp
+	c.compositionsMutex.Unlock()
 
 	return comp, nil
 }
 
+// AddLocalCompositions caches compositions supplied from files rather than read from the
+// cluster. It's called after Initialize, so a local composition replaces a cluster composition
+// of the same name and FindMatchingComposition selects among them exactly as it would in the
+// cluster.
+func (c *DefaultCompositionClient) AddLocalCompositions(comps []*apiextensionsv1.Composition) {
+	c.compositionsMutex.Lock()
+	defer c.compositionsMutex.Unlock()
+
+	for _, comp := range comps {
+		c.compositions[comp.GetName()] = comp
+	}
+
+	c.logger.Debug("Registered local compositions", "count", len(comps), "total", len(c.compositions))
+}
+
+// cachedCompositions returns a snapshot of the cached compositions, safe to range over while
+// other goroutines update the cache.
+func (c *DefaultCompositionClient) cachedCompositions() []*apiextensionsv1.Composition {
+	c.compositionsMutex.RLock()
+	defer c.compositionsMutex.RUnlock()
+
+	return slices.Collect(maps.Values(c.compositions))
+}
+
 // getCompositionRevisionRef reads the compositionRevisionRef from an XR/Claim spec.
 // Returns the revision name and whether it was found. A non-nil error means the field is malformed
 // (present but not a string/object), which is treated as a hard failure by the caller.
@@ -200,6 +264,32 @@ func (c *DefaultCompositionClient) getCompositionRevisionRef(xrd, res *un.Unstru
 	return name, found && name != "", nil
 }
 
+// enforcedCompositionName returns the composition the XRD forces on all of its XRs via
+// spec.enforcedCompositionRef, if any. Crossplane overrides the XR's own compositionRef and
+// compositionSelector with it; revision selection then follows the XR's update policy as usual.
+func enforcedCompositionName(xrd *un.Unstructured) (string, bool) {
+	if xrd == nil {
+		return "", false
+	}
+
+	name, found, err := un.NestedString(xrd.Object, "spec", "enforcedCompositionRef", "name")
+
+	return name, err == nil && found && name != ""
+}
+
+// revisionSelection controls how selectRevision picks the CompositionRevision for a resource.
+type revisionSelection int
+
+const (
+	// selectByPolicy follows the resource's update policy and revision reference, as Crossplane does.
+	selectByPolicy revisionSelection = iota
+	// selectLatest uses the latest revision whatever the update policy, selector or pinned revision.
+	selectLatest
+	// selectSkippingMissing follows the update policy, but falls back to the latest revision when the
+	// pinned revision no longer exists.
+	selectSkippingMissing
+)
+
 // resolveCompositionFromRevisions determines which composition to use based on revision logic.
 // Returns a composition or nil if standard resolution should be used.
 func (c *DefaultCompositionClient) resolveCompositionFromRevisions(
@@ -207,16 +297,67 @@ func (c *DefaultCompositionClient) resolveCompositionFromRevisions(
 	xrd, res *un.Unstructured,
 	compositionName string,
 	resourceID string,
+	selection revisionSelection,
 ) (*apiextensionsv1.Composition, error) {
+	revision, _, err := c.selectRevision(ctx, xrd, res, compositionName, resourceID, selection)
+	if err != nil || revision == nil {
+		return nil, err
+	}
+
+	return c.compositionFromRevision(ctx, revision), nil
+}
+
+// compositionFromRevision converts revision to the Composition it was published from, recording
+// it on the ResolvedRevision in ctx, if any.
+func (c *DefaultCompositionClient) compositionFromRevision(ctx context.Context, revision *apiextensionsv1.CompositionRevision) *apiextensionsv1.Composition {
+	recordResolvedRevision(ctx, revision)
+	return c.revisionClient.GetCompositionFromRevision(revision)
+}
+
+// checkRevisionComposition returns an error if the revision was published by a composition other
+// than compositionName, per its LabelCompositionName label. A revision without the label passes.
+func checkRevisionComposition(revision *apiextensionsv1.CompositionRevision, compositionName, resourceID string) error {
+	if revCompName := revision.GetLabels()[LabelCompositionName]; revCompName != "" && revCompName != compositionName {
+		return errors.Errorf(
+			"composition revision %s belongs to composition %s, not %s (resource: %s)",
+			revision.GetName(), revCompName, compositionName, resourceID)
+	}
+
+	return nil
+}
+
+// reasonNoRevisions explains falling back to the Composition itself when it has no published revisions.
+const reasonNoRevisions = "composition has no published revisions; using the Composition directly"
+
+// warnNoRevisions warns that a composition has never produced a revision, so the diff renders
+// against its current spec. A controller honoring the update policy may render something else once
+// a revision is published.
+func (c *DefaultCompositionClient) warnNoRevisions(compositionName, resourceID, updatePolicy string) {
+	c.logger.Info("Warning: composition has no published revisions; using current spec. The diff may not match what Crossplane renders under the revision update policy.",
+		"composition", compositionName,
+		"resource", resourceID,
+		"updatePolicy", updatePolicy)
+}
+
+// selectRevision picks the CompositionRevision Crossplane would use for the resource, along with a
+// human-readable reason for the choice. Returns a nil revision if the composition has no published
+// revisions, in which case the composition itself should be used.
+func (c *DefaultCompositionClient) selectRevision(
+	ctx context.Context,
+	xrd, res *un.Unstructured,
+	compositionName string,
+	resourceID string,
+	selection revisionSelection,
+) (*apiextensionsv1.CompositionRevision, string, error) {
 	// Check if there's a composition revision reference
 	revisionRefName, hasRevisionRef, err := c.getCompositionRevisionRef(xrd, res)
 	if err != nil {
-		return nil, errors.Wrapf(err, "cannot read compositionRevisionRef for %s", resourceID)
+		return nil, "", errors.Wrapf(err, "cannot read compositionRevisionRef for %s", resourceID)
 	}
 
 	updatePolicy, err := XRUpdatePolicy(res.Object, xrd.GetAPIVersion())
 	if err != nil {
-		return nil, errors.Wrapf(err, "cannot read compositionUpdatePolicy for %s", resourceID)
+		return nil, "", errors.Wrapf(err, "cannot read compositionUpdatePolicy for %s", resourceID)
 	}
 
 	c.logger.Debug("Checking revision resolution",
@@ -226,68 +367,92 @@ func (c *DefaultCompositionClient) resolveCompositionFromRevisions(
 		"updatePolicy", updatePolicy)
 
 	switch {
+	case selection == selectLatest:
+		// --latest-revision: use the latest revision whatever the policy, selector or pin, to
+		// preview every XR moving to it.
+		latest, err := c.revisionClient.GetLatestRevisionForComposition(ctx, compositionName, nil)
+		if err != nil {
+			if strings.Contains(err.Error(), "no composition revisions found") {
+				c.warnNoRevisions(compositionName, resourceID, updatePolicy)
+
+				return nil, reasonNoRevisions, nil
+			}
+
+			return nil, "", errors.Wrapf(err,
+				"cannot resolve latest composition revision for %s (composition: %s, --latest-revision)",
+				resourceID, compositionName)
+		}
+
+		c.logger.Debug("Using latest revision for --latest-revision",
+			"resource", resourceID,
+			"revisionName", latest.GetName(),
+			"revisionNumber", latest.Spec.Revision,
+			"updatePolicy", updatePolicy)
+
+		return latest, "--latest-revision uses the latest revision regardless of update policy", nil
+
 	case updatePolicy == updatePolicyAutomatic:
 		// Case 1: Automatic policy - use the latest revision that matches the XR's
 		// compositionRevisionSelector (a nil/Everything selector when there is none), mirroring
 		// Crossplane's revision selection.
 		selector, err := XRRevisionLabelSelector(res)
 		if err != nil {
-			return nil, errors.Wrapf(err, "cannot evaluate compositionRevisionSelector for %s", resourceID)
+			return nil, "", errors.Wrapf(err, "cannot evaluate compositionRevisionSelector for %s", resourceID)
 		}
 
 		latest, err := c.revisionClient.GetLatestRevisionForComposition(ctx, compositionName, selector)
 		if err != nil {
 			// Check if this is a "no revisions found" case (new/unpublished composition)
 			if strings.Contains(err.Error(), "no composition revisions found") {
-				c.logger.Debug("No revisions found for composition (likely unpublished), falling back to composition directly",
-					"compositionName", compositionName,
-					"resource", resourceID)
-
 				// Fall back to using composition directly for unpublished compositions
-				return nil, nil
+				c.warnNoRevisions(compositionName, resourceID, updatePolicy)
+
+				return nil, reasonNoRevisions, nil
 			}
 
 			// For other errors (including a selector that matches no revision), fail the diff to
 			// ensure accuracy rather than silently rendering against the wrong revision.
-			return nil, errors.Wrapf(err,
+			return nil, "", errors.Wrapf(err,
 				"cannot resolve latest composition revision for %s with Automatic update policy (composition: %s)",
 				resourceID, compositionName)
 		}
 
-		comp := c.revisionClient.GetCompositionFromRevision(latest)
 		c.logger.Debug("Using latest matching revision for Automatic policy",
 			"resource", resourceID,
 			"revisionName", latest.GetName(),
 			"revisionNumber", latest.Spec.Revision,
 			"selector", selector.String())
 
-		return comp, nil
+		if selector.Empty() {
+			return latest, "Automatic update policy uses the latest revision", nil
+		}
+
+		return latest, fmt.Sprintf("Automatic update policy uses the latest revision matching compositionRevisionSelector %s", selector.String()), nil
 
 	case updatePolicy == updatePolicyManual && hasRevisionRef:
 		// Case 2: Manual policy with revision reference - use that specific revision
 		revision, err := c.revisionClient.GetCompositionRevision(ctx, revisionRefName)
+		if apierrors.IsNotFound(err) && selection == selectSkippingMissing {
+			return c.skipMissingRevision(ctx, revisionRefName, compositionName, resourceID)
+		}
+
 		if err != nil {
-			return nil, errors.Wrapf(err,
+			return nil, "", errors.Wrapf(err,
 				"cannot get pinned composition revision %s for %s (composition: %s, policy: Manual)",
 				revisionRefName, resourceID, compositionName)
 		}
 
 		// Validate that revision belongs to the referenced composition
-		if labels := revision.GetLabels(); labels != nil {
-			if revCompName := labels[LabelCompositionName]; revCompName != "" && revCompName != compositionName {
-				return nil, errors.Errorf(
-					"composition revision %s belongs to composition %s, not %s (resource: %s)",
-					revisionRefName, revCompName, compositionName, resourceID)
-			}
+		if err := checkRevisionComposition(revision, compositionName, resourceID); err != nil {
+			return nil, "", err
 		}
 
-		comp := c.revisionClient.GetCompositionFromRevision(revision)
 		c.logger.Debug("Using pinned revision for Manual policy",
 			"resource", resourceID,
 			"revisionName", revisionRefName,
 			"revisionNumber", revision.Spec.Revision)
 
-		return comp, nil
+		return revision, "Manual update policy uses the revision pinned by compositionRevisionRef", nil
 
 	default:
 		// Case 3: Manual policy without revision reference in spec
@@ -302,30 +467,73 @@ func (c *DefaultCompositionClient) resolveCompositionFromRevisions(
 		if err != nil {
 			// Check if this is a "no revisions found" case (new/unpublished composition)
 			if strings.Contains(err.Error(), "no composition revisions found") {
-				c.logger.Debug("No revisions found for composition (likely unpublished), falling back to composition directly",
-					"compositionName", compositionName,
-					"resource", resourceID)
+				c.warnNoRevisions(compositionName, resourceID, updatePolicy)
 
-				return nil, nil
+				return nil, reasonNoRevisions, nil
 			}
 
-			return nil, errors.Wrapf(err,
+			return nil, "", errors.Wrapf(err,
 				"cannot resolve latest composition revision for %s with Manual policy (composition: %s)",
 				resourceID, compositionName)
 		}
 
-		comp := c.revisionClient.GetCompositionFromRevision(latest)
 		c.logger.Debug("Using latest revision for Manual policy",
 			"resource", resourceID,
 			"revisionName", latest.GetName(),
 			"revisionNumber", latest.Spec.Revision)
 
-		return comp, nil
+		return latest, "Manual update policy without compositionRevisionRef uses the latest revision (pinned on creation)", nil
 	}
 }
 
+// skipMissingRevision falls back to the latest revision of the composition when the revision an XR
+// pins has been garbage-collected, and records a note saying so.
+func (c *DefaultCompositionClient) skipMissingRevision(
+	ctx context.Context,
+	revisionRefName string,
+	compositionName string,
+	resourceID string,
+) (*apiextensionsv1.CompositionRevision, string, error) {
+	latest, err := c.revisionClient.GetLatestRevisionForComposition(ctx, compositionName, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err,
+			"cannot resolve latest composition revision for %s in place of missing pinned revision %s (composition: %s)",
+			resourceID, revisionRefName, compositionName)
+	}
+
+	c.logger.Info("Warning: pinned composition revision no longer exists; using the latest revision",
+		"resource", resourceID,
+		"composition", compositionName,
+		"pinnedRevision", revisionRefName,
+		"revisionName", latest.GetName())
+
+	recordSkippedRevision(ctx, fmt.Sprintf(
+		"pinned composition revision %s no longer exists; rendered against the latest revision %s instead",
+		revisionRefName, latest.GetName()))
+
+	return latest, fmt.Sprintf("pinned compositionRevisionRef %s no longer exists; using the latest revision", revisionRefName), nil
+}
+
 // FindMatchingComposition finds a composition matching the given resource.
 func (c *DefaultCompositionClient) FindMatchingComposition(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+	return c.findMatchingComposition(ctx, res, selectByPolicy)
+}
+
+// FindMatchingCompositionAtLatestRevision finds the composition matching the given resource, resolving
+// a direct composition reference to its latest revision whatever the resource's update policy.
+func (c *DefaultCompositionClient) FindMatchingCompositionAtLatestRevision(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+	return c.findMatchingComposition(ctx, res, selectLatest)
+}
+
+// FindMatchingCompositionSkippingMissingRevisions finds the composition matching the given resource,
+// rendering a resource pinned to a garbage-collected revision against the latest revision instead.
+func (c *DefaultCompositionClient) FindMatchingCompositionSkippingMissingRevisions(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+	return c.findMatchingComposition(ctx, res, selectSkippingMissing)
+}
+
+// findMatchingComposition finds a composition matching the given resource, picking its revision as
+// selection says.
+func (c *DefaultCompositionClient) findMatchingComposition(ctx context.Context, res *un.Unstructured, selection revisionSelection) (*apiextensionsv1.Composition, error) {
 	gvk := res.GroupVersionKind()
 	resourceID := fmt.Sprintf("%s/%s", gvk.String(), res.GetName())
 
@@ -364,7 +572,7 @@ func (c *DefaultCompositionClient) FindMatchingComposition(ctx context.Context,
 	}
 
 	// Case 1: Check for direct composition reference in spec.compositionRef.name
-	comp, err := c.findByDirectReference(ctx, xrd, res, targetGVK, resourceID)
+	comp, err := c.findByDirectReference(ctx, xrd, res, targetGVK, resourceID, selection)
 	if err != nil || comp != nil {
 		return comp, err
 	}
@@ -379,6 +587,119 @@ func (c *DefaultCompositionClient) FindMatchingComposition(ctx context.Context,
 	return c.findByTypeReference(ctx, xrd, targetGVK, resourceID)
 }
 
+// ExplainCompositionSelection resolves the composition for the given resource the same way
+// FindMatchingComposition does, and reports the revision and the reasons behind the selection.
+func (c *DefaultCompositionClient) ExplainCompositionSelection(ctx context.Context, res *un.Unstructured) (*dtypes.CompositionSelection, error) {
+	comp, err := c.FindMatchingComposition(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := res.GroupVersionKind()
+	resourceID := fmt.Sprintf("%s/%s", gvk.String(), res.GetName())
+
+	// FindMatchingComposition already resolved the XRD successfully, so these lookups hit the cache.
+	xrd, _ := c.definitionClient.GetXRDForClaim(ctx, gvk)
+	if xrd == nil {
+		xrd, err = c.definitionClient.GetXRDForXR(ctx, gvk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resource %s requires its XR type to find a composition", resourceID)
+		}
+	}
+
+	sel := &dtypes.CompositionSelection{Composition: comp}
+
+	refName, hasRef := c.getCompositionRefName(xrd, res)
+	if !hasRef {
+		matchLabels, hasSelector, _ := nestedCrossplaneMap(res.Object, xrd.GetAPIVersion(), "compositionSelector", "matchLabels")
+		if hasSelector && len(matchLabels) > 0 {
+			sel.Reasons = append(sel.Reasons, fmt.Sprintf("only composition matching compositionSelector labels %v", matchLabels))
+		} else {
+			sel.Reasons = append(sel.Reasons, fmt.Sprintf("only composition whose compositeTypeRef is %s %s",
+				comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind))
+		}
+
+		sel.Reasons = append(sel.Reasons, "revisions are only resolved for an explicit compositionRef; using the Composition directly")
+
+		return sel, nil
+	}
+
+	if _, enforced := enforcedCompositionName(xrd); enforced {
+		sel.Reasons = append(sel.Reasons, fmt.Sprintf("enforced by XRD %s enforcedCompositionRef %s", xrd.GetName(), refName))
+	} else {
+		sel.Reasons = append(sel.Reasons, fmt.Sprintf("referenced by compositionRef %s", refName))
+	}
+
+	revision, reason, err := c.selectRevision(ctx, xrd, res, refName, resourceID, selectByPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	sel.Reasons = append(sel.Reasons, reason)
+
+	if revision != nil {
+		sel.RevisionName = revision.GetName()
+		sel.RevisionNumber = revision.Spec.Revision
+	}
+
+	return sel, nil
+}
+
+// FindMatchingCompositionAsOf finds the composition matching the given resource, then substitutes the
+// CompositionRevision of that composition that was current at asOf. Fails if no revision existed then,
+// rather than silently rendering against the live composition.
+func (c *DefaultCompositionClient) FindMatchingCompositionAsOf(ctx context.Context, res *un.Unstructured, asOf time.Time) (*apiextensionsv1.Composition, error) {
+	resourceID := fmt.Sprintf("%s/%s", res.GroupVersionKind().String(), res.GetName())
+
+	comp, err := c.FindMatchingComposition(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := c.revisionClient.GetRevisionAsOf(ctx, comp.GetName(), asOf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot resolve composition revision as of %s for %s", asOf.Format(time.RFC3339), resourceID)
+	}
+
+	c.logger.Debug("Using composition revision as of time",
+		"resource", resourceID,
+		"composition", comp.GetName(),
+		"asOf", asOf,
+		"revisionName", revision.GetName(),
+		"revisionNumber", revision.Spec.Revision)
+
+	return c.compositionFromRevision(ctx, revision), nil
+}
+
+// FindMatchingCompositionForRevision finds the composition matching the given resource and returns it
+// as defined by the named CompositionRevision, ignoring the resource's update policy and revision
+// reference. The revision must belong to the matched composition.
+func (c *DefaultCompositionClient) FindMatchingCompositionForRevision(ctx context.Context, res *un.Unstructured, revisionName string) (*apiextensionsv1.Composition, error) {
+	resourceID := fmt.Sprintf("%s/%s", res.GroupVersionKind().String(), res.GetName())
+
+	comp, err := c.FindMatchingComposition(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := c.revisionClient.GetCompositionRevision(ctx, revisionName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get composition revision %s for %s", revisionName, resourceID)
+	}
+
+	if err := checkRevisionComposition(revision, comp.GetName(), resourceID); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Using named composition revision",
+		"resource", resourceID,
+		"composition", comp.GetName(),
+		"revisionName", revision.GetName(),
+		"revisionNumber", revision.Spec.Revision)
+
+	return c.compositionFromRevision(ctx, revision), nil
+}
+
 // getXRTypeFromXRD extracts the XR GroupVersionKind from an XRD.
 func (c *DefaultCompositionClient) getXRTypeFromXRD(xrdForClaim *un.Unstructured, resourceID string) (schema.GroupVersionKind, error) {
 	// Get the XR type from the XRD
@@ -558,34 +879,40 @@ func nestedCrossplaneMap(obj map[string]any, apiVersion string, path ...string)
 	return nestedCrossplaneValue(obj, apiVersion, "an object", un.NestedMap, path...)
 }
 
-// findByDirectReference attempts to find a composition directly referenced by name.
-// Checks both v2 (spec.crossplane.compositionRef) and v1 (spec.compositionRef) paths.
-func (c *DefaultCompositionClient) findByDirectReference(ctx context.Context, xrd, res *un.Unstructured, targetGVK schema.GroupVersionKind, resourceID string) (*apiextensionsv1.Composition, error) {
-	// Try all possible paths for compositionRef (v2 path first, then v1 fallback)
-	var (
-		compositionRefName  string
-		compositionRefFound bool
-	)
+// getCompositionRefName reads the compositionRef name from an XR/Claim spec, trying the v2 path first
+// and then the v1 fallback. Returns the name and whether a non-empty one was found. A composition
+// enforced by the XRD wins over whatever the resource references.
+func (c *DefaultCompositionClient) getCompositionRefName(xrd, res *un.Unstructured) (string, bool) {
+	if name, ok := enforcedCompositionName(xrd); ok {
+		c.logger.Debug("Using composition enforced by XRD", "xrd", xrd.GetName(), "name", name)
+
+		return name, true
+	}
 
 	for _, path := range getCrossplaneRefPaths(xrd.GetAPIVersion(), "compositionRef", "name") {
 		name, found, err := un.NestedString(res.Object, path...)
 		if err == nil && found && name != "" {
-			compositionRefName = name
-			compositionRefFound = true
-
 			c.logger.Debug("Found compositionRef at path", "path", path, "name", name)
 
-			break
+			return name, true
 		}
 	}
 
-	if compositionRefFound && compositionRefName != "" {
+	return "", false
+}
+
+// findByDirectReference attempts to find a composition directly referenced by name.
+// Checks both v2 (spec.crossplane.compositionRef) and v1 (spec.compositionRef) paths.
+func (c *DefaultCompositionClient) findByDirectReference(ctx context.Context, xrd, res *un.Unstructured, targetGVK schema.GroupVersionKind, resourceID string, selection revisionSelection) (*apiextensionsv1.Composition, error) {
+	compositionRefName, compositionRefFound := c.getCompositionRefName(xrd, res)
+
+	if compositionRefFound {
 		c.logger.Debug("Found direct composition reference",
 			"resource", resourceID,
 			"compositionName", compositionRefName)
 
 		// Check if we should use a revision instead
-		comp, err := c.resolveCompositionFromRevisions(ctx, xrd, res, compositionRefName, resourceID)
+		comp, err := c.resolveCompositionFromRevisions(ctx, xrd, res, compositionRefName, resourceID, selection)
 		if err != nil {
 			return nil, err
 		}
@@ -651,14 +978,14 @@ func (c *DefaultCompositionClient) findByLabelSelector(ctx context.Context, xrd,
 		var matchingCompositions []*apiextensionsv1.Composition
 
 		// Get all compositions if we haven't loaded them yet
-		if len(c.compositions) == 0 {
+		if len(c.cachedCompositions()) == 0 {
 			if _, err := c.ListCompositions(ctx); err != nil {
 				return nil, errors.Wrap(err, "cannot list compositions to match selector")
 			}
 		}
 
 		// Search through all compositions looking for compatible ones with matching labels
-		for _, comp := range c.compositions {
+		for _, comp := range c.cachedCompositions() {
 			// Check if this composition is for the right XR type
 			if c.isCompositionCompatible(comp, targetGVK) {
 				// Check if labels match
@@ -696,7 +1023,7 @@ func (c *DefaultCompositionClient) findByLabelSelector(ctx context.Context, xrd,
 // findByTypeReference attempts to find a composition by matching the type reference.
 func (c *DefaultCompositionClient) findByTypeReference(ctx context.Context, _ *un.Unstructured, targetGVK schema.GroupVersionKind, resourceID string) (*apiextensionsv1.Composition, error) {
 	// Get all compositions if we haven't loaded them yet
-	if len(c.compositions) == 0 {
+	if len(c.cachedCompositions()) == 0 {
 		if _, err := c.ListCompositions(ctx); err != nil {
 			return nil, errors.Wrap(err, "cannot list compositions to match type")
 		}
@@ -705,7 +1032,7 @@ func (c *DefaultCompositionClient) findByTypeReference(ctx context.Context, _ *u
 	// Find all compositions that match this target type
 	var compatibleCompositions []*apiextensionsv1.Composition
 
-	for _, comp := range c.compositions {
+	for _, comp := range c.cachedCompositions() {
 		if c.isCompositionCompatible(comp, targetGVK) {
 			compatibleCompositions = append(compatibleCompositions, comp)
 		}
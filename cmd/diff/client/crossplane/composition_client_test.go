@@ -4,10 +4,13 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	dtypes "github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
+	"github.com/go-logr/logr/funcr"
 	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -15,6 +18,7 @@ import (
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
 )
@@ -178,6 +182,36 @@ func TestDefaultCompositionClient_FindMatchingComposition(t *testing.T) {
 				composition: referencedComp,
 			},
 		},
+		"EnforcedCompositionOverridesReference": {
+			reason: "Should return the composition an XRD's enforcedCompositionRef names in place of the XR's compositionRef",
+			mockResource: *tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithEmptyListResources().
+				Build(),
+			mockDef: *tu.NewMockDefinitionClient().
+				WithSuccessfulInitialize().
+				WithEmptyXRDsFetch().
+				WithXRDForXR(tu.NewResource(CrossplaneAPIExtGroupV1, CompositeResourceDefinitionKind, "xr1s.example.org").
+					WithSpecField("enforcedCompositionRef", map[string]any{"name": "referenced-comp"}).
+					Build()).
+				Build(),
+			fields: fields{
+				compositions: map[string]*apiextensionsv1.Composition{
+					"matching-comp":   matchingComp,
+					"referenced-comp": referencedComp,
+				},
+			},
+			args: args{
+				ctx: t.Context(),
+				res: tu.NewResource("example.org/v1", "XR1", "my-xr").
+					WithSpecField("compositionRef", map[string]any{"name": "matching-comp"}).
+					WithSpecField("compositionUpdatePolicy", "Automatic").
+					Build(),
+			},
+			want: want{
+				composition: referencedComp,
+			},
+		},
 		"DirectCompositionReferenceIncompatible": {
 			reason: "Should return error when directly referenced composition is incompatible",
 			mockResource: *tu.NewMockResourceClient().
@@ -1086,6 +1120,165 @@ func TestDefaultCompositionClient_Initialize(t *testing.T) {
 	}
 }
 
+func TestDefaultCompositionClient_AddLocalCompositions(t *testing.T) {
+	clusterComp := tu.NewComposition("comp-a").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		Build()
+
+	localComp := tu.NewComposition("comp-a").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		Build()
+	localComp.SetLabels(map[string]string{"source": "local"})
+
+	otherLocalComp := tu.NewComposition("comp-b").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		Build()
+
+	tests := map[string]struct {
+		reason   string
+		local    []*apiextensionsv1.Composition
+		wantComp string
+		wantErr  string
+	}{
+		"ReplacesClusterComposition": {
+			reason:   "A local composition should replace the cluster composition of the same name.",
+			local:    []*apiextensionsv1.Composition{localComp},
+			wantComp: "comp-a",
+		},
+		"AmbiguousWithClusterComposition": {
+			reason:  "A local composition for the same type as a cluster composition should make selection ambiguous, as it would be in the cluster.",
+			local:   []*apiextensionsv1.Composition{otherLocalComp},
+			wantErr: "ambiguous composition selection",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithEmptyListResources().
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				definitionClient: tu.NewMockDefinitionClient().
+					WithSuccessfulInitialize().
+					WithEmptyXRDsFetch().
+					WithV1XRDForXR().
+					Build(),
+				revisionClient: NewCompositionRevisionClient(mockResource, tu.TestLogger(t, false)),
+				logger:         tu.TestLogger(t, false),
+				compositions:   map[string]*apiextensionsv1.Composition{clusterComp.GetName(): clusterComp},
+			}
+
+			c.AddLocalCompositions(tt.local)
+
+			got, err := c.FindMatchingComposition(t.Context(), tu.NewResource("example.org/v1", "XR1", "my-xr").Build())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("\n%s\nFindMatchingComposition(...): want error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nFindMatchingComposition(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if got.GetName() != tt.wantComp || got.GetLabels()["source"] != "local" {
+				t.Errorf("\n%s\nFindMatchingComposition(...): want local composition %s, got %s with labels %v", tt.reason, tt.wantComp, got.GetName(), got.GetLabels())
+			}
+		})
+	}
+}
+
+func TestDefaultCompositionClient_NoRevisionsWarning(t *testing.T) {
+	rev1 := &apiextensionsv1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-comp-rev1",
+			Labels: map[string]string{LabelCompositionName: "test-comp"},
+		},
+		Spec: apiextensionsv1.CompositionRevisionSpec{
+			Revision:         1,
+			CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+		},
+	}
+
+	rev1Un := &un.Unstructured{}
+	obj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(rev1)
+	rev1Un.SetUnstructuredContent(obj)
+	rev1Un.SetGroupVersionKind(schema.GroupVersionKind{Group: CrossplaneAPIExtGroup, Version: "v1", Kind: "CompositionRevision"})
+
+	v1XRD := tu.NewResource(CrossplaneAPIExtGroupV1, CompositeResourceDefinitionKind, "xr1s.example.org").
+		WithSpecField("group", "example.org").
+		WithSpecField("names", map[string]any{"kind": "XR1"}).
+		WithSpecField("versions", []any{
+			map[string]any{"name": "v1", "served": true, "referenceable": true},
+		}).Build()
+
+	tests := map[string]struct {
+		reason      string
+		policy      string
+		revisions   []*un.Unstructured
+		wantWarning bool
+	}{
+		"AutomaticNoRevisions": {
+			reason:      "Should warn when an Automatic XR's composition has never produced a revision.",
+			policy:      "Automatic",
+			revisions:   []*un.Unstructured{},
+			wantWarning: true,
+		},
+		"ManualNoRevisions": {
+			reason:      "Should warn when a Manual XR without a pinned revision has no revision to pin.",
+			policy:      "Manual",
+			revisions:   []*un.Unstructured{},
+			wantWarning: true,
+		},
+		"RevisionPublished": {
+			reason:    "Should not warn when a revision exists.",
+			policy:    "Automatic",
+			revisions: []*un.Unstructured{rev1Un},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs strings.Builder
+
+			logger := logging.NewLogrLogger(funcr.New(func(prefix, args string) {
+				logs.WriteString(prefix + args + "\n")
+			}, funcr.Options{}))
+
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithResourcesFoundByLabel(tt.revisions, LabelCompositionName, "test-comp").
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				revisionClient: NewCompositionRevisionClient(mockResource, tu.TestLogger(t, false)),
+				logger:         logger,
+				compositions:   make(map[string]*apiextensionsv1.Composition),
+			}
+
+			res := tu.NewResource("example.org/v1", "XR1", "my-xr").
+				WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+				WithSpecField("compositionUpdatePolicy", tt.policy).
+				Build()
+
+			if _, err := c.resolveCompositionFromRevisions(t.Context(), v1XRD, res, "test-comp", "test-resource-id", selectByPolicy); err != nil {
+				t.Fatalf("\n%s\nresolveCompositionFromRevisions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			got := strings.Contains(logs.String(), "composition has no published revisions; using current spec")
+			if got != tt.wantWarning {
+				t.Errorf("\n%s\nresolveCompositionFromRevisions(...): warning logged = %t, want %t\nlogs:\n%s", tt.reason, got, tt.wantWarning, logs.String())
+			}
+		})
+	}
+}
+
 func TestDefaultCompositionClient_ResolveCompositionFromRevisions(t *testing.T) {
 	ctx := t.Context()
 
@@ -1447,7 +1640,7 @@ func TestDefaultCompositionClient_ResolveCompositionFromRevisions(t *testing.T)
 				compositions:   make(map[string]*apiextensionsv1.Composition),
 			}
 
-			comp, err := c.resolveCompositionFromRevisions(ctx, tt.xrd, tt.res, tt.compositionName, "test-resource-id")
+			comp, err := c.resolveCompositionFromRevisions(ctx, tt.xrd, tt.res, tt.compositionName, "test-resource-id", selectByPolicy)
 
 			if tt.expectError {
 				if err == nil {
@@ -1492,6 +1685,544 @@ func TestDefaultCompositionClient_ResolveCompositionFromRevisions(t *testing.T)
 	}
 }
 
+func TestDefaultCompositionClient_ResolveCompositionFromRevisions_SkipMissing(t *testing.T) {
+	rev := func(name string, revision int64) *un.Unstructured {
+		obj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{LabelCompositionName: "test-comp"},
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+			},
+		})
+
+		u := &un.Unstructured{Object: obj}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: CrossplaneAPIExtGroup, Version: "v1", Kind: CompositionRevisionKind})
+
+		return u
+	}
+
+	xrd := tu.NewResource(CrossplaneAPIExtGroupV1, CompositeResourceDefinitionKind, "xr1s.example.org").Build()
+	res := tu.NewResource("example.org/v1", "XR1", "my-xr").
+		WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+		WithSpecField("compositionRevisionRef", map[string]any{"name": "test-comp-gone"}).
+		WithSpecField("compositionUpdatePolicy", "Manual").
+		Build()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: CrossplaneAPIExtGroup, Resource: "compositionrevisions"}, "test-comp-gone")
+
+	tests := map[string]struct {
+		reason       string
+		skip         bool
+		getErr       error
+		wantNotes    []string
+		errorPattern string
+	}{
+		"SkipFallsBackToLatest": {
+			reason:    "With skipping enabled, a missing pinned revision should be replaced by the latest revision and noted.",
+			skip:      true,
+			getErr:    notFound,
+			wantNotes: []string{"pinned composition revision test-comp-gone no longer exists; rendered against the latest revision test-comp-rev2 instead"},
+		},
+		"DefaultFails": {
+			reason:       "Without skipping, a missing pinned revision should fail the XR.",
+			getErr:       notFound,
+			errorPattern: "cannot get pinned composition revision test-comp-gone",
+		},
+		"SkipOnlyNotFound": {
+			reason:       "Errors other than NotFound should still fail the XR when skipping.",
+			skip:         true,
+			getErr:       errors.New("connection refused"),
+			errorPattern: "cannot get pinned composition revision test-comp-gone",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithGetResource(func(context.Context, schema.GroupVersionKind, string, string) (*un.Unstructured, error) {
+					return nil, tt.getErr
+				}).
+				WithResourcesFoundByLabel([]*un.Unstructured{rev("test-comp-rev1", 1), rev("test-comp-rev2", 2)}, LabelCompositionName, "test-comp").
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				revisionClient: NewCompositionRevisionClient(mockResource, tu.TestLogger(t, false)),
+				logger:         tu.TestLogger(t, false),
+				compositions:   make(map[string]*apiextensionsv1.Composition),
+			}
+
+			ctx, resolved := WithResolvedRevision(t.Context())
+			ctx, skipped := WithSkippedRevisions(ctx)
+
+			selection := selectByPolicy
+			if tt.skip {
+				selection = selectSkippingMissing
+			}
+
+			comp, err := c.resolveCompositionFromRevisions(ctx, xrd, res, "test-comp", "test-resource-id", selection)
+			if tt.errorPattern != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("\n%s\nresolveCompositionFromRevisions(...): expected error containing %q, got %v", tt.reason, tt.errorPattern, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nresolveCompositionFromRevisions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if comp == nil || comp.GetName() != "test-comp" {
+				t.Errorf("\n%s\nresolveCompositionFromRevisions(...): want composition test-comp, got %v", tt.reason, comp)
+			}
+
+			if diff := cmp.Diff(tt.wantNotes, skipped.Notes()); diff != "" {
+				t.Errorf("\n%s\nSkippedRevisions.Notes(): -want, +got:\n%s", tt.reason, diff)
+			}
+
+			if name, number := resolved.Get(); name != "test-comp-rev2" || number != 2 {
+				t.Errorf("\n%s\nResolvedRevision.Get(): want test-comp-rev2 #2, got %s #%d", tt.reason, name, number)
+			}
+		})
+	}
+}
+
+func TestDefaultCompositionClient_ResolveCompositionFromRevisions_LatestRevision(t *testing.T) {
+	rev := func(name string, revision int64) *un.Unstructured {
+		obj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{LabelCompositionName: "test-comp"},
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+			},
+		})
+
+		u := &un.Unstructured{Object: obj}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: CrossplaneAPIExtGroup, Version: "v1", Kind: CompositionRevisionKind})
+
+		return u
+	}
+
+	xrd := tu.NewResource(CrossplaneAPIExtGroupV1, CompositeResourceDefinitionKind, "xr1s.example.org").Build()
+	res := tu.NewResource("example.org/v1", "XR1", "my-xr").
+		WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+		WithSpecField("compositionRevisionRef", map[string]any{"name": "test-comp-rev1"}).
+		WithSpecField("compositionUpdatePolicy", "Manual").
+		Build()
+
+	tests := map[string]struct {
+		reason       string
+		latest       bool
+		wantRevision string
+	}{
+		"PinnedByDefault": {
+			reason:       "Without --latest-revision, a Manual XR should use its pinned revision.",
+			wantRevision: "test-comp-rev1",
+		},
+		"LatestOverridesPin": {
+			reason:       "With --latest-revision, a Manual XR should use the latest revision despite its pin.",
+			latest:       true,
+			wantRevision: "test-comp-rev2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithGetResource(func(context.Context, schema.GroupVersionKind, string, string) (*un.Unstructured, error) {
+					return rev("test-comp-rev1", 1), nil
+				}).
+				WithResourcesFoundByLabel([]*un.Unstructured{rev("test-comp-rev1", 1), rev("test-comp-rev2", 2)}, LabelCompositionName, "test-comp").
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				revisionClient: NewCompositionRevisionClient(mockResource, tu.TestLogger(t, false)),
+				logger:         tu.TestLogger(t, false),
+				compositions:   make(map[string]*apiextensionsv1.Composition),
+			}
+
+			ctx, resolved := WithResolvedRevision(t.Context())
+
+			selection := selectByPolicy
+			if tt.latest {
+				selection = selectLatest
+			}
+
+			if _, err := c.resolveCompositionFromRevisions(ctx, xrd, res, "test-comp", "test-resource-id", selection); err != nil {
+				t.Fatalf("\n%s\nresolveCompositionFromRevisions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if name, _ := resolved.Get(); name != tt.wantRevision {
+				t.Errorf("\n%s\nresolveCompositionFromRevisions(...): want revision %s, got %s", tt.reason, tt.wantRevision, name)
+			}
+		})
+	}
+}
+
+func TestDefaultCompositionClient_FindMatchingCompositionAsOf(t *testing.T) {
+	base := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	liveComp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		WithPipelineMode().
+		WithPipelineStep("live-step", "function-live", nil).
+		Build()
+
+	rev := func(name string, revision int64, created time.Time, step string) *apiextensionsv1.CompositionRevision {
+		return &apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{LabelCompositionName: "test-comp"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+				Pipeline:         []apiextensionsv1.PipelineStep{{Step: step}},
+			},
+		}
+	}
+
+	revisions := []*apiextensionsv1.CompositionRevision{
+		rev("test-comp-rev1", 1, base, "rev1-step"),
+		rev("test-comp-rev2", 2, base.Add(24*time.Hour), "rev2-step"),
+	}
+
+	tests := map[string]struct {
+		reason       string
+		asOf         time.Time
+		expectStep   string
+		errorPattern string
+	}{
+		"UsesRevisionCurrentAtTime": {
+			reason:     "Should render against the revision that was current at the given time, not the live composition",
+			asOf:       base.Add(time.Hour),
+			expectStep: "rev1-step",
+		},
+		"NoRevisionAtTime": {
+			reason:       "Should return an error when the matched composition had no revision at the given time",
+			asOf:         base.Add(-time.Hour),
+			errorPattern: "cannot resolve composition revision as of",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithEmptyListResources().
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				definitionClient: tu.NewMockDefinitionClient().
+					WithSuccessfulInitialize().
+					WithEmptyXRDsFetch().
+					WithV1XRDForXR().
+					Build(),
+				revisionClient: &DefaultCompositionRevisionClient{
+					resourceClient:         mockResource,
+					logger:                 tu.TestLogger(t, false),
+					revisions:              make(map[string]*apiextensionsv1.CompositionRevision),
+					revisionsByComposition: map[string][]*apiextensionsv1.CompositionRevision{"test-comp": revisions},
+				},
+				logger:       tu.TestLogger(t, false),
+				compositions: map[string]*apiextensionsv1.Composition{"test-comp": liveComp},
+			}
+
+			res := tu.NewResource("example.org/v1", "XR1", "my-xr").Build()
+
+			got, err := c.FindMatchingCompositionAsOf(t.Context(), res, tt.asOf)
+
+			if tt.errorPattern != "" {
+				if err == nil {
+					t.Fatalf("\n%s\nFindMatchingCompositionAsOf(...): expected error but got none", tt.reason)
+				}
+
+				if !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("\n%s\nFindMatchingCompositionAsOf(...): expected error containing %q, got %q", tt.reason, tt.errorPattern, err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nFindMatchingCompositionAsOf(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff("test-comp", got.GetName()); diff != "" {
+				t.Errorf("\n%s\nFindMatchingCompositionAsOf(...): -want name, +got name:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.expectStep, got.Spec.Pipeline[0].Step); diff != "" {
+				t.Errorf("\n%s\nFindMatchingCompositionAsOf(...): -want step, +got step:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultCompositionClient_FindMatchingCompositionForRevision(t *testing.T) {
+	liveComp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		WithPipelineMode().
+		WithPipelineStep("live-step", "function-live", nil).
+		Build()
+
+	rev := func(name, composition string, revision int64, step string) *apiextensionsv1.CompositionRevision {
+		return &apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{LabelCompositionName: composition},
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+				Pipeline:         []apiextensionsv1.PipelineStep{{Step: step}},
+			},
+		}
+	}
+
+	rev1 := rev("test-comp-rev1", "test-comp", 1, "rev1-step")
+	rev2 := rev("test-comp-rev2", "test-comp", 2, "rev2-step")
+	other := rev("other-comp-rev1", "other-comp", 1, "other-step")
+
+	tests := map[string]struct {
+		reason       string
+		revision     string
+		expectStep   string
+		errorPattern string
+	}{
+		"UsesNamedRevision": {
+			reason:     "Should render against the named revision even when it isn't the latest",
+			revision:   "test-comp-rev1",
+			expectStep: "rev1-step",
+		},
+		"RevisionOfOtherComposition": {
+			reason:       "Should reject a revision published by a different composition",
+			revision:     "other-comp-rev1",
+			errorPattern: "composition revision other-comp-rev1 belongs to composition other-comp, not test-comp",
+		},
+		"MissingRevision": {
+			reason:       "Should return an error when the named revision doesn't exist",
+			revision:     "missing-rev",
+			errorPattern: "cannot get composition revision missing-rev",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithEmptyListResources().
+				WithResourceNotFound().
+				Build()
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				definitionClient: tu.NewMockDefinitionClient().
+					WithSuccessfulInitialize().
+					WithEmptyXRDsFetch().
+					WithV1XRDForXR().
+					Build(),
+				revisionClient: &DefaultCompositionRevisionClient{
+					resourceClient: mockResource,
+					logger:         tu.TestLogger(t, false),
+					revisions: map[string]*apiextensionsv1.CompositionRevision{
+						rev1.GetName():  rev1,
+						rev2.GetName():  rev2,
+						other.GetName(): other,
+					},
+					revisionsByComposition: map[string][]*apiextensionsv1.CompositionRevision{
+						"test-comp":  {rev1, rev2},
+						"other-comp": {other},
+					},
+				},
+				logger:       tu.TestLogger(t, false),
+				compositions: map[string]*apiextensionsv1.Composition{"test-comp": liveComp},
+			}
+
+			res := tu.NewResource("example.org/v1", "XR1", "my-xr").Build()
+
+			got, err := c.FindMatchingCompositionForRevision(t.Context(), res, tt.revision)
+
+			if tt.errorPattern != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("\n%s\nFindMatchingCompositionForRevision(...): expected error containing %q, got %v", tt.reason, tt.errorPattern, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nFindMatchingCompositionForRevision(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff("test-comp", got.GetName()); diff != "" {
+				t.Errorf("\n%s\nFindMatchingCompositionForRevision(...): -want name, +got name:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.expectStep, got.Spec.Pipeline[0].Step); diff != "" {
+				t.Errorf("\n%s\nFindMatchingCompositionForRevision(...): -want step, +got step:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultCompositionClient_ExplainCompositionSelection(t *testing.T) {
+	liveComp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		Build()
+
+	rev := func(name string, revision int64) *apiextensionsv1.CompositionRevision {
+		return &apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{LabelCompositionName: "test-comp"},
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+			},
+		}
+	}
+
+	rev1 := rev("test-comp-rev1", 1)
+	rev2 := rev("test-comp-rev2", 2)
+
+	tests := map[string]struct {
+		reason         string
+		res            *un.Unstructured
+		revisions      []*apiextensionsv1.CompositionRevision
+		expectRevision string
+		expectNumber   int64
+		expectReasons  []string
+		errorPattern   string
+	}{
+		"TypeReference": {
+			reason:        "Should explain a match by compositeTypeRef and that no revision is resolved",
+			res:           tu.NewResource("example.org/v1", "XR1", "my-xr").Build(),
+			revisions:     []*apiextensionsv1.CompositionRevision{rev1, rev2},
+			expectReasons: []string{"only composition whose compositeTypeRef is example.org/v1 XR1", "revisions are only resolved for an explicit compositionRef"},
+		},
+		"DirectReferenceAutomatic": {
+			reason: "Should report the latest revision for a compositionRef under the default Automatic policy",
+			res: tu.NewResource("example.org/v1", "XR1", "my-xr").
+				WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+				Build(),
+			revisions:      []*apiextensionsv1.CompositionRevision{rev1, rev2},
+			expectRevision: "test-comp-rev2",
+			expectNumber:   2,
+			expectReasons:  []string{"referenced by compositionRef test-comp", "Automatic update policy uses the latest revision"},
+		},
+		"DirectReferenceManualPinned": {
+			reason: "Should report the pinned revision under Manual policy with a compositionRevisionRef",
+			res: tu.NewResource("example.org/v1", "XR1", "my-xr").
+				WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+				WithSpecField("compositionUpdatePolicy", "Manual").
+				WithSpecField("compositionRevisionRef", map[string]any{"name": "test-comp-rev1"}).
+				Build(),
+			revisions:      []*apiextensionsv1.CompositionRevision{rev1, rev2},
+			expectRevision: "test-comp-rev1",
+			expectNumber:   1,
+			expectReasons:  []string{"referenced by compositionRef test-comp", "pinned by compositionRevisionRef"},
+		},
+		"DirectReferenceNoRevisions": {
+			reason: "Should explain falling back to the Composition when no revisions are published",
+			res: tu.NewResource("example.org/v1", "XR1", "my-xr").
+				WithSpecField("compositionRef", map[string]any{"name": "test-comp"}).
+				Build(),
+			revisions:     []*apiextensionsv1.CompositionRevision{},
+			expectReasons: []string{"referenced by compositionRef test-comp", "no published revisions"},
+		},
+		"NoMatchingComposition": {
+			reason:       "Should return the composition lookup error",
+			res:          tu.NewResource("example.org/v1", "OtherXR", "my-xr").Build(),
+			errorPattern: "no composition found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockResource := tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithEmptyListResources().
+				Build()
+
+			revisions := make(map[string]*apiextensionsv1.CompositionRevision)
+			for _, r := range tt.revisions {
+				revisions[r.GetName()] = r
+			}
+
+			c := &DefaultCompositionClient{
+				resourceClient: mockResource,
+				definitionClient: tu.NewMockDefinitionClient().
+					WithSuccessfulInitialize().
+					WithEmptyXRDsFetch().
+					WithV1XRDForXR().
+					Build(),
+				revisionClient: &DefaultCompositionRevisionClient{
+					resourceClient:         mockResource,
+					logger:                 tu.TestLogger(t, false),
+					revisions:              revisions,
+					revisionsByComposition: map[string][]*apiextensionsv1.CompositionRevision{"test-comp": tt.revisions},
+				},
+				logger:       tu.TestLogger(t, false),
+				compositions: map[string]*apiextensionsv1.Composition{"test-comp": liveComp},
+			}
+
+			got, err := c.ExplainCompositionSelection(t.Context(), tt.res)
+
+			if tt.errorPattern != "" {
+				if err == nil {
+					t.Fatalf("\n%s\nExplainCompositionSelection(...): expected error but got none", tt.reason)
+				}
+
+				if !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("\n%s\nExplainCompositionSelection(...): expected error containing %q, got %q", tt.reason, tt.errorPattern, err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nExplainCompositionSelection(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff("test-comp", got.Composition.GetName()); diff != "" {
+				t.Errorf("\n%s\nExplainCompositionSelection(...): -want composition, +got composition:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.expectRevision, got.RevisionName); diff != "" {
+				t.Errorf("\n%s\nExplainCompositionSelection(...): -want revision, +got revision:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.expectNumber, got.RevisionNumber); diff != "" {
+				t.Errorf("\n%s\nExplainCompositionSelection(...): -want revision number, +got revision number:\n%s", tt.reason, diff)
+			}
+
+			if len(got.Reasons) != len(tt.expectReasons) {
+				t.Fatalf("\n%s\nExplainCompositionSelection(...): want %d reasons, got %v", tt.reason, len(tt.expectReasons), got.Reasons)
+			}
+
+			for i, want := range tt.expectReasons {
+				if !strings.Contains(got.Reasons[i], want) {
+					t.Errorf("\n%s\nExplainCompositionSelection(...): reason %d %q does not contain %q", tt.reason, i, got.Reasons[i], want)
+				}
+			}
+		})
+	}
+}
+
 func TestGetCrossplaneRefPaths(t *testing.T) {
 	tests := map[string]struct {
 		reason     string
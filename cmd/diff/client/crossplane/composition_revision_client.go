@@ -3,6 +3,8 @@ package crossplane
 import (
 	"context"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
@@ -39,6 +41,10 @@ type CompositionRevisionClient interface {
 	// restriction (latest overall).
 	GetLatestRevisionForComposition(ctx context.Context, compositionName string, selector labels.Selector) (*apiextensionsv1.CompositionRevision, error)
 
+	// GetRevisionAsOf finds the revision of a given composition that was current at the given time,
+	// i.e. the one with the latest creation timestamp not after asOf.
+	GetRevisionAsOf(ctx context.Context, compositionName string, asOf time.Time) (*apiextensionsv1.CompositionRevision, error)
+
 	// GetCompositionFromRevision extracts a Composition from a CompositionRevision
 	GetCompositionFromRevision(revision *apiextensionsv1.CompositionRevision) *apiextensionsv1.Composition
 }
@@ -48,6 +54,8 @@ type DefaultCompositionRevisionClient struct {
 	resourceClient kubernetes.ResourceClient
 	logger         logging.Logger
 
+	// Guards revisions and revisionsByComposition
+	cacheMutex sync.RWMutex
 	// Cache of composition revisions by name (for individual revision lookups)
 	revisions map[string]*apiextensionsv1.CompositionRevision
 	// Cache of revisions per composition (lazy-loaded on demand)
@@ -170,7 +178,11 @@ func (c *DefaultCompositionRevisionClient) ListCompositionRevisions(ctx context.
 // GetCompositionRevision gets a composition revision by name.
 func (c *DefaultCompositionRevisionClient) GetCompositionRevision(ctx context.Context, name string) (*apiextensionsv1.CompositionRevision, error) {
 	// Check cache first
-	if rev, ok := c.revisions[name]; ok {
+	c.cacheMutex.RLock()
+	rev, ok := c.revisions[name]
+	c.cacheMutex.RUnlock()
+
+	if ok {
 		return rev, nil
 	}
 
@@ -187,13 +199,15 @@ func (c *DefaultCompositionRevisionClient) GetCompositionRevision(ctx context.Co
 	}
 
 	// Convert to typed
-	rev := &apiextensionsv1.CompositionRevision{}
+	rev = &apiextensionsv1.CompositionRevision{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unRev.Object, rev); err != nil {
 		return nil, errors.Wrap(err, "cannot convert unstructured to CompositionRevision")
 	}
 
 	// Update cache
+	c.cacheMutex.Lock()
 	c.revisions[name] = rev
+	c.cacheMutex.Unlock()
 
 	return rev, nil
 }
@@ -201,7 +215,11 @@ func (c *DefaultCompositionRevisionClient) GetCompositionRevision(ctx context.Co
 // revisionsForComposition returns all CompositionRevisions for the named composition, loading and
 // caching them (by composition and by name) on first access.
 func (c *DefaultCompositionRevisionClient) revisionsForComposition(ctx context.Context, compositionName string) ([]*apiextensionsv1.CompositionRevision, error) {
-	if cached, ok := c.revisionsByComposition[compositionName]; ok {
+	c.cacheMutex.RLock()
+	cached, ok := c.revisionsByComposition[compositionName]
+	c.cacheMutex.RUnlock()
+
+	if ok {
 		return cached, nil
 	}
 
@@ -212,6 +230,9 @@ func (c *DefaultCompositionRevisionClient) revisionsForComposition(ctx context.C
 		return nil, errors.Wrap(err, "cannot list composition revisions")
 	}
 
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
 	// Cache by name for individual lookups.
 	for _, rev := range revisions {
 		c.revisions[rev.GetName()] = rev
@@ -278,6 +299,49 @@ func (c *DefaultCompositionRevisionClient) GetLatestRevisionForComposition(ctx c
 	return latest, nil
 }
 
+// GetRevisionAsOf finds the revision of a given composition that was current at the given time: the
+// one with the latest creation timestamp at or before asOf. Ties on timestamp (which has one-second
+// granularity) are broken by revision number. Returns an error if no revision existed at that time.
+func (c *DefaultCompositionRevisionClient) GetRevisionAsOf(ctx context.Context, compositionName string, asOf time.Time) (*apiextensionsv1.CompositionRevision, error) {
+	c.logger.Debug("Finding revision as of time", "compositionName", compositionName, "asOf", asOf)
+
+	revisions, err := c.revisionsForComposition(ctx, compositionName)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *apiextensionsv1.CompositionRevision
+
+	for _, rev := range revisions {
+		created := rev.GetCreationTimestamp().Time
+		if created.After(asOf) {
+			continue
+		}
+
+		if current == nil {
+			current = rev
+			continue
+		}
+
+		currentCreated := current.GetCreationTimestamp().Time
+		if created.After(currentCreated) || (created.Equal(currentCreated) && rev.Spec.Revision > current.Spec.Revision) {
+			current = rev
+		}
+	}
+
+	if current == nil {
+		return nil, errors.Errorf("no composition revisions for composition %s existed as of %s", compositionName, asOf.Format(time.RFC3339))
+	}
+
+	c.logger.Debug("Found revision as of time",
+		"compositionName", compositionName,
+		"asOf", asOf,
+		"revisionName", current.GetName(),
+		"revisionNumber", current.Spec.Revision)
+
+	return current, nil
+}
+
 // GetCompositionFromRevision extracts a Composition from a CompositionRevision.
 // CompositionRevision contains the full Composition spec, so we construct a Composition object.
 func (c *DefaultCompositionRevisionClient) GetCompositionFromRevision(revision *apiextensionsv1.CompositionRevision) *apiextensionsv1.Composition {
@@ -5,6 +5,7 @@ import (
 	"maps"
 	"strings"
 	"testing"
+	"time"
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
@@ -543,6 +544,116 @@ func TestDefaultCompositionRevisionClient_GetLatestRevisionForComposition_Select
 	}
 }
 
+func TestDefaultCompositionRevisionClient_GetRevisionAsOf(t *testing.T) {
+	ctx := t.Context()
+
+	base := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	rev := func(name string, revision int64, created time.Time) *apiextensionsv1.CompositionRevision {
+		return &apiextensionsv1.CompositionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{LabelCompositionName: "test-comp"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: apiextensionsv1.CompositionRevisionSpec{
+				Revision:         revision,
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "example.org/v1", Kind: "XR1"},
+			},
+		}
+	}
+
+	rev1 := rev("test-comp-rev1", 1, base)
+	rev2 := rev("test-comp-rev2", 2, base.Add(24*time.Hour))
+	rev3 := rev("test-comp-rev3", 3, base.Add(48*time.Hour))
+	// rev4 shares rev3's creation second; the higher revision number wins.
+	rev4 := rev("test-comp-rev4", 4, base.Add(48*time.Hour))
+
+	tests := map[string]struct {
+		reason       string
+		revisions    []*apiextensionsv1.CompositionRevision
+		asOf         time.Time
+		expectName   string
+		errorPattern string
+	}{
+		"BetweenRevisions": {
+			reason:     "Should select the newest revision created before the given time",
+			revisions:  []*apiextensionsv1.CompositionRevision{rev3, rev1, rev2},
+			asOf:       base.Add(36 * time.Hour),
+			expectName: "test-comp-rev2",
+		},
+		"ExactlyAtCreation": {
+			reason:     "A revision created exactly at the given time counts as current",
+			revisions:  []*apiextensionsv1.CompositionRevision{rev1, rev2, rev3},
+			asOf:       base.Add(24 * time.Hour),
+			expectName: "test-comp-rev2",
+		},
+		"AfterAllRevisions": {
+			reason:     "Should select the newest revision when the time is after every revision",
+			revisions:  []*apiextensionsv1.CompositionRevision{rev1, rev2, rev3},
+			asOf:       base.Add(72 * time.Hour),
+			expectName: "test-comp-rev3",
+		},
+		"TimestampTieBrokenByRevisionNumber": {
+			reason:     "Should prefer the higher revision number when creation timestamps are equal",
+			revisions:  []*apiextensionsv1.CompositionRevision{rev4, rev3, rev1},
+			asOf:       base.Add(72 * time.Hour),
+			expectName: "test-comp-rev4",
+		},
+		"BeforeAllRevisions": {
+			reason:       "Should return an error when no revision existed at the given time",
+			revisions:    []*apiextensionsv1.CompositionRevision{rev1, rev2},
+			asOf:         base.Add(-time.Hour),
+			errorPattern: "no composition revisions for composition test-comp existed as of",
+		},
+		"NoRevisions": {
+			reason:       "Should return an error when the composition has no revisions",
+			revisions:    []*apiextensionsv1.CompositionRevision{},
+			asOf:         base,
+			errorPattern: "no composition revisions for composition test-comp existed as of",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &DefaultCompositionRevisionClient{
+				resourceClient: tu.NewMockResourceClient().
+					WithGetResourcesByLabel(func(context.Context, schema.GroupVersionKind, string, metav1.LabelSelector) ([]*un.Unstructured, error) {
+						return nil, errors.New("should not call GetResourcesByLabel when cache is populated")
+					}).
+					Build(),
+				logger:    tu.TestLogger(t, false),
+				revisions: make(map[string]*apiextensionsv1.CompositionRevision),
+				revisionsByComposition: map[string][]*apiextensionsv1.CompositionRevision{
+					"test-comp": tt.revisions,
+				},
+			}
+
+			got, err := c.GetRevisionAsOf(ctx, "test-comp", tt.asOf)
+
+			if tt.errorPattern != "" {
+				if err == nil {
+					t.Fatalf("\n%s\nGetRevisionAsOf(...): expected error but got none", tt.reason)
+				}
+
+				if !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("\n%s\nGetRevisionAsOf(...): expected error containing %q, got %q", tt.reason, tt.errorPattern, err.Error())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nGetRevisionAsOf(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.expectName, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nGetRevisionAsOf(...): -want name, +got name:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestDefaultCompositionRevisionClient_GetCompositionFromRevision(t *testing.T) {
 	tests := map[string]struct {
 		reason     string
@@ -2,10 +2,12 @@ package crossplane
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -17,19 +19,24 @@ import (
 // CompositeResourceDefinitionKind is the kind for Composite Resource Definitions.
 const CompositeResourceDefinitionKind = "CompositeResourceDefinition"
 
+// xrdsCacheEntry is the disk cache entry holding every XRD in the cluster.
+const xrdsCacheEntry = "xrds"
+
 // DefinitionClient handles Crossplane definitions (XRDs).
 //
-//nolint:interfacebloat // The 6 methods are cohesively about XRD lookup; splitting just to satisfy the linter would create surface without value.
+//nolint:interfacebloat // The 7 methods are cohesively about XRD lookup; splitting just to satisfy the linter would create surface without value.
 type DefinitionClient interface {
 	core.Initializable
 
 	// GetXRDs gets all XRDs in the cluster
 	GetXRDs(ctx context.Context) ([]*un.Unstructured, error)
 
-	// GetXRDForClaim finds the XRD that defines the given claim type
+	// GetXRDForClaim finds the XRD that defines the given claim type. It returns a NotFound
+	// error if no XRD does.
 	GetXRDForClaim(ctx context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error)
 
-	// GetXRDForXR finds the XRD that defines the given XR type
+	// GetXRDForXR finds the XRD that defines the given XR type. It returns a NotFound error if
+	// no XRD does.
 	GetXRDForXR(ctx context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error)
 
 	// IsClaimResource checks if the given resource is a claim type
@@ -44,11 +51,17 @@ type DefinitionClient interface {
 	// rule the render binary uses (selectSchema in crossplane's
 	// internal/render/composite/render.go).
 	GetCompositeSchema(ctx context.Context, gvk schema.GroupVersionKind) (ucomposite.Schema, error)
+
+	// AddLocalXRDs registers XRDs supplied with the input rather than read
+	// from the cluster, e.g. for XR types that aren't installed yet. Lookups
+	// prefer them over cluster XRDs.
+	AddLocalXRDs(xrds []*un.Unstructured)
 }
 
 // DefaultDefinitionClient implements DefinitionClient.
 type DefaultDefinitionClient struct {
 	resourceClient kubernetes.ResourceClient
+	cache          *core.DiskCache
 	logger         logging.Logger
 
 	// XRDs cache
@@ -56,12 +69,17 @@ type DefaultDefinitionClient struct {
 	xrds       []*un.Unstructured
 	xrdsMutex  sync.RWMutex
 	xrdsLoaded bool
+
+	// XRDs supplied with the input, guarded by xrdsMutex
+	localXRDs []*un.Unstructured
 }
 
-// NewDefinitionClient creates a new DefaultDefinitionClient.
-func NewDefinitionClient(resourceClient kubernetes.ResourceClient, logger logging.Logger) DefinitionClient {
+// NewDefinitionClient creates a new DefaultDefinitionClient. XRDs fetched from
+// the cluster are persisted to cache, which may be nil.
+func NewDefinitionClient(resourceClient kubernetes.ResourceClient, cache *core.DiskCache, logger logging.Logger) DefinitionClient {
 	return &DefaultDefinitionClient{
 		resourceClient: resourceClient,
+		cache:          cache,
 		logger:         logger,
 		xrds:           []*un.Unstructured{},
 	}
@@ -71,6 +89,23 @@ func NewDefinitionClient(resourceClient kubernetes.ResourceClient, logger loggin
 func (c *DefaultDefinitionClient) Initialize(ctx context.Context) error {
 	c.logger.Debug("Initializing definition client")
 
+	var cached []map[string]any
+	if c.cache.Load(xrdsCacheEntry, &cached) {
+		c.xrdsMutex.Lock()
+		c.xrds = make([]*un.Unstructured, 0, len(cached))
+
+		for _, obj := range cached {
+			c.xrds = append(c.xrds, &un.Unstructured{Object: obj})
+		}
+
+		c.xrdsLoaded = true
+		c.xrdsMutex.Unlock()
+
+		c.logger.Debug("Definition client initialized from disk cache", "xrdsCount", len(c.xrds))
+
+		return nil
+	}
+
 	gvks, err := c.resourceClient.GetGVKsForGroupKind(ctx, CrossplaneAPIExtGroup, CompositeResourceDefinitionKind)
 	if err != nil {
 		return errors.Wrap(err, "cannot get XRD GVKs")
@@ -95,7 +130,7 @@ func (c *DefaultDefinitionClient) GetXRDs(ctx context.Context) ([]*un.Unstructur
 	c.xrdsMutex.RLock()
 
 	if c.xrdsLoaded {
-		xrds := c.xrds
+		xrds := withLocalXRDs(c.xrds, c.localXRDs)
 		c.xrdsMutex.RUnlock()
 		c.logger.Debug("Using cached XRDs", "count", len(xrds))
 
@@ -111,7 +146,7 @@ func (c *DefaultDefinitionClient) GetXRDs(ctx context.Context) ([]*un.Unstructur
 	// Double-check now that we have the write lock
 	if c.xrdsLoaded {
 		c.logger.Debug("Using cached XRDs (after recheck)", "count", len(c.xrds))
-		return c.xrds, nil
+		return withLocalXRDs(c.xrds, c.localXRDs), nil
 	}
 
 	c.logger.Debug("Fetching XRDs from cluster")
@@ -127,9 +162,53 @@ func (c *DefaultDefinitionClient) GetXRDs(ctx context.Context) ([]*un.Unstructur
 	c.xrds = xrds
 	c.xrdsLoaded = true
 
+	objs := make([]map[string]any, 0, len(xrds))
+	for _, xrd := range xrds {
+		objs = append(objs, xrd.Object)
+	}
+
+	if err := c.cache.Store(xrdsCacheEntry, objs); err != nil {
+		c.logger.Debug("Failed to cache XRDs on disk", "error", err)
+	}
+
 	c.logger.Debug("Successfully retrieved and cached XRDs", "count", len(xrds))
 
-	return xrds, nil
+	return withLocalXRDs(xrds, c.localXRDs), nil
+}
+
+// AddLocalXRDs registers XRDs supplied with the input rather than read from the cluster.
+func (c *DefaultDefinitionClient) AddLocalXRDs(xrds []*un.Unstructured) {
+	c.xrdsMutex.Lock()
+	defer c.xrdsMutex.Unlock()
+
+	c.localXRDs = append(c.localXRDs, xrds...)
+
+	c.logger.Debug("Registered local XRDs", "count", len(xrds), "total", len(c.localXRDs))
+}
+
+// withLocalXRDs returns the cluster XRDs with the local XRDs in front, dropping any cluster XRD a
+// local one replaces by name. Lookups take the first match, so a local XRD also wins over a cluster
+// XRD of another name that defines the same type.
+func withLocalXRDs(cluster, local []*un.Unstructured) []*un.Unstructured {
+	if len(local) == 0 {
+		return cluster
+	}
+
+	replaced := make(map[string]bool, len(local))
+	for _, xrd := range local {
+		replaced[xrd.GetName()] = true
+	}
+
+	merged := make([]*un.Unstructured, 0, len(local)+len(cluster))
+	merged = append(merged, local...)
+
+	for _, xrd := range cluster {
+		if !replaced[xrd.GetName()] {
+			merged = append(merged, xrd)
+		}
+	}
+
+	return merged
 }
 
 // GetXRDForClaim finds the XRD that defines the given claim type.
@@ -170,7 +249,7 @@ func (c *DefaultDefinitionClient) GetXRDForClaim(ctx context.Context, gvk schema
 		return xrd, nil
 	}
 
-	return nil, errors.Errorf("no XRD found that defines claim type %s", gvk.String())
+	return nil, errNoXRD("claim", gvk)
 }
 
 // GetXRDForXR finds the XRD that defines the given XR type.
@@ -231,7 +310,16 @@ func (c *DefaultDefinitionClient) GetXRDForXR(ctx context.Context, gvk schema.Gr
 		return xrd, nil
 	}
 
-	return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+	return nil, errNoXRD("XR", gvk)
+}
+
+// errNoXRD returns a NotFound error saying no XRD defines gvk as the given type, XR or claim, so
+// callers can tell it apart from a failure to list XRDs.
+func errNoXRD(typ string, gvk schema.GroupVersionKind) error {
+	err := apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.crossplane.io", Resource: "compositeresourcedefinitions"}, gvk.String())
+	err.ErrStatus.Message = fmt.Sprintf("no XRD found that defines %s type %s", typ, gvk.String())
+
+	return err
 }
 
 // IsClaimResource checks if the given resource is a claim type by attempting
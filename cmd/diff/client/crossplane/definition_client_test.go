@@ -4,9 +4,12 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -241,6 +244,7 @@ func TestDefaultDefinitionClient_GetXRDForClaim(t *testing.T) {
 		want              *un.Unstructured
 		wantErr           bool
 		errSubstring      string
+		wantNotFound      bool // whether the error should be NotFound, i.e. no XRD defines the type
 	}{
 		"MatchingClaimFound": {
 			reason: "Should return the XRD that defines the claim kind",
@@ -275,6 +279,7 @@ func TestDefaultDefinitionClient_GetXRDForClaim(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines claim type",
+			wantNotFound: true,
 		},
 		"GetXRDsError": {
 			reason: "Should propagate error from GetXRDs",
@@ -311,6 +316,7 @@ func TestDefaultDefinitionClient_GetXRDForClaim(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines claim type",
+			wantNotFound: true,
 		},
 		"DifferentKind": {
 			reason: "Should not match XRD with different claim kind",
@@ -328,6 +334,7 @@ func TestDefaultDefinitionClient_GetXRDForClaim(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines claim type",
+			wantNotFound: true,
 		},
 	}
 
@@ -353,6 +360,10 @@ func TestDefaultDefinitionClient_GetXRDForClaim(t *testing.T) {
 					t.Errorf("\n%s\nGetXRDForClaim(): expected error containing %q, got %q", tt.reason, tt.errSubstring, err.Error())
 				}
 
+				if got := apierrors.IsNotFound(err); got != tt.wantNotFound {
+					t.Errorf("\n%s\nGetXRDForClaim(): IsNotFound(err) = %t, want %t", tt.reason, got, tt.wantNotFound)
+				}
+
 				return
 			}
 
@@ -435,6 +446,7 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 		want              *un.Unstructured
 		wantErr           bool
 		errSubstring      string
+		wantNotFound      bool // whether the error should be NotFound, i.e. no XRD defines the type
 	}{
 		"MatchingXRFound": {
 			reason: "Should return the XRD that defines the XR kind",
@@ -484,6 +496,7 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines XR type",
+			wantNotFound: true,
 		},
 		"GetXRDsError": {
 			reason: "Should propagate error from GetXRDs",
@@ -520,6 +533,7 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines XR type",
+			wantNotFound: true,
 		},
 		"VersionNotFound": {
 			reason: "Should not match XRD if version doesn't exist",
@@ -537,6 +551,7 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 			want:         nil,
 			wantErr:      true,
 			errSubstring: "no XRD found that defines XR type",
+			wantNotFound: true,
 		},
 	}
 
@@ -562,6 +577,10 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 					t.Errorf("\n%s\nGetXRDForXR(): expected error containing %q, got %q", tt.reason, tt.errSubstring, err.Error())
 				}
 
+				if got := apierrors.IsNotFound(err); got != tt.wantNotFound {
+					t.Errorf("\n%s\nGetXRDForXR(): IsNotFound(err) = %t, want %t", tt.reason, got, tt.wantNotFound)
+				}
+
 				return
 			}
 
@@ -584,13 +603,94 @@ func TestDefaultDefinitionClient_GetXRDForXR(t *testing.T) {
 	}
 }
 
+func TestDefaultDefinitionClient_AddLocalXRDs(t *testing.T) {
+	ctx := t.Context()
+
+	xrd := func(name, kind, version string) *un.Unstructured {
+		return tu.NewResource("apiextensions.crossplane.io/v2", CompositeResourceDefinitionKind, name).
+			WithSpecField("group", "example.org").
+			WithSpecField("names", map[string]any{
+				"kind":   kind,
+				"plural": strings.ToLower(kind) + "s",
+			}).
+			WithSpecField("versions", []any{
+				map[string]any{"name": version, "served": true, "storage": true},
+			}).
+			Build()
+	}
+
+	clusterXR1 := xrd("xr1s.example.org", "XR1", "v1")
+	clusterXR2 := xrd("xr2s.example.org", "XR2", "v1")
+
+	tests := map[string]struct {
+		reason    string
+		local     []*un.Unstructured
+		gvk       schema.GroupVersionKind
+		wantXRD   string
+		wantNames []string
+	}{
+		"LocalOnly": {
+			reason:    "An XRD that exists only locally should be found, ahead of the cluster XRDs.",
+			local:     []*un.Unstructured{xrd("xr3s.example.org", "XR3", "v1")},
+			gvk:       schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR3"},
+			wantXRD:   "xr3s.example.org",
+			wantNames: []string{"xr3s.example.org", "xr1s.example.org", "xr2s.example.org"},
+		},
+		"ReplacesClusterXRD": {
+			reason:    "A local XRD should replace the cluster XRD of the same name, e.g. to add a version.",
+			local:     []*un.Unstructured{xrd("xr1s.example.org", "XR1", "v2")},
+			gvk:       schema.GroupVersionKind{Group: "example.org", Version: "v2", Kind: "XR1"},
+			wantXRD:   "xr1s.example.org",
+			wantNames: []string{"xr1s.example.org", "xr2s.example.org"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &DefaultDefinitionClient{
+				resourceClient: tu.NewMockResourceClient().Build(),
+				logger:         tu.TestLogger(t, false),
+				xrds:           []*un.Unstructured{clusterXR1, clusterXR2},
+				xrdsLoaded:     true,
+			}
+
+			c.AddLocalXRDs(tt.local)
+
+			got, err := c.GetXRDForXR(ctx, tt.gvk)
+			if err != nil {
+				t.Fatalf("\n%s\nGetXRDForXR(): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantXRD, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nGetXRDForXR(): -want name, +got name:\n%s", tt.reason, diff)
+			}
+
+			xrds, err := c.GetXRDs(ctx)
+			if err != nil {
+				t.Fatalf("\n%s\nGetXRDs(): unexpected error: %v", tt.reason, err)
+			}
+
+			names := make([]string, 0, len(xrds))
+			for _, x := range xrds {
+				names = append(names, x.GetName())
+			}
+
+			if diff := cmp.Diff(tt.wantNames, names); diff != "" {
+				t.Errorf("\n%s\nGetXRDs(): -want names, +got names:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestDefaultDefinitionClient_Initialize(t *testing.T) {
 	ctx := t.Context()
 
 	tests := map[string]struct {
 		reason       string
 		mockResource tu.MockResourceClient
+		cachedXRDs   []*un.Unstructured
 		wantErr      bool
+		wantXRDs     int
 	}{
 		"SuccessfulInitialization": {
 			reason: "Should successfully initialize the client",
@@ -609,12 +709,38 @@ func TestDefaultDefinitionClient_Initialize(t *testing.T) {
 				Build(),
 			wantErr: true,
 		},
+		"FreshDiskCache": {
+			reason: "Should load XRDs from a fresh disk cache without listing them from the cluster",
+			mockResource: *tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				WithListResourcesFailure("list error").
+				Build(),
+			cachedXRDs: []*un.Unstructured{
+				tu.NewResource(CrossplaneAPIExtGroup+"/v1", CompositeResourceDefinitionKind, "xresources.example.org").Build(),
+			},
+			wantErr:  false,
+			wantXRDs: 1,
+		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			cache := core.NewDiskCache(t.TempDir(), "https://cluster.example:6443", time.Hour)
+
+			if tt.cachedXRDs != nil {
+				objs := make([]map[string]any, 0, len(tt.cachedXRDs))
+				for _, xrd := range tt.cachedXRDs {
+					objs = append(objs, xrd.Object)
+				}
+
+				if err := cache.Store(xrdsCacheEntry, objs); err != nil {
+					t.Fatalf("seed cache: %v", err)
+				}
+			}
+
 			c := &DefaultDefinitionClient{
 				resourceClient: &tt.mockResource,
+				cache:          cache,
 				logger:         tu.TestLogger(t, false),
 			}
 
@@ -631,6 +757,10 @@ func TestDefaultDefinitionClient_Initialize(t *testing.T) {
 				if !c.xrdsLoaded {
 					t.Errorf("\n%s\nInitialize(): XRDs not marked as loaded after successful initialization", tt.reason)
 				}
+
+				if len(c.xrds) != tt.wantXRDs {
+					t.Errorf("\n%s\nInitialize(): want %d XRDs, got %d", tt.reason, tt.wantXRDs, len(c.xrds))
+				}
 			}
 		})
 	}
@@ -2,10 +2,15 @@ package crossplane
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
@@ -21,6 +26,13 @@ type EnvironmentClient interface {
 
 	// GetEnvironmentConfig gets a specific environment config by name
 	GetEnvironmentConfig(ctx context.Context, name string) (*un.Unstructured, error)
+
+	// GetEnvironmentConfigsBySelector gets the environment configs whose labels match the selector
+	GetEnvironmentConfigsBySelector(ctx context.Context, selector metav1.LabelSelector) ([]*un.Unstructured, error)
+
+	// AddLocalEnvironmentConfigs caches environment configs supplied from files, overriding the
+	// cluster's configs of the same name.
+	AddLocalEnvironmentConfigs(configs []*un.Unstructured)
 }
 
 // DefaultEnvironmentClient implements EnvironmentClient.
@@ -31,6 +43,9 @@ type DefaultEnvironmentClient struct {
 	// Cache of environment configs
 	envConfigs map[string]*un.Unstructured
 	gvks       []schema.GroupVersionKind
+
+	// Environment configs supplied from files, by name
+	localConfigs map[string]*un.Unstructured
 }
 
 // NewEnvironmentClient creates a new DefaultEnvironmentClient.
@@ -39,6 +54,7 @@ func NewEnvironmentClient(resourceClient kubernetes.ResourceClient, logger loggi
 		resourceClient: resourceClient,
 		logger:         logger,
 		envConfigs:     make(map[string]*un.Unstructured),
+		localConfigs:   make(map[string]*un.Unstructured),
 	}
 }
 
@@ -46,7 +62,7 @@ func NewEnvironmentClient(resourceClient kubernetes.ResourceClient, logger loggi
 func (c *DefaultEnvironmentClient) Initialize(ctx context.Context) error {
 	c.logger.Debug("Initializing environment client")
 
-	gvks, err := c.resourceClient.GetGVKsForGroupKind(ctx, CrossplaneAPIExtGroup, "EnvironmentConfig")
+	gvks, err := c.resourceClient.GetGVKsForGroupKind(ctx, CrossplaneAPIExtGroup, EnvironmentConfigKind)
 	if err != nil {
 		return errors.Wrap(err, "cannot get EnvironmentConfig GVKs")
 	}
@@ -73,19 +89,81 @@ func (c *DefaultEnvironmentClient) Initialize(ctx context.Context) error {
 func (c *DefaultEnvironmentClient) GetEnvironmentConfigs(ctx context.Context) ([]*un.Unstructured, error) {
 	c.logger.Debug("Getting environment configs")
 
-	envConfigs, err := listMatchingResources(ctx, c.resourceClient, c.gvks, "" /* ECs are cluster scoped */)
+	listed, err := listMatchingResources(ctx, c.resourceClient, c.gvks, "" /* ECs are cluster scoped */)
 	if err != nil {
 		return nil, err
 	}
 
-	c.logger.Debug("Environment configs retrieved", "count", len(envConfigs))
+	// Local configs replace cluster configs of the same name.
+	envConfigs := make([]*un.Unstructured, 0, len(listed)+len(c.localConfigs))
+	for _, config := range listed {
+		if _, ok := c.localConfigs[config.GetName()]; !ok {
+			envConfigs = append(envConfigs, config)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.localConfigs)) {
+		envConfigs = append(envConfigs, c.localConfigs[name])
+	}
+
+	c.logger.Debug("Environment configs retrieved", "count", len(envConfigs), "local", len(c.localConfigs))
 
 	return envConfigs, nil
 }
 
+// AddLocalEnvironmentConfigs caches environment configs supplied from files rather than read from
+// the cluster. They take precedence over cluster configs of the same name.
+func (c *DefaultEnvironmentClient) AddLocalEnvironmentConfigs(configs []*un.Unstructured) {
+	for _, config := range configs {
+		c.localConfigs[config.GetName()] = config
+		c.envConfigs[cacheKey("", config.GetName())] = config
+	}
+
+	c.logger.Debug("Registered local environment configs", "count", len(configs), "total", len(c.envConfigs))
+}
+
 // GetEnvironmentConfig gets a specific environment config by name.
 func (c *DefaultEnvironmentClient) GetEnvironmentConfig(ctx context.Context, name string) (*un.Unstructured, error) {
 	c.logger.Debug("Getting environment config", "name", name)
 
 	return getFirstMatchingResource(ctx, c.resourceClient, c.gvks, name, "" /* ECs are cluster scoped */, c.envConfigs)
 }
+
+// GetEnvironmentConfigsBySelector gets the environment configs whose labels match the selector,
+// ordered by name, as Crossplane selects them for a function's EnvironmentConfig requirement. It
+// is served from the cache once the client is initialized, and returns an empty list rather than
+// an error when no config matches, leaving the fallback to the function.
+func (c *DefaultEnvironmentClient) GetEnvironmentConfigsBySelector(ctx context.Context, selector metav1.LabelSelector) ([]*un.Unstructured, error) {
+	sel, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid environment config selector")
+	}
+
+	candidates := make([]*un.Unstructured, 0, len(c.envConfigs))
+	for _, config := range c.envConfigs {
+		candidates = append(candidates, config)
+	}
+
+	if len(candidates) == 0 {
+		candidates, err = c.GetEnvironmentConfigs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matched []*un.Unstructured
+
+	for _, config := range candidates {
+		if sel.Matches(labels.Set(config.GetLabels())) {
+			matched = append(matched, config)
+		}
+	}
+
+	slices.SortFunc(matched, func(a, b *un.Unstructured) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	c.logger.Debug("Environment configs selected", "selector", sel.String(), "count", len(matched))
+
+	return matched, nil
+}
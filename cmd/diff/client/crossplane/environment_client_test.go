@@ -7,6 +7,7 @@ import (
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -387,3 +388,144 @@ func TestDefaultEnvironmentClient_Initialize(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultEnvironmentClient_GetEnvironmentConfigsBySelector(t *testing.T) {
+	prodA := tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod-a").
+		WithLabels(map[string]string{"env": "prod", "region": "us-east-1"}).
+		Build()
+	prodB := tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod-b").
+		WithLabels(map[string]string{"env": "prod", "region": "eu-west-1"}).
+		Build()
+	dev := tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "dev").
+		WithLabels(map[string]string{"env": "dev"}).
+		Build()
+
+	tests := map[string]struct {
+		reason   string
+		cached   []*un.Unstructured
+		listed   []*un.Unstructured
+		selector metav1.LabelSelector
+		want     []string
+		wantErr  bool
+	}{
+		"MatchesFromCache": {
+			reason:   "Configs whose labels match should be returned in name order, from the cache.",
+			cached:   []*un.Unstructured{prodB, dev, prodA},
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			want:     []string{"prod-a", "prod-b"},
+		},
+		"AllLabelsMustMatch": {
+			reason:   "Every label in the selector should have to match.",
+			cached:   []*un.Unstructured{prodA, prodB, dev},
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod", "region": "eu-west-1"}},
+			want:     []string{"prod-b"},
+		},
+		"ListsWhenNotCached": {
+			reason:   "Configs should be listed from the cluster when the cache is empty.",
+			listed:   []*un.Unstructured{prodA, dev},
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+			want:     []string{"dev"},
+		},
+		"NoMatch": {
+			reason:   "No matching config should yield an empty list, not an error, leaving the fallback to the function.",
+			cached:   []*un.Unstructured{prodA, dev},
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}},
+		},
+		"InvalidSelector": {
+			reason: "An invalid selector should be reported.",
+			cached: []*un.Unstructured{prodA},
+			selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: "Bogus"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &DefaultEnvironmentClient{
+				resourceClient: tu.NewMockResourceClient().
+					WithListResources(func(context.Context, schema.GroupVersionKind, string) ([]*un.Unstructured, error) {
+						return tt.listed, nil
+					}).
+					Build(),
+				logger:     tu.TestLogger(t, false),
+				envConfigs: make(map[string]*un.Unstructured),
+				gvks:       []schema.GroupVersionKind{EnvConfigV1beta1GVK},
+			}
+
+			for _, cfg := range tt.cached {
+				c.envConfigs[cacheKey("", cfg.GetName())] = cfg
+			}
+
+			got, err := c.GetEnvironmentConfigsBySelector(t.Context(), tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nGetEnvironmentConfigsBySelector(...): expected error but got none", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nGetEnvironmentConfigsBySelector(...): unexpected error: %v", tt.reason, err)
+			}
+
+			var names []string
+			for _, cfg := range got {
+				names = append(names, cfg.GetName())
+			}
+
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("\n%s\nGetEnvironmentConfigsBySelector(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultEnvironmentClient_AddLocalEnvironmentConfigs(t *testing.T) {
+	withRegion := func(name, region string) *un.Unstructured {
+		cfg := tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", name).Build()
+		_ = un.SetNestedField(cfg.Object, region, "data", "region")
+
+		return cfg
+	}
+
+	c := &DefaultEnvironmentClient{
+		resourceClient: tu.NewMockResourceClient().
+			WithListResources(func(context.Context, schema.GroupVersionKind, string) ([]*un.Unstructured, error) {
+				return []*un.Unstructured{withRegion("prod", "us-east-1"), withRegion("dev", "us-west-2")}, nil
+			}).
+			Build(),
+		logger:       tu.TestLogger(t, false),
+		envConfigs:   make(map[string]*un.Unstructured),
+		localConfigs: make(map[string]*un.Unstructured),
+		gvks:         []schema.GroupVersionKind{EnvConfigV1beta1GVK},
+	}
+
+	c.AddLocalEnvironmentConfigs([]*un.Unstructured{withRegion("prod", "eu-west-1"), withRegion("staging", "eu-central-1")})
+
+	got, err := c.GetEnvironmentConfigs(t.Context())
+	if err != nil {
+		t.Fatalf("GetEnvironmentConfigs(): unexpected error: %v", err)
+	}
+
+	regions := make(map[string]string, len(got))
+	for _, cfg := range got {
+		regions[cfg.GetName()], _, _ = un.NestedString(cfg.Object, "data", "region")
+	}
+
+	want := map[string]string{"prod": "eu-west-1", "dev": "us-west-2", "staging": "eu-central-1"}
+	if diff := cmp.Diff(want, regions); diff != "" {
+		t.Errorf("GetEnvironmentConfigs(): local configs should override cluster configs of the same name, -want, +got:\n%s", diff)
+	}
+
+	prod, err := c.GetEnvironmentConfig(t.Context(), "prod")
+	if err != nil {
+		t.Fatalf("GetEnvironmentConfig(): unexpected error: %v", err)
+	}
+
+	if region, _, _ := un.NestedString(prod.Object, "data", "region"); region != "eu-west-1" {
+		t.Errorf("GetEnvironmentConfig(): want the local prod config, got region %q", region)
+	}
+}
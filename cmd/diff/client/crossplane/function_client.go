@@ -25,6 +25,10 @@ type FunctionClient interface {
 
 	// ListFunctions lists all functions in the cluster
 	ListFunctions(ctx context.Context) ([]pkgv1.Function, error)
+
+	// AddLocalFunctions caches functions supplied from files, e.g. to render with a version
+	// that isn't installed. They replace any cluster function of the same name.
+	AddLocalFunctions(fns []pkgv1.Function)
 }
 
 // DefaultFunctionClient implements FunctionClient.
@@ -116,6 +120,16 @@ func (c *DefaultFunctionClient) ListFunctions(ctx context.Context) ([]pkgv1.Func
 	return functions, nil
 }
 
+// AddLocalFunctions caches functions supplied from files rather than read from the cluster.
+// It's called after Initialize, so a local function replaces a cluster function of the same name.
+func (c *DefaultFunctionClient) AddLocalFunctions(fns []pkgv1.Function) {
+	for _, fn := range fns {
+		c.functions[fn.GetName()] = fn
+	}
+
+	c.logger.Debug("Registered local functions", "count", len(fns), "total", len(c.functions))
+}
+
 // GetFunctionsFromPipeline gets functions used in a composition pipeline.
 func (c *DefaultFunctionClient) GetFunctionsFromPipeline(comp *apiextensionsv1.Composition) ([]pkgv1.Function, error) {
 	c.logger.Debug("Getting functions from pipeline", "composition_name", comp.GetName())
@@ -135,6 +149,9 @@ func (c *DefaultFunctionClient) GetFunctionsFromPipeline(comp *apiextensionsv1.C
 	functions := make([]pkgv1.Function, 0, len(comp.Spec.Pipeline))
 	c.logger.Debug("Processing pipeline steps", "steps_count", len(comp.Spec.Pipeline))
 
+	// Check every step before failing, so all missing functions are reported at once.
+	var missing []error
+
 	for _, step := range comp.Spec.Pipeline {
 		fn, ok := c.functions[step.FunctionRef.Name]
 		if !ok {
@@ -142,7 +159,10 @@ func (c *DefaultFunctionClient) GetFunctionsFromPipeline(comp *apiextensionsv1.C
 				"step", step.Step,
 				"function_name", step.FunctionRef.Name)
 
-			return nil, errors.Errorf("function %q referenced in pipeline step %q not found", step.FunctionRef.Name, step.Step)
+			missing = append(missing, errors.Errorf("function %q referenced in pipeline step %q of composition %q is neither installed in the cluster nor supplied locally",
+				step.FunctionRef.Name, step.Step, comp.GetName()))
+
+			continue
 		}
 
 		c.logger.Debug("Found function for step",
@@ -151,6 +171,10 @@ func (c *DefaultFunctionClient) GetFunctionsFromPipeline(comp *apiextensionsv1.C
 		functions = append(functions, fn)
 	}
 
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+
 	c.logger.Debug("Retrieved functions from pipeline",
 		"functions_count", len(functions),
 		"composition_name", comp.GetName())
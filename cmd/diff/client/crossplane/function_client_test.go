@@ -139,7 +139,32 @@ func TestDefaultFunctionClient_GetFunctionsFromPipeline(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Errorf("function %q referenced in pipeline step %q not found", "function-b", "step-b"),
+				err: errors.Errorf("function %q referenced in pipeline step %q of composition %q is neither installed in the cluster nor supplied locally", "function-b", "step-b", ""),
+			},
+		},
+		"MultipleMissingFunctions": {
+			reason: "Should report every missing function, with its step and composition, in one error",
+			fields: fields{
+				functions: map[string]pkgv1.Function{
+					"function-a": {ObjectMeta: metav1.ObjectMeta{Name: "function-a"}},
+				},
+			},
+			mockResource: tu.NewMockResourceClient().
+				WithSuccessfulInitialize().
+				Build(),
+			args: args{
+				comp: tu.NewComposition("test-comp").
+					WithPipelineMode().
+					WithPipelineStep("step-a", "function-a", nil).
+					WithPipelineStep("step-b", "function-go-templating", nil).
+					WithPipelineStep("step-c", "function-auto-ready", nil).
+					Build(),
+			},
+			want: want{
+				err: errors.Join(
+					errors.Errorf("function %q referenced in pipeline step %q of composition %q is neither installed in the cluster nor supplied locally", "function-go-templating", "step-b", "test-comp"),
+					errors.Errorf("function %q referenced in pipeline step %q of composition %q is neither installed in the cluster nor supplied locally", "function-auto-ready", "step-c", "test-comp"),
+				),
 			},
 		},
 		"AllFunctionsFound": {
@@ -305,6 +330,59 @@ func TestDefaultFunctionClient_GetFunctionsFromPipeline(t *testing.T) {
 	}
 }
 
+func TestDefaultFunctionClient_AddLocalFunctions(t *testing.T) {
+	fn := func(name, pkg string) pkgv1.Function {
+		return pkgv1.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: pkgv1.FunctionSpec{
+				PackageSpec: pkgv1.PackageSpec{Package: pkg},
+			},
+		}
+	}
+
+	c := &DefaultFunctionClient{
+		functions: map[string]pkgv1.Function{
+			"function-a": fn("function-a", "xpkg.example.org/function-a:v1.0.0"),
+			"function-c": fn("function-c", "xpkg.example.org/function-c:v1.0.0"),
+		},
+		logger: tu.TestLogger(t, false),
+	}
+
+	c.AddLocalFunctions([]pkgv1.Function{
+		fn("function-a", "xpkg.example.org/function-a:v2.0.0"),
+		fn("function-b", "xpkg.example.org/function-b:v1.0.0"),
+	})
+
+	comp := tu.NewComposition("test-comp").
+		WithPipelineMode().
+		WithPipelineStep("step-a", "function-a", nil).
+		WithPipelineStep("step-b", "function-b", nil).
+		WithPipelineStep("step-c", "function-c", nil).
+		Build()
+
+	got, err := c.GetFunctionsFromPipeline(comp)
+	if err != nil {
+		t.Fatalf("GetFunctionsFromPipeline(...): unexpected error: %v", err)
+	}
+
+	// The local function-a replaces the cluster's; function-b is only local and
+	// function-c only in the cluster.
+	want := []string{
+		"xpkg.example.org/function-a:v2.0.0",
+		"xpkg.example.org/function-b:v1.0.0",
+		"xpkg.example.org/function-c:v1.0.0",
+	}
+
+	pkgs := make([]string, 0, len(got))
+	for _, f := range got {
+		pkgs = append(pkgs, f.Spec.Package)
+	}
+
+	if diff := cmp.Diff(want, pkgs); diff != "" {
+		t.Errorf("GetFunctionsFromPipeline(...): -want packages, +got:\n%s", diff)
+	}
+}
+
 func TestDefaultFunctionClient_ListFunctions(t *testing.T) {
 	ctx := t.Context()
 
@@ -0,0 +1,48 @@
+package crossplane
+
+import (
+	"context"
+	"sync"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+)
+
+// resolvedRevisionKey is the context key under which a ResolvedRevision is stored.
+type resolvedRevisionKey struct{}
+
+// ResolvedRevision records the CompositionRevision a composition was last resolved from, for
+// requests made with a context from WithResolvedRevision.
+type ResolvedRevision struct {
+	mu     sync.Mutex
+	name   string
+	number int64
+}
+
+// WithResolvedRevision returns a context under which the CompositionClient records the
+// CompositionRevision it resolves a composition from, and the ResolvedRevision that holds it.
+func WithResolvedRevision(ctx context.Context) (context.Context, *ResolvedRevision) {
+	r := &ResolvedRevision{}
+	return context.WithValue(ctx, resolvedRevisionKey{}, r), r
+}
+
+// Get returns the name and number of the recorded revision. The name is empty if the composition
+// wasn't resolved from a revision.
+func (r *ResolvedRevision) Get() (name string, number int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.name, r.number
+}
+
+// recordResolvedRevision records revision on the ResolvedRevision in ctx, if any.
+func recordResolvedRevision(ctx context.Context, revision *apiextensionsv1.CompositionRevision) {
+	r, ok := ctx.Value(resolvedRevisionKey{}).(*ResolvedRevision)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.name, r.number = revision.GetName(), revision.Spec.Revision
+}
@@ -2,14 +2,21 @@ package crossplane
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource/xrm"
+	corev1 "k8s.io/api/core/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource/unstructured/claim"
+	ucomposite "github.com/crossplane/crossplane-runtime/v2/pkg/resource/unstructured/composite"
 )
 
 // ResourceTreeClient handles resource tree operations.
@@ -72,3 +79,88 @@ func (c *DefaultResourceTreeClient) GetResourceTree(ctx context.Context, root *u
 
 	return tree, nil
 }
+
+// ObservedResourceTreeClient builds resource trees by following the resource
+// references of claims and XRs through a ResourceClient, e.g. one serving
+// resources exported from a cluster, instead of querying the cluster.
+type ObservedResourceTreeClient struct {
+	resourceClient kubernetes.ResourceClient
+	logger         logging.Logger
+}
+
+// NewObservedResourceTreeClient creates a new ObservedResourceTreeClient.
+func NewObservedResourceTreeClient(resourceClient kubernetes.ResourceClient, logger logging.Logger) ResourceTreeClient {
+	return &ObservedResourceTreeClient{
+		resourceClient: resourceClient,
+		logger:         logger,
+	}
+}
+
+// Initialize initializes the resource tree client.
+func (c *ObservedResourceTreeClient) Initialize(_ context.Context) error {
+	return nil
+}
+
+// GetResourceTree gets the resource tree for a root resource. Unlike the
+// cluster-backed client, a referenced resource that can't be found is an error,
+// since diffing against an incomplete tree would report it as removed.
+func (c *ObservedResourceTreeClient) GetResourceTree(ctx context.Context, root *un.Unstructured) (*resource.Resource, error) {
+	tree := &resource.Resource{Unstructured: *root}
+	if err := c.loadChildren(ctx, tree, map[string]bool{}); err != nil {
+		return nil, errors.Wrap(err, "failed to get resource tree")
+	}
+
+	return tree, nil
+}
+
+func (c *ObservedResourceTreeClient) loadChildren(ctx context.Context, node *resource.Resource, seen map[string]bool) error {
+	for _, ref := range childRefs(&node.Unstructured) {
+		key := fmt.Sprintf("%s/%s/%s/%s", ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		child, err := c.resourceClient.GetResource(ctx, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind), ref.Namespace, ref.Name)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get %s %s referenced by %s %s", ref.Kind, ref.Name, node.Unstructured.GetKind(), node.Unstructured.GetName())
+		}
+
+		childNode := &resource.Resource{Unstructured: *child}
+		if err := c.loadChildren(ctx, childNode, seen); err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, childNode)
+	}
+
+	return nil
+}
+
+// childRefs returns the references a claim or XR holds to the resources it
+// owns, following the same fields as the cluster-backed tree client.
+func childRefs(obj *un.Unstructured) []corev1.ObjectReference {
+	cm := claim.Unstructured{Unstructured: *obj}
+	if ref := cm.GetResourceReference(); ref != nil {
+		return []corev1.ObjectReference{{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+			Namespace:  ptr.Deref(ref.Namespace, ""),
+		}}
+	}
+
+	modern := ucomposite.Unstructured{Schema: ucomposite.SchemaModern, Unstructured: *obj}
+	legacy := ucomposite.Unstructured{Schema: ucomposite.SchemaLegacy, Unstructured: *obj}
+	refs := append(modern.GetResourceReferences(), legacy.GetResourceReferences()...)
+
+	// References held by a namespaced XR leave their namespace implicit.
+	for i := range refs {
+		if refs[i].Namespace == "" {
+			refs[i].Namespace = obj.GetNamespace()
+		}
+	}
+
+	return refs
+}
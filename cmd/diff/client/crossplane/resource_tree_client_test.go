@@ -1,6 +1,89 @@
 package crossplane
 
-// This file intentionally left blank.  The ResourceTreeClient is a thin shim around the xrm.Client struct.  We
+import (
+	"testing"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
+	"github.com/google/go-cmp/cmp"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultResourceTreeClient is intentionally untested. It is a thin shim around the xrm.Client struct.  We
 // went to construct an interface around the xrm.Client in order to test this class, but it turned out to be the
-// same interface that this one provides.  No real logic in ResourceTreeClient except for converting to unstructured
-// and logging.
+// same interface that this one provides.  No real logic in DefaultResourceTreeClient except for converting to
+// unstructured and logging.
+
+func TestObservedResourceTreeClient_GetResourceTree(t *testing.T) {
+	xr := tu.NewResource("example.org/v1", "XR", "parent").
+		InNamespace("team-a").
+		WithNestedField([]any{
+			map[string]any{"apiVersion": "example.org/v1", "kind": "XChild", "name": "child"},
+		}, "spec", "crossplane", "resourceRefs").
+		Build()
+	child := tu.NewResource("example.org/v1", "XChild", "child").
+		InNamespace("team-a").
+		WithNestedField([]any{
+			map[string]any{"apiVersion": "v1", "kind": "ConfigMap", "name": "cm"},
+		}, "spec", "crossplane", "resourceRefs").
+		Build()
+	cm := tu.NewResource("v1", "ConfigMap", "cm").InNamespace("team-a").Build()
+
+	tests := map[string]struct {
+		reason    string
+		observed  []*un.Unstructured
+		wantNames []string
+		wantErr   bool
+	}{
+		"FollowsReferences": {
+			reason:    "Should follow resource references recursively, inheriting the XR's namespace.",
+			observed:  []*un.Unstructured{xr, child, cm},
+			wantNames: []string{"parent", "child", "cm"},
+		},
+		"MissingReference": {
+			reason:   "Should fail when a referenced resource wasn't observed.",
+			observed: []*un.Unstructured{xr, child},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rc, err := kubernetes.NewObservedClient("testdata", tt.observed, tu.TestLogger(t, false))
+			if err != nil {
+				t.Fatalf("NewObservedClient(...): unexpected error: %v", err)
+			}
+
+			tree, err := NewObservedResourceTreeClient(rc, tu.TestLogger(t, false)).GetResourceTree(t.Context(), xr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nGetResourceTree(...): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nGetResourceTree(...): unexpected error: %v", tt.reason, err)
+			}
+
+			var names []string
+
+			var walk func(r *resource.Resource)
+
+			walk = func(r *resource.Resource) {
+				names = append(names, r.Unstructured.GetName())
+				for _, c := range r.Children {
+					walk(c)
+				}
+			}
+
+			walk(tree)
+
+			if diff := cmp.Diff(tt.wantNames, names); diff != "" {
+				t.Errorf("\n%s\nGetResourceTree(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
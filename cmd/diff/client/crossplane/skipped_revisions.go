@@ -0,0 +1,48 @@
+package crossplane
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// skippedRevisionsKey is the context key under which SkippedRevisions are stored.
+type skippedRevisionsKey struct{}
+
+// SkippedRevisions collects a note for each XR whose pinned CompositionRevision was missing and
+// replaced by the latest revision, for requests made with a context from WithSkippedRevisions.
+type SkippedRevisions struct {
+	mu    sync.Mutex
+	notes []string
+}
+
+// WithSkippedRevisions returns a context under which
+// FindMatchingCompositionSkippingMissingRevisions records a note for each missing pinned revision it
+// replaces, and the SkippedRevisions that collects them.
+func WithSkippedRevisions(ctx context.Context) (context.Context, *SkippedRevisions) {
+	s := &SkippedRevisions{}
+	return context.WithValue(ctx, skippedRevisionsKey{}, s), s
+}
+
+// Notes returns the distinct notes recorded so far, in the order they were first seen.
+func (s *SkippedRevisions) Notes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.notes)
+}
+
+// recordSkippedRevision records note on the SkippedRevisions in ctx, if any.
+func recordSkippedRevision(ctx context.Context, note string) {
+	s, ok := ctx.Value(skippedRevisionsKey{}).(*SkippedRevisions)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.Contains(s.notes, note) {
+		s.notes = append(s.notes, note)
+	}
+}
@@ -3,9 +3,11 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
@@ -41,11 +43,50 @@ func GetComposedFieldOwner(obj *un.Unstructured) string {
 	return ""
 }
 
+// conflictManager extracts the field manager from a conflict cause message
+// such as `conflict with "kubectl" using apps/v1`.
+var conflictManager = regexp.MustCompile(`conflict with "([^"]+)"`) //nolint:gochecknoglobals // Compiled once.
+
+// conflictError turns a server-side apply conflict into an error naming each
+// conflicting field and the field manager that owns it. It returns nil when err
+// isn't a field manager conflict.
+func conflictError(err error, resourceID string) error {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsConflict(err) || status.Status().Details == nil {
+		return nil
+	}
+
+	var conflicts []string
+
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		manager := cause.Message
+		if m := conflictManager.FindStringSubmatch(cause.Message); m != nil {
+			manager = m[1]
+		}
+
+		conflicts = append(conflicts, fmt.Sprintf("%s is owned by field manager %q", cause.Field, manager))
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("dry-run apply of %s conflicts with other field managers: %s; "+
+		"pass --force-conflicts to take ownership of these fields, as a forced apply would",
+		resourceID, strings.Join(conflicts, ", "))
+}
+
 // ApplyClient handles server-side apply operations.
 type ApplyClient interface {
 	// DryRunApply performs a dry-run server-side apply.
-	// If fieldOwner is empty, uses the default field owner.
-	DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error)
+	// If fieldOwner is empty, uses the default field owner. With force, the apply
+	// takes ownership of fields other field managers own, as a forced apply would;
+	// without it, such conflicts fail the dry-run apply.
+	DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error)
 }
 
 // DefaultApplyClient implements ApplyClient.
@@ -65,8 +106,9 @@ func NewApplyClient(clients *core.Clients, converter TypeConverter, logger loggi
 }
 
 // DryRunApply performs a dry-run server-side apply.
-// If fieldOwner is empty, uses the default field owner.
-func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error) {
+// If fieldOwner is empty, uses the default field owner. With force, conflicts
+// with other field managers are forced.
+func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error) {
 	resourceID := fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
 
 	// Use default field owner if not specified
@@ -74,7 +116,7 @@ func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructur
 		fieldOwner = FieldOwnerDefault
 	}
 
-	c.logger.Debug("Performing dry-run apply", "resource", resourceID, "namespace", obj.GetNamespace(), "fieldOwner", fieldOwner)
+	c.logger.Debug("Performing dry-run apply", "resource", resourceID, "namespace", obj.GetNamespace(), "fieldOwner", fieldOwner, "force", force)
 
 	// Get the GVK from the object
 	gvk := obj.GroupVersionKind()
@@ -92,7 +134,7 @@ func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructur
 	// Create apply options for a dry run with the specified field owner
 	applyOptions := metav1.ApplyOptions{
 		FieldManager: fieldOwner,
-		Force:        true,
+		Force:        force,
 		DryRun:       []string{metav1.DryRunAll},
 	}
 
@@ -101,6 +143,10 @@ func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructur
 	if err != nil {
 		c.logger.Debug("Dry-run apply failed", "resource", resourceID, "error", err)
 
+		if cerr := conflictError(err, resourceID); cerr != nil {
+			return nil, cerr
+		}
+
 		return nil, errors.Wrapf(err, "failed to apply resource %s/%s",
 			obj.GetNamespace(), obj.GetName())
 	}
@@ -109,3 +155,52 @@ func (c *DefaultApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructur
 
 	return result, nil
 }
+
+// DryRunNamespaceApplyClient wraps another ApplyClient and supplies a fallback
+// namespace for namespaced resources that reach the dry-run without one.
+type DryRunNamespaceApplyClient struct {
+	inner     ApplyClient
+	resources ResourceClient
+	namespace string
+	logger    logging.Logger
+}
+
+// NewDryRunNamespaceApplyClient wraps inner, applying namespaced resources that
+// have no namespace in the given namespace. Cluster-scoped resources and resources
+// that already have a namespace are passed through unchanged.
+func NewDryRunNamespaceApplyClient(inner ApplyClient, resources ResourceClient, namespace string, logger logging.Logger) ApplyClient {
+	return &DryRunNamespaceApplyClient{
+		inner:     inner,
+		resources: resources,
+		namespace: namespace,
+		logger:    logger,
+	}
+}
+
+// DryRunApply sets the fallback namespace on a copy of obj when obj is namespaced
+// but has no namespace, then delegates to the wrapped client.
+func (c *DryRunNamespaceApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error) {
+	if obj.GetNamespace() != "" {
+		return c.inner.DryRunApply(ctx, obj, fieldOwner, force)
+	}
+
+	resourceID := fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+
+	namespaced, err := c.resources.IsNamespacedResource(ctx, obj.GroupVersionKind())
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot determine scope for resource %s", resourceID)
+	}
+
+	if !namespaced {
+		return c.inner.DryRunApply(ctx, obj, fieldOwner, force)
+	}
+
+	c.logger.Info("Namespaced resource has no namespace after render; using dry-run namespace",
+		"resource", resourceID,
+		"namespace", c.namespace)
+
+	withNamespace := obj.DeepCopy()
+	withNamespace.SetNamespace(c.namespace)
+
+	return c.inner.DryRunApply(ctx, withNamespace, fieldOwner, force)
+}
@@ -7,6 +7,8 @@ import (
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -193,7 +195,7 @@ func TestApplyClient_DryRunApply(t *testing.T) {
 				logger:        tu.TestLogger(t, false),
 			}
 
-			got, err := c.DryRunApply(tc.args.ctx, tc.args.obj, "")
+			got, err := c.DryRunApply(tc.args.ctx, tc.args.obj, "", false)
 
 			if tc.want.err != nil {
 				if err == nil {
@@ -233,6 +235,81 @@ func TestApplyClient_DryRunApply(t *testing.T) {
 	}
 }
 
+func TestApplyClient_DryRunApply_Conflicts(t *testing.T) {
+	conflict := apierrors.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "kubectl" using example.org/v1`,
+		Field:   ".spec.property",
+	}}, "Apply failed with 1 conflict")
+
+	tests := map[string]struct {
+		reason    string
+		force     bool
+		wantForce bool
+		wantErr   string
+	}{
+		"ConflictExplained": {
+			reason:  "Without forcing, a conflict should be reported with its field and owning manager.",
+			wantErr: `.spec.property is owned by field manager "kubectl"; pass --force-conflicts`,
+		},
+		"Forced": {
+			reason:    "Forcing should make the dry-run apply force ownership of conflicting fields.",
+			force:     true,
+			wantForce: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := tu.NewResource("example.org/v1", "ExampleResource", "test-resource").
+				InNamespace("test-namespace").
+				WithSpecField("property", "new-value").
+				Build()
+
+			var gotForce bool
+
+			dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			dynamicClient.PrependReactor("patch", "exampleresources", func(action kt.Action) (bool, runtime.Object, error) {
+				opts := action.(kt.PatchActionImpl).PatchOptions
+				gotForce = opts.Force != nil && *opts.Force
+
+				if !gotForce {
+					return true, nil, conflict
+				}
+
+				return true, obj.DeepCopy(), nil
+			})
+
+			c := &DefaultApplyClient{
+				dynamicClient: dynamicClient,
+				typeConverter: tu.NewMockTypeConverter().
+					WithGVKToGVR(func(_ context.Context, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+						return gvk.GroupVersion().WithResource("exampleresources"), nil
+					}).Build(),
+				logger: tu.TestLogger(t, false),
+			}
+
+			_, err := c.DryRunApply(t.Context(), obj, "crossplane-diff", tt.force)
+
+			if gotForce != tt.wantForce {
+				t.Errorf("\n%s\nDryRunApply(...): want force %t, got %t", tt.reason, tt.wantForce, gotForce)
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("\n%s\nDryRunApply(...): unexpected error: %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("\n%s\nDryRunApply(...): want error containing %q, got %v", tt.reason, tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestGetComposedFieldOwner(t *testing.T) {
 	tests := map[string]struct {
 		reason string
@@ -301,3 +378,78 @@ func TestGetComposedFieldOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRunNamespaceApplyClient_DryRunApply(t *testing.T) {
+	namespacedGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "ExampleResource"}
+	clusterGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "ClusterExampleResource"}
+
+	tests := map[string]struct {
+		reason    string
+		resources ResourceClient
+		obj       *un.Unstructured
+		want      string
+		wantErr   bool
+	}{
+		"NamespacedWithoutNamespace": {
+			reason:    "Should apply a namespaced resource without a namespace in the fallback namespace",
+			resources: tu.NewMockResourceClient().WithNamespacedResource(namespacedGVK).Build(),
+			obj:       tu.NewResource("example.org/v1", "ExampleResource", "test-resource").Build(),
+			want:      "fallback",
+		},
+		"NamespacedWithNamespace": {
+			reason:    "Should keep the namespace of a resource that already has one",
+			resources: tu.NewMockResourceClient().WithNamespacedResource(namespacedGVK).Build(),
+			obj:       tu.NewResource("example.org/v1", "ExampleResource", "test-resource").InNamespace("rendered").Build(),
+			want:      "rendered",
+		},
+		"ClusterScoped": {
+			reason:    "Should not add a namespace to a cluster-scoped resource",
+			resources: tu.NewMockResourceClient().WithClusterScopedResource(clusterGVK).Build(),
+			obj:       tu.NewResource("example.org/v1", "ClusterExampleResource", "test-resource").Build(),
+			want:      "",
+		},
+		"ScopeLookupFails": {
+			reason:    "Should return an error when the resource scope cannot be determined",
+			resources: tu.NewMockResourceClient().WithClusterScopedResource(clusterGVK).Build(),
+			obj:       tu.NewResource("example.org/v1", "ExampleResource", "test-resource").Build(),
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var applied *un.Unstructured
+
+			inner := tu.NewMockApplyClient().
+				WithDryRunApply(func(_ context.Context, obj *un.Unstructured, _ string, _ bool) (*un.Unstructured, error) {
+					applied = obj
+					return obj, nil
+				}).Build()
+
+			c := NewDryRunNamespaceApplyClient(inner, tc.resources, "fallback", tu.TestLogger(t, false))
+
+			before := tc.obj.GetNamespace()
+
+			_, err := c.DryRunApply(t.Context(), tc.obj, "", false)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nDryRunApply(...): expected error but got none", tc.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nDryRunApply(...): unexpected error: %v", tc.reason, err)
+			}
+
+			if got := applied.GetNamespace(); got != tc.want {
+				t.Errorf("\n%s\nDryRunApply(...): want namespace %q, got %q", tc.reason, tc.want, got)
+			}
+
+			if tc.obj.GetNamespace() != before {
+				t.Errorf("\n%s\nDryRunApply(...): input object was mutated", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// FixtureResourceClient is a ResourceClient that reads resources from a fixed
+// set loaded from files rather than from the cluster, so functions' required
+// resources can be supplied offline. Resources are matched by group and kind,
+// whatever their version. Only questions about types, such as their scope, go
+// to the cluster.
+type FixtureResourceClient struct {
+	inner    ResourceClient
+	fixtures []*un.Unstructured
+}
+
+// NewFixtureResourceClient returns a ResourceClient that reads resources from
+// fixtures and asks inner about types.
+func NewFixtureResourceClient(inner ResourceClient, fixtures []*un.Unstructured) ResourceClient {
+	return &FixtureResourceClient{inner: inner, fixtures: fixtures}
+}
+
+// GetResource implements ResourceClient. It returns a NotFound error when no
+// fixture matches, as the cluster would.
+func (c *FixtureResourceClient) GetResource(_ context.Context, gvk schema.GroupVersionKind, namespace, name string) (*un.Unstructured, error) {
+	for _, res := range c.fixtures {
+		if matchesGroupKind(res, gvk) && res.GetNamespace() == namespace && res.GetName() == name {
+			return res.DeepCopy(), nil
+		}
+	}
+
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}, name)
+}
+
+// ListResources implements ResourceClient. An empty namespace lists fixtures
+// in every namespace.
+func (c *FixtureResourceClient) ListResources(_ context.Context, gvk schema.GroupVersionKind, namespace string) ([]*un.Unstructured, error) {
+	var out []*un.Unstructured
+
+	for _, res := range c.fixtures {
+		if matchesGroupKind(res, gvk) && (namespace == "" || res.GetNamespace() == namespace) {
+			out = append(out, res.DeepCopy())
+		}
+	}
+
+	return out, nil
+}
+
+// GetResourcesByLabel implements ResourceClient. An empty namespace selects
+// fixtures in every namespace.
+func (c *FixtureResourceClient) GetResourcesByLabel(ctx context.Context, gvk schema.GroupVersionKind, namespace string, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&sel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid label selector for '%s'", gvk.String())
+	}
+
+	all, err := c.ListResources(ctx, gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(all, func(res *un.Unstructured) bool {
+		return !selector.Matches(labels.Set(res.GetLabels()))
+	}), nil
+}
+
+// GetGVKsForGroupKind implements ResourceClient.
+func (c *FixtureResourceClient) GetGVKsForGroupKind(ctx context.Context, group, kind string) ([]schema.GroupVersionKind, error) {
+	return c.inner.GetGVKsForGroupKind(ctx, group, kind)
+}
+
+// IsNamespacedResource implements ResourceClient.
+func (c *FixtureResourceClient) IsNamespacedResource(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	return c.inner.IsNamespacedResource(ctx, gvk)
+}
+
+// matchesGroupKind reports whether res has gvk's group and kind.
+func matchesGroupKind(res *un.Unstructured, gvk schema.GroupVersionKind) bool {
+	return res.GroupVersionKind().GroupKind() == gvk.GroupKind()
+}
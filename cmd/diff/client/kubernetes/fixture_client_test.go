@@ -0,0 +1,94 @@
+package kubernetes
+
+import (
+	"testing"
+
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestFixtureResourceClient_GetResource(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	fixtures := []*un.Unstructured{
+		tu.NewResource("v1", "ConfigMap", "settings").InNamespace("team-a").Build(),
+		tu.NewResource("v1", "Secret", "settings").InNamespace("team-b").Build(),
+	}
+
+	tests := map[string]struct {
+		reason       string
+		namespace    string
+		name         string
+		wantNotFound bool
+	}{
+		"Found": {
+			reason:    "A fixture of the requested kind, namespace and name should be returned.",
+			namespace: "team-a",
+			name:      "settings",
+		},
+		"OtherNamespace": {
+			reason:       "A fixture in another namespace should not match.",
+			namespace:    "team-b",
+			name:         "settings",
+			wantNotFound: true,
+		},
+		"Missing": {
+			reason:       "A name no fixture has should be NotFound, as the cluster would report it.",
+			namespace:    "team-a",
+			name:         "absent",
+			wantNotFound: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewFixtureResourceClient(tu.NewMockResourceClient().Build(), fixtures)
+
+			got, err := c.GetResource(t.Context(), gvk, tt.namespace, tt.name)
+			if tt.wantNotFound {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("\n%s\nGetResource(...): want NotFound, got %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nGetResource(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if got.GetName() != tt.name || got.GetNamespace() != tt.namespace {
+				t.Errorf("\n%s\nGetResource(...): want %s/%s, got %s/%s", tt.reason, tt.namespace, tt.name, got.GetNamespace(), got.GetName())
+			}
+		})
+	}
+}
+
+func TestFixtureResourceClient_GetResourcesByLabel(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}
+	fixtures := []*un.Unstructured{
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "viewer").WithLabels(map[string]string{"team": "a"}).Build(),
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "editor").WithLabels(map[string]string{"team": "b"}).Build(),
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "admin").WithLabels(map[string]string{"team": "a"}).Build(),
+		tu.NewResource("v1", "ConfigMap", "cm").WithLabels(map[string]string{"team": "a"}).Build(),
+	}
+
+	c := NewFixtureResourceClient(tu.NewMockResourceClient().Build(), fixtures)
+
+	got, err := c.GetResourcesByLabel(t.Context(), gvk, "", metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}})
+	if err != nil {
+		t.Fatalf("GetResourcesByLabel(...): unexpected error: %v", err)
+	}
+
+	names := make([]string, 0, len(got))
+	for _, res := range got {
+		names = append(names, res.GetName())
+	}
+
+	if diff := cmp.Diff([]string{"viewer", "admin"}, names); diff != "" {
+		t.Errorf("GetResourcesByLabel(...): want only matching fixtures of the kind, in file order, -want, +got:\n%s", diff)
+	}
+}
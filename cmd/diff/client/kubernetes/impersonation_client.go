@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// ImpersonatedIdentity describes the identity in cfg, for error messages. It
+// returns an empty string when cfg doesn't impersonate anyone.
+func ImpersonatedIdentity(cfg rest.ImpersonationConfig) string {
+	if cfg.UserName == "" {
+		return ""
+	}
+
+	id := cfg.UserName
+	if len(cfg.Groups) > 0 {
+		id += " (groups " + strings.Join(cfg.Groups, ", ") + ")"
+	}
+
+	return id
+}
+
+// explainForbidden wraps a Forbidden error so it reads as a permission
+// boundary of the impersonated identity rather than a failure of the tool.
+// Other errors are returned unchanged.
+func explainForbidden(err error, identity string) error {
+	if err == nil || !apierrors.IsForbidden(err) {
+		return err
+	}
+
+	return errors.Wrapf(err, "forbidden under impersonated identity %s; this is what the identity may access, not a crossplane-diff error", identity)
+}
+
+// ImpersonatedResourceClient is a ResourceClient whose Forbidden errors
+// explain that the request was made under an impersonated identity.
+type ImpersonatedResourceClient struct {
+	inner    ResourceClient
+	identity string
+}
+
+// NewImpersonatedResourceClient wraps inner so its Forbidden errors name
+// identity. It returns inner unchanged when identity is empty.
+func NewImpersonatedResourceClient(inner ResourceClient, identity string) ResourceClient {
+	if identity == "" {
+		return inner
+	}
+
+	return &ImpersonatedResourceClient{inner: inner, identity: identity}
+}
+
+// GetResource implements ResourceClient.
+func (c *ImpersonatedResourceClient) GetResource(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (*un.Unstructured, error) {
+	res, err := c.inner.GetResource(ctx, gvk, namespace, name)
+	return res, explainForbidden(err, c.identity)
+}
+
+// ListResources implements ResourceClient.
+func (c *ImpersonatedResourceClient) ListResources(ctx context.Context, gvk schema.GroupVersionKind, namespace string) ([]*un.Unstructured, error) {
+	res, err := c.inner.ListResources(ctx, gvk, namespace)
+	return res, explainForbidden(err, c.identity)
+}
+
+// GetResourcesByLabel implements ResourceClient.
+func (c *ImpersonatedResourceClient) GetResourcesByLabel(ctx context.Context, gvk schema.GroupVersionKind, namespace string, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+	res, err := c.inner.GetResourcesByLabel(ctx, gvk, namespace, sel)
+	return res, explainForbidden(err, c.identity)
+}
+
+// GetGVKsForGroupKind implements ResourceClient.
+func (c *ImpersonatedResourceClient) GetGVKsForGroupKind(ctx context.Context, group, kind string) ([]schema.GroupVersionKind, error) {
+	gvks, err := c.inner.GetGVKsForGroupKind(ctx, group, kind)
+	return gvks, explainForbidden(err, c.identity)
+}
+
+// IsNamespacedResource implements ResourceClient.
+func (c *ImpersonatedResourceClient) IsNamespacedResource(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	namespaced, err := c.inner.IsNamespacedResource(ctx, gvk)
+	return namespaced, explainForbidden(err, c.identity)
+}
+
+// ImpersonatedSchemaClient is a SchemaClient whose cluster reads explain
+// Forbidden errors as in ImpersonatedResourceClient.
+type ImpersonatedSchemaClient struct {
+	SchemaClient
+
+	identity string
+}
+
+// NewImpersonatedSchemaClient wraps inner so its Forbidden errors name
+// identity. It returns inner unchanged when identity is empty.
+func NewImpersonatedSchemaClient(inner SchemaClient, identity string) SchemaClient {
+	if identity == "" {
+		return inner
+	}
+
+	return &ImpersonatedSchemaClient{SchemaClient: inner, identity: identity}
+}
+
+// GetCRD implements SchemaClient.
+func (c *ImpersonatedSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error) {
+	crd, err := c.SchemaClient.GetCRD(ctx, gvk)
+	return crd, explainForbidden(err, c.identity)
+}
+
+// LoadCRDsFromXRDs implements SchemaClient.
+func (c *ImpersonatedSchemaClient) LoadCRDsFromXRDs(ctx context.Context, xrds []*un.Unstructured) error {
+	return explainForbidden(c.SchemaClient.LoadCRDsFromXRDs(ctx, xrds), c.identity)
+}
+
+// ImpersonatedApplyClient is an ApplyClient whose dry-run applies explain
+// Forbidden errors as in ImpersonatedResourceClient.
+type ImpersonatedApplyClient struct {
+	inner    ApplyClient
+	identity string
+}
+
+// NewImpersonatedApplyClient wraps inner so its Forbidden errors name
+// identity. It returns inner unchanged when identity is empty.
+func NewImpersonatedApplyClient(inner ApplyClient, identity string) ApplyClient {
+	if identity == "" {
+		return inner
+	}
+
+	return &ImpersonatedApplyClient{inner: inner, identity: identity}
+}
+
+// DryRunApply implements ApplyClient.
+func (c *ImpersonatedApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error) {
+	res, err := c.inner.DryRunApply(ctx, obj, fieldOwner, force)
+	return res, explainForbidden(err, c.identity)
+}
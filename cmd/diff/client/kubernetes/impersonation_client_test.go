@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+func TestImpersonatedIdentity(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		cfg    rest.ImpersonationConfig
+		want   string
+	}{
+		"None": {
+			reason: "No identity should be reported when no user is impersonated.",
+		},
+		"User": {
+			reason: "A user on its own should be reported as is.",
+			cfg:    rest.ImpersonationConfig{UserName: "system:serviceaccount:team-a:ci"},
+			want:   "system:serviceaccount:team-a:ci",
+		},
+		"UserAndGroups": {
+			reason: "Impersonated groups should be listed after the user.",
+			cfg:    rest.ImpersonationConfig{UserName: "jane", Groups: []string{"dev", "ops"}},
+			want:   "jane (groups dev, ops)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ImpersonatedIdentity(tt.cfg); got != tt.want {
+				t.Errorf("\n%s\nImpersonatedIdentity(...): want %q, got %q", tt.reason, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestImpersonatedResourceClient_GetResource(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+	gr := schema.GroupResource{Group: testExampleOrgGroup, Resource: testXResourcePlural}
+
+	tests := map[string]struct {
+		reason        string
+		err           error
+		wantForbidden bool
+		wantExplained bool
+	}{
+		"Forbidden": {
+			reason:        "A Forbidden error should name the impersonated identity and stay recognisable as Forbidden.",
+			err:           apierrors.NewForbidden(gr, "a", errors.New("denied")),
+			wantForbidden: true,
+			wantExplained: true,
+		},
+		"NotFound": {
+			reason: "Other errors should be returned unchanged.",
+			err:    apierrors.NewNotFound(gr, "a"),
+		},
+		"Success": {
+			reason: "A successful call should pass straight through.",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			inner := tu.NewMockResourceClient().
+				WithGetResource(func(context.Context, schema.GroupVersionKind, string, string) (*un.Unstructured, error) {
+					return nil, tt.err
+				}).
+				Build()
+
+			c := NewImpersonatedResourceClient(inner, "system:serviceaccount:team-a:ci")
+
+			_, err := c.GetResource(t.Context(), gvk, "", "a")
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("\n%s\nGetResource(...): unexpected error: %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if apierrors.IsForbidden(err) != tt.wantForbidden {
+				t.Errorf("\n%s\nGetResource(...): want IsForbidden %t, got %v", tt.reason, tt.wantForbidden, err)
+			}
+
+			if got := strings.Contains(err.Error(), "forbidden under impersonated identity system:serviceaccount:team-a:ci"); got != tt.wantExplained {
+				t.Errorf("\n%s\nGetResource(...): want explained %t, got %v", tt.reason, tt.wantExplained, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package kubernetes
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"dario.cat/mergo"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// ObservedClient serves resources exported from a cluster in place of the API
+// server, so diffs can be computed without cluster access. It implements
+// ResourceClient, TypeConverter and ApplyClient. Resource names and scopes come
+// from the CRDs among the resources, falling back to the conventional plural
+// and the namespaces of the resources themselves.
+type ObservedClient struct {
+	source    string
+	resources []*un.Unstructured
+	crds      map[schema.GroupKind]*extv1.CustomResourceDefinition
+	logger    logging.Logger
+}
+
+// NewObservedClient creates an ObservedClient serving resources, which were
+// read from source. The source is only used in error messages.
+func NewObservedClient(source string, resources []*un.Unstructured, logger logging.Logger) (*ObservedClient, error) {
+	crds := make(map[schema.GroupKind]*extv1.CustomResourceDefinition)
+
+	for _, res := range resources {
+		if res.GroupVersionKind() != crdGVK {
+			continue
+		}
+
+		crd := &extv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(res.Object, crd); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert CRD %s to typed", res.GetName())
+		}
+
+		crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+	}
+
+	logger.Debug("Loaded observed resources", "source", source, "count", len(resources), "crds", len(crds))
+
+	return &ObservedClient{
+		source:    source,
+		resources: resources,
+		crds:      crds,
+		logger:    logger,
+	}, nil
+}
+
+// crdGVK is the GVK of a CustomResourceDefinition.
+var crdGVK = extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") //nolint:gochecknoglobals // Constant GVK.
+
+// CRDs returns the CRDs among the observed resources.
+func (c *ObservedClient) CRDs() []*extv1.CustomResourceDefinition {
+	crds := make([]*extv1.CustomResourceDefinition, 0, len(c.crds))
+	for _, crd := range c.crds {
+		crds = append(crds, crd)
+	}
+
+	slices.SortFunc(crds, func(a, b *extv1.CustomResourceDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return crds
+}
+
+// GetResource returns the observed resource with the given GVK, namespace and
+// name. A resource that wasn't exported yields a NotFound error.
+func (c *ObservedClient) GetResource(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (*un.Unstructured, error) {
+	if res := c.find(gvk, namespace, name); res != nil {
+		return res.DeepCopy(), nil
+	}
+
+	gvr, _ := c.GVKToGVR(ctx, gvk)
+
+	return nil, errors.Wrapf(apierrors.NewNotFound(gvr.GroupResource(), name),
+		"cannot get resource %s/%s of kind %s from %s", namespace, name, gvk.Kind, c.source)
+}
+
+// ListResources returns the observed resources with the given GVK in the given
+// namespace, or in all namespaces when namespace is empty.
+func (c *ObservedClient) ListResources(_ context.Context, gvk schema.GroupVersionKind, namespace string) ([]*un.Unstructured, error) {
+	var out []*un.Unstructured
+
+	for _, res := range c.resources {
+		if res.GroupVersionKind() == gvk && (namespace == "" || res.GetNamespace() == namespace) {
+			out = append(out, res.DeepCopy())
+		}
+	}
+
+	return out, nil
+}
+
+// GetResourcesByLabel returns the observed resources with the given GVK in the
+// given namespace whose labels match sel.
+func (c *ObservedClient) GetResourcesByLabel(ctx context.Context, gvk schema.GroupVersionKind, namespace string, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&sel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid label selector for '%s'", gvk.String())
+	}
+
+	all, err := c.ListResources(ctx, gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(all, func(res *un.Unstructured) bool {
+		return !selector.Matches(labels.Set(res.GetLabels()))
+	}), nil
+}
+
+// GetGVKsForGroupKind returns the versions of group and kind served by an
+// observed CRD or used by an observed resource. It returns none, rather than an
+// error, when nothing of that kind was exported.
+func (c *ObservedClient) GetGVKsForGroupKind(_ context.Context, group, kind string) ([]schema.GroupVersionKind, error) {
+	var gvks []schema.GroupVersionKind
+
+	add := func(gvk schema.GroupVersionKind) {
+		if !slices.Contains(gvks, gvk) {
+			gvks = append(gvks, gvk)
+		}
+	}
+
+	if crd, ok := c.crds[schema.GroupKind{Group: group, Kind: kind}]; ok {
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				add(schema.GroupVersionKind{Group: group, Version: v.Name, Kind: kind})
+			}
+		}
+	}
+
+	for _, res := range c.resources {
+		if gvk := res.GroupVersionKind(); gvk.Group == group && gvk.Kind == kind {
+			add(gvk)
+		}
+	}
+
+	return gvks, nil
+}
+
+// IsNamespacedResource reports whether gvk is namespaced, per its observed CRD
+// or else the observed resources of that kind.
+func (c *ObservedClient) IsNamespacedResource(_ context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	if crd, ok := c.crds[gvk.GroupKind()]; ok {
+		return crd.Spec.Scope == extv1.NamespaceScoped, nil
+	}
+
+	found := false
+
+	for _, res := range c.resources {
+		if res.GroupVersionKind() != gvk {
+			continue
+		}
+
+		if res.GetNamespace() != "" {
+			return true, nil
+		}
+
+		found = true
+	}
+
+	if !found {
+		return false, errors.Errorf("cannot determine the scope of %s: no CRD or resource of that kind in %s", gvk.String(), c.source)
+	}
+
+	return false, nil
+}
+
+// GVKToGVR converts a GroupVersionKind to a GroupVersionResource.
+func (c *ObservedClient) GVKToGVR(ctx context.Context, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	name, err := c.GetResourceNameForGVK(ctx, gvk)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	return gvk.GroupVersion().WithResource(name), nil
+}
+
+// GetResourceNameForGVK returns the plural name from the kind's observed CRD,
+// or else the conventional lower-case plural of the kind.
+func (c *ObservedClient) GetResourceNameForGVK(_ context.Context, gvk schema.GroupVersionKind) (string, error) {
+	if crd, ok := c.crds[gvk.GroupKind()]; ok {
+		return crd.Spec.Names.Plural, nil
+	}
+
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	return plural.Resource, nil
+}
+
+// DryRunApply approximates a server-side apply by overlaying obj on the
+// observed resource of the same name, as the diff processor does for kinds
+// excluded from dry-run. Fields the API server would default or remove aren't
+// reflected. An object that wasn't observed is returned unchanged.
+func (c *ObservedClient) DryRunApply(_ context.Context, obj *un.Unstructured, _ string, _ bool) (*un.Unstructured, error) {
+	current := c.find(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	if current == nil {
+		return obj.DeepCopy(), nil
+	}
+
+	result := current.DeepCopy()
+	if err := mergo.Merge(&result.Object, obj.Object, mergo.WithOverride); err != nil {
+		return nil, errors.Wrapf(err, "cannot merge %s/%s onto the observed resource", obj.GetKind(), obj.GetName())
+	}
+
+	return result, nil
+}
+
+func (c *ObservedClient) find(gvk schema.GroupVersionKind, namespace, name string) *un.Unstructured {
+	for _, res := range c.resources {
+		if res.GroupVersionKind() == gvk && res.GetNamespace() == namespace && res.GetName() == name {
+			return res
+		}
+	}
+
+	return nil
+}
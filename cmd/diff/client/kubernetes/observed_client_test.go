@@ -0,0 +1,277 @@
+package kubernetes
+
+import (
+	"testing"
+
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTestObservedClient(t *testing.T) *ObservedClient {
+	t.Helper()
+
+	crd, err := runtime.DefaultUnstructuredConverter.ToUnstructured(
+		tu.NewCRD(testXResourcePlural+"."+testExampleOrgGroup, testExampleOrgGroup, testXResourceKind).
+			WithPlural(testXResourcePlural).
+			WithSingular("xresource").
+			WithNamespaceScope().
+			WithDefaultVersion().
+			Build())
+	if err != nil {
+		t.Fatalf("cannot convert CRD: %v", err)
+	}
+
+	resources := []*un.Unstructured{
+		{Object: crd},
+		tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "a").
+			InNamespace("team-a").
+			WithLabels(map[string]string{"env": "prod"}).
+			WithSpecField("size", "small").
+			Build(),
+		tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "b").
+			InNamespace("team-b").
+			Build(),
+		tu.NewResource("v1", "ConfigMap", "cm").InNamespace("team-a").Build(),
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "role").Build(),
+	}
+
+	c, err := NewObservedClient("testdata", resources, tu.TestLogger(t, false))
+	if err != nil {
+		t.Fatalf("NewObservedClient(...): unexpected error: %v", err)
+	}
+
+	return c
+}
+
+func TestObservedClient_GetResource(t *testing.T) {
+	xrGVK := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+
+	tests := map[string]struct {
+		reason       string
+		gvk          schema.GroupVersionKind
+		namespace    string
+		name         string
+		wantNotFound bool
+	}{
+		"Found": {
+			reason:    "Should return an observed resource matching GVK, namespace and name.",
+			gvk:       xrGVK,
+			namespace: "team-a",
+			name:      "a",
+		},
+		"WrongNamespace": {
+			reason:       "Should report NotFound for a resource observed only in another namespace.",
+			gvk:          xrGVK,
+			namespace:    "team-b",
+			name:         "a",
+			wantNotFound: true,
+		},
+		"Missing": {
+			reason:       "Should report NotFound for a resource that wasn't exported.",
+			gvk:          schema.GroupVersionKind{Version: "v1", Kind: "Secret"},
+			namespace:    "team-a",
+			name:         "missing",
+			wantNotFound: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestObservedClient(t)
+
+			got, err := c.GetResource(t.Context(), tt.gvk, tt.namespace, tt.name)
+			if tt.wantNotFound {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("\n%s\nGetResource(...): want NotFound error, got %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nGetResource(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if got.GetName() != tt.name || got.GetNamespace() != tt.namespace {
+				t.Errorf("\n%s\nGetResource(...): got %s/%s", tt.reason, got.GetNamespace(), got.GetName())
+			}
+		})
+	}
+}
+
+func TestObservedClient_ListResources(t *testing.T) {
+	xrGVK := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+
+	tests := map[string]struct {
+		reason    string
+		namespace string
+		selector  metav1.LabelSelector
+		want      []string
+	}{
+		"AllNamespaces": {
+			reason: "An empty namespace should list resources in every namespace.",
+			want:   []string{"a", "b"},
+		},
+		"OneNamespace": {
+			reason:    "A namespace should limit the list to resources in it.",
+			namespace: "team-b",
+			want:      []string{"b"},
+		},
+		"ByLabel": {
+			reason:   "A label selector should limit the list to matching resources.",
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			want:     []string{"a"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestObservedClient(t)
+
+			got, err := c.GetResourcesByLabel(t.Context(), xrGVK, tt.namespace, tt.selector)
+			if err != nil {
+				t.Fatalf("\n%s\nGetResourcesByLabel(...): unexpected error: %v", tt.reason, err)
+			}
+
+			names := make([]string, 0, len(got))
+			for _, res := range got {
+				names = append(names, res.GetName())
+			}
+
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("\n%s\nGetResourcesByLabel(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObservedClient_Discovery(t *testing.T) {
+	tests := map[string]struct {
+		reason         string
+		gvk            schema.GroupVersionKind
+		wantResource   string
+		wantNamespaced bool
+		wantScopeErr   bool
+	}{
+		"FromCRD": {
+			reason:         "A kind with an observed CRD should take its plural and scope from the CRD.",
+			gvk:            schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind},
+			wantResource:   testXResourcePlural,
+			wantNamespaced: true,
+		},
+		"NamespacedFromResources": {
+			reason:         "A kind without a CRD should be namespaced when its observed resources are.",
+			gvk:            schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			wantResource:   "configmaps",
+			wantNamespaced: true,
+		},
+		"ClusterFromResources": {
+			reason:       "A kind without a CRD should be cluster scoped when its observed resources are.",
+			gvk:          schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+			wantResource: "clusterroles",
+		},
+		"Unknown": {
+			reason:       "The scope of a kind with no CRD or resources can't be determined.",
+			gvk:          schema.GroupVersionKind{Group: "other.org", Version: "v1", Kind: "Widget"},
+			wantResource: "widgets",
+			wantScopeErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestObservedClient(t)
+
+			gvr, err := c.GVKToGVR(t.Context(), tt.gvk)
+			if err != nil {
+				t.Fatalf("\n%s\nGVKToGVR(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if gvr.Resource != tt.wantResource {
+				t.Errorf("\n%s\nGVKToGVR(...): want resource %q, got %q", tt.reason, tt.wantResource, gvr.Resource)
+			}
+
+			namespaced, err := c.IsNamespacedResource(t.Context(), tt.gvk)
+			if tt.wantScopeErr {
+				if err == nil {
+					t.Errorf("\n%s\nIsNamespacedResource(...): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nIsNamespacedResource(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if namespaced != tt.wantNamespaced {
+				t.Errorf("\n%s\nIsNamespacedResource(...): want %t, got %t", tt.reason, tt.wantNamespaced, namespaced)
+			}
+		})
+	}
+}
+
+func TestObservedClient_GetGVKsForGroupKind(t *testing.T) {
+	c := newTestObservedClient(t)
+
+	got, err := c.GetGVKsForGroupKind(t.Context(), testExampleOrgGroup, testXResourceKind)
+	if err != nil {
+		t.Fatalf("GetGVKsForGroupKind(...): unexpected error: %v", err)
+	}
+
+	want := []schema.GroupVersionKind{{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetGVKsForGroupKind(...): -want, +got:\n%s", diff)
+	}
+
+	none, err := c.GetGVKsForGroupKind(t.Context(), "apiextensions.crossplane.io", "Composition")
+	if err != nil || len(none) != 0 {
+		t.Errorf("GetGVKsForGroupKind(...): want no GVKs and no error for an unexported kind, got %v, %v", none, err)
+	}
+}
+
+func TestObservedClient_DryRunApply(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		obj    *un.Unstructured
+		want   *un.Unstructured
+	}{
+		"Observed": {
+			reason: "Desired fields should be overlaid on the observed resource.",
+			obj: tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "a").
+				InNamespace("team-a").
+				WithSpecField("size", "large").
+				Build(),
+			want: tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "a").
+				InNamespace("team-a").
+				WithLabels(map[string]string{"env": "prod"}).
+				WithSpecField("size", "large").
+				Build(),
+		},
+		"New": {
+			reason: "A resource that wasn't observed should be returned unchanged.",
+			obj:    tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "c").InNamespace("team-a").Build(),
+			want:   tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "c").InNamespace("team-a").Build(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestObservedClient(t)
+
+			got, err := c.DryRunApply(t.Context(), tt.obj, "", false)
+			if err != nil {
+				t.Fatalf("\n%s\nDryRunApply(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nDryRunApply(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
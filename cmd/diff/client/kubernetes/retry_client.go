@@ -0,0 +1,165 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// RetryPolicy configures how read calls against the API server are retried
+// when they fail with a transient error.
+type RetryPolicy struct {
+	// Retries is how many times a failed call is retried. Zero disables retries.
+	Retries int
+
+	// Backoff is the wait before the first retry. It doubles for each retry after that.
+	Backoff time.Duration
+}
+
+// IsRetryable reports whether err is a transient API error worth retrying, such
+// as throttling, a timeout, an unavailable server or an etcd leader change.
+// NotFound and other errors that would recur on retry are not retryable.
+func IsRetryable(err error) bool {
+	switch {
+	case err == nil, apierrors.IsNotFound(err), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case apierrors.IsTooManyRequests(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsInternalError(err),
+		utilnet.IsConnectionReset(err),
+		utilnet.IsProbableEOF(err):
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry calls fn until it succeeds, fails with an error that isn't
+// retryable, or policy.Retries retries have been made. It waits with
+// exponential backoff between calls and gives up early, returning the last
+// error, when the wait would outlast ctx.
+func withRetry[T any](ctx context.Context, policy RetryPolicy, logger logging.Logger, op string, fn func() (T, error)) (T, error) {
+	backoff := policy.Backoff
+
+	for attempt := 0; ; attempt++ {
+		v, err := fn()
+		if err == nil || attempt >= policy.Retries || !IsRetryable(err) {
+			return v, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return v, err
+		}
+
+		logger.Debug("Retrying after transient API error", "operation", op, "attempt", attempt+1, "backoff", backoff, "error", err)
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, err
+		case <-t.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+// RetryingResourceClient is a ResourceClient that retries transient errors
+// from another ResourceClient.
+type RetryingResourceClient struct {
+	inner  ResourceClient
+	policy RetryPolicy
+	logger logging.Logger
+}
+
+// NewRetryingResourceClient wraps inner so its calls are retried per policy.
+// It returns inner unchanged when policy allows no retries.
+func NewRetryingResourceClient(inner ResourceClient, policy RetryPolicy, logger logging.Logger) ResourceClient {
+	if policy.Retries <= 0 {
+		return inner
+	}
+
+	return &RetryingResourceClient{inner: inner, policy: policy, logger: logger}
+}
+
+// GetResource implements ResourceClient.
+func (c *RetryingResourceClient) GetResource(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) (*un.Unstructured, error) {
+	return withRetry(ctx, c.policy, c.logger, "GetResource", func() (*un.Unstructured, error) {
+		return c.inner.GetResource(ctx, gvk, namespace, name)
+	})
+}
+
+// ListResources implements ResourceClient.
+func (c *RetryingResourceClient) ListResources(ctx context.Context, gvk schema.GroupVersionKind, namespace string) ([]*un.Unstructured, error) {
+	return withRetry(ctx, c.policy, c.logger, "ListResources", func() ([]*un.Unstructured, error) {
+		return c.inner.ListResources(ctx, gvk, namespace)
+	})
+}
+
+// GetResourcesByLabel implements ResourceClient.
+func (c *RetryingResourceClient) GetResourcesByLabel(ctx context.Context, gvk schema.GroupVersionKind, namespace string, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+	return withRetry(ctx, c.policy, c.logger, "GetResourcesByLabel", func() ([]*un.Unstructured, error) {
+		return c.inner.GetResourcesByLabel(ctx, gvk, namespace, sel)
+	})
+}
+
+// GetGVKsForGroupKind implements ResourceClient.
+func (c *RetryingResourceClient) GetGVKsForGroupKind(ctx context.Context, group, kind string) ([]schema.GroupVersionKind, error) {
+	return withRetry(ctx, c.policy, c.logger, "GetGVKsForGroupKind", func() ([]schema.GroupVersionKind, error) {
+		return c.inner.GetGVKsForGroupKind(ctx, group, kind)
+	})
+}
+
+// IsNamespacedResource implements ResourceClient.
+func (c *RetryingResourceClient) IsNamespacedResource(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	return withRetry(ctx, c.policy, c.logger, "IsNamespacedResource", func() (bool, error) {
+		return c.inner.IsNamespacedResource(ctx, gvk)
+	})
+}
+
+// RetryingSchemaClient is a SchemaClient whose calls that read from the cluster
+// retry transient errors. Calls served from its cache are passed straight through.
+type RetryingSchemaClient struct {
+	SchemaClient
+
+	policy RetryPolicy
+	logger logging.Logger
+}
+
+// NewRetryingSchemaClient wraps inner so its cluster reads are retried per
+// policy. It returns inner unchanged when policy allows no retries.
+func NewRetryingSchemaClient(inner SchemaClient, policy RetryPolicy, logger logging.Logger) SchemaClient {
+	if policy.Retries <= 0 {
+		return inner
+	}
+
+	return &RetryingSchemaClient{SchemaClient: inner, policy: policy, logger: logger}
+}
+
+// GetCRD implements SchemaClient.
+func (c *RetryingSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error) {
+	return withRetry(ctx, c.policy, c.logger, "GetCRD", func() (*extv1.CustomResourceDefinition, error) {
+		return c.SchemaClient.GetCRD(ctx, gvk)
+	})
+}
+
+// LoadCRDsFromXRDs implements SchemaClient.
+func (c *RetryingSchemaClient) LoadCRDsFromXRDs(ctx context.Context, xrds []*un.Unstructured) error {
+	_, err := withRetry(ctx, c.policy, c.logger, "LoadCRDsFromXRDs", func() (struct{}, error) {
+		return struct{}{}, c.SchemaClient.LoadCRDsFromXRDs(ctx, xrds)
+	})
+
+	return err
+}
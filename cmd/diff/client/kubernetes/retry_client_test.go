@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+func TestIsRetryable(t *testing.T) {
+	gr := schema.GroupResource{Group: testExampleOrgGroup, Resource: testXResourcePlural}
+
+	tests := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"Nil": {
+			reason: "A nil error should not be retried.",
+		},
+		"TooManyRequests": {
+			reason: "Client-side or server-side throttling should be retried.",
+			err:    apierrors.NewTooManyRequests("slow down", 1),
+			want:   true,
+		},
+		"ServerTimeout": {
+			reason: "A server timeout should be retried.",
+			err:    apierrors.NewServerTimeout(gr, "get", 1),
+			want:   true,
+		},
+		"ServiceUnavailable": {
+			reason: "An unavailable API server should be retried.",
+			err:    apierrors.NewServiceUnavailable("unavailable"),
+			want:   true,
+		},
+		"LeaderChanged": {
+			reason: "An etcd leader change surfaces as an internal error and should be retried.",
+			err:    apierrors.NewInternalError(errors.New("etcdserver: leader changed")),
+			want:   true,
+		},
+		"Wrapped": {
+			reason: "A retryable error should be recognised through wrapping.",
+			err:    errors.Wrap(apierrors.NewTooManyRequests("slow down", 1), "cannot get resource"),
+			want:   true,
+		},
+		"NotFound": {
+			reason: "NotFound should never be retried.",
+			err:    apierrors.NewNotFound(gr, "missing"),
+		},
+		"Forbidden": {
+			reason: "An error that would recur on retry should not be retried.",
+			err:    apierrors.NewForbidden(gr, "x", errors.New("denied")),
+		},
+		"DeadlineExceeded": {
+			reason: "An expired context should not be retried.",
+			err:    context.DeadlineExceeded,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("\n%s\nIsRetryable(...): want %t, got %t", tt.reason, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRetryingResourceClient_GetResource(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+	gr := schema.GroupResource{Group: testExampleOrgGroup, Resource: testXResourcePlural}
+	throttled := apierrors.NewTooManyRequests("slow down", 1)
+	res := tu.NewResource(testExampleOrgGroup+"/v1", testXResourceKind, "a").Build()
+
+	tests := map[string]struct {
+		reason    string
+		policy    RetryPolicy
+		errs      []error
+		timeout   time.Duration
+		wantCalls int
+		wantErr   bool
+	}{
+		"SucceedsAfterRetries": {
+			reason:    "Transient errors should be retried until the call succeeds.",
+			policy:    RetryPolicy{Retries: 3, Backoff: time.Millisecond},
+			errs:      []error{throttled, throttled},
+			wantCalls: 3,
+		},
+		"NotFoundNotRetried": {
+			reason:    "NotFound should be returned without retrying.",
+			policy:    RetryPolicy{Retries: 3, Backoff: time.Millisecond},
+			errs:      []error{apierrors.NewNotFound(gr, "a")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		"ExhaustsRetries": {
+			reason:    "The last error should be returned once the retries are used up.",
+			policy:    RetryPolicy{Retries: 2, Backoff: time.Millisecond},
+			errs:      []error{throttled, throttled, throttled, throttled},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		"HonorsDeadline": {
+			reason:    "No retry should be made when the backoff would outlast the context's deadline.",
+			policy:    RetryPolicy{Retries: 3, Backoff: time.Hour},
+			errs:      []error{throttled},
+			timeout:   time.Minute,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			calls := 0
+			inner := tu.NewMockResourceClient().
+				WithGetResource(func(context.Context, schema.GroupVersionKind, string, string) (*un.Unstructured, error) {
+					calls++
+					if calls <= len(tt.errs) {
+						return nil, tt.errs[calls-1]
+					}
+
+					return res, nil
+				}).
+				Build()
+
+			c := NewRetryingResourceClient(inner, tt.policy, tu.TestLogger(t, false))
+
+			_, err := c.GetResource(ctx, gvk, "", "a")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("\n%s\nGetResource(...): want error %t, got %v", tt.reason, tt.wantErr, err)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("\n%s\nGetResource(...): want %d calls, got %d", tt.reason, tt.wantCalls, calls)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	xpcrd "github.com/crossplane/cli/v2/cmd/crossplane/common/crd"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -45,6 +46,14 @@ type SchemaClient interface {
 	// LoadCRDsFromXRDs converts XRDs to CRDs and caches them
 	LoadCRDsFromXRDs(ctx context.Context, xrds []*un.Unstructured) error
 
+	// LoadCRDsFromLocalXRDs derives CRDs from XRDs that aren't installed in
+	// the cluster and caches them in preference to cluster CRDs
+	LoadCRDsFromLocalXRDs(xrds []*un.Unstructured) error
+
+	// AddLocalCRDs caches CRDs supplied from files in preference to cluster
+	// CRDs of the same name
+	AddLocalCRDs(crds []*extv1.CustomResourceDefinition)
+
 	// GetAllCRDs returns all cached CRDs (needed for external validation library)
 	GetAllCRDs() []*extv1.CustomResourceDefinition
 }
@@ -53,6 +62,7 @@ type SchemaClient interface {
 type DefaultSchemaClient struct {
 	dynamicClient dynamic.Interface
 	typeConverter TypeConverter
+	cache         *core.DiskCache
 	logger        logging.Logger
 
 	// Resource type caching
@@ -64,23 +74,53 @@ type DefaultSchemaClient struct {
 	crdsMu       sync.RWMutex
 	crdByName    map[string]*extv1.CustomResourceDefinition // for fast lookup by name
 	xrdToCRDName map[string]string                          // maps XRD name to CRD name
+
+	// CRDs derived from local XRDs, keyed by the types they serve. Discovery
+	// doesn't know these types, so GetCRD looks them up here first.
+	localCRDs map[schema.GroupVersionKind]*extv1.CustomResourceDefinition
 }
 
-// NewSchemaClient creates a new DefaultSchemaClient.
-func NewSchemaClient(clients *core.Clients, typeConverter TypeConverter, logger logging.Logger) SchemaClient {
+// NewSchemaClient creates a new DefaultSchemaClient. CRDs fetched from the
+// cluster are persisted to cache, which may be nil.
+func NewSchemaClient(clients *core.Clients, typeConverter TypeConverter, cache *core.DiskCache, logger logging.Logger) SchemaClient {
 	return &DefaultSchemaClient{
 		dynamicClient:   clients.Dynamic,
 		typeConverter:   typeConverter,
+		cache:           cache,
 		logger:          logger,
 		resourceTypeMap: make(map[schema.GroupVersionKind]bool),
 		crds:            []*extv1.CustomResourceDefinition{},
 		crdByName:       make(map[string]*extv1.CustomResourceDefinition),
 		xrdToCRDName:    make(map[string]string),
+		localCRDs:       make(map[schema.GroupVersionKind]*extv1.CustomResourceDefinition),
 	}
 }
 
+// NewObservedSchemaClient creates a DefaultSchemaClient that serves only the
+// given CRDs, e.g. those exported alongside observed resources, and never
+// contacts a cluster.
+func NewObservedSchemaClient(crds []*extv1.CustomResourceDefinition, typeConverter TypeConverter, logger logging.Logger) SchemaClient {
+	c := NewSchemaClient(&core.Clients{}, typeConverter, nil, logger).(*DefaultSchemaClient)
+	for _, crd := range crds {
+		c.addCRD(crd)
+	}
+
+	return c
+}
+
 // GetCRD gets the CustomResourceDefinition for a given GVK.
 func (c *DefaultSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error) {
+	c.crdsMu.RLock()
+
+	if local, ok := c.localCRDs[gvk]; ok {
+		c.crdsMu.RUnlock()
+		c.logger.Debug("Using local CRD", "gvk", gvk.String(), "crdName", local.Name)
+
+		return local, nil
+	}
+
+	c.crdsMu.RUnlock()
+
 	// Get the pluralized resource name to construct CRD name
 	resourceName, err := c.typeConverter.GetResourceNameForGVK(ctx, gvk)
 	if err != nil {
@@ -102,8 +142,20 @@ func (c *DefaultSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersio
 
 	c.crdsMu.RUnlock()
 
+	crdTyped := &extv1.CustomResourceDefinition{}
+	if c.cache.Load(crdCacheEntry(crdName), crdTyped) {
+		c.logger.Debug("Using CRD from disk cache", "gvk", gvk.String(), "crdName", crdName)
+		c.addCRD(crdTyped)
+
+		return crdTyped, nil
+	}
+
 	c.logger.Debug("Looking up CRD", "gvk", gvk.String(), "crdName", resourceName)
 
+	if c.dynamicClient == nil {
+		return nil, errors.Errorf("CRD %s for %s is not among the observed resources", crdName, gvk.String())
+	}
+
 	// Define the CRD GVR directly to avoid recursion
 	crdGVR := schema.GroupVersionResource{
 		Group:    "apiextensions.k8s.io",
@@ -121,7 +173,6 @@ func (c *DefaultSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersio
 	c.logger.Debug("Successfully retrieved CRD", "gvk", gvk.String(), "crdName", resourceName)
 
 	// Convert to typed CRD
-	crdTyped := &extv1.CustomResourceDefinition{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(crdObj.Object, crdTyped); err != nil {
 		c.logger.Debug("Error converting CRD", "gvk", gvk.String(), "crdName", crdName, "error", err)
 		return nil, errors.Wrapf(err, "cannot convert CRD %s to typed", crdName)
@@ -130,9 +181,18 @@ func (c *DefaultSchemaClient) GetCRD(ctx context.Context, gvk schema.GroupVersio
 	// Add to cache
 	c.addCRD(crdTyped)
 
+	if err := c.cache.Store(crdCacheEntry(crdName), crdTyped); err != nil {
+		c.logger.Debug("Failed to cache CRD on disk", "crdName", crdName, "error", err)
+	}
+
 	return crdTyped, nil
 }
 
+// crdCacheEntry returns the disk cache entry name for the named CRD.
+func crdCacheEntry(name string) string {
+	return "crds/" + name
+}
+
 // IsCRDRequired checks if a GVK requires a CRD.
 func (c *DefaultSchemaClient) IsCRDRequired(ctx context.Context, gvk schema.GroupVersionKind) bool {
 	// Check cache first
@@ -315,6 +375,54 @@ func (c *DefaultSchemaClient) LoadCRDsFromXRDs(ctx context.Context, xrds []*un.U
 	return nil
 }
 
+// LoadCRDsFromLocalXRDs derives CRDs from XRDs supplied with the input and caches them. Unlike
+// LoadCRDsFromXRDs nothing is fetched: the XR (and claim) CRDs are generated the way Crossplane would
+// generate them on install, and replace any cached cluster CRD of the same name.
+func (c *DefaultSchemaClient) LoadCRDsFromLocalXRDs(xrds []*un.Unstructured) error {
+	crds, err := xpcrd.ConvertToCRDs(xrds)
+	if err != nil {
+		return errors.Wrap(err, "cannot derive CRDs from local XRDs")
+	}
+
+	c.AddLocalCRDs(crds)
+
+	return nil
+}
+
+// AddLocalCRDs caches CRDs supplied from files rather than read from the cluster, replacing
+// any cached cluster CRD of the same name. GetCRD looks them up by the types they serve
+// before consulting discovery, so they also cover types the cluster doesn't have.
+func (c *DefaultSchemaClient) AddLocalCRDs(crds []*extv1.CustomResourceDefinition) {
+	c.crdsMu.Lock()
+
+	gvks := make([]schema.GroupVersionKind, 0, len(crds))
+
+	for _, crd := range crds {
+		if _, exists := c.crdByName[crd.Name]; exists {
+			c.crds = slices.DeleteFunc(c.crds, func(cached *extv1.CustomResourceDefinition) bool {
+				return cached.Name == crd.Name
+			})
+		}
+
+		c.crds = append(c.crds, crd)
+		c.crdByName[crd.Name] = crd
+
+		for _, v := range crd.Spec.Versions {
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}
+			c.localCRDs[gvk] = crd
+			gvks = append(gvks, gvk)
+		}
+
+		c.logger.Debug("Added local CRD to cache", "crdName", crd.Name)
+	}
+
+	c.crdsMu.Unlock()
+
+	for _, gvk := range gvks {
+		c.cacheResourceType(gvk, true)
+	}
+}
+
 // loadCRDsFromGVKs fetches CRDs from the cluster for the given GVKs and caches them.
 // This method fetches the actual CRDs from the cluster for each provided GVK.
 func (c *DefaultSchemaClient) loadCRDsFromGVKs(ctx context.Context, gvks []schema.GroupVersionKind) error {
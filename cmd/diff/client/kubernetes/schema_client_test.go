@@ -4,7 +4,9 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -504,6 +506,192 @@ func TestSchemaClient_LoadCRDsFromXRDs(t *testing.T) {
 	}
 }
 
+func TestSchemaClient_LoadCRDsFromLocalXRDs(t *testing.T) {
+	ctx := t.Context()
+
+	rawSchema := []byte(`{"type": "object", "properties": {"spec": {"type": "object", "properties": {"field": {"type": "string"}}}}}`)
+	crdName := testXResourcePlural + "." + testExampleOrgGroup
+	xrGVK := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+	claimGVK := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: "Resource"}
+
+	xrd := tu.NewXRD(crdName, testExampleOrgGroup, testXResourceKind).
+		WithPlural(testXResourcePlural).
+		WithDefaultVersion().
+		WithRawSchema(rawSchema).
+		BuildAsUnstructured()
+
+	// The cluster's copy of the CRD, which a local XRD of the same name should replace.
+	clusterCRD := tu.NewCRD(crdName, testExampleOrgGroup, testXResourceKind).
+		WithPlural(testXResourcePlural).
+		Build()
+
+	tests := map[string]struct {
+		reason       string
+		xrds         []*un.Unstructured
+		cached       []*extv1.CustomResourceDefinition
+		wantGVKs     []schema.GroupVersionKind
+		wantCRDNames []string
+		wantErr      string
+	}{
+		"OnlyLocal": {
+			reason:       "The XR type of an XRD the cluster doesn't have should resolve to a generated CRD without discovery.",
+			xrds:         []*un.Unstructured{xrd},
+			wantGVKs:     []schema.GroupVersionKind{xrGVK},
+			wantCRDNames: []string{crdName},
+		},
+		"WithClaim": {
+			reason:       "An XRD that offers a claim should also produce the claim CRD.",
+			xrds:         []*un.Unstructured{tu.NewXRD(crdName, testExampleOrgGroup, testXResourceKind).WithPlural(testXResourcePlural).WithClaimNames("Resource", "resources").WithDefaultVersion().WithRawSchema(rawSchema).BuildAsUnstructured()},
+			wantGVKs:     []schema.GroupVersionKind{xrGVK, claimGVK},
+			wantCRDNames: []string{crdName, "resources." + testExampleOrgGroup},
+		},
+		"ReplacesClusterCRD": {
+			reason:       "A CRD derived from a local XRD should replace the cached cluster CRD of the same name.",
+			xrds:         []*un.Unstructured{xrd},
+			cached:       []*extv1.CustomResourceDefinition{clusterCRD},
+			wantGVKs:     []schema.GroupVersionKind{xrGVK},
+			wantCRDNames: []string{crdName},
+		},
+		"MissingSchema": {
+			reason:  "An XRD version without a schema can't be turned into a CRD.",
+			xrds:    []*un.Unstructured{tu.NewXRD(crdName, testExampleOrgGroup, testXResourceKind).WithPlural(testXResourcePlural).WithDefaultVersion().BuildAsUnstructured()},
+			wantErr: "cannot derive CRDs from local XRDs",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Discovery and the cluster know nothing about the type.
+			converter := tu.NewMockTypeConverter().
+				WithGetResourceNameForGVK(func(context.Context, schema.GroupVersionKind) (string, error) {
+					return "", errors.New("no resource found")
+				}).Build()
+
+			client := NewSchemaClient(&core.Clients{Dynamic: fake.NewSimpleDynamicClient(runtime.NewScheme())}, converter, nil, tu.TestLogger(t, false)).(*DefaultSchemaClient)
+			for _, crd := range tc.cached {
+				client.addCRD(crd)
+			}
+
+			err := client.LoadCRDsFromLocalXRDs(tc.xrds)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("\n%s\nLoadCRDsFromLocalXRDs(): expected error containing %q, got %v", tc.reason, tc.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nLoadCRDsFromLocalXRDs(): unexpected error: %v", tc.reason, err)
+			}
+
+			for _, gvk := range tc.wantGVKs {
+				crd, err := client.GetCRD(ctx, gvk)
+				if err != nil {
+					t.Errorf("\n%s\nGetCRD(%s): unexpected error: %v", tc.reason, gvk, err)
+					continue
+				}
+
+				if crd.Spec.Names.Kind != gvk.Kind {
+					t.Errorf("\n%s\nGetCRD(%s): got CRD for kind %s", tc.reason, gvk, crd.Spec.Names.Kind)
+				}
+
+				if !client.IsCRDRequired(ctx, gvk) {
+					t.Errorf("\n%s\nIsCRDRequired(%s): want true", tc.reason, gvk)
+				}
+			}
+
+			names := make([]string, 0, len(tc.wantCRDNames))
+			for _, crd := range client.GetAllCRDs() {
+				names = append(names, crd.Name)
+			}
+
+			if diff := cmp.Diff(tc.wantCRDNames, names); diff != "" {
+				t.Errorf("\n%s\nGetAllCRDs(): -want names, +got names:\n%s", tc.reason, diff)
+			}
+
+			// The path schema validation initializes through must not go to the cluster either.
+			if err := client.LoadCRDsFromXRDs(ctx, tc.xrds); err != nil {
+				t.Errorf("\n%s\nLoadCRDsFromXRDs(): unexpected error: %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestSchemaClient_AddLocalCRDs(t *testing.T) {
+	ctx := t.Context()
+
+	crdName := testXResourcePlural + "." + testExampleOrgGroup
+	xrGVK := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+
+	clusterCRD := tu.NewCRD(crdName, testExampleOrgGroup, testXResourceKind).
+		WithPlural(testXResourcePlural).
+		WithDefaultVersion().
+		WithStringFieldSchema("cluster").
+		Build()
+	localCRD := tu.NewCRD(crdName, testExampleOrgGroup, testXResourceKind).
+		WithPlural(testXResourcePlural).
+		WithDefaultVersion().
+		WithStringFieldSchema("local").
+		Build()
+
+	tests := map[string]struct {
+		reason string
+		cached []*extv1.CustomResourceDefinition
+	}{
+		"NotInCluster": {
+			reason: "A local CRD should resolve a type the cluster doesn't have without discovery.",
+		},
+		"ReplacesClusterCRD": {
+			reason: "A local CRD should take precedence over a cached cluster CRD of the same name.",
+			cached: []*extv1.CustomResourceDefinition{clusterCRD},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Discovery and the cluster know nothing about the type.
+			converter := tu.NewMockTypeConverter().
+				WithGetResourceNameForGVK(func(context.Context, schema.GroupVersionKind) (string, error) {
+					return "", errors.New("no resource found")
+				}).Build()
+
+			client := NewSchemaClient(&core.Clients{Dynamic: fake.NewSimpleDynamicClient(runtime.NewScheme())}, converter, nil, tu.TestLogger(t, false)).(*DefaultSchemaClient)
+			for _, crd := range tc.cached {
+				client.addCRD(crd)
+			}
+
+			client.AddLocalCRDs([]*extv1.CustomResourceDefinition{localCRD})
+
+			got, err := client.GetCRD(ctx, xrGVK)
+			if err != nil {
+				t.Fatalf("\n%s\nGetCRD(): unexpected error: %v", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(localCRD, got); diff != "" {
+				t.Errorf("\n%s\nGetCRD(): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			byName, err := client.GetCRDByName(crdName)
+			if err != nil {
+				t.Fatalf("\n%s\nGetCRDByName(): unexpected error: %v", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(localCRD, byName); diff != "" {
+				t.Errorf("\n%s\nGetCRDByName(): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if n := len(client.GetAllCRDs()); n != 1 {
+				t.Errorf("\n%s\nGetAllCRDs(): want 1 CRD, got %d", tc.reason, n)
+			}
+
+			if !client.IsCRDRequired(ctx, xrGVK) {
+				t.Errorf("\n%s\nIsCRDRequired(): want true", tc.reason)
+			}
+		})
+	}
+}
+
 func TestSchemaClient_GetCRDByName(t *testing.T) {
 	// Create test CRDs
 	testCRDName := testXResourcePlural + "." + testExampleOrgGroup
@@ -956,3 +1144,58 @@ func TestSchemaClient_CachingBehavior(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaClient_DiskCache(t *testing.T) {
+	ctx := t.Context()
+	gvk := schema.GroupVersionKind{Group: testExampleOrgGroup, Version: "v1", Kind: testXResourceKind}
+
+	testCRDUnstructuredObj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(
+		tu.NewCRD(testXResourcePlural+"."+testExampleOrgGroup, testExampleOrgGroup, testXResourceKind).
+			WithPlural(testXResourcePlural).
+			WithSingular("xresource").
+			Build())
+	testCRDUnstructured := &un.Unstructured{Object: testCRDUnstructuredObj}
+
+	cache := core.NewDiskCache(t.TempDir(), "https://cluster.example:6443", time.Hour)
+
+	// newClient returns a client backed by cache whose dynamic client serves
+	// the test CRD, along with a count of the CRD gets it served.
+	newClient := func() (SchemaClient, *int) {
+		calls := 0
+		dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		dynamicClient.PrependReactor("get", "customresourcedefinitions", func(kt.Action) (bool, runtime.Object, error) {
+			calls++
+			return true, testCRDUnstructured, nil
+		})
+
+		converter := tu.NewMockTypeConverter().WithResourceNameForGVK(gvk, testXResourcePlural).Build()
+
+		return NewSchemaClient(&core.Clients{Dynamic: dynamicClient}, converter, cache, tu.TestLogger(t, false)), &calls
+	}
+
+	first, firstCalls := newClient()
+
+	want, err := first.GetCRD(ctx, gvk)
+	if err != nil {
+		t.Fatalf("first GetCRD(...): unexpected error: %v", err)
+	}
+
+	if *firstCalls != 1 {
+		t.Errorf("first GetCRD(...): want 1 call to the cluster, got %d", *firstCalls)
+	}
+
+	second, secondCalls := newClient()
+
+	got, err := second.GetCRD(ctx, gvk)
+	if err != nil {
+		t.Fatalf("second GetCRD(...): unexpected error: %v", err)
+	}
+
+	if *secondCalls != 0 {
+		t.Errorf("second GetCRD(...): a fresh disk cache entry should skip the cluster, got %d calls", *secondCalls)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("second GetCRD(...): -want, +got:\n%s", diff)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
@@ -96,6 +97,8 @@ func (c *DefaultTypeConverter) GetResourceNameForGVK(_ context.Context, gvk sche
 		}
 	}
 
-	// If we get here, we couldn't find a matching resource kind
-	return "", errors.Errorf("no resource found for kind %s in group version %s", gvk.Kind, gvk.GroupVersion().String())
+	// If we get here, we couldn't find a matching resource kind. Wrap a NoKindMatchError so callers
+	// can tell a kind the cluster doesn't serve from a failed lookup.
+	return "", errors.Wrapf(&meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}},
+		"no resource found for kind %s in group version %s", gvk.Kind, gvk.GroupVersion().String())
 }
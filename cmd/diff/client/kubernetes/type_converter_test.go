@@ -7,6 +7,7 @@ import (
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	testdiscovery "k8s.io/client-go/discovery/fake"
@@ -24,8 +25,9 @@ func TestTypeConverter_GVKToGVR(t *testing.T) {
 	}
 
 	type want struct {
-		gvr schema.GroupVersionResource
-		err error
+		gvr     schema.GroupVersionResource
+		err     error
+		noMatch bool
 	}
 
 	tests := map[string]struct {
@@ -133,7 +135,8 @@ func TestTypeConverter_GVKToGVR(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.New("no resource found for kind Resource in group version example.org/v1"),
+				err:     errors.New("no resource found for kind Resource in group version example.org/v1"),
+				noMatch: true,
 			},
 		},
 	}
@@ -164,6 +167,10 @@ func TestTypeConverter_GVKToGVR(t *testing.T) {
 						tc.reason, tc.want.err.Error(), err.Error())
 				}
 
+				if got := meta.IsNoMatchError(err); got != tc.want.noMatch {
+					t.Errorf("\n%s\nGVKToGVR(...): meta.IsNoMatchError(err) = %t, want %t", tc.reason, got, tc.want.noMatch)
+				}
+
 				return
 			}
 
@@ -18,16 +18,33 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/alecthomas/kong"
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	ld "github.com/crossplane/cli/v2/cmd/crossplane/common/load"
+	"golang.org/x/term"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/v2/pkg/v1"
 )
 
 // initializeAppContext initializes the application context with timeout and error handling.
@@ -46,19 +63,45 @@ func initializeAppContext(timeout time.Duration, appCtx *AppContext, log logging
 func defaultProcessorOptions(fields CommonCmdFields) []dp.ProcessorOption {
 	// Default ignored paths - always filtered from diffs
 	// Preallocate with capacity for default + user-specified paths
-	allIgnorePaths := make([]string, 0, 1+len(fields.IgnorePaths))
+	allIgnorePaths := make([]string, 0, 1+len(fields.IgnorePaths)+len(fields.IgnorePathsFile.Value))
 	allIgnorePaths = append(allIgnorePaths, "metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]")
 
 	// Combine default paths with user-specified ones
 	allIgnorePaths = append(allIgnorePaths, fields.IgnorePaths...)
+	allIgnorePaths = append(allIgnorePaths, fields.IgnorePathsFile.Value...)
 
 	opts := []dp.ProcessorOption{
-		dp.WithColorize(!fields.NoColor),
-		dp.WithCompact(fields.Compact),
+		dp.WithColorize(fields.colorize()),
+		dp.WithCompact(fields.compact()),
+		dp.WithContextLines(fields.ContextLines),
+		dp.WithQuiet(fields.Quiet),
+		dp.WithSummaryOnly(fields.SummaryOnly),
+		dp.WithDiffStyle(renderer.DiffStyle(fields.DiffStyle)),
+		dp.WithDiffWidth(fields.diffWidth()),
 		dp.WithMaxNestedDepth(fields.MaxNestedDepth),
+		dp.WithMaxResources(fields.MaxResources),
+		dp.WithPartialNested(fields.PartialNested),
 		dp.WithMaxRenderIterations(fields.MaxIterations),
 		dp.WithEventualState(fields.EventualState),
+		dp.WithOwnerController(fields.OwnerController),
+		dp.WithShowWarnings(fields.ShowWarnings),
+		dp.WithShowExternalResources(fields.ShowExternalResources),
+		dp.WithSkipMissingRevisions(fields.SkipMissingRevisions),
+		dp.WithLatestRevision(fields.LatestRevision),
+		dp.WithStrict(fields.Strict),
+		dp.WithFailOn(fields.failOn()),
+		dp.WithDryRunKinds(fields.DryRunKinds),
+		dp.WithNoDryRunKinds(fields.NoDryRunKinds),
 		dp.WithIgnorePaths(allIgnorePaths),
+		dp.WithNormalizationRules(fields.Normalization.Value),
+		dp.WithNormalize(fields.Normalize),
+		dp.WithShowLabelsDiffOnly(fields.ShowLabelsDiffOnly),
+		dp.WithIgnoreManagedFields(fields.IgnoreManagedFields),
+		dp.WithFieldManager(fields.FieldManager),
+		dp.WithForceConflicts(fields.ForceConflicts),
+		dp.WithMaxConcurrentRenders(fields.MaxConcurrentRenders),
+		dp.WithMaxDiffFieldSize(fields.MaxDiffFieldSize),
+		dp.WithResourceTimeout(fields.TimeoutPerResource),
 	}
 
 	// Add output format option
@@ -70,6 +113,20 @@ func defaultProcessorOptions(fields CommonCmdFields) []dp.ProcessorOption {
 		outputFormat = renderer.OutputFormatJSON
 	case renderer.OutputFormatYAML:
 		outputFormat = renderer.OutputFormatYAML
+	case renderer.OutputFormatCSV:
+		outputFormat = renderer.OutputFormatCSV
+	case renderer.OutputFormatDesired:
+		outputFormat = renderer.OutputFormatDesired
+	case renderer.OutputFormatGitHub:
+		outputFormat = renderer.OutputFormatGitHub
+	case renderer.OutputFormatDOT:
+		outputFormat = renderer.OutputFormatDOT
+	case renderer.OutputFormatMarkdown:
+		outputFormat = renderer.OutputFormatMarkdown
+	case renderer.OutputFormatJUnit:
+		outputFormat = renderer.OutputFormatJUnit
+	case renderer.OutputFormatTextNoANSI:
+		outputFormat = renderer.OutputFormatTextNoANSI
 	case renderer.OutputFormatDiff:
 		outputFormat = renderer.OutputFormatDiff
 	default:
@@ -79,15 +136,31 @@ func defaultProcessorOptions(fields CommonCmdFields) []dp.ProcessorOption {
 
 	opts = append(opts, dp.WithOutputFormat(outputFormat))
 
-	// Add function credentials if provided (empty path with no secrets errors in FunctionCredentials.Decode)
-	if len(fields.FunctionCredentials.Secrets) > 0 {
-		opts = append(opts, dp.WithFunctionCredentials(fields.FunctionCredentials.Secrets))
+	// Add function credentials if provided (empty path with no secrets errors in credentialsLoader.Load)
+	if len(fields.FunctionCredentials.Value) > 0 {
+		opts = append(opts, dp.WithFunctionCredentials(fields.FunctionCredentials.Value))
+	}
+
+	if len(fields.ContextResources.Values) > 0 {
+		opts = append(opts, dp.WithContextResources(fields.ContextResources.Values))
+	}
+
+	if len(fields.ExternalResourcesDir.Value) > 0 {
+		opts = append(opts, dp.WithExternalResources(fields.ExternalResourcesDir.Value))
+	}
+
+	if len(fields.FunctionInputs.Values) > 0 {
+		opts = append(opts, dp.WithFunctionInputs(fields.FunctionInputs.Values))
 	}
 
 	if fields.FunctionRegistryOverride != "" {
 		opts = append(opts, dp.WithFunctionRegistryOverride(fields.FunctionRegistryOverride))
 	}
 
+	if fields.DryRunNamespace != "" {
+		opts = append(opts, dp.WithDryRunNamespace(fields.DryRunNamespace))
+	}
+
 	if fields.CrossplaneRenderBinary != "" {
 		opts = append(opts, dp.WithCrossplaneRenderBinary(fields.CrossplaneRenderBinary))
 	}
@@ -103,6 +176,116 @@ func defaultProcessorOptions(fields CommonCmdFields) []dp.ProcessorOption {
 	return opts
 }
 
+// exitCodeHelp returns the exit code section shared by the help of the xr and comp commands,
+// built from the dp constants so both commands always document the same numbers.
+func exitCodeHelp() string {
+	return fmt.Sprintf(`
+Exit codes:
+  %d  No differences and no errors.
+  %d  Tool error, e.g. the cluster can't be reached or the input is invalid.
+  %d  Schema validation failed, and there were no other errors.
+  %d  Differences were detected, and there were no errors.
+`, dp.ExitCodeSuccess, dp.ExitCodeToolError, dp.ExitCodeSchemaValidation, dp.ExitCodeDiffDetected)
+}
+
+// colorize reports whether output should be colorized. Colors are off under --no-color, and by
+// default when writing to --output-file; an explicit --no-color=false turns them back on.
+func (c *CommonCmdFields) colorize() bool {
+	if c.OutputFile != "" && !c.noColorSet {
+		return false
+	}
+
+	return !c.NoColor
+}
+
+// compact reports whether diffs should be compact: under --compact, or when --context-lines is
+// given explicitly.
+func (c *CommonCmdFields) compact() bool {
+	return c.Compact || c.contextSet
+}
+
+// diffWidth returns the width side-by-side diffs are laid out in: the terminal's width when
+// writing to a terminal, otherwise renderer.DefaultDiffWidth.
+func (c *CommonCmdFields) diffWidth() int {
+	if c.OutputFile != "" {
+		return renderer.DefaultDiffWidth
+	}
+
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+
+	return renderer.DefaultDiffWidth
+}
+
+// failOn returns the diff types --fail-on counts as differences for the exit code. none yields
+// an empty list, so no difference fails the run.
+func (c *CommonCmdFields) failOn() []dt.DiffType {
+	types := make([]dt.DiffType, 0, len(c.FailOn))
+
+	for _, word := range c.FailOn {
+		switch word {
+		case dt.DiffTypeWordAdded:
+			types = append(types, dt.DiffTypeAdded)
+		case dt.DiffTypeWordModified:
+			types = append(types, dt.DiffTypeModified)
+		case dt.DiffTypeWordRemoved:
+			types = append(types, dt.DiffTypeRemoved)
+		}
+	}
+
+	return types
+}
+
+// xrOnlyOutput reports whether --output selects a format only the xr command supports. These
+// formats render a flat set of resource diffs and have no form for composition impact.
+func (c *CommonCmdFields) xrOnlyOutput() bool {
+	switch renderer.OutputFormat(c.Output) {
+	case renderer.OutputFormatCSV, renderer.OutputFormatDesired, renderer.OutputFormatDOT, renderer.OutputFormatMarkdown, renderer.OutputFormatJUnit:
+		return true
+	case renderer.OutputFormatDiff, renderer.OutputFormatTextNoANSI, renderer.OutputFormatJSON, renderer.OutputFormatYAML, renderer.OutputFormatGitHub:
+		return false
+	default:
+		return false
+	}
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// openOutputFile creates or truncates --output-file and makes it the command's stdout. It runs
+// before the processor is built, so an unwritable path fails before anything is diffed.
+func (c *CommonCmdFields) openOutputFile(kongCtx *kong.Context) error {
+	if c.OutputFile == "" {
+		return nil
+	}
+
+	f, err := os.Create(c.OutputFile)
+	if err != nil {
+		return errors.Wrapf(err, "cannot open output file %q", c.OutputFile)
+	}
+
+	c.outputFile = f
+	kongCtx.Stdout = f
+
+	return nil
+}
+
+// closeOutputFile closes the file opened by openOutputFile, if any.
+func (c *CommonCmdFields) closeOutputFile() error {
+	if c.outputFile == nil {
+		return nil
+	}
+
+	err := c.outputFile.Close()
+	c.outputFile = nil
+
+	return errors.Wrapf(err, "cannot close output file %q", c.OutputFile)
+}
+
 // LoadFunctionCredentials loads Secret resources from a YAML file or directory.
 // The function supports both single files and directories containing YAML files.
 // Only resources of kind "Secret" are returned; other resources are silently skipped.
@@ -143,3 +326,604 @@ func LoadFunctionCredentials(path string) ([]corev1.Secret, error) {
 
 	return secrets, nil
 }
+
+// LoadLocalCRDs loads the CustomResourceDefinitions in a YAML file or directory. Other
+// resources are skipped, but a path without any CRDs is an error.
+func LoadLocalCRDs(path string) ([]*extv1.CustomResourceDefinition, error) {
+	loader, err := ld.NewLoader(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create loader for path %q", path)
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load resources from %q", path)
+	}
+
+	var crds []*extv1.CustomResourceDefinition
+
+	for _, res := range resources {
+		if res.GroupVersionKind() != extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			continue
+		}
+
+		crd := &extv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(res.UnstructuredContent(), crd); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert CRD %q to apiextensions/v1", res.GetName())
+		}
+
+		crds = append(crds, crd)
+	}
+
+	if len(crds) == 0 {
+		return nil, fmt.Errorf("no CustomResourceDefinition resources found in %q - path must contain apiextensions.k8s.io/v1 CRDs", path)
+	}
+
+	return crds, nil
+}
+
+// LoadLocalXRDs loads the CompositeResourceDefinitions in a YAML file or directory. Other
+// resources are ignored; it's an error if there are no XRDs.
+func LoadLocalXRDs(path string) ([]*un.Unstructured, error) {
+	return loadLocalKind(path, xp.CrossplaneAPIExtGroup, xp.CompositeResourceDefinitionKind)
+}
+
+// LoadLocalCompositions loads the Compositions in a YAML file or directory. Other resources
+// are ignored; it's an error if there are no Compositions.
+func LoadLocalCompositions(path string) ([]*apiextensionsv1.Composition, error) {
+	resources, err := loadLocalKind(path, xp.CrossplaneAPIExtGroup, xp.CompositionKind)
+	if err != nil {
+		return nil, err
+	}
+
+	comps := make([]*apiextensionsv1.Composition, 0, len(resources))
+
+	for _, res := range resources {
+		comp := &apiextensionsv1.Composition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(res.UnstructuredContent(), comp); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert Composition %q to apiextensions/v1", res.GetName())
+		}
+
+		comps = append(comps, comp)
+	}
+
+	return comps, nil
+}
+
+// LoadLocalFunctions loads the Functions in a YAML file or directory. Other resources are
+// ignored; it's an error if there are no Functions.
+func LoadLocalFunctions(path string) ([]pkgv1.Function, error) {
+	resources, err := loadLocalKind(path, xp.CrossplanePkgGroup, xp.FunctionKind)
+	if err != nil {
+		return nil, err
+	}
+
+	fns := make([]pkgv1.Function, 0, len(resources))
+
+	for _, res := range resources {
+		fn := pkgv1.Function{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(res.UnstructuredContent(), &fn); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert Function %q to pkg/v1", res.GetName())
+		}
+
+		fns = append(fns, fn)
+	}
+
+	return fns, nil
+}
+
+// LoadLocalEnvironmentConfigs loads the EnvironmentConfigs in a YAML file or directory. Other
+// resources are ignored; it's an error if there are no EnvironmentConfigs.
+func LoadLocalEnvironmentConfigs(path string) ([]*un.Unstructured, error) {
+	return loadLocalKind(path, xp.CrossplaneAPIExtGroup, xp.EnvironmentConfigKind)
+}
+
+// LoadExternalResources loads every resource in a YAML file or directory, to resolve functions'
+// required resources from. It's an error if there are none.
+func LoadExternalResources(path string) ([]*un.Unstructured, error) {
+	loader, err := ld.NewLoader(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create loader for path %q", path)
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load resources from %q", path)
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no resources found in %q", path)
+	}
+
+	return resources, nil
+}
+
+// loadLocalKind loads the resources of the given group and kind in a YAML file or directory.
+func loadLocalKind(path, group, kind string) ([]*un.Unstructured, error) {
+	loader, err := ld.NewLoader(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create loader for path %q", path)
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load resources from %q", path)
+	}
+
+	var out []*un.Unstructured
+
+	for _, res := range resources {
+		if gvk := res.GroupVersionKind(); gvk.Group == group && gvk.Kind == kind {
+			out = append(out, res)
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no %s resources found in %q - path must contain %s resources", kind, path, group)
+	}
+
+	return out, nil
+}
+
+// registerLocalDefinitions registers the CRDs, XRDs, compositions, functions and environment configs
+// given with --local-crds, --local-xrds, --local-compositions, --functions-file and --env-config-file
+// with the clients, so they're used in preference to the cluster's. It must be called after the clients are initialized. CRDs are registered last, so
+// one given explicitly wins over one derived from a local XRD.
+func registerLocalDefinitions(appCtx *AppContext, fields *CommonCmdFields, log logging.Logger) error {
+	if xrds := fields.LocalXRDs.Value; len(xrds) > 0 {
+		if err := addLocalXRDs(appCtx, xrds); err != nil {
+			return errors.Wrapf(err, "cannot load XRDs from %q", fields.LocalXRDs.Path)
+		}
+
+		log.Debug("Using local XRDs", "path", fields.LocalXRDs.Path, "count", len(xrds))
+	}
+
+	if comps := fields.LocalCompositions.Value; len(comps) > 0 {
+		appCtx.XpClients.Composition.AddLocalCompositions(comps)
+
+		log.Debug("Using local compositions", "path", fields.LocalCompositions.Path, "count", len(comps))
+	}
+
+	if fns := fields.FunctionsFile.Value; len(fns) > 0 {
+		appCtx.XpClients.Function.AddLocalFunctions(fns)
+
+		log.Debug("Using local functions", "path", fields.FunctionsFile.Path, "count", len(fns))
+	}
+
+	if configs := fields.EnvConfigFile.Value; len(configs) > 0 {
+		appCtx.XpClients.Environment.AddLocalEnvironmentConfigs(configs)
+
+		log.Debug("Using local environment configs", "path", fields.EnvConfigFile.Path, "count", len(configs))
+	}
+
+	if crds := fields.LocalCRDs.Value; len(crds) > 0 {
+		appCtx.K8sClients.Schema.AddLocalCRDs(crds)
+
+		log.Debug("Using local CRDs", "path", fields.LocalCRDs.Path, "count", len(crds))
+	}
+
+	return nil
+}
+
+// registerLocalXRDs removes the XRDs from resources and registers them with the
+// definition and schema clients, so XRs of types the cluster doesn't have yet
+// can be rendered and validated. A local XRD replaces a cluster XRD of the same
+// name.
+func registerLocalXRDs(appCtx *AppContext, resources []*un.Unstructured, log logging.Logger) ([]*un.Unstructured, error) {
+	var xrds []*un.Unstructured
+
+	rest := make([]*un.Unstructured, 0, len(resources))
+
+	for _, res := range resources {
+		gvk := res.GroupVersionKind()
+		if gvk.Group != xp.CrossplaneAPIExtGroup || gvk.Kind != xp.CompositeResourceDefinitionKind {
+			rest = append(rest, res)
+			continue
+		}
+
+		xrds = append(xrds, res)
+	}
+
+	if len(xrds) == 0 {
+		return resources, nil
+	}
+
+	if err := addLocalXRDs(appCtx, xrds); err != nil {
+		return nil, errors.Wrap(err, "cannot load XRDs from input")
+	}
+
+	log.Debug("Using XRDs from input", "count", len(xrds))
+
+	return rest, nil
+}
+
+// addLocalXRDs registers xrds with the schema and definition clients. A local
+// XRD replaces a cluster XRD of the same name.
+func addLocalXRDs(appCtx *AppContext, xrds []*un.Unstructured) error {
+	xrds = slices.Clone(xrds)
+	for i, xrd := range xrds {
+		xrds[i] = defaultXRDScope(xrd)
+	}
+
+	if err := appCtx.K8sClients.Schema.LoadCRDsFromLocalXRDs(xrds); err != nil {
+		return err
+	}
+
+	appCtx.XpClients.Definition.AddLocalXRDs(xrds)
+
+	return nil
+}
+
+// defaultXRDScope returns xrd with spec.scope set to the default its API version
+// gets on install. v2 XRDs default to Namespaced; v1 XRDs are left alone, since
+// an unset scope already reads as LegacyCluster.
+func defaultXRDScope(xrd *un.Unstructured) *un.Unstructured {
+	if xrd.GetAPIVersion() != xp.CrossplaneAPIExtGroup+"/v2" {
+		return xrd
+	}
+
+	if scope, _, _ := un.NestedString(xrd.Object, "spec", "scope"); scope != "" {
+		return xrd
+	}
+
+	xrd = xrd.DeepCopy()
+	_ = un.SetNestedField(xrd.Object, "Namespaced", "spec", "scope")
+
+	return xrd
+}
+
+// LoadContextResource loads the single resource in the YAML file at path so it
+// can be placed into the function pipeline context. A context key holds one
+// value, so files that contain zero or several resources are rejected.
+func LoadContextResource(path string) (map[string]any, error) {
+	loader, err := ld.NewLoader(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create loader for path %q", path)
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load resources from %q", path)
+	}
+
+	if len(resources) != 1 {
+		return nil, errors.Errorf("expected exactly one resource in %q, found %d", path, len(resources))
+	}
+
+	return resources[0].UnstructuredContent(), nil
+}
+
+// LoadFunctionInput loads the YAML object in the file at path to merge into a
+// pipeline step's input. Unlike a context resource it needn't be a Kubernetes
+// object, so it is parsed as a plain mapping.
+func LoadFunctionInput(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a user-supplied CLI argument
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read function input %q", path)
+	}
+
+	var input map[string]any
+	if err := yaml.Unmarshal(data, &input); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse function input %q: expected a YAML object", path)
+	}
+
+	if len(input) == 0 {
+		return nil, errors.Errorf("function input %q is empty", path)
+	}
+
+	return input, nil
+}
+
+// LoadNormalizationConfig loads and validates a YAML file of per-kind normalization
+// rules (see renderer.NormalizationConfig). Unknown fields are rejected so a typo in a
+// rule type isn't silently ignored.
+func LoadNormalizationConfig(path string) (*renderer.NormalizationConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a user-supplied CLI argument
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read normalization config %q", path)
+	}
+
+	config := &renderer.NormalizationConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse normalization config %q", path)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid normalization config %q", path)
+	}
+
+	return config, nil
+}
+
+// LoadCompositionMap loads a YAML file mapping resource kind to composition name,
+// for example:
+//
+//	XDatabase: database-v2
+//	XNetwork: network-experimental
+//
+// Kinds are matched against the kind of the resource being rendered, so a claim
+// is selected by its claim kind.
+func LoadCompositionMap(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a user-supplied CLI argument
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read composition map %q", path)
+	}
+
+	kinds := make(map[string]string)
+	if err := yaml.UnmarshalStrict(data, &kinds); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse composition map %q: expected a mapping of kind to composition name", path)
+	}
+
+	for kind, name := range kinds {
+		if kind == "" || name == "" {
+			return nil, errors.Errorf("composition map %q: kind and composition name must both be set (got %q: %q)", path, kind, name)
+		}
+	}
+
+	return kinds, nil
+}
+
+// LoadBaseline loads the changes from the output of an earlier xr run with
+// --output=json or --output=yaml, to report drift against.
+func LoadBaseline(path string) (*renderer.Baseline, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a user-supplied CLI argument
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read baseline %q", path)
+	}
+
+	var out renderer.StructuredDiffOutput
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse baseline %q: expected the output of --output=json or --output=yaml", path)
+	}
+
+	return renderer.NewBaseline(out.Changes), nil
+}
+
+// LoadIgnorePathsFile loads ignore paths from a file with one path per line, in
+// the same syntax as --ignore-paths. Surrounding whitespace is trimmed, and blank
+// lines and lines starting with # are skipped. A malformed path is reported with
+// its line number.
+func LoadIgnorePathsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a user-supplied CLI argument
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read ignore paths file %q", path)
+	}
+
+	var paths []string
+
+	n := 0
+
+	for line := range strings.Lines(string(data)) {
+		n++
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := renderer.ValidateIgnorePath(line); err != nil {
+			return nil, errors.Wrapf(err, "ignore paths file %q line %d", path, n)
+		}
+
+		paths = append(paths, line)
+	}
+
+	return paths, nil
+}
+
+// LoadObservedResources loads the resources exported from a cluster to the
+// YAML files under dir, e.g. with kubectl get -o yaml. The items of a List are
+// loaded as separate resources.
+func LoadObservedResources(dir string) ([]*un.Unstructured, error) {
+	files, err := sourceFiles(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read observed resources from %q", dir)
+	}
+
+	var all []*un.Unstructured
+
+	for _, file := range files {
+		loader, err := ld.NewLoader(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create loader for %q", file)
+		}
+
+		resources, err := loader.Load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load observed resources from %q", file)
+		}
+
+		for _, res := range resources {
+			if !res.IsList() {
+				all = append(all, res)
+				continue
+			}
+
+			list, err := res.ToList()
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot read list in %q", file)
+			}
+
+			for i := range list.Items {
+				all = append(all, &list.Items[i])
+			}
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, errors.Errorf("no observed resources found in %q", dir)
+	}
+
+	return all, nil
+}
+
+// SourceFileLoader loads resources from files, directories, or "-" for stdin, like
+// ld.CompositeLoader, and records the file each resource came from in the
+// dp.AnnotationSourceFile annotation. Directories are expanded to the YAML files they contain.
+type SourceFileLoader struct {
+	sources []string
+}
+
+// NewSourceFileLoader creates a SourceFileLoader for the supplied sources. As with
+// ld.NewLoader, a source may be a comma-separated list. Glob patterns are expanded with
+// expandSourceGlobs. Stdin is only read once.
+func NewSourceFileLoader(sources []string) (ld.Loader, error) {
+	expanded, err := expandSourceGlobs(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range expanded {
+		if s == "-" {
+			continue
+		}
+
+		if _, err := os.Stat(s); err != nil {
+			return nil, errors.Wrapf(err, "cannot create loader for %q", s)
+		}
+	}
+
+	return &SourceFileLoader{sources: expanded}, nil
+}
+
+// expandSourceGlobs splits comma-separated sources and replaces each glob pattern with the paths
+// it matches, for shells that don't expand globs themselves. A pattern with no matches is kept
+// as a literal path, so it fails like any other missing file. Duplicate paths, including "-",
+// are dropped.
+func expandSourceGlobs(sources []string) ([]string, error) {
+	expanded := make([]string, 0, len(sources))
+	seen := make(map[string]bool)
+
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			expanded = append(expanded, s)
+		}
+	}
+
+	for _, source := range sources {
+		for s := range strings.SplitSeq(source, ",") {
+			if s == "-" {
+				add(s)
+				continue
+			}
+
+			matches, err := filepath.Glob(s)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid glob pattern %q", s)
+			}
+
+			if len(matches) == 0 {
+				add(s)
+				continue
+			}
+
+			for _, m := range matches {
+				add(m)
+			}
+		}
+	}
+
+	return expanded, nil
+}
+
+// Load implements ld.Loader.
+func (l *SourceFileLoader) Load() ([]*un.Unstructured, error) {
+	if len(l.sources) == 0 {
+		return nil, errors.New("no loaders configured")
+	}
+
+	var all []*un.Unstructured
+
+	// A file can be reached through more than one source, e.g. a glob and the directory it is in.
+	seen := make(map[string]bool)
+
+	for _, source := range l.sources {
+		files, err := sourceFiles(source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read %q", source)
+		}
+
+		for _, file := range files {
+			if seen[filepath.Clean(file)] {
+				continue
+			}
+
+			seen[filepath.Clean(file)] = true
+
+			resources, err := loadSourceFile(file)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot load resources from loader")
+			}
+
+			all = append(all, resources...)
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, errors.New("no resources found from any source")
+	}
+
+	return all, nil
+}
+
+// sourceFiles returns the YAML files under source if it is a directory, or source itself otherwise.
+func sourceFiles(source string) ([]string, error) {
+	if source == "-" {
+		return []string{source}, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{source}, nil
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ext := filepath.Ext(path); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// loadSourceFile loads the resources in file and annotates each with the file it came from.
+func loadSourceFile(file string) ([]*un.Unstructured, error) {
+	var loader ld.Loader = &ld.StdinLoader{}
+
+	if file != "-" {
+		var err error
+		if loader, err = ld.NewLoader(file); err != nil {
+			return nil, err
+		}
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range resources {
+		annotations := res.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+
+		annotations[dp.AnnotationSourceFile] = file
+		res.SetAnnotations(annotations)
+	}
+
+	return resources, nil
+}
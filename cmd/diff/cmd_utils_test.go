@@ -0,0 +1,1426 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
+	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/kubecfg"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/v2/pkg/v1"
+)
+
+func TestContextResourcesFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	env := filepath.Join(dir, "env.yaml")
+	if err := os.WriteFile(env, []byte("apiVersion: apiextensions.crossplane.io/v1beta1\nkind: EnvironmentConfig\nmetadata:\n  name: env\ndata:\n  region: us-east-1\n"), 0o600); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+
+	multi := filepath.Join(dir, "multi.yaml")
+	if err := os.WriteFile(multi, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n"), 0o600); err != nil {
+		t.Fatalf("write multi file: %v", err)
+	}
+
+	envResource := map[string]any{
+		"apiVersion": "apiextensions.crossplane.io/v1beta1",
+		"kind":       "EnvironmentConfig",
+		"metadata":   map[string]any{"name": "env"},
+		"data":       map[string]any{"region": "us-east-1"},
+	}
+
+	tests := map[string]struct {
+		reason  string
+		args    []string
+		want    map[string]any
+		wantErr string
+	}{
+		"NotSet": {
+			reason: "Without the flag no context resources should be loaded.",
+			args:   []string{"xr", "<file>"},
+		},
+		"SingleResource": {
+			reason: "The resource in FILE should be stored under KEY.",
+			args:   []string{"xr", "<file>", "--context-resource=apiextensions.crossplane.io/environment=" + env},
+			want:   map[string]any{"apiextensions.crossplane.io/environment": envResource},
+		},
+		"Repeated": {
+			reason: "Each occurrence of the flag should add its own key.",
+			args:   []string{"xr", "<file>", "--context-resource=a=" + env, "--context-resource=b=" + env},
+			want:   map[string]any{"a": envResource, "b": envResource},
+		},
+		"MissingKey": {
+			reason:  "A value without KEY= should be rejected.",
+			args:    []string{"xr", "<file>", "--context-resource=" + env},
+			wantErr: "expected KEY=FILE",
+		},
+		"MultipleResources": {
+			reason:  "A context key holds one value, so files with several resources should be rejected.",
+			args:    []string{"xr", "<file>", "--context-resource=a=" + multi},
+			wantErr: "expected exactly one resource",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("%s\nwant error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nunexpected parse error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.want, c.XR.ContextResources.Values); diff != "" {
+				t.Errorf("%s\nContextResources: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFunctionInputsFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "input.yaml")
+	if err := os.WriteFile(input, []byte("resources:\n  bucket:\n    region: eu-west-1\n"), 0o600); err != nil {
+		t.Fatalf("write input file: %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(empty, []byte("# nothing yet\n"), 0o600); err != nil {
+		t.Fatalf("write empty file: %v", err)
+	}
+
+	list := filepath.Join(dir, "list.yaml")
+	if err := os.WriteFile(list, []byte("- a\n- b\n"), 0o600); err != nil {
+		t.Fatalf("write list file: %v", err)
+	}
+
+	wantInput := map[string]any{
+		"resources": map[string]any{"bucket": map[string]any{"region": "eu-west-1"}},
+	}
+
+	tests := map[string]struct {
+		reason  string
+		args    []string
+		want    map[string]map[string]any
+		wantErr string
+	}{
+		"NotSet": {
+			reason: "Without the flag no function inputs should be loaded.",
+			args:   []string{"xr", "<file>"},
+		},
+		"Repeated": {
+			reason: "Each occurrence of the flag should add input for its own step.",
+			args:   []string{"comp", "<file>", "--function-input=patch-and-transform=" + input, "--function-input=auto-ready=" + input},
+			want:   map[string]map[string]any{"patch-and-transform": wantInput, "auto-ready": wantInput},
+		},
+		"MissingStep": {
+			reason:  "A value without STEP= should be rejected.",
+			args:    []string{"xr", "<file>", "--function-input=" + input},
+			wantErr: "expected STEP=FILE",
+		},
+		"EmptyFile": {
+			reason:  "A file with no input should be rejected rather than silently merging nothing.",
+			args:    []string{"xr", "<file>", "--function-input=a=" + empty},
+			wantErr: "is empty",
+		},
+		"NotAnObject": {
+			reason:  "Step input is an object, so a YAML list should be rejected.",
+			args:    []string{"xr", "<file>", "--function-input=a=" + list},
+			wantErr: "expected a YAML object",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("%s\nwant error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nunexpected parse error: %v", tt.reason, err)
+			}
+
+			got := c.XR.FunctionInputs.Values
+			if tt.args[0] == "comp" {
+				got = c.Comp.FunctionInputs.Values
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s\nFunctionInputs: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOutputFlagColors(t *testing.T) {
+	tests := map[string]struct {
+		reason     string
+		args       []string
+		wantFormat renderer.OutputFormat
+		wantColors bool
+	}{
+		"Default": {
+			reason:     "The default diff output should be colorized.",
+			args:       []string{"xr", "<file>"},
+			wantFormat: renderer.OutputFormatDiff,
+			wantColors: true,
+		},
+		"NoColor": {
+			reason:     "--no-color should turn colors off for the diff output.",
+			args:       []string{"xr", "<file>", "--no-color"},
+			wantFormat: renderer.OutputFormatDiff,
+		},
+		"TextNoANSI": {
+			reason:     "--output=text-no-ansi should never be colorized, even without --no-color.",
+			args:       []string{"xr", "<file>", "--output=text-no-ansi"},
+			wantFormat: renderer.OutputFormatTextNoANSI,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			config := dp.ProcessorConfig{}
+			for _, opt := range defaultProcessorOptions(c.XR.CommonCmdFields) {
+				opt(&config)
+			}
+
+			if config.OutputFormat != tt.wantFormat {
+				t.Errorf("\n%s\nOutputFormat = %q, want %q", tt.reason, config.OutputFormat, tt.wantFormat)
+			}
+
+			if got := config.UseColors(); got != tt.wantColors {
+				t.Errorf("\n%s\nUseColors() = %t, want %t", tt.reason, got, tt.wantColors)
+			}
+		})
+	}
+}
+
+func TestOutputFileFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		reason     string
+		args       []string
+		wantColors bool
+		wantErr    bool
+	}{
+		"DisablesColors": {
+			reason: "Writing to a file should turn colors off and truncate the existing file.",
+			args:   []string{"xr", "<file>", "--output-file", filepath.Join(dir, "existing.diff")},
+		},
+		"ExplicitColors": {
+			reason:     "An explicit --no-color=false should keep colors on when writing to a file.",
+			args:       []string{"xr", "<file>", "--output-file", filepath.Join(dir, "colored.diff"), "--no-color=false"},
+			wantColors: true,
+		},
+		"Comp": {
+			reason: "The comp command should accept --output-file too.",
+			args:   []string{"comp", "<file>", "--output-file", filepath.Join(dir, "comp.diff")},
+		},
+		"Unwritable": {
+			reason:  "A path that can't be created should fail at parse time, before anything is diffed.",
+			args:    []string{"xr", "<file>", "--output-file", filepath.Join(dir, "missing", "out.diff")},
+			wantErr: true,
+		},
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "existing.diff"), []byte("stale"), 0o600); err != nil {
+		t.Fatalf("write existing output file: %v", err)
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "cannot open output file") {
+					t.Errorf("\n%s\nparse: want output file error, got %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			fields := &c.XR.CommonCmdFields
+			if tt.args[0] == "comp" {
+				fields = &c.Comp.CommonCmdFields
+			}
+
+			if err := fields.closeOutputFile(); err != nil {
+				t.Fatalf("\n%s\ncloseOutputFile(): unexpected error: %v", tt.reason, err)
+			}
+
+			info, err := os.Stat(fields.OutputFile)
+			if err != nil {
+				t.Fatalf("\n%s\nstat output file: %v", tt.reason, err)
+			}
+
+			if info.Size() != 0 {
+				t.Errorf("\n%s\noutput file size = %d, want a truncated file", tt.reason, info.Size())
+			}
+
+			if got := fields.colorize(); got != tt.wantColors {
+				t.Errorf("\n%s\ncolorize() = %t, want %t", tt.reason, got, tt.wantColors)
+			}
+		})
+	}
+}
+
+func TestContextLinesFlag(t *testing.T) {
+	tests := map[string]struct {
+		reason      string
+		args        []string
+		wantCompact bool
+		wantLines   int
+		wantErr     bool
+	}{
+		"Default": {
+			reason:    "Without flags diffs should be full, with three context lines for compact mode.",
+			args:      []string{"xr", "<file>"},
+			wantLines: 3,
+		},
+		"Compact": {
+			reason:      "--compact alone should keep the default context lines.",
+			args:        []string{"xr", "<file>", "--compact"},
+			wantCompact: true,
+			wantLines:   3,
+		},
+		"ImpliesCompact": {
+			reason:      "An explicit --context-lines should turn on compact diffs.",
+			args:        []string{"xr", "<file>", "--context-lines", "0"},
+			wantCompact: true,
+			wantLines:   0,
+		},
+		"Negative": {
+			reason:  "A negative --context-lines should be rejected at parse time.",
+			args:    []string{"xr", "<file>", "--context-lines=-1"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "--context-lines must not be negative") {
+					t.Errorf("\n%s\nparse: want negative context lines error, got %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			if got := c.XR.compact(); got != tt.wantCompact {
+				t.Errorf("\n%s\ncompact() = %t, want %t", tt.reason, got, tt.wantCompact)
+			}
+
+			if got := c.XR.ContextLines; got != tt.wantLines {
+				t.Errorf("\n%s\nContextLines = %d, want %d", tt.reason, got, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestSummaryOnlyFlag(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		args    []string
+		wantErr string
+	}{
+		"Diff": {
+			reason: "--summary-only should be accepted with the default output.",
+			args:   []string{"xr", "<file>", "--summary-only"},
+		},
+		"JSON": {
+			reason: "--summary-only should be accepted with structured output, which it reduces to the counts.",
+			args:   []string{"comp", "<file>", "--summary-only", "--output=json"},
+		},
+		"CSV": {
+			reason:  "CSV output has no summary, so --summary-only should be rejected.",
+			args:    []string{"xr", "<file>", "--summary-only", "--output=csv"},
+			wantErr: "--summary-only cannot be used with --output=csv",
+		},
+		"GitHub": {
+			reason:  "GitHub annotations are per resource, so --summary-only should be rejected.",
+			args:    []string{"comp", "<file>", "--summary-only", "--output=github"},
+			wantErr: "--summary-only cannot be used with --output=github",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseArgs(t, tt.args...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("\n%s\nparse: want error containing %q, got %v", tt.reason, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFailOnFlag(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		args    []string
+		want    []dt.DiffType
+		wantErr string
+	}{
+		"Default": {
+			reason: "Without --fail-on every kind of change should fail the run.",
+			args:   []string{"xr", "<file>"},
+			want:   []dt.DiffType{dt.DiffTypeAdded, dt.DiffTypeModified, dt.DiffTypeRemoved},
+		},
+		"Removed": {
+			reason: "--fail-on=removed should only fail the run on deletions.",
+			args:   []string{"comp", "<file>", "--fail-on=removed"},
+			want:   []dt.DiffType{dt.DiffTypeRemoved},
+		},
+		"List": {
+			reason: "--fail-on should accept a comma-separated list of diff types.",
+			args:   []string{"xr", "<file>", "--fail-on=added,removed"},
+			want:   []dt.DiffType{dt.DiffTypeAdded, dt.DiffTypeRemoved},
+		},
+		"None": {
+			reason: "--fail-on=none should fail the run on no change.",
+			args:   []string{"xr", "<file>", "--fail-on=none"},
+			want:   []dt.DiffType{},
+		},
+		"NoneWithOthers": {
+			reason:  "--fail-on=none contradicts any other diff type, so should be rejected.",
+			args:    []string{"xr", "<file>", "--fail-on=none,removed"},
+			wantErr: "--fail-on=none cannot be combined with other diff types",
+		},
+		"UnknownType": {
+			reason:  "An unknown diff type should be rejected.",
+			args:    []string{"xr", "<file>", "--fail-on=deleted"},
+			wantErr: "--fail-on",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("\n%s\nparse: want error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			fields := c.XR.CommonCmdFields
+			if len(tt.args) > 0 && tt.args[0] == "comp" {
+				fields = c.Comp.CommonCmdFields
+			}
+
+			if diff := cmp.Diff(tt.want, fields.failOn()); diff != "" {
+				t.Errorf("\n%s\nfailOn(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUserAgentFlag(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		args   []string
+		get    func(c *cli) string
+		want   string
+	}{
+		"XRDefault": {
+			reason: "The default agent should be tagged with the xr command.",
+			args:   []string{"xr", "<file>"},
+			get:    func(c *cli) string { return c.XR.GetUserAgent() },
+			want:   kubecfg.DefaultUserAgent("xr"),
+		},
+		"CompDefault": {
+			reason: "The default agent should be tagged with the comp command.",
+			args:   []string{"comp", "<file>"},
+			get:    func(c *cli) string { return c.Comp.GetUserAgent() },
+			want:   kubecfg.DefaultUserAgent("comp"),
+		},
+		"Override": {
+			reason: "--user-agent should replace the default agent verbatim.",
+			args:   []string{"xr", "<file>", "--user-agent=ci-pipeline/42"},
+			get:    func(c *cli) string { return c.XR.GetUserAgent() },
+			want:   "ci-pipeline/42",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			if got := tt.get(c); got != tt.want {
+				t.Errorf("\n%s\nGetUserAgent() = %q, want %q", tt.reason, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheFlags(t *testing.T) {
+	const host = "https://cluster.example:6443"
+
+	tests := map[string]struct {
+		reason    string
+		args      []string
+		seed      bool
+		wantErr   bool
+		wantCache bool
+		wantSeed  bool
+	}{
+		"NoCacheDir": {
+			reason: "Without --cache-dir nothing should be cached.",
+			args:   []string{"xr", "<file>"},
+		},
+		"CacheDir": {
+			reason:    "--cache-dir should reuse entries already cached for the cluster.",
+			args:      []string{"xr", "<file>", "--cache-dir=DIR"},
+			seed:      true,
+			wantCache: true,
+			wantSeed:  true,
+		},
+		"NoCache": {
+			reason: "--no-cache should override --cache-dir.",
+			args:   []string{"xr", "<file>", "--cache-dir=DIR", "--no-cache"},
+		},
+		"RefreshCache": {
+			reason:    "--refresh-cache should discard entries already cached for the cluster.",
+			args:      []string{"comp", "<file>", "--cache-dir=DIR", "--refresh-cache"},
+			seed:      true,
+			wantCache: true,
+		},
+		"Expired": {
+			reason:    "Entries older than --cache-ttl should be treated as missing.",
+			args:      []string{"xr", "<file>", "--cache-dir=DIR", "--cache-ttl=0s"},
+			seed:      true,
+			wantCache: true,
+		},
+		"NegativeTTL": {
+			reason:  "A negative --cache-ttl should be rejected at parse time.",
+			args:    []string{"xr", "<file>", "--cache-dir=DIR", "--cache-ttl=-1m"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if tt.seed {
+				if err := core.NewDiskCache(dir, host, time.Hour).Store("xrds", []string{"seed"}); err != nil {
+					t.Fatalf("seed cache: %v", err)
+				}
+			}
+
+			args := make([]string, 0, len(tt.args))
+			for _, a := range tt.args {
+				args = append(args, strings.ReplaceAll(a, "DIR", dir))
+			}
+
+			c, err := parseArgs(t, args...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nparse: expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			fields := &c.XR.CommonCmdFields
+			if tt.args[0] == "comp" {
+				fields = &c.Comp.CommonCmdFields
+			}
+
+			cache, err := fields.GetDiskCache(host)
+			if err != nil {
+				t.Fatalf("\n%s\nGetDiskCache(): unexpected error: %v", tt.reason, err)
+			}
+
+			if got := cache != nil; got != tt.wantCache {
+				t.Fatalf("\n%s\nGetDiskCache() enabled = %t, want %t", tt.reason, got, tt.wantCache)
+			}
+
+			var got []string
+			if hit := cache.Load("xrds", &got); hit != tt.wantSeed {
+				t.Errorf("\n%s\nLoad() hit = %t, want %t", tt.reason, hit, tt.wantSeed)
+			}
+		})
+	}
+}
+
+func TestLoadObservedResources(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		files   map[string]string
+		want    []string
+		wantErr bool
+	}{
+		"FilesAndLists": {
+			reason: "Should load resources from every YAML file under the directory, unpacking Lists.",
+			files: map[string]string{
+				"xr.yaml": "apiVersion: example.org/v1\nkind: XR\nmetadata:\n  name: xr\n",
+				"nested/cms.yaml": `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: b
+`,
+				"notes.txt": "ignored",
+			},
+			want: []string{"ConfigMap/a", "ConfigMap/b", "XR/xr"},
+		},
+		"Empty": {
+			reason:  "Should fail when the directory holds no resources.",
+			files:   map[string]string{"notes.txt": "ignored"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for file, content := range tt.files {
+				path := filepath.Join(dir, file)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadObservedResources(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nLoadObservedResources(): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nLoadObservedResources(): unexpected error: %v", tt.reason, err)
+			}
+
+			ids := make([]string, 0, len(got))
+			for _, res := range got {
+				ids = append(ids, res.GetKind()+"/"+res.GetName())
+			}
+
+			if diff := cmp.Diff(tt.want, ids); diff != "" {
+				t.Errorf("\n%s\nLoadObservedResources(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIgnorePathsFileFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "ignore-paths")
+	if err := os.WriteFile(file, []byte("# ArgoCD tracking\nmetadata.annotations[argocd.argoproj.io/tracking-id]\n\n  metadata.labels[argocd.argoproj.io/instance]  \n\t# indented comment\nspec.forProvider.tags"), 0o600); err != nil {
+		t.Fatalf("write ignore paths file: %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte("# nothing to ignore yet\n\n"), 0o600); err != nil {
+		t.Fatalf("write empty file: %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte("# Flux\n\nmetadata.labels[kustomize.toolkit.fluxcd.io/name\n"), 0o600); err != nil {
+		t.Fatalf("write invalid file: %v", err)
+	}
+
+	tests := map[string]struct {
+		reason       string
+		args         []string
+		wantPaths    []string
+		wantCLIPaths []string
+		wantErr      string
+	}{
+		"NotSet": {
+			reason: "Without the flag no paths should be loaded.",
+			args:   []string{"xr", "<file>"},
+		},
+		"CommentsAndBlankLines": {
+			reason:    "Comments and blank lines should be skipped and each path trimmed.",
+			args:      []string{"xr", "<file>", "--ignore-paths-file=" + file},
+			wantPaths: []string{"metadata.annotations[argocd.argoproj.io/tracking-id]", "metadata.labels[argocd.argoproj.io/instance]", "spec.forProvider.tags"},
+		},
+		"MergedWithIgnorePaths": {
+			reason:       "The file should be usable alongside --ignore-paths.",
+			args:         []string{"comp", "<file>", "--ignore-paths-file=" + file, "--ignore-paths=status.atProvider"},
+			wantPaths:    []string{"metadata.annotations[argocd.argoproj.io/tracking-id]", "metadata.labels[argocd.argoproj.io/instance]", "spec.forProvider.tags"},
+			wantCLIPaths: []string{"status.atProvider"},
+		},
+		"OnlyComments": {
+			reason: "A file with no paths should load nothing rather than fail.",
+			args:   []string{"xr", "<file>", "--ignore-paths-file=" + empty},
+		},
+		"Missing": {
+			reason:  "A missing file should fail at parse time.",
+			args:    []string{"xr", "<file>", "--ignore-paths-file=" + filepath.Join(dir, "missing")},
+			wantErr: "cannot read ignore paths file",
+		},
+		"InvalidLine": {
+			reason:  "A malformed path should fail at parse time, naming its line.",
+			args:    []string{"xr", "<file>", "--ignore-paths-file=" + invalid},
+			wantErr: "line 3: malformed path \"metadata.labels[kustomize.toolkit.fluxcd.io/name\"",
+		},
+		"InvalidIgnorePaths": {
+			reason:  "A malformed --ignore-paths entry should fail at parse time too.",
+			args:    []string{"comp", "<file>", "--ignore-paths=spec..forProvider"},
+			wantErr: "invalid --ignore-paths: malformed path",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("\n%s\nwant parse error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			fields := c.XR.CommonCmdFields
+			if tt.args[0] == "comp" {
+				fields = c.Comp.CommonCmdFields
+			}
+
+			if diff := cmp.Diff(tt.wantPaths, fields.IgnorePathsFile.Value); diff != "" {
+				t.Errorf("\n%s\nIgnorePathsFile.Value: -want, +got:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantCLIPaths, fields.IgnorePaths); diff != "" {
+				t.Errorf("\n%s\nIgnorePaths: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNormalizationConfigFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+
+		return path
+	}
+
+	valid := write("valid.yaml", `rules:
+- kinds: [SecurityGroup.ec2.aws.upbound.io]
+  keyedArray:
+    path: spec.forProvider.ingress
+    key: fromPort
+- lateInit: spec.forProvider
+`)
+	twoTypes := write("two-types.yaml", `rules:
+- ignorePath: spec.forProvider.tags
+  lateInit: spec.forProvider
+`)
+	unknownField := write("unknown.yaml", `rules:
+- ignorePaths: spec.forProvider.tags
+`)
+
+	tests := map[string]struct {
+		reason    string
+		args      []string
+		wantRules []renderer.NormalizationRule
+		wantErr   string
+	}{
+		"NotSet": {
+			reason: "Without the flag no rules should be loaded.",
+			args:   []string{"xr", "<file>"},
+		},
+		"Valid": {
+			reason: "Rules should be loaded in order.",
+			args:   []string{"comp", "<file>", "--normalization-config=" + valid},
+			wantRules: []renderer.NormalizationRule{
+				{
+					Kinds:      []string{"SecurityGroup.ec2.aws.upbound.io"},
+					KeyedArray: &renderer.KeyedArrayRule{Path: "spec.forProvider.ingress", Key: "fromPort"},
+				},
+				{LateInit: "spec.forProvider"},
+			},
+		},
+		"TwoRuleTypes": {
+			reason:  "A rule setting more than one rule type should be rejected.",
+			args:    []string{"xr", "<file>", "--normalization-config=" + twoTypes},
+			wantErr: "invalid normalization rule 0",
+		},
+		"UnknownField": {
+			reason:  "A misspelled rule type should be rejected rather than ignored.",
+			args:    []string{"xr", "<file>", "--normalization-config=" + unknownField},
+			wantErr: "cannot parse normalization config",
+		},
+		"Missing": {
+			reason:  "A missing file should fail at parse time.",
+			args:    []string{"xr", "<file>", "--normalization-config=" + filepath.Join(dir, "missing")},
+			wantErr: "cannot read normalization config",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("\n%s\nwant parse error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nparse: unexpected error: %v", tt.reason, err)
+			}
+
+			fields := c.XR.CommonCmdFields
+			if tt.args[0] == "comp" {
+				fields = c.Comp.CommonCmdFields
+			}
+
+			if diff := cmp.Diff(tt.wantRules, fields.Normalization.Value); diff != "" {
+				t.Errorf("\n%s\nNormalization.Value: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSourceFileLoader(t *testing.T) {
+	dir := t.TempDir()
+
+	single := filepath.Join(dir, "single.yaml")
+	if err := os.WriteFile(single, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: single\n  annotations:\n    team: a\n"), 0o600); err != nil {
+		t.Fatalf("write single file: %v", err)
+	}
+
+	sub := filepath.Join(dir, "xrs")
+	if err := os.MkdirAll(filepath.Join(sub, "nested"), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	first := filepath.Join(sub, "first.yaml")
+	if err := os.WriteFile(first, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n"), 0o600); err != nil {
+		t.Fatalf("write first file: %v", err)
+	}
+
+	second := filepath.Join(sub, "nested", "second.yml")
+	if err := os.WriteFile(second, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\n"), 0o600); err != nil {
+		t.Fatalf("write second file: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(sub, "README.md"), []byte("not yaml"), 0o600); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+
+	tests := map[string]struct {
+		reason  string
+		sources []string
+		stdin   string
+		want    map[string]string
+		wantErr string
+	}{
+		"StdinWithFile": {
+			reason:  "Stdin should be split into one resource per document and combined with file sources, once however often it is named.",
+			sources: []string{"-", single, "-"},
+			stdin:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: piped-1\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: piped-2\n",
+			want:    map[string]string{"piped-1": "-", "piped-2": "-", "single": single},
+		},
+		"File": {
+			reason:  "Each resource should be annotated with its file, keeping existing annotations.",
+			sources: []string{single},
+			want:    map[string]string{"single": single},
+		},
+		"Directory": {
+			reason:  "A directory should be expanded so each resource records the YAML file it came from.",
+			sources: []string{sub},
+			want:    map[string]string{"a": first, "b": first, "c": second},
+		},
+		"CommaSeparated": {
+			reason:  "A comma-separated source should be split, as the upstream loader does.",
+			sources: []string{single + "," + second},
+			want:    map[string]string{"single": single, "c": second},
+		},
+		"Glob": {
+			reason:  "A glob should be expanded, and a directory it matches recursed into like an explicit directory.",
+			sources: []string{filepath.Join(sub, "*.yaml"), filepath.Join(sub, "nest*")},
+			want:    map[string]string{"a": first, "b": first, "c": second},
+		},
+		"Deduplicated": {
+			reason:  "A file reached through several sources should be loaded once.",
+			sources: []string{sub, filepath.Join(sub, "*.yaml"), first},
+			want:    map[string]string{"a": first, "b": first, "c": second},
+		},
+		"GlobNoMatch": {
+			reason:  "A glob with no matches should fail like a missing file.",
+			sources: []string{filepath.Join(dir, "*.json")},
+			wantErr: "cannot create loader",
+		},
+		"BadGlob": {
+			reason:  "A malformed glob should be reported.",
+			sources: []string{filepath.Join(dir, "[")},
+			wantErr: "invalid glob pattern",
+		},
+		"Missing": {
+			reason:  "A source that doesn't exist should fail when the loader is created.",
+			sources: []string{filepath.Join(dir, "missing.yaml")},
+			wantErr: "cannot create loader",
+		},
+		"NoSources": {
+			reason:  "No sources should fail to load.",
+			wantErr: "no loaders configured",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.stdin != "" {
+				stdin := filepath.Join(t.TempDir(), "stdin.yaml")
+				if err := os.WriteFile(stdin, []byte(tt.stdin), 0o600); err != nil {
+					t.Fatalf("write stdin: %v", err)
+				}
+
+				f, err := os.Open(stdin)
+				if err != nil {
+					t.Fatalf("open stdin: %v", err)
+				}
+
+				defer f.Close()
+
+				orig := os.Stdin
+				os.Stdin = f
+
+				t.Cleanup(func() { os.Stdin = orig })
+			}
+
+			var resources []*un.Unstructured
+
+			loader, err := NewSourceFileLoader(tt.sources)
+			if err == nil {
+				resources, err = loader.Load()
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("\n%s\nwant error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nunexpected error: %v", tt.reason, err)
+			}
+
+			got := make(map[string]string, len(resources))
+			for _, res := range resources {
+				got[res.GetName()] = res.GetAnnotations()[dp.AnnotationSourceFile]
+
+				if res.GetName() == "single" && res.GetAnnotations()["team"] != "a" {
+					t.Errorf("\n%s\nLoad(): existing annotations were dropped: %v", tt.reason, res.GetAnnotations())
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nLoad(): -want source files, +got source files:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRegisterLocalXRDs(t *testing.T) {
+	xr := tu.NewResource("example.org/v1", "XNew", "my-xr").Build()
+	v2XRD := tu.NewResource("apiextensions.crossplane.io/v2", "CompositeResourceDefinition", "xnews.example.org").Build()
+	v2ClusterXRD := tu.NewResource("apiextensions.crossplane.io/v2", "CompositeResourceDefinition", "xclusters.example.org").
+		WithSpecField("scope", "Cluster").
+		Build()
+	v1XRD := tu.NewResource("apiextensions.crossplane.io/v1", "CompositeResourceDefinition", "xlegacies.example.org").Build()
+
+	tests := map[string]struct {
+		reason        string
+		resources     []*un.Unstructured
+		loadErr       error
+		wantResources []string
+		wantXRDScopes map[string]string
+		wantErr       string
+	}{
+		"NoXRDs": {
+			reason:        "Without XRDs in the input nothing should be registered.",
+			resources:     []*un.Unstructured{xr},
+			wantResources: []string{"my-xr"},
+		},
+		"XRDsRegistered": {
+			reason:        "XRDs should be removed from the input and registered, with v2 XRDs defaulting to Namespaced.",
+			resources:     []*un.Unstructured{v2XRD, xr, v2ClusterXRD, v1XRD},
+			wantResources: []string{"my-xr"},
+			wantXRDScopes: map[string]string{
+				"xnews.example.org":     "Namespaced",
+				"xclusters.example.org": "Cluster",
+				"xlegacies.example.org": "",
+			},
+		},
+		"CRDGenerationFails": {
+			reason:    "An XRD that can't be turned into a CRD should fail the run.",
+			resources: []*un.Unstructured{v2XRD, xr},
+			loadErr:   errors.New("missing schema"),
+			wantErr:   "cannot load XRDs from input",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var schemaXRDs, defXRDs []*un.Unstructured
+
+			appCtx := &AppContext{
+				K8sClients: k8.Clients{Schema: &tu.MockSchemaClient{
+					LoadCRDsFromLocalXRDsFn: func(xrds []*un.Unstructured) error {
+						schemaXRDs = xrds
+						return tt.loadErr
+					},
+				}},
+				XpClients: xp.Clients{Definition: &tu.MockDefinitionClient{
+					AddLocalXRDsFn: func(xrds []*un.Unstructured) { defXRDs = xrds },
+				}},
+			}
+
+			got, err := registerLocalXRDs(appCtx, tt.resources, tu.TestLogger(t, false))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("\n%s\nregisterLocalXRDs(): want error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nregisterLocalXRDs(): unexpected error: %v", tt.reason, err)
+			}
+
+			gotNames := make([]string, 0, len(got))
+			for _, res := range got {
+				gotNames = append(gotNames, res.GetName())
+			}
+
+			if diff := cmp.Diff(tt.wantResources, gotNames); diff != "" {
+				t.Errorf("\n%s\nregisterLocalXRDs(): -want resources, +got:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(schemaXRDs, defXRDs); diff != "" {
+				t.Errorf("\n%s\nschema and definition clients should get the same XRDs: -schema, +definition:\n%s", tt.reason, diff)
+			}
+
+			var gotScopes map[string]string
+			for _, xrd := range defXRDs {
+				if gotScopes == nil {
+					gotScopes = make(map[string]string)
+				}
+
+				gotScopes[xrd.GetName()], _, _ = un.NestedString(xrd.Object, "spec", "scope")
+			}
+
+			if diff := cmp.Diff(tt.wantXRDScopes, gotScopes); diff != "" {
+				t.Errorf("\n%s\nregistered XRD scopes: -want, +got:\n%s", tt.reason, diff)
+			}
+
+			if scope, _, _ := un.NestedString(v2XRD.Object, "spec", "scope"); scope != "" {
+				t.Errorf("\n%s\nregisterLocalXRDs() modified the input XRD", tt.reason)
+			}
+		})
+	}
+}
+
+func TestExitCodeHelp(t *testing.T) {
+	for name, help := range map[string]string{"xr": (&XRCmd{}).Help(), "comp": (&CompCmd{}).Help()} {
+		if !strings.HasSuffix(help, exitCodeHelp()) {
+			t.Errorf("%s help should end with the shared exit code section, got:\n%s", name, help)
+		}
+	}
+}
+
+func TestLoadLocalCRDs(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		"CRDs": {
+			reason: "Should load the CRDs in the file, skipping other resources.",
+			content: `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: xwidgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: XWidget
+    plural: xwidgets
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`,
+			want: []string{"xwidgets.example.org"},
+		},
+		"NoCRDs": {
+			reason:  "Should fail when the file holds no CRDs.",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "crds.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadLocalCRDs(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nLoadLocalCRDs(): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nLoadLocalCRDs(): unexpected error: %v", tt.reason, err)
+			}
+
+			names := make([]string, 0, len(got))
+			for _, crd := range got {
+				names = append(names, crd.Name)
+			}
+
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("\n%s\nLoadLocalCRDs(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLoadLocalDefinitions(t *testing.T) {
+	const content = `apiVersion: apiextensions.crossplane.io/v2
+kind: CompositeResourceDefinition
+metadata:
+  name: xwidgets.example.org
+---
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: widget
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XWidget
+  mode: Pipeline
+---
+apiVersion: pkg.crossplane.io/v1
+kind: Function
+metadata:
+  name: function-patch-and-transform
+spec:
+  package: xpkg.crossplane.io/crossplane-contrib/function-patch-and-transform:v0.8.2
+---
+apiVersion: apiextensions.crossplane.io/v1beta1
+kind: EnvironmentConfig
+metadata:
+  name: prod
+data:
+  region: us-east-1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+
+	path := filepath.Join(t.TempDir(), "defs.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	xrds, err := LoadLocalXRDs(path)
+	if err != nil {
+		t.Fatalf("LoadLocalXRDs(): unexpected error: %v", err)
+	}
+
+	if len(xrds) != 1 || xrds[0].GetName() != "xwidgets.example.org" {
+		t.Errorf("LoadLocalXRDs(): want only the XRD, got %v", xrds)
+	}
+
+	comps, err := LoadLocalCompositions(path)
+	if err != nil {
+		t.Fatalf("LoadLocalCompositions(): unexpected error: %v", err)
+	}
+
+	if len(comps) != 1 || comps[0].GetName() != "widget" || comps[0].Spec.CompositeTypeRef.Kind != "XWidget" {
+		t.Errorf("LoadLocalCompositions(): want only the typed composition, got %v", comps)
+	}
+
+	fns, err := LoadLocalFunctions(path)
+	if err != nil {
+		t.Fatalf("LoadLocalFunctions(): unexpected error: %v", err)
+	}
+
+	if len(fns) != 1 || fns[0].Spec.Package != "xpkg.crossplane.io/crossplane-contrib/function-patch-and-transform:v0.8.2" {
+		t.Errorf("LoadLocalFunctions(): want only the typed function, got %v", fns)
+	}
+
+	configs, err := LoadLocalEnvironmentConfigs(path)
+	if err != nil {
+		t.Fatalf("LoadLocalEnvironmentConfigs(): unexpected error: %v", err)
+	}
+
+	if len(configs) != 1 || configs[0].GetName() != "prod" {
+		t.Errorf("LoadLocalEnvironmentConfigs(): want only the environment config, got %v", configs)
+	}
+
+	external, err := LoadExternalResources(path)
+	if err != nil {
+		t.Fatalf("LoadExternalResources(): unexpected error: %v", err)
+	}
+
+	if len(external) != 5 {
+		t.Errorf("LoadExternalResources(): want every resource in the file, got %d", len(external))
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	if err := os.WriteFile(empty, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadLocalXRDs(empty); err == nil {
+		t.Error("LoadLocalXRDs(): expected error for a file without XRDs, got nil")
+	}
+
+	if _, err := LoadLocalCompositions(empty); err == nil {
+		t.Error("LoadLocalCompositions(): expected error for a file without Compositions, got nil")
+	}
+
+	if _, err := LoadLocalFunctions(empty); err == nil {
+		t.Error("LoadLocalFunctions(): expected error for a file without Functions, got nil")
+	}
+
+	if _, err := LoadLocalEnvironmentConfigs(empty); err == nil {
+		t.Error("LoadLocalEnvironmentConfigs(): expected error for a file without EnvironmentConfigs, got nil")
+	}
+}
+
+func TestRegisterLocalDefinitions(t *testing.T) {
+	xrd := tu.NewResource("apiextensions.crossplane.io/v2", "CompositeResourceDefinition", "xwidgets.example.org").Build()
+	comp := tu.NewComposition("widget").WithCompositeTypeRef("example.org/v1", "XWidget").Build()
+	crd := tu.NewCRD("widgets.example.org", "example.org", "Widget").Build()
+
+	var calls []string
+
+	appCtx := &AppContext{
+		K8sClients: k8.Clients{Schema: &tu.MockSchemaClient{
+			LoadCRDsFromLocalXRDsFn: func([]*un.Unstructured) error {
+				calls = append(calls, "LoadCRDsFromLocalXRDs")
+				return nil
+			},
+			AddLocalCRDsFn: func([]*extv1.CustomResourceDefinition) { calls = append(calls, "AddLocalCRDs") },
+		}},
+		XpClients: xp.Clients{
+			Definition: &tu.MockDefinitionClient{
+				AddLocalXRDsFn: func(xrds []*un.Unstructured) {
+					calls = append(calls, "AddLocalXRDs")
+
+					if scope, _, _ := un.NestedString(xrds[0].Object, "spec", "scope"); scope != "Namespaced" {
+						t.Errorf("AddLocalXRDs(): want the v2 XRD defaulted to Namespaced, got scope %q", scope)
+					}
+				},
+			},
+			Composition: &tu.MockCompositionClient{
+				AddLocalCompositionsFn: func([]*apiextensionsv1.Composition) { calls = append(calls, "AddLocalCompositions") },
+			},
+			Function: &tu.MockFunctionClient{
+				AddLocalFunctionsFn: func([]pkgv1.Function) { calls = append(calls, "AddLocalFunctions") },
+			},
+			Environment: &tu.MockEnvironmentClient{
+				AddLocalEnvironmentConfigsFn: func([]*un.Unstructured) { calls = append(calls, "AddLocalEnvironmentConfigs") },
+			},
+		},
+	}
+
+	fields := &CommonCmdFields{
+		LocalXRDs:         LocalXRDs{Path: "xrds", Value: []*un.Unstructured{xrd}},
+		LocalCompositions: LocalCompositions{Path: "comps", Value: []*apiextensionsv1.Composition{comp}},
+		LocalCRDs:         LocalCRDs{Path: "crds", Value: []*extv1.CustomResourceDefinition{crd}},
+		FunctionsFile:     LocalFunctions{Path: "fns", Value: []pkgv1.Function{{}}},
+		EnvConfigFile:     LocalEnvConfigs{Path: "envs", Value: []*un.Unstructured{tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod").Build()}},
+	}
+
+	if err := registerLocalDefinitions(appCtx, fields, tu.TestLogger(t, false)); err != nil {
+		t.Fatalf("registerLocalDefinitions(): unexpected error: %v", err)
+	}
+
+	// Explicit CRDs are registered after those derived from XRDs, so they win.
+	want := []string{"LoadCRDsFromLocalXRDs", "AddLocalXRDs", "AddLocalCompositions", "AddLocalFunctions", "AddLocalEnvironmentConfigs", "AddLocalCRDs"}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("registerLocalDefinitions(): -want calls, +got:\n%s", diff)
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	tests := map[string]struct {
+		reason       string
+		content      string
+		wantResolved []string
+		wantErr      bool
+	}{
+		"JSON": {
+			reason:       "Should load the changes of --output=json output.",
+			content:      `{"summary":{"added":1,"modified":0,"removed":0},"changes":[{"type":"added","apiVersion":"v1","kind":"ConfigMap","name":"cm","namespace":"default","diff":{"spec":{"kind":"ConfigMap"}}}]}`,
+			wantResolved: []string{"ConfigMap/cm"},
+		},
+		"YAML": {
+			reason: "Should load the changes of --output=yaml output.",
+			content: `summary:
+  added: 1
+changes:
+- type: added
+  apiVersion: v1
+  kind: ConfigMap
+  name: cm
+  diff: {}
+`,
+			wantResolved: []string{"ConfigMap/cm"},
+		},
+		"Invalid": {
+			reason:  "Should fail when the file isn't structured diff output.",
+			content: "changes: not-a-list\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "baseline")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadBaseline(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nLoadBaseline(): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nLoadBaseline(): unexpected error: %v", tt.reason, err)
+			}
+
+			// Nothing changes in an empty run, so every baseline change is resolved.
+			_, report := got.Compare(nil)
+			if diff := cmp.Diff(tt.wantResolved, report.Resolved); diff != "" {
+				t.Errorf("\n%s\nLoadBaseline(): -want resolved, +got resolved:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
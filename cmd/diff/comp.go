@@ -18,15 +18,22 @@ package main
 
 import (
 	"context"
+	run "runtime"
 	"time"
 
 	"github.com/alecthomas/kong"
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/kubecfg"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/ref"
 	ld "github.com/crossplane/cli/v2/cmd/crossplane/common/load"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
 )
 
 // CompDiffProcessor is imported from the diffprocessor package
@@ -36,24 +43,68 @@ type CompCmd struct {
 	// Embed common fields
 	CommonCmdFields
 
-	Files []string `arg:"" help:"YAML files containing updated Composition(s)." optional:""`
+	Files []string `arg:"" help:"YAML files containing updated Composition(s), or - to read a YAML stream from stdin." optional:""`
 
 	// Configuration options
-	Namespace           string   `default:""                                                                                                                                          help:"Namespace to find XRs (empty = all namespaces)."                                                                                                                             name:"namespace"            short:"n"`
+	Namespace           string   `default:""                                                                                                                                          help:"Namespace to find XRs. Defaults to the namespace of the current kubeconfig context, or all namespaces if it sets none."                                                      name:"namespace"                                                                                                                                      short:"n"`
+	AllNamespaces       bool     `default:"false"                                                                                                                                     help:"Find XRs in all namespaces, ignoring the namespace of the current kubeconfig context."                                                                                       name:"all-namespaces"                                                                                                                                 short:"A"`
 	IncludeManual       bool     `default:"false"                                                                                                                                     help:"Include XRs with Manual update policy (default: only Automatic policy XRs)"                                                                                                  name:"include-manual"`
 	MinimizeComposition bool     `default:"false"                                                                                                                                     help:"Collapse each changed composition to a single marker line (human-readable output only; JSON/YAML keeps full detail; errors and no-change compositions still print in full)." name:"minimize-composition"`
+	MaxConcurrentXRs    int      `aliases:"concurrency"                                                                                                                               default:"0"                                                                                                                                                                        help:"Maximum number of affected XRs diffed at once. 0 (the default) uses the number of CPUs. Renders are still bounded by --max-concurrent-renders." name:"max-concurrent-xrs"`
+	Progress            bool     `default:"false"                                                                                                                                     help:"Write an 'Analyzing N/M XRs...' progress line to stderr while affected XRs are diffed. Ignored when stderr isn't a terminal."                                                name:"progress"`
+	Compositions        []string `aliases:"compositions"                                                                                                                              help:"Diff the named composition installed in the cluster instead of one from a file, e.g. to find XRs that have drifted from it. Repeatable."                                     name:"composition"                                                                                                                                    placeholder:"NAME"`
+	Against             string   `help:"Diff against the compositions in this file instead of the cluster's, rendering each affected XR with both to show the downstream difference." name:"against"                                                                                                                                                                     placeholder:"FILE"`
 	Resources           []string `help:"Limit impact analysis to specific composites in [namespace/]name format. Repeatable or comma-separated. Mutually exclusive with --namespace." name:"resource"`
+
+	against []*un.Unstructured `kong:"-"` // compositions loaded from --against in AfterApply.
 }
 
-// validateFlags returns an error if mutually exclusive flags are set together.
+// validateFlags returns an error if mutually exclusive flags are set together or
+// --max-concurrent-xrs is negative.
 func (c *CompCmd) validateFlags() error {
+	if len(c.Files) > 0 && len(c.Compositions) > 0 {
+		return errors.New("composition files and --composition are mutually exclusive")
+	}
+
 	if c.Namespace != "" && len(c.Resources) > 0 {
 		return errors.New("--namespace and --resource are mutually exclusive; use --resource=[namespace/]name to scope by name")
 	}
 
+	if c.Namespace != "" && c.AllNamespaces {
+		return errors.New("--namespace and --all-namespaces are mutually exclusive")
+	}
+
+	if c.xrOnlyOutput() {
+		return errors.Errorf("--output=%s is only supported by the xr command", c.Output)
+	}
+
+	if c.MaxConcurrentXRs < 0 {
+		return errors.Errorf("--max-concurrent-xrs must not be negative, got %d", c.MaxConcurrentXRs)
+	}
+
 	return nil
 }
 
+// namespace returns the namespace to find XRs in: --namespace if set, otherwise
+// the namespace of the kubeconfig context as returned by contextNamespace. It
+// is empty, meaning all namespaces, with --all-namespaces or --resource.
+func (c *CompCmd) namespace(contextNamespace func(kubecfg.Provider) string) string {
+	if c.Namespace != "" || c.AllNamespaces || len(c.Resources) > 0 {
+		return c.Namespace
+	}
+
+	return contextNamespace(&c.CommonCmdFields)
+}
+
+// maxConcurrentXRs returns --max-concurrent-xrs, or the number of CPUs when it is 0.
+func (c *CompCmd) maxConcurrentXRs() int {
+	if c.MaxConcurrentXRs == 0 {
+		return run.NumCPU()
+	}
+
+	return c.MaxConcurrentXRs
+}
+
 // Help returns help instructions for the composition diff command.
 func (c *CompCmd) Help() string {
 	return `
@@ -72,6 +123,9 @@ Examples:
   # Show impact only on XRs in a specific namespace
   crossplane-diff comp updated-composition.yaml -n production
 
+  # Show impact on XRs in every namespace, even if the kubeconfig context sets one
+  crossplane-diff comp updated-composition.yaml --all-namespaces
+
   # Show compact diffs with minimal context
   crossplane-diff comp updated-composition.yaml --compact
 
@@ -85,19 +139,30 @@ Examples:
   # Show eventual state with function-sequencer (all stages, not just first).
   crossplane-diff comp updated-composition.yaml --eventual-state
 
+  # Show how far the analysis has got when a composition is used by many XRs
+  crossplane-diff comp updated-composition.yaml --progress
+
+  # Find XRs that have drifted from a composition installed in the cluster
+  crossplane-diff comp --composition=my-composition
+
+  # Diff one composition file against another instead of the cluster's version
+  crossplane-diff comp composition-b.yaml --against=composition-a.yaml
+
   # Limit impact analysis to specific composites (by [namespace/]name)
   crossplane-diff comp updated-composition.yaml --resource=default/my-claim
   crossplane-diff comp updated-composition.yaml --resource=default/xr-1,default/xr-2
 
 Notes:
-  --resource cannot be combined with --namespace.
+  Without --namespace, XRs are found in the namespace of the current kubeconfig
+  context, or in all namespaces if the context sets none or --all-namespaces is passed.
+  --resource cannot be combined with --namespace, and always looks in all namespaces.
   Composites with Manual update policy are surfaced with status "filtered"
   (reason "manual_policy") unless --include-manual is also passed. Composites with an
   Automatic update policy whose compositionRevisionSelector does not match the diffed
   composition's labels are surfaced with status "filtered" (reason
   "revision_selector_mismatch"); --include-manual does not re-include them, since they
   would not select the resulting revision.
-`
+` + exitCodeHelp()
 }
 
 // AfterApply implements kong's AfterApply method to bind command-specific dependencies.
@@ -108,9 +173,22 @@ func (c *CompCmd) AfterApply(ctx *kong.Context, log logging.Logger, appCtx *AppC
 		return err
 	}
 
+	if err := c.openOutputFile(ctx); err != nil {
+		return err
+	}
+
+	if err := c.loadAgainst(); err != nil {
+		return err
+	}
+
 	proc := makeDefaultCompProc(c, ctx, appCtx, log)
 
-	loader, err := ld.NewCompositeLoader(c.Files)
+	files, err := expandSourceGlobs(c.Files)
+	if err != nil {
+		return errors.Wrap(err, "cannot create composition loader")
+	}
+
+	loader, err := ld.NewCompositeLoader(files)
 	if err != nil {
 		return errors.Wrap(err, "cannot create composition loader")
 	}
@@ -128,8 +206,11 @@ func makeDefaultCompProc(c *CompCmd, kongCtx *kong.Context, appCtx *AppContext,
 		dp.WithLogger(log),
 		dp.WithIncludeManual(c.IncludeManual),
 		dp.WithMinimizeComposition(c.MinimizeComposition),
+		dp.WithMaxConcurrentXRs(c.maxConcurrentXRs()),
+		dp.WithProgress(c.Progress && isTerminal(kongCtx.Stderr)),
 		dp.WithStdout(kongCtx.Stdout),
 		dp.WithStderr(kongCtx.Stderr),
+		dp.WithAgainstCompositions(c.against),
 	)
 
 	// Create XR processor first (peer processor)
@@ -139,6 +220,65 @@ func makeDefaultCompProc(c *CompCmd, kongCtx *kong.Context, appCtx *AppContext,
 	return dp.NewCompDiffProcessor(xrProc, appCtx.XpClients.Composition, opts...)
 }
 
+// loadAgainst reads the compositions in the --against file, if one is given.
+func (c *CompCmd) loadAgainst() error {
+	if c.Against == "" {
+		return nil
+	}
+
+	loader, err := ld.NewCompositeLoader([]string{c.Against})
+	if err != nil {
+		return errors.Wrap(err, "cannot create --against composition loader")
+	}
+
+	objs, err := loader.Load()
+	if err != nil {
+		return errors.Wrapf(err, "cannot load --against compositions from %q", c.Against)
+	}
+
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == apiextensionsv1.CompositionGroupVersionKind.GroupKind() {
+			c.against = append(c.against, obj)
+		}
+	}
+
+	if len(c.against) == 0 {
+		return errors.Errorf("no compositions found in --against file %q", c.Against)
+	}
+
+	return nil
+}
+
+// loadCompositions returns the compositions named by --composition, fetched
+// from the cluster, or else the compositions loader reads from the files given.
+// An installed composition diffed against itself shows only the XRs whose
+// resources have drifted from what it renders.
+func (c *CompCmd) loadCompositions(ctx context.Context, client xp.CompositionClient, loader ld.Loader) ([]*un.Unstructured, error) {
+	if len(c.Compositions) == 0 {
+		return loader.Load()
+	}
+
+	compositions := make([]*un.Unstructured, 0, len(c.Compositions))
+
+	for _, name := range c.Compositions {
+		comp, err := client.GetComposition(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get composition %q from the cluster", name)
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(comp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert composition %q to unstructured", name)
+		}
+
+		u := &un.Unstructured{Object: obj}
+		u.SetGroupVersionKind(apiextensionsv1.CompositionGroupVersionKind)
+		compositions = append(compositions, u)
+	}
+
+	return compositions, nil
+}
+
 // Run executes the composition diff command.
 func (c *CompCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, proc dp.CompDiffProcessor, loader ld.Loader, exitCode *ExitCode) error {
 	ctx, cancel, err := initializeAppContext(c.Timeout, appCtx, log)
@@ -148,6 +288,17 @@ func (c *CompCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, p
 	}
 	defer cancel()
 
+	if err := registerLocalDefinitions(appCtx, &c.CommonCmdFields, log); err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return err
+	}
+
+	defer func() {
+		if err := c.closeOutputFile(); err != nil {
+			log.Info("Failed to close output file", "error", err)
+		}
+	}()
+
 	// Cleanup any resources held by the processor (e.g., Docker containers)
 	defer func() {
 		// Use background context with timeout for cleanup instead of the command context.
@@ -169,7 +320,7 @@ func (c *CompCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, p
 		return errors.Wrap(err, "cannot initialize composition diff processor")
 	}
 
-	compositions, err := loader.Load()
+	compositions, err := c.loadCompositions(ctx, appCtx.XpClients.Composition, loader)
 	if err != nil {
 		exitCode.Code = dp.ExitCodeToolError
 		return errors.Wrap(err, "cannot load compositions")
@@ -181,7 +332,12 @@ func (c *CompCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, p
 		return err
 	}
 
-	hasDiffs, err := proc.DiffComposition(ctx, compositions, c.Namespace, parsedRefs)
+	namespace := c.namespace(kubecfg.ContextNamespace)
+	if namespace != c.Namespace {
+		log.Debug("Finding XRs in the namespace of the kubeconfig context", "namespace", namespace)
+	}
+
+	hasDiffs, err := proc.DiffComposition(ctx, compositions, namespace, parsedRefs)
 
 	// Determine exit code based on result
 	exitCode.Code = dp.DetermineExitCode(err, hasDiffs)
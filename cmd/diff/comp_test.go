@@ -17,8 +17,15 @@ limitations under the License.
 package main
 
 import (
+	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/kubecfg"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	ld "github.com/crossplane/cli/v2/cmd/crossplane/common/load"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
 )
 
 func TestCompCmd_ValidateFlags(t *testing.T) {
@@ -28,19 +35,68 @@ func TestCompCmd_ValidateFlags(t *testing.T) {
 		errMustContain []string
 	}{
 		"NeitherSet": {
-			cmd: CompCmd{},
+			cmd: CompCmd{MaxConcurrentXRs: 1},
 		},
 		"OnlyNamespace": {
-			cmd: CompCmd{Namespace: "default"},
+			cmd: CompCmd{Namespace: "default", MaxConcurrentXRs: 1},
 		},
 		"OnlyResources": {
-			cmd: CompCmd{Resources: []string{"default/foo"}},
+			cmd: CompCmd{Resources: []string{"default/foo"}, MaxConcurrentXRs: 1},
 		},
 		"BothSet": {
-			cmd:            CompCmd{Namespace: "default", Resources: []string{"default/foo"}},
+			cmd:            CompCmd{Namespace: "default", Resources: []string{"default/foo"}, MaxConcurrentXRs: 1},
 			wantErr:        true,
 			errMustContain: []string{"--namespace", "--resource"},
 		},
+		"NamespaceAndAllNamespaces": {
+			cmd:            CompCmd{Namespace: "default", AllNamespaces: true, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--namespace", "--all-namespaces"},
+		},
+		"CSVOutput": {
+			cmd:            CompCmd{CommonCmdFields: CommonCmdFields{Output: "csv"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--output=csv", "xr command"},
+		},
+		"DesiredOutput": {
+			cmd:            CompCmd{CommonCmdFields: CommonCmdFields{Output: "desired"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--output=desired", "xr command"},
+		},
+		"DOTOutput": {
+			cmd:            CompCmd{CommonCmdFields: CommonCmdFields{Output: "dot"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--output=dot", "xr command"},
+		},
+		"MarkdownOutput": {
+			cmd:            CompCmd{CommonCmdFields: CommonCmdFields{Output: "markdown"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--output=markdown", "xr command"},
+		},
+		"JUnitOutput": {
+			cmd:            CompCmd{CommonCmdFields: CommonCmdFields{Output: "junit"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--output=junit", "xr command"},
+		},
+		"ConcurrentXRs": {
+			cmd: CompCmd{MaxConcurrentXRs: 8},
+		},
+		"ZeroConcurrentXRs": {
+			cmd: CompCmd{MaxConcurrentXRs: 0},
+		},
+		"FilesAndComposition": {
+			cmd:            CompCmd{Files: []string{"comp.yaml"}, Compositions: []string{"my-comp"}, MaxConcurrentXRs: 1},
+			wantErr:        true,
+			errMustContain: []string{"--composition", "mutually exclusive"},
+		},
+		"OnlyComposition": {
+			cmd: CompCmd{Compositions: []string{"my-comp"}, MaxConcurrentXRs: 1},
+		},
+		"NegativeConcurrentXRs": {
+			cmd:            CompCmd{MaxConcurrentXRs: -1},
+			wantErr:        true,
+			errMustContain: []string{"--max-concurrent-xrs", "must not be negative"},
+		},
 	}
 
 	for name, tt := range tests {
@@ -66,3 +122,116 @@ func TestCompCmd_ValidateFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestCompCmd_Namespace(t *testing.T) {
+	contextNamespace := func(kubecfg.Provider) string { return "team-a" }
+
+	tests := map[string]struct {
+		cmd  CompCmd
+		want string
+	}{
+		"ContextNamespace": {
+			cmd:  CompCmd{},
+			want: "team-a",
+		},
+		"ExplicitNamespace": {
+			cmd:  CompCmd{Namespace: "production"},
+			want: "production",
+		},
+		"AllNamespaces": {
+			cmd:  CompCmd{AllNamespaces: true},
+			want: "",
+		},
+		"Resources": {
+			cmd:  CompCmd{Resources: []string{"default/foo"}},
+			want: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.cmd.namespace(contextNamespace); got != tt.want {
+				t.Errorf("namespace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompCmd_MaxConcurrentXRs(t *testing.T) {
+	tests := map[string]struct {
+		set  int
+		want int
+	}{
+		"DefaultsToCPUs": {set: 0, want: runtime.NumCPU()},
+		"Explicit":       {set: 4, want: 4},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := CompCmd{MaxConcurrentXRs: tt.set}
+			if got := c.maxConcurrentXRs(); got != tt.want {
+				t.Errorf("maxConcurrentXRs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompCmd_LoadCompositions(t *testing.T) {
+	installed := tu.NewComposition("installed").WithCompositeTypeRef("example.org/v1", "XR").Build()
+	client := tu.NewMockCompositionClient().
+		WithSuccessfulCompositionFetches([]*apiextensionsv1.Composition{installed}).
+		Build()
+
+	tests := map[string]struct {
+		reason    string
+		cmd       CompCmd
+		wantNames []string
+		wantErr   bool
+	}{
+		"FromCluster": {
+			reason:    "--composition should fetch the named composition from the cluster as an unstructured Composition.",
+			cmd:       CompCmd{Compositions: []string{"installed"}},
+			wantNames: []string{"installed"},
+		},
+		"NotInstalled": {
+			reason:  "A --composition that isn't installed should be an error.",
+			cmd:     CompCmd{Compositions: []string{"missing"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader, err := ld.NewCompositeLoader(nil)
+			if err != nil {
+				t.Fatalf("NewCompositeLoader(...): unexpected error: %v", err)
+			}
+
+			got, err := tt.cmd.loadCompositions(t.Context(), client, loader)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("\n%s\nloadCompositions(...): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nloadCompositions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			names := make([]string, 0, len(got))
+			for _, comp := range got {
+				if comp.GroupVersionKind() != apiextensionsv1.CompositionGroupVersionKind {
+					t.Errorf("\n%s\nloadCompositions(...): want GVK %s, got %s", tt.reason, apiextensionsv1.CompositionGroupVersionKind, comp.GroupVersionKind())
+				}
+
+				names = append(names, comp.GetName())
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("\n%s\nloadCompositions(...): want %v, got %v", tt.reason, tt.wantNames, names)
+			}
+		})
+	}
+}
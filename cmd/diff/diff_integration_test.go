@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/alecthomas/kong"
+	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -300,7 +301,7 @@ func runIntegrationTest(t *testing.T, testType DiffTestType, tt IntegrationTestC
 	exitCode := &ExitCode{}
 
 	// Create AppContext from the test environment's config
-	appCtx, err := NewAppContext(cfg, logger)
+	appCtx, err := NewAppContext(cfg, nil, k8.RetryPolicy{}, logger)
 	if err != nil {
 		t.Fatalf("failed to create app context: %v", err)
 	}
@@ -482,7 +483,7 @@ func TestDiffIntegration(t *testing.T) {
 			},
 			inputFiles: []string{"testdata/diff/modified-xr.yaml"},
 			expectedOutput: `
-~~~ XDownstreamResource/test-resource
+~~~ XDownstreamResource/test-resource (+1 -1)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -499,7 +500,7 @@ func TestDiffIntegration(t *testing.T) {
 ` + tu.Green("+     configData: modified-value") + `
 
 ---
-~~~ XNopResource/test-resource
+~~~ XNopResource/test-resource (+1 -1)
   apiVersion: ns.diff.example.org/v1alpha1
   kind: XNopResource
   metadata:
@@ -616,6 +617,32 @@ Summary: 2 modified`,
 			expectedError:    false,
 			expectedExitCode: dp.ExitCodeDiffDetected,
 		},
+		"EnvironmentPatchPropagation": {
+			reason: "Validates that an environment patch written by one pipeline step reaches resources templated by a later step",
+			// the downstream resource only picks up the XR's configKey via the environment, so a change to
+			// serviceLevel proves the patched environment was threaded through the render.
+			outputFormat: "json",
+			setupFiles: []string{
+				"testdata/diff/resources/xdownstreamenvresource-xrd.yaml",
+				"testdata/diff/resources/env-xrd.yaml",
+				"testdata/diff/resources/env-patch-composition.yaml",
+				"testdata/diff/resources/functions.yaml",
+				"testdata/diff/resources/environment-config-v1beta1.yaml",
+				"testdata/diff/resources/existing-env-patch-downstream-resource.yaml",
+				"testdata/diff/resources/existing-env-xr.yaml",
+			},
+			inputFiles: []string{"testdata/diff/modified-env-xr.yaml"},
+			expectedStructuredOutput: tu.ExpectDiff().
+				WithSummary(0, 2, 0).
+				WithModifiedResource("XDownstreamEnvResource", "test-env-resource", "").
+				WithFieldChange("spec.forProvider.serviceLevel", "existing-config-value", "modified-config-value").
+				And().
+				WithModifiedResource("XEnvResource", "test-env-resource", "").
+				WithFieldChange("spec.configKey", "existing-config-value", "modified-config-value").
+				And(),
+			expectedError:    false,
+			expectedExitCode: dp.ExitCodeDiffDetected,
+		},
 		"ExternalResourceDependencies": {
 			reason:       "Validates diff with external resource dependencies via fn-external-resources",
 			outputFormat: "json",
@@ -862,7 +889,7 @@ Summary: 2 modified`,
 			},
 			inputFiles: []string{"testdata/diff/modified-legacy-xr.yaml"},
 			expectedOutput: `
-~~~ XDownstreamResource/resource-to-be-kept
+~~~ XDownstreamResource/resource-to-be-kept (+1 -1)
   apiVersion: legacycluster.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -878,7 +905,7 @@ Summary: 2 modified`,
 +     configData: modified-value
 
 ---
---- XDownstreamResource/resource-to-be-removed
+--- XDownstreamResource/resource-to-be-removed (no-longer-composed)
 - apiVersion: legacycluster.nop.example.org/v1alpha1
 - kind: XDownstreamResource
 - metadata:
@@ -893,22 +920,22 @@ Summary: 2 modified`,
 -     configData: existing-value
 
 ---
---- XDownstreamResource/resource-to-be-removed-child
-- apiVersion: legacycluster.nop.example.org/v1alpha1
-- kind: XDownstreamResource
-- metadata:
--   annotations:
--     crossplane.io/composition-resource-name: resource2-child
--   generateName: test-resource-child-
--   labels:
--     crossplane.io/composite: test-resource
--   name: resource-to-be-removed-child
-- spec:
--   forProvider:
--     configData: child-value
+  --- XDownstreamResource/resource-to-be-removed-child (owner-removed, owned by XDownstreamResource/resource-to-be-removed)
+  - apiVersion: legacycluster.nop.example.org/v1alpha1
+  - kind: XDownstreamResource
+  - metadata:
+  -   annotations:
+  -     crossplane.io/composition-resource-name: resource2-child
+  -   generateName: test-resource-child-
+  -   labels:
+  -     crossplane.io/composite: test-resource
+  -   name: resource-to-be-removed-child
+  - spec:
+  -   forProvider:
+  -     configData: child-value
 
 ---
-~~~ XNopResource/test-resource
+~~~ XNopResource/test-resource (+1 -1)
   apiVersion: legacycluster.diff.example.org/v1alpha1
   kind: XNopResource
   metadata:
@@ -1324,6 +1351,28 @@ Summary: 2 modified, 2 removed`,
 			expectedError:    false,
 			expectedExitCode: dp.ExitCodeDiffDetected,
 		},
+		"NewClaimInMissingNamespace": {
+			reason:       "Shows everything as new when diffing a claim into a namespace that doesn't exist yet",
+			outputFormat: "json",
+			setupFiles: []string{
+				// deliberately no namespace setup file: greenfield-namespace does not exist in the cluster
+				"testdata/diff/resources/claim-xrd.yaml",
+				"testdata/diff/resources/claim-composition.yaml",
+				"testdata/diff/resources/claim-composition-revision.yaml",
+				"testdata/diff/resources/functions.yaml",
+			},
+			inputFiles: []string{"testdata/diff/new-claim-missing-namespace.yaml"},
+			expectedStructuredOutput: tu.ExpectDiff().
+				WithSummary(2, 0, 0).
+				WithAddedResource("NopClaim", "test-claim", "greenfield-namespace").
+				WithField("spec.coolField", "new-value").
+				And().
+				WithAddedResource("XDownstreamResource", "test-claim", "").
+				WithField("spec.forProvider.configData", "new-value").
+				And(),
+			expectedError:    false,
+			expectedExitCode: dp.ExitCodeDiffDetected,
+		},
 		"NewClaimWithClaimRefComposition": {
 			reason:       "Shows diff for new claim when composition uses spec.claimRef - claimRef is synthesized for new claims",
 			outputFormat: "json",
@@ -2019,7 +2068,7 @@ func TestCompDiffIntegration(t *testing.T) {
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xnopresources.diff.example.org
+~~~ Composition/xnopresources.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2069,7 +2118,7 @@ Summary: 2 resources with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/another-resource
+~~~ XDownstreamResource/another-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2088,7 +2137,7 @@ Summary: 2 resources with changes
 +     resourceTier: premium
 
 ---
-~~~ XDownstreamResource/test-resource
+~~~ XDownstreamResource/test-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2172,7 +2221,7 @@ All composite resources are up-to-date. No downstream resource changes detected.
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xnopresources.diff.example.org
+~~~ Composition/xnopresources.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2221,7 +2270,7 @@ Summary: 1 resource with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/custom-namespace-resource
+~~~ XDownstreamResource/custom-namespace-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2269,7 +2318,7 @@ Summary: 1 modified`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xnopresources.diff.example.org
+~~~ Composition/xnopresources.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2319,7 +2368,7 @@ Summary: 2 resources with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/another-resource
+~~~ XDownstreamResource/another-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2338,7 +2387,7 @@ Summary: 2 resources with changes
 +     resourceTier: premium
 
 ---
-~~~ XDownstreamResource/test-resource
+~~~ XDownstreamResource/test-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2393,7 +2442,7 @@ No XRs found using composition xnopresources-v2.diff.example.org`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xnopresources.diff.example.org
+~~~ Composition/xnopresources.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2442,7 +2491,7 @@ Summary: 1 resource with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/test-resource
+~~~ XDownstreamResource/test-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2493,7 +2542,7 @@ Summary: 1 modified`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xapimigrateresources.example.org
+~~~ Composition/xapimigrateresources.example.org (+1 -1)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2540,7 +2589,7 @@ Summary: 1 resource with changes
 
 === Impact Analysis ===
 
-~~~ XApiMigrateResource/test-api-version-api-resource
+~~~ XApiMigrateResource/test-api-version-api-resource (+1 -0)
   apiVersion: comp.example.org/v1beta2
   kind: XApiMigrateResource
   metadata:
@@ -2646,7 +2695,7 @@ No XRs found using composition xnewresources.diff.example.org`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xstatus.diff.example.org
+~~~ Composition/xstatus.diff.example.org (+2 -0)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2729,7 +2778,7 @@ All composite resources are up-to-date. No downstream resource changes detected.
 				`
 === Composition Changes ===
 
-~~~ Composition/xmixed.diff.example.org
+~~~ Composition/xmixed.diff.example.org (+2 -1)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2805,7 +2854,7 @@ Summary: 2 resources with changes, 1 resource unchanged
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/mixed-test-xr-1-database
+~~~ XDownstreamResource/mixed-test-xr-1-database (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2827,7 +2876,7 @@ Summary: 2 resources with changes, 1 resource unchanged
   
 
 ---
-~~~ XDownstreamResource/mixed-test-xr-2-database
+~~~ XDownstreamResource/mixed-test-xr-2-database (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2881,7 +2930,7 @@ Summary: 2 modified
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/nopclaims.diff.example.org
+~~~ Composition/nopclaims.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -2930,7 +2979,7 @@ Summary: 2 resources with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/test-claim-1-xr
+~~~ XDownstreamResource/test-claim-1-xr (+3 -2)
   apiVersion: nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2950,7 +2999,7 @@ Summary: 2 resources with changes
 +     resourceTier: premium
 
 ---
-~~~ XDownstreamResource/test-claim-2-xr
+~~~ XDownstreamResource/test-claim-2-xr (+3 -2)
   apiVersion: nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -2999,7 +3048,7 @@ Summary: 2 modified`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xfieldremoval.diff.example.org
+~~~ Composition/xfieldremoval.diff.example.org (+0 -1)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -3046,7 +3095,7 @@ Summary: 1 resource with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/field-removal-test
+~~~ XDownstreamResource/field-removal-test (+0 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -3083,7 +3132,7 @@ Summary: 1 modified`,
 			expectedOutput: `
 === Composition Changes ===
 
-~~~ Composition/xnopresources-sha256.diff.example.org
+~~~ Composition/xnopresources-sha256.diff.example.org (+2 -2)
   apiVersion: apiextensions.crossplane.io/v1
   kind: Composition
   metadata:
@@ -3132,7 +3181,7 @@ Summary: 1 resource with changes
 
 === Impact Analysis ===
 
-~~~ XDownstreamResource/sha256-test-resource
+~~~ XDownstreamResource/sha256-test-resource (+3 -2)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -3200,7 +3249,7 @@ Summary: 1 modified`,
 			// If it did, it would mean the nested XR incorrectly used the parent's CLI composition.
 			expectedOutput: `=== Impact Analysis ===
 
-~~~ XDownstreamResource/test-parent-direct
+~~~ XDownstreamResource/test-parent-direct (+1 -1)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -3275,7 +3324,7 @@ Summary: 1 modified`,
 			//   from-ns-b → prefix-from-ns-a (wrong ConfigMap cached from ns-a)
 			expectedOutput: `=== Impact Analysis ===
 
-~~~ XDownstreamResource/xr-in-ns-a
+~~~ XDownstreamResource/xr-in-ns-a (+1 -1)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -3291,7 +3340,7 @@ Summary: 1 modified`,
 +     configData: prefix-from-ns-a
 
 ---
-~~~ XDownstreamResource/xr-in-ns-b
+~~~ XDownstreamResource/xr-in-ns-b (+1 -1)
   apiVersion: ns.nop.example.org/v1alpha1
   kind: XDownstreamResource
   metadata:
@@ -17,10 +17,15 @@ limitations under the License.
 package diffprocessor
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"maps"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/ref"
@@ -28,6 +33,7 @@ import (
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	dtypes "github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -248,7 +254,7 @@ func (p *DefaultCompDiffProcessor) DiffComposition(ctx context.Context, composit
 				ImpactAnalysis: []renderer.XRImpact{},
 			})
 		} else {
-			if compResult.HasChanges() {
+			if p.compositionFails(compResult) {
 				hasDiffs = true
 			}
 
@@ -292,6 +298,28 @@ func (p *DefaultCompDiffProcessor) DiffComposition(ctx context.Context, composit
 	return hasDiffs, nil
 }
 
+// compositionFails reports whether a composition diff counts as a difference for the exit code:
+// the composition itself, or a resource of a changed XR, has a diff of a --fail-on type.
+func (p *DefaultCompDiffProcessor) compositionFails(c *renderer.CompositionDiff) bool {
+	if c.CompositionDiff != nil && p.config.failsOn(c.CompositionDiff.DiffType) {
+		return true
+	}
+
+	for _, impact := range c.ImpactAnalysis {
+		if impact.Status != renderer.XRStatusChanged {
+			continue
+		}
+
+		for _, diff := range impact.Diffs {
+			if p.config.failsOn(diff.DiffType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // preflightResourceRefs resolves user --resource refs against every supplied composition before
 // any rendering happens. Returns the per-composition matched set keyed by composition name.
 // If any ref is relevant to no supplied composition, it returns an error naming the unmatched
@@ -376,6 +404,41 @@ func (p *DefaultCompDiffProcessor) processSingleComposition(ctx context.Context,
 
 	result.CompositionDiff = compDiff
 
+	// A composition whose compositeTypeRef changed no longer applies to the XRs of its old type.
+	// Default discovery lists XRs by the cluster composition's (old) type, so every XR it found is
+	// orphaned: surface them all instead of rendering them against a composition for another type.
+	// In --resource mode refs are resolved against the new type, so they are diffed as usual.
+	retargetedFrom, retargetedTo, err := p.detectRetarget(ctx, newComp)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot detect whether the composition was retargeted")
+	}
+
+	result.RetargetedFrom = retargetedFrom
+	result.StepsReordered = p.detectStepReorder(ctx, newComp)
+
+	if retargetedFrom != "" && !surfaceFiltered {
+		for _, xr := range affectedXRs {
+			result.ImpactAnalysis = append(result.ImpactAnalysis, renderer.XRImpact{
+				ObjectReference: corev1.ObjectReference{
+					APIVersion: xr.GetAPIVersion(),
+					Kind:       xr.GetKind(),
+					Name:       xr.GetName(),
+					Namespace:  xr.GetNamespace(),
+				},
+				Status:       renderer.XRStatusFiltered,
+				FilterReason: renderer.FilterReasonRetargeted,
+				FilterDetail: "composition now targets " + retargetedTo,
+			})
+		}
+
+		result.AffectedResources.Total = len(affectedXRs)
+		result.AffectedResources.Orphaned = len(affectedXRs)
+
+		sortImpacts(result.ImpactAnalysis)
+
+		return result, nil
+	}
+
 	p.config.Logger.Debug("Processing affected XRs", "composition", newComp.GetName(), "count", len(affectedXRs), "surfaceFiltered", surfaceFiltered)
 
 	// Partition XRs by whether they would adopt the diffed composition's resulting revision.
@@ -386,6 +449,11 @@ func (p *DefaultCompDiffProcessor) processSingleComposition(ctx context.Context,
 
 	filteredByPolicy, filteredBySelector := countFilterReasons(droppedXRs)
 
+	result.Reconciliation, err = summarizeReconciliation(keptXRs, droppedXRs)
+	if err != nil {
+		return nil, err
+	}
+
 	p.config.Logger.Debug("Filtered XRs by update policy and revision selector",
 		"composition", newComp.GetName(),
 		"originalCount", len(affectedXRs),
@@ -420,6 +488,8 @@ func (p *DefaultCompDiffProcessor) processSingleComposition(ctx context.Context,
 		result.AffectedResources.FilteredByPolicy = filteredByPolicy
 		result.AffectedResources.FilteredBySelector = filteredBySelector
 
+		sortImpacts(result.ImpactAnalysis)
+
 		return result, nil
 	}
 
@@ -438,35 +508,38 @@ func (p *DefaultCompDiffProcessor) processSingleComposition(ctx context.Context,
 	keptSummary.FilteredBySelector = filteredBySelector
 	result.AffectedResources = keptSummary
 
+	sortImpacts(result.ImpactAnalysis)
+
 	return result, nil
 }
 
+// sortImpacts orders impacts by namespace, then name, so XRs from every namespace are listed in a
+// stable order. Cluster-scoped XRs, having no namespace, come first.
+func sortImpacts(impacts []renderer.XRImpact) {
+	slices.SortStableFunc(impacts, func(a, b renderer.XRImpact) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+}
+
 // collectXRDiffs processes XRs and collects their diffs, returning results for each XR.
 func (p *DefaultCompDiffProcessor) collectXRDiffs(ctx context.Context, xrs []*un.Unstructured, newComp *un.Unstructured) map[string]*XRDiffResult {
 	// Convert the CLI composition to typed once for reuse
 	cliComp := &apiextensionsv1.Composition{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(newComp.Object, cliComp); err != nil {
 		// If we can't convert, return an error result for all XRs
-		results := make(map[string]*XRDiffResult)
-
-		for _, xr := range xrs {
-			resourceID := dt.MakeDiffKeyFromResource(xr)
-			results[resourceID] = &XRDiffResult{
-				Diffs: make(map[string]*dt.ResourceDiff),
-				Error: errors.Wrap(err, "cannot convert CLI composition to typed"),
-			}
-		}
-
-		return results
+		return errorResults(xrs, errors.Wrap(err, "cannot convert CLI composition to typed"))
 	}
 
-	// Extract the target GVK from the CLI composition's compositeTypeRef
-	cliCompTargetAPIVersion := cliComp.Spec.CompositeTypeRef.APIVersion
-	cliCompTargetKind := cliComp.Spec.CompositeTypeRef.Kind
+	// With --against, each XR is also rendered with the baseline composition so the two
+	// renderings can be diffed against each other instead of against the cluster.
+	var againstComp *apiextensionsv1.Composition
 
-	p.config.Logger.Debug("CLI composition targets",
-		"apiVersion", cliCompTargetAPIVersion,
-		"kind", cliCompTargetKind)
+	if against, ok := p.config.AgainstCompositions[newComp.GetName()]; ok {
+		againstComp = &apiextensionsv1.Composition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(against.Object, againstComp); err != nil {
+			return errorResults(xrs, errors.Wrap(err, "cannot convert --against composition to typed"))
+		}
+	}
 
 	// Build a set of root-level resource keys (apiVersion/kind/namespace/name) for quick lookup.
 	// Root-level resources are XRs and Claims supplied as `affectedXRs` to processSingleComposition
@@ -480,12 +553,70 @@ func (p *DefaultCompDiffProcessor) collectXRDiffs(ctx context.Context, xrs []*un
 		rootResourceKeys[key] = true
 	}
 
+	compositionProvider := p.compositionProviderFor(cliComp, rootResourceKeys)
+
+	var againstProvider dtypes.CompositionProvider
+	if againstComp != nil {
+		againstProvider = p.compositionProviderFor(againstComp, rootResourceKeys)
+	}
+
+	// Diff up to MaxConcurrentXRs XRs at once. Renders stay bounded separately by the RenderFn, and
+	// callers order the results by walking xrs, so output doesn't depend on completion order.
+	var (
+		results   = make(map[string]*XRDiffResult, len(xrs))
+		resultsMu sync.Mutex
+		wg        sync.WaitGroup
+	)
+
+	slots := make(chan struct{}, max(p.config.MaxConcurrentXRs, 1))
+	progress := p.newProgressLine(len(xrs))
+
+	for _, xr := range xrs {
+		slots <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-slots }()
+
+			resourceID := dt.MakeDiffKeyFromResource(xr)
+
+			var result *XRDiffResult
+			if againstProvider != nil {
+				result = p.diffXRAgainst(ctx, xr, againstProvider, compositionProvider)
+			} else {
+				result = p.diffXR(ctx, xr, compositionProvider)
+			}
+
+			resultsMu.Lock()
+			defer resultsMu.Unlock()
+
+			results[resourceID] = result
+			progress.update(len(results))
+		})
+	}
+
+	wg.Wait()
+	progress.clear()
+
+	return results
+}
+
+// compositionProviderFor returns a composition provider that resolves the affected XRs, whose
+// keys are in rootResourceKeys, to cliComp.
+func (p *DefaultCompDiffProcessor) compositionProviderFor(cliComp *apiextensionsv1.Composition, rootResourceKeys map[string]bool) dtypes.CompositionProvider {
+	// Extract the target GVK from the CLI composition's compositeTypeRef
+	cliCompTargetAPIVersion := cliComp.Spec.CompositeTypeRef.APIVersion
+	cliCompTargetKind := cliComp.Spec.CompositeTypeRef.Kind
+
+	p.config.Logger.Debug("CLI composition targets",
+		"apiVersion", cliCompTargetAPIVersion,
+		"kind", cliCompTargetKind)
+
 	// Composition provider that returns CLI composition for:
 	// 1. Root-level resources (XRs and Claims that use this composition)
 	// 2. XRs whose type matches the CLI composition's compositeTypeRef
 	//
 	// For nested XRs with different types, looks up from the cluster.
-	compositionProvider := func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+	return func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
 		resGVK := res.GroupVersionKind()
 		resAPIVersion := resGVK.GroupVersion().String()
 		resKind := resGVK.Kind
@@ -520,33 +651,178 @@ func (p *DefaultCompDiffProcessor) collectXRDiffs(ctx context.Context, xrs []*un
 			"cliCompTargetAPIVersion", cliCompTargetAPIVersion,
 			"cliCompTargetKind", cliCompTargetKind)
 
+		return p.matchComposition(ctx, res)
+	}
+}
+
+// matchComposition looks up the composition for a resource from the cluster, resolving its revision
+// as --latest-revision and --skip-missing-revisions say.
+func (p *DefaultCompDiffProcessor) matchComposition(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+	switch {
+	case p.config.LatestRevision:
+		return p.compositionClient.FindMatchingCompositionAtLatestRevision(ctx, res)
+	case p.config.SkipMissingRevisions:
+		return p.compositionClient.FindMatchingCompositionSkippingMissingRevisions(ctx, res)
+	default:
 		return p.compositionClient.FindMatchingComposition(ctx, res)
 	}
+}
 
-	results := make(map[string]*XRDiffResult)
+// errorResults returns a result carrying err for each of xrs.
+func errorResults(xrs []*un.Unstructured, err error) map[string]*XRDiffResult {
+	results := make(map[string]*XRDiffResult, len(xrs))
 
 	for _, xr := range xrs {
-		resourceID := dt.MakeDiffKeyFromResource(xr)
+		results[dt.MakeDiffKeyFromResource(xr)] = &XRDiffResult{
+			Diffs: make(map[string]*dt.ResourceDiff),
+			Error: err,
+		}
+	}
 
-		diffs, err := p.xrProc.DiffSingleResource(ctx, xr, compositionProvider)
-		if err != nil {
-			p.config.Logger.Debug("Failed to process resource", "resource", resourceID, "error", err)
+	return results
+}
+
+// progressLine rewrites a single stderr line with the number of XRs analyzed so far. Its methods
+// are no-ops on a nil progressLine, which is what newProgressLine returns unless
+// ProcessorConfig.Progress is set.
+type progressLine struct {
+	w        io.Writer
+	total    int
+	colorize bool
+	width    int
+}
+
+// newProgressLine starts reporting progress through total XRs, or returns nil if progress
+// reporting is off.
+func (p *DefaultCompDiffProcessor) newProgressLine(total int) *progressLine {
+	if !p.config.Progress || p.config.Stderr == nil || total == 0 {
+		return nil
+	}
+
+	l := &progressLine{w: p.config.Stderr, total: total, colorize: p.config.Colorize}
+	l.update(0)
+
+	return l
+}
+
+// update overwrites the line with the current count. Callers serialize calls.
+func (l *progressLine) update(done int) {
+	if l == nil {
+		return
+	}
+
+	msg := fmt.Sprintf("Analyzing %d/%d XRs...", done, l.total)
+	l.width = len(msg)
+
+	if l.colorize {
+		msg = dt.ColorYellow + msg + dt.ColorReset
+	}
+
+	_, _ = fmt.Fprint(l.w, "\r"+msg)
+}
+
+// clear blanks the line so that later output starts on a clean line.
+func (l *progressLine) clear() {
+	if l == nil {
+		return
+	}
+
+	_, _ = fmt.Fprint(l.w, "\r"+strings.Repeat(" ", l.width)+"\r")
+}
+
+// diffXR diffs a single affected XR, capturing a failure in the result rather than returning it.
+func (p *DefaultCompDiffProcessor) diffXR(ctx context.Context, xr *un.Unstructured, compositionProvider dtypes.CompositionProvider) *XRDiffResult {
+	resourceID := dt.MakeDiffKeyFromResource(xr)
+
+	diffs, err := p.xrProc.DiffSingleResource(ctx, xr, compositionProvider)
+	if err != nil {
+		p.config.Logger.Debug("Failed to process resource", "resource", resourceID, "error", err)
+
+		return &XRDiffResult{
+			Diffs: make(map[string]*dt.ResourceDiff),
+			Error: errors.Wrapf(err, "unable to process resource %s", resourceID),
+		}
+	}
+
+	return &XRDiffResult{Diffs: diffs}
+}
+
+// diffXRAgainst diffs what a single affected XR renders to with the baseline composition against
+// what it renders to with the updated one, capturing a failure in the result rather than returning it.
+func (p *DefaultCompDiffProcessor) diffXRAgainst(ctx context.Context, xr *un.Unstructured, baseline, updated dtypes.CompositionProvider) *XRDiffResult {
+	before := p.diffXR(ctx, xr, baseline)
+	if before.HasError() {
+		before.Error = errors.Wrap(before.Error, "cannot render with the --against composition")
+		return before
+	}
+
+	after := p.diffXR(ctx, xr, updated)
+	if after.HasError() {
+		return after
+	}
+
+	diffs, err := diffRenderings(ctx, before.Diffs, after.Diffs, p.config.Logger, p.config.GetDiffOptions())
+	if err != nil {
+		return &XRDiffResult{
+			Diffs: make(map[string]*dt.ResourceDiff),
+			Error: errors.Wrapf(err, "unable to compare renderings of %s", dt.MakeDiffKeyFromResource(xr)),
+		}
+	}
+
+	return &XRDiffResult{Diffs: diffs}
+}
+
+// diffRenderings diffs the resources an XR rendered to before a composition change against those
+// it rendered to after. Both sets of diffs are against the cluster, so a resource's rendered state
+// is its desired view, and a resource either diff would remove from the cluster wasn't rendered.
+func diffRenderings(ctx context.Context, before, after map[string]*dt.ResourceDiff, logger logging.Logger, opts renderer.DiffOptions) (map[string]*dt.ResourceDiff, error) {
+	rendered := func(d *dt.ResourceDiff) *un.Unstructured {
+		if d == nil || d.DiffType == dt.DiffTypeRemoved {
+			return nil
+		}
 
-			// Store the error in the result
-			results[resourceID] = &XRDiffResult{
-				Diffs: make(map[string]*dt.ResourceDiff),
-				Error: errors.Wrapf(err, "unable to process resource %s", resourceID),
+		return d.Desired.Raw
+	}
+
+	diffs := make(map[string]*dt.ResourceDiff, len(after))
+
+	for _, side := range []map[string]*dt.ResourceDiff{before, after} {
+		for key := range side {
+			if _, done := diffs[key]; done {
+				continue
 			}
-		} else {
-			// Store successful result with diffs
-			results[resourceID] = &XRDiffResult{
-				Diffs: diffs,
-				Error: nil,
+
+			from, to := rendered(before[key]), rendered(after[key])
+			if from == nil && to == nil {
+				continue
+			}
+
+			diff, err := renderer.GenerateDiffWithOptions(ctx, from, to, logger, opts)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot diff renderings of %s", key)
 			}
+
+			diffs[key] = diff
 		}
 	}
 
-	return results
+	return diffs, nil
+}
+
+// originalComposition returns the composition a change to the named composition is compared
+// against: the one supplied with --against if any, otherwise the one in the cluster.
+func (p *DefaultCompDiffProcessor) originalComposition(ctx context.Context, name string) (*apiextensionsv1.Composition, error) {
+	against, ok := p.config.AgainstCompositions[name]
+	if !ok {
+		return p.compositionClient.GetComposition(ctx, name)
+	}
+
+	comp := &apiextensionsv1.Composition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(against.Object, comp); err != nil {
+		return nil, errors.Wrapf(err, "cannot convert --against composition %q to typed", name)
+	}
+
+	return comp, nil
 }
 
 // calculateCompositionDiff calculates the diff between the cluster composition and the file composition.
@@ -557,7 +833,7 @@ func (p *DefaultCompDiffProcessor) calculateCompositionDiff(ctx context.Context,
 	var originalCompUnstructured *un.Unstructured
 
 	// Get the original composition from the cluster
-	originalComp, err := p.compositionClient.GetComposition(ctx, newComp.GetName())
+	originalComp, err := p.originalComposition(ctx, newComp.GetName())
 	if err != nil {
 		p.config.Logger.Debug("Original composition not found in cluster, treating as new composition",
 			"composition", newComp.GetName(), "error", err)
@@ -595,7 +871,7 @@ func (p *DefaultCompDiffProcessor) calculateCompositionDiff(ctx context.Context,
 	// Calculate the composition diff directly without dry-run apply
 	// (compositions are static YAML documents that don't need server-side processing)
 	diffOptions := renderer.DefaultDiffOptions()
-	diffOptions.UseColors = p.config.Colorize
+	diffOptions.UseColors = p.config.UseColors()
 	diffOptions.Compact = p.config.Compact
 	diffOptions.IgnorePaths = p.config.IgnorePaths
 
@@ -618,6 +894,79 @@ func (p *DefaultCompDiffProcessor) calculateCompositionDiff(ctx context.Context,
 	return compDiff, nil
 }
 
+// detectRetarget reports whether newComp targets a different composite type than the composition of
+// the same name in the cluster, returning both types in "Kind (apiVersion)" form when it does. A
+// composition that is not in the cluster yet cannot have been retargeted; any other error getting
+// it is returned, rather than reporting an impact without the XRs it orphans.
+func (p *DefaultCompDiffProcessor) detectRetarget(ctx context.Context, newComp *un.Unstructured) (from, to string, err error) {
+	clusterComp, err := p.originalComposition(ctx, newComp.GetName())
+	switch {
+	case apierrors.IsNotFound(err):
+		return "", "", nil
+	case err != nil:
+		return "", "", err
+	}
+
+	oldRef := clusterComp.Spec.CompositeTypeRef
+	newAPIVersion, _, _ := un.NestedString(newComp.Object, "spec", "compositeTypeRef", "apiVersion")
+	newKind, _, _ := un.NestedString(newComp.Object, "spec", "compositeTypeRef", "kind")
+
+	if oldRef.APIVersion == newAPIVersion && oldRef.Kind == newKind {
+		return "", "", nil
+	}
+
+	from = fmt.Sprintf("%s (%s)", oldRef.Kind, oldRef.APIVersion)
+	to = fmt.Sprintf("%s (%s)", newKind, newAPIVersion)
+
+	p.config.Logger.Debug("Composition retargeted to a different composite type",
+		"composition", newComp.GetName(),
+		"from", from,
+		"to", to)
+
+	return from, to, nil
+}
+
+// detectStepReorder reports whether newComp runs the same pipeline steps as the composition of the
+// same name in the cluster, but in a different order. The line diff of a reordered pipeline is
+// noisy and easy to misread, so the reorder is surfaced on its own. Steps whose content changed
+// still count; adding, removing or renaming a step does not make a reorder.
+func (p *DefaultCompDiffProcessor) detectStepReorder(ctx context.Context, newComp *un.Unstructured) *renderer.StepReorder {
+	clusterComp, err := p.originalComposition(ctx, newComp.GetName())
+	if err != nil {
+		return nil
+	}
+
+	from := make([]string, 0, len(clusterComp.Spec.Pipeline))
+	for _, step := range clusterComp.Spec.Pipeline {
+		from = append(from, step.Step)
+	}
+
+	pipeline, _, _ := un.NestedSlice(newComp.Object, "spec", "pipeline")
+
+	to := make([]string, 0, len(pipeline))
+
+	for _, s := range pipeline {
+		step, ok := s.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		name, _ := step["step"].(string)
+		to = append(to, name)
+	}
+
+	if slices.Equal(from, to) || !slices.Equal(slices.Sorted(slices.Values(from)), slices.Sorted(slices.Values(to))) {
+		return nil
+	}
+
+	p.config.Logger.Debug("Composition pipeline steps reordered",
+		"composition", newComp.GetName(),
+		"from", from,
+		"to", to)
+
+	return &renderer.StepReorder{From: from, To: to}
+}
+
 // predictedRevisionLabels returns the label set the CompositionRevision resulting from this
 // composition would carry, for evaluating an XR's compositionRevisionSelector. Crossplane stamps
 // every revision with the composition's own metadata.labels plus crossplane.io/composition-name
@@ -683,7 +1032,12 @@ func (p *DefaultCompDiffProcessor) partitionXRsByUpdatePolicy(xrs []*un.Unstruct
 //     revision_selector_mismatch). NOT overridden by IncludeManual, since the XR genuinely would
 //     not select the resulting revision.
 //   - Automatic policy with no selector, or a matching selector: kept.
+//   - With LatestRevision, every XR is kept, as if it were Automatic with no selector.
 func (p *DefaultCompDiffProcessor) classifyXR(xr *un.Unstructured, targetLabels, compLabels map[string]string) (*filteredXR, error) {
+	if p.config.LatestRevision {
+		return nil, nil
+	}
+
 	policy, err := xp.XRUpdatePolicy(xr.Object, xr.GetAPIVersion())
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot read compositionUpdatePolicy for XR %q", xr.GetName())
@@ -726,12 +1080,47 @@ func countFilterReasons(dropped []filteredXR) (filteredByPolicy, filteredBySelec
 			filteredByPolicy++
 		case renderer.FilterReasonRevisionSelectorMismatch:
 			filteredBySelector++
+		case renderer.FilterReasonRetargeted:
+			// Orphaned XRs are counted separately; see processSingleComposition.
 		}
 	}
 
 	return filteredByPolicy, filteredBySelector
 }
 
+// summarizeReconciliation buckets the partitioned XRs by effective update policy. Kept XRs are
+// Automatic unless --include-manual let Manual ones through; dropped XRs are bucketed by reason.
+func summarizeReconciliation(kept []*un.Unstructured, dropped []filteredXR) (*renderer.ReconciliationSummary, error) {
+	summary := &renderer.ReconciliationSummary{}
+
+	for _, xr := range kept {
+		policy, err := xp.XRUpdatePolicy(xr.Object, xr.GetAPIVersion())
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read compositionUpdatePolicy for XR %q", xr.GetName())
+		}
+
+		if policy == compositionUpdatePolicyManual {
+			summary.Manual++
+			continue
+		}
+
+		summary.AutoUpdate++
+	}
+
+	for _, d := range dropped {
+		switch d.reason {
+		case renderer.FilterReasonManualPolicy:
+			summary.Manual++
+		case renderer.FilterReasonRevisionSelectorMismatch:
+			summary.NotSelected++
+		case renderer.FilterReasonRetargeted:
+			// Retargeted compositions return before partitioning; see processSingleComposition.
+		}
+	}
+
+	return summary, nil
+}
+
 // buildImpactAnalysis builds the impact analysis and summary from XR results.
 func (p *DefaultCompDiffProcessor) buildImpactAnalysis(xrs []*un.Unstructured, results map[string]*XRDiffResult) ([]renderer.XRImpact, renderer.AffectedResourcesSummary) {
 	impacts := make([]renderer.XRImpact, 0, len(xrs))
@@ -752,6 +1141,11 @@ func (p *DefaultCompDiffProcessor) buildImpactAnalysis(xrs []*un.Unstructured, r
 			},
 		}
 
+		// Kept XRs were classified already, so their policy can be read.
+		if policy, err := xp.XRUpdatePolicy(xr.Object, xr.GetAPIVersion()); err == nil && policy == compositionUpdatePolicyManual {
+			impact.ManualPolicy = true
+		}
+
 		switch {
 		case result != nil && result.HasError():
 			impact.Status = renderer.XRStatusError
@@ -20,8 +20,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"runtime"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
@@ -31,9 +35,11 @@ import (
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
 	gcmp "github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
@@ -192,6 +198,65 @@ func TestDefaultCompDiffProcessor_DiffComposition(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		"RetargetedComposition": {
+			namespace: "default",
+			compositions: []*un.Unstructured{
+				tu.NewComposition("test-composition").
+					WithCompositeTypeRef("example.org/v2", "XNewResource").
+					WithPipelineMode().
+					BuildAsUnstructured(),
+			},
+			setupMocks: func() xp.Clients {
+				return xp.Clients{
+					Composition: tu.NewMockCompositionClient().
+						WithSuccessfulCompositionFetch(testComp).
+						WithResourcesForComposition("test-composition", "default", []*un.Unstructured{testXR}).
+						Build(),
+					Definition:   tu.NewMockDefinitionClient().Build(),
+					Environment:  tu.NewMockEnvironmentClient().Build(),
+					Function:     tu.NewMockFunctionClient().Build(),
+					ResourceTree: tu.NewMockResourceTreeClient().Build(),
+				}
+			},
+			verifyOutput: func(t *testing.T, output string) {
+				t.Helper()
+				// Should explain the retarget rather than report an empty impact
+				if !strings.Contains(output, "Composition retargeted: no longer applies to XResource (example.org/v1)'s XRs.") {
+					t.Errorf("Expected output to explain the retarget, got:\n%s", output)
+				}
+				// Should list the orphaned XR with the new target type
+				if !strings.Contains(output, "XResource/test-xr (namespace: default) — orphaned: composition now targets XNewResource (example.org/v2)") {
+					t.Errorf("Expected output to list the orphaned XR, got:\n%s", output)
+				}
+			},
+			wantErr: false,
+		},
+		"CompositionFetchError": {
+			namespace: "default",
+			compositions: []*un.Unstructured{
+				tu.NewComposition("test-composition").
+					WithCompositeTypeRef("example.org/v2", "XNewResource").
+					WithPipelineMode().
+					BuildAsUnstructured(),
+			},
+			setupMocks: func() xp.Clients {
+				return xp.Clients{
+					// A failure other than NotFound must fail the composition, not report it as
+					// not retargeted with an empty impact.
+					Composition: tu.NewMockCompositionClient().
+						WithGetComposition(func(context.Context, string) (*apiextensionsv1.Composition, error) {
+							return nil, errors.New("compositions is forbidden")
+						}).
+						WithResourcesForComposition("test-composition", "default", []*un.Unstructured{testXR}).
+						Build(),
+					Definition:   tu.NewMockDefinitionClient().Build(),
+					Environment:  tu.NewMockEnvironmentClient().Build(),
+					Function:     tu.NewMockFunctionClient().Build(),
+					ResourceTree: tu.NewMockResourceTreeClient().Build(),
+				}
+			},
+			wantErr: true,
+		},
 		"NoCompositions": {
 			namespace:    "default",
 			compositions: []*un.Unstructured{},
@@ -321,13 +386,14 @@ func TestDefaultCompDiffProcessor_partitionXRsByUpdatePolicy(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		includeManual bool
-		compName      string // defaults to "test-comp" when empty
-		compLabels    map[string]string
-		xrs           []*un.Unstructured
-		wantKept      []string
-		wantDropped   []droppedWant
-		wantErr       bool
+		includeManual  bool
+		latestRevision bool
+		compName       string // defaults to "test-comp" when empty
+		compLabels     map[string]string
+		xrs            []*un.Unstructured
+		wantKept       []string
+		wantDropped    []droppedWant
+		wantErr        bool
 	}{
 		// AC2.5 (Manual side): --include-manual keeps Manual XRs...
 		"IncludeManualTrue_KeepsManualXRs": {
@@ -357,6 +423,21 @@ func TestDefaultCompDiffProcessor_partitionXRsByUpdatePolicy(t *testing.T) {
 			wantKept:    []string{"manual-xr"},
 			wantDropped: []droppedWant{{name: "auto-mismatch", reason: renderer.FilterReasonRevisionSelectorMismatch}},
 		},
+		// --latest-revision previews every XR moving to the latest revision, so neither Manual nor
+		// selector-mismatched XRs are dropped.
+		"LatestRevision_KeepsAllXRs": {
+			latestRevision: true,
+			compLabels:     map[string]string{"version": "0.0.2"},
+			xrs: []*un.Unstructured{
+				tu.NewResource("example.org/v1", "XResource", "manual-xr").WithNamespace("default").
+					WithNestedField("Manual", "spec", "crossplane", "compositionUpdatePolicy").Build(),
+				tu.NewResource("example.org/v1", "XResource", "auto-mismatch").WithNamespace("default").
+					WithNestedField("Automatic", "spec", "crossplane", "compositionUpdatePolicy").
+					WithCompositionRevisionSelector(xp.CrossplaneAPIExtGroupV2, map[string]string{"version": "0.0.1"}, nil).Build(),
+			},
+			wantKept:    []string{"manual-xr", "auto-mismatch"},
+			wantDropped: nil,
+		},
 		"IncludeManualFalse_FiltersManualXRs": {
 			includeManual: false,
 			compLabels:    map[string]string{"version": "0.0.2"},
@@ -461,8 +542,9 @@ func TestDefaultCompDiffProcessor_partitionXRsByUpdatePolicy(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			processor := &DefaultCompDiffProcessor{
 				config: ProcessorConfig{
-					IncludeManual: tt.includeManual,
-					Logger:        tu.TestLogger(t, false),
+					IncludeManual:  tt.includeManual,
+					LatestRevision: tt.latestRevision,
+					Logger:         tu.TestLogger(t, false),
 				},
 			}
 
@@ -514,6 +596,55 @@ func TestDefaultCompDiffProcessor_partitionXRsByUpdatePolicy(t *testing.T) {
 // TestPredictedRevisionLabels verifies the label set used to evaluate an XR's
 // compositionRevisionSelector mirrors what a real CompositionRevision would carry: the composition's
 // own labels plus crossplane.io/composition-name. The source composition must not be mutated.
+func TestSummarizeReconciliation(t *testing.T) {
+	manual := func(name string) *un.Unstructured {
+		return tu.NewResource("example.org/v1", "XResource", name).
+			WithNestedField("Manual", "spec", "crossplane", "compositionUpdatePolicy").Build()
+	}
+	auto := func(name string) *un.Unstructured {
+		return tu.NewResource("example.org/v1", "XResource", name).
+			WithNestedField("Automatic", "spec", "crossplane", "compositionUpdatePolicy").Build()
+	}
+
+	tests := map[string]struct {
+		reason  string
+		kept    []*un.Unstructured
+		dropped []filteredXR
+		want    *renderer.ReconciliationSummary
+	}{
+		"ManualFiltered": {
+			reason:  "Manual XRs dropped by the default filter should still count as pinned.",
+			kept:    []*un.Unstructured{auto("a"), auto("b")},
+			dropped: []filteredXR{{xr: manual("m"), reason: renderer.FilterReasonManualPolicy}},
+			want:    &renderer.ReconciliationSummary{AutoUpdate: 2, Manual: 1},
+		},
+		"ManualIncluded": {
+			reason: "Manual XRs kept by --include-manual are diffed but still won't auto-update.",
+			kept:   []*un.Unstructured{auto("a"), manual("m")},
+			want:   &renderer.ReconciliationSummary{AutoUpdate: 1, Manual: 1},
+		},
+		"SelectorMismatch": {
+			reason:  "Automatic XRs whose selector won't match the new revision should not count as auto-updating.",
+			kept:    []*un.Unstructured{auto("a")},
+			dropped: []filteredXR{{xr: auto("s"), reason: renderer.FilterReasonRevisionSelectorMismatch}},
+			want:    &renderer.ReconciliationSummary{AutoUpdate: 1, NotSelected: 1},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := summarizeReconciliation(tt.kept, tt.dropped)
+			if err != nil {
+				t.Fatalf("\n%s\nsummarizeReconciliation(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := gcmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nsummarizeReconciliation(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestPredictedRevisionLabels(t *testing.T) {
 	newComp := tu.NewComposition("xnopresources.example.org").
 		WithCompositeTypeRef("example.org/v1", "XR").
@@ -730,6 +861,226 @@ func TestDefaultCompDiffProcessor_collectXRDiffs_NestedXRCompositionLookup(t *te
 	}
 }
 
+// TestDefaultCompDiffProcessor_collectXRDiffs_Concurrent verifies that affected XRs are diffed at
+// most MaxConcurrentXRs at a time, and that the impact analysis is ordered by the input XRs rather
+// than by which diff finished first.
+func TestDefaultCompDiffProcessor_collectXRDiffs_Concurrent(t *testing.T) {
+	ctx := t.Context()
+
+	comp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		WithPipelineMode().
+		BuildAsUnstructured()
+
+	names := []string{"xr-a", "xr-b", "xr-c", "xr-d", "xr-e", "xr-f"}
+
+	xrs := make([]*un.Unstructured, 0, len(names))
+	for _, name := range names {
+		xrs = append(xrs, tu.NewResource("example.org/v1", "XR1", name).Build())
+	}
+
+	tests := map[string]struct {
+		reason           string
+		maxConcurrentXRs int
+	}{
+		"Serial": {
+			reason:           "The default of one should diff XRs one at a time.",
+			maxConcurrentXRs: 1,
+		},
+		"Bounded": {
+			reason:           "XRs should be diffed concurrently, never more than the limit at once.",
+			maxConcurrentXRs: 3,
+		},
+		"Unset": {
+			reason:           "A limit below one should fall back to diffing XRs one at a time.",
+			maxConcurrentXRs: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int32
+
+			mockXRProc := &tu.MockDiffProcessor{
+				DiffSingleResourceFn: func(_ context.Context, res *un.Unstructured, _ types.CompositionProvider) (map[string]*dt.ResourceDiff, error) {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+
+					for {
+						m := maxInFlight.Load()
+						if n <= m || maxInFlight.CompareAndSwap(m, n) {
+							break
+						}
+					}
+
+					// Finish earlier XRs last so completion order is the reverse of input order.
+					idx := slices.Index(names, res.GetName())
+					time.Sleep(time.Duration(len(names)-idx) * time.Millisecond)
+
+					switch res.GetName() {
+					case "xr-b":
+						return nil, errors.New("render failed")
+					case "xr-d":
+						return map[string]*dt.ResourceDiff{
+							"XR1/xr-d": {DiffType: dt.DiffTypeModified},
+						}, nil
+					default:
+						return map[string]*dt.ResourceDiff{}, nil
+					}
+				},
+			}
+
+			processor := &DefaultCompDiffProcessor{
+				compositionClient: tu.NewMockCompositionClient().Build(),
+				xrProc:            mockXRProc,
+				config: ProcessorConfig{
+					Logger:           tu.TestLogger(t, false),
+					MaxConcurrentXRs: tt.maxConcurrentXRs,
+				},
+			}
+
+			results := processor.collectXRDiffs(ctx, xrs, comp)
+			impacts, summary := processor.buildImpactAnalysis(xrs, results)
+
+			if got, limit := int(maxInFlight.Load()), max(tt.maxConcurrentXRs, 1); got > limit {
+				t.Errorf("\n%s\ncollectXRDiffs(...): %d XRs diffed at once, want at most %d", tt.reason, got, limit)
+			}
+
+			gotOrder := make([]string, 0, len(impacts))
+			for _, impact := range impacts {
+				gotOrder = append(gotOrder, fmt.Sprintf("%s=%s", impact.Name, impact.Status))
+			}
+
+			wantOrder := []string{
+				"xr-a=" + string(renderer.XRStatusUnchanged),
+				"xr-b=" + string(renderer.XRStatusError),
+				"xr-c=" + string(renderer.XRStatusUnchanged),
+				"xr-d=" + string(renderer.XRStatusChanged),
+				"xr-e=" + string(renderer.XRStatusUnchanged),
+				"xr-f=" + string(renderer.XRStatusUnchanged),
+			}
+
+			if diff := gcmp.Diff(wantOrder, gotOrder); diff != "" {
+				t.Errorf("\n%s\nbuildImpactAnalysis(...): -want impacts, +got impacts:\n%s", tt.reason, diff)
+			}
+
+			wantSummary := renderer.AffectedResourcesSummary{Total: 6, WithChanges: 1, WithErrors: 1, Unchanged: 4}
+			if diff := gcmp.Diff(wantSummary, summary); diff != "" {
+				t.Errorf("\n%s\nbuildImpactAnalysis(...): -want summary, +got summary:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+// TestDefaultCompDiffProcessor_collectXRDiffs_Race diffs many XRs at the default concurrency of
+// one per CPU, so that concurrent writes to the shared results map, and the progress line read
+// alongside them, are exercised. It's meant to be run with -race, which reports any unsynchronized
+// access; without it, it only checks that every XR's result is collected.
+func TestDefaultCompDiffProcessor_collectXRDiffs_Race(t *testing.T) {
+	comp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		WithPipelineMode().
+		BuildAsUnstructured()
+
+	xrs := make([]*un.Unstructured, 0, 500)
+	for i := range cap(xrs) {
+		xrs = append(xrs, tu.NewResource("example.org/v1", "XR1", fmt.Sprintf("xr-%03d", i)).InNamespace("default").Build())
+	}
+
+	processor := &DefaultCompDiffProcessor{
+		compositionClient: tu.NewMockCompositionClient().Build(),
+		xrProc: &tu.MockDiffProcessor{
+			DiffSingleResourceFn: func(_ context.Context, res *un.Unstructured, _ types.CompositionProvider) (map[string]*dt.ResourceDiff, error) {
+				if strings.HasSuffix(res.GetName(), "7") {
+					return nil, errors.New("render failed")
+				}
+
+				return map[string]*dt.ResourceDiff{
+					dt.MakeDiffKeyFromResource(res): {ResourceName: res.GetName(), DiffType: dt.DiffTypeModified},
+				}, nil
+			},
+		},
+		config: ProcessorConfig{
+			Logger:           tu.TestLogger(t, false),
+			MaxConcurrentXRs: runtime.NumCPU(),
+		},
+	}
+
+	results := processor.collectXRDiffs(t.Context(), xrs, comp)
+
+	if len(results) != len(xrs) {
+		t.Fatalf("collectXRDiffs(...): got %d results, want one for each of %d XRs", len(results), len(xrs))
+	}
+
+	for _, xr := range xrs {
+		key := dt.MakeDiffKeyFromResource(xr)
+
+		result, ok := results[key]
+		if !ok {
+			t.Errorf("collectXRDiffs(...): no result for %s", key)
+			continue
+		}
+
+		if wantErr := strings.HasSuffix(xr.GetName(), "7"); (result.Error != nil) != wantErr {
+			t.Errorf("collectXRDiffs(...): %s: got error %v, want error %t", key, result.Error, wantErr)
+		}
+	}
+}
+
+func TestDefaultCompDiffProcessor_compositionFails(t *testing.T) {
+	modified := &dt.ResourceDiff{DiffType: dt.DiffTypeModified}
+	changedXR := renderer.XRImpact{
+		Status: renderer.XRStatusChanged,
+		Diffs: map[string]*dt.ResourceDiff{
+			"xr":     {DiffType: dt.DiffTypeEqual},
+			"bucket": {DiffType: dt.DiffTypeRemoved},
+		},
+	}
+
+	tests := map[string]struct {
+		reason string
+		failOn []dt.DiffType
+		diff   *renderer.CompositionDiff
+		want   bool
+	}{
+		"Unchanged": {
+			reason: "A composition with no diff and no changed XRs should never fail.",
+			diff:   &renderer.CompositionDiff{ImpactAnalysis: []renderer.XRImpact{{Status: renderer.XRStatusUnchanged}}},
+		},
+		"AnyChange": {
+			reason: "Without --fail-on a modified composition should fail.",
+			diff:   &renderer.CompositionDiff{CompositionDiff: modified},
+			want:   true,
+		},
+		"CompositionNotListed": {
+			reason: "A modified composition shouldn't fail when modified isn't a --fail-on type.",
+			failOn: []dt.DiffType{dt.DiffTypeRemoved},
+			diff:   &renderer.CompositionDiff{CompositionDiff: modified},
+		},
+		"DownstreamRemoval": {
+			reason: "A resource a changed XR would remove should fail with --fail-on=removed.",
+			failOn: []dt.DiffType{dt.DiffTypeRemoved},
+			diff:   &renderer.CompositionDiff{CompositionDiff: modified, ImpactAnalysis: []renderer.XRImpact{changedXR}},
+			want:   true,
+		},
+		"None": {
+			reason: "No change should fail with --fail-on=none.",
+			failOn: []dt.DiffType{},
+			diff:   &renderer.CompositionDiff{CompositionDiff: modified, ImpactAnalysis: []renderer.XRImpact{changedXR}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &DefaultCompDiffProcessor{config: ProcessorConfig{FailOn: tt.failOn}}
+
+			if got := p.compositionFails(tt.diff); got != tt.want {
+				t.Errorf("\n%s\ncompositionFails(...) = %t, want %t", tt.reason, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestDefaultCompDiffProcessor_DiffComposition_StderrErrorOutput verifies that when
 // XR processing fails, detailed errors are written to stderr for human visibility.
 // This tests the WithStderr option and the stderr error output path.
@@ -1057,3 +1408,296 @@ func TestDefaultCompDiffProcessor_DiffComposition_ResourceMode(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultCompDiffProcessor_detectStepReorder(t *testing.T) {
+	clusterComp := tu.NewComposition("test-composition").
+		WithCompositeTypeRef("example.org/v1", "XResource").
+		WithPipelineMode().
+		WithPipelineStep("render", "function-patch-and-transform", nil).
+		WithPipelineStep("ready", "function-auto-ready", nil).
+		Build()
+
+	tests := map[string]struct {
+		reason  string
+		newComp *un.Unstructured
+		want    *renderer.StepReorder
+	}{
+		"Reordered": {
+			reason: "The same steps in a different order should be reported as a reorder.",
+			newComp: tu.NewComposition("test-composition").
+				WithCompositeTypeRef("example.org/v1", "XResource").
+				WithPipelineMode().
+				WithPipelineStep("ready", "function-auto-ready", nil).
+				WithPipelineStep("render", "function-patch-and-transform", map[string]any{"changed": true}).
+				BuildAsUnstructured(),
+			want: &renderer.StepReorder{From: []string{"render", "ready"}, To: []string{"ready", "render"}},
+		},
+		"SameOrder": {
+			reason: "Steps in the same order should not be reported, even if their content changed.",
+			newComp: tu.NewComposition("test-composition").
+				WithCompositeTypeRef("example.org/v1", "XResource").
+				WithPipelineMode().
+				WithPipelineStep("render", "function-patch-and-transform", map[string]any{"changed": true}).
+				WithPipelineStep("ready", "function-auto-ready", nil).
+				BuildAsUnstructured(),
+		},
+		"StepAdded": {
+			reason: "Adding a step changes the step set, so it is not a reorder.",
+			newComp: tu.NewComposition("test-composition").
+				WithCompositeTypeRef("example.org/v1", "XResource").
+				WithPipelineMode().
+				WithPipelineStep("ready", "function-auto-ready", nil).
+				WithPipelineStep("render", "function-patch-and-transform", nil).
+				WithPipelineStep("extra", "function-extra", nil).
+				BuildAsUnstructured(),
+		},
+		"NewComposition": {
+			reason: "A composition that isn't in the cluster yet has no order to compare against.",
+			newComp: tu.NewComposition("other-composition").
+				WithCompositeTypeRef("example.org/v1", "XResource").
+				WithPipelineMode().
+				WithPipelineStep("ready", "function-auto-ready", nil).
+				WithPipelineStep("render", "function-patch-and-transform", nil).
+				BuildAsUnstructured(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			processor := &DefaultCompDiffProcessor{
+				compositionClient: tu.NewMockCompositionClient().
+					WithSuccessfulCompositionFetch(clusterComp).
+					Build(),
+				config: ProcessorConfig{
+					Logger: tu.TestLogger(t, false),
+				},
+			}
+
+			got := processor.detectStepReorder(t.Context(), tt.newComp)
+			if diff := gcmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\ndetectStepReorder(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSortImpacts(t *testing.T) {
+	impact := func(kind, namespace, name string) renderer.XRImpact {
+		return renderer.XRImpact{ObjectReference: corev1.ObjectReference{Kind: kind, Namespace: namespace, Name: name}}
+	}
+
+	impacts := []renderer.XRImpact{
+		impact("XR", "team-b", "a"),
+		impact("XR", "team-a", "b"),
+		impact("XR", "", "z"),
+		impact("Claim", "team-a", "a"),
+		impact("XR", "team-a", "a"),
+	}
+
+	want := []renderer.XRImpact{
+		impact("XR", "", "z"),
+		impact("Claim", "team-a", "a"),
+		impact("XR", "team-a", "a"),
+		impact("XR", "team-a", "b"),
+		impact("XR", "team-b", "a"),
+	}
+
+	sortImpacts(impacts)
+
+	if diff := gcmp.Diff(want, impacts); diff != "" {
+		t.Errorf("sortImpacts() should order by namespace then name, keeping ties in place: -want, +got:\n%s", diff)
+	}
+}
+
+func TestDefaultCompDiffProcessor_buildImpactAnalysis_ManualPolicy(t *testing.T) {
+	xrs := []*un.Unstructured{
+		tu.NewResource("example.org/v1", "XResource", "manual-xr").WithNamespace("default").
+			WithNestedField("Manual", "spec", "crossplane", "compositionUpdatePolicy").Build(),
+		tu.NewResource("example.org/v1", "XResource", "auto-xr").WithNamespace("default").
+			WithNestedField("Automatic", "spec", "crossplane", "compositionUpdatePolicy").Build(),
+		tu.NewResource("example.org/v1", "XResource", "default-xr").WithNamespace("default").Build(),
+	}
+
+	processor := &DefaultCompDiffProcessor{
+		config: ProcessorConfig{IncludeManual: true, Logger: tu.TestLogger(t, false)},
+	}
+
+	impacts, _ := processor.buildImpactAnalysis(xrs, map[string]*XRDiffResult{})
+
+	got := map[string]bool{}
+	for _, impact := range impacts {
+		got[impact.Name] = impact.ManualPolicy
+	}
+
+	want := map[string]bool{"manual-xr": true, "auto-xr": false, "default-xr": false}
+	if diff := gcmp.Diff(want, got); diff != "" {
+		t.Errorf("Only XRs with the Manual update policy should be marked: -want, +got:\n%s", diff)
+	}
+}
+
+func TestDefaultCompDiffProcessor_collectXRDiffs_Progress(t *testing.T) {
+	comp := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", "XR1").
+		WithPipelineMode().
+		BuildAsUnstructured()
+
+	xrs := []*un.Unstructured{
+		tu.NewResource("example.org/v1", "XR1", "xr-a").Build(),
+		tu.NewResource("example.org/v1", "XR1", "xr-b").Build(),
+	}
+
+	tests := map[string]struct {
+		reason   string
+		progress bool
+		colorize bool
+		want     string
+	}{
+		"Off": {
+			reason: "Nothing should be written to stderr without progress reporting.",
+			want:   "",
+		},
+		"On": {
+			reason:   "Each analyzed XR should rewrite the progress line, which is blanked at the end.",
+			progress: true,
+			want:     "\rAnalyzing 0/2 XRs...\rAnalyzing 1/2 XRs...\rAnalyzing 2/2 XRs...\r" + strings.Repeat(" ", 20) + "\r",
+		},
+		"Colorized": {
+			reason:   "With colors on, the progress line should be colored.",
+			progress: true,
+			colorize: true,
+			want: "\r" + dt.ColorYellow + "Analyzing 0/2 XRs..." + dt.ColorReset +
+				"\r" + dt.ColorYellow + "Analyzing 1/2 XRs..." + dt.ColorReset +
+				"\r" + dt.ColorYellow + "Analyzing 2/2 XRs..." + dt.ColorReset +
+				"\r" + strings.Repeat(" ", 20) + "\r",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stderr bytes.Buffer
+
+			processor := &DefaultCompDiffProcessor{
+				compositionClient: tu.NewMockCompositionClient().Build(),
+				xrProc: &tu.MockDiffProcessor{
+					DiffSingleResourceFn: func(context.Context, *un.Unstructured, types.CompositionProvider) (map[string]*dt.ResourceDiff, error) {
+						return map[string]*dt.ResourceDiff{}, nil
+					},
+				},
+				config: ProcessorConfig{
+					Logger:   tu.TestLogger(t, false),
+					Stderr:   &stderr,
+					Progress: tt.progress,
+					Colorize: tt.colorize,
+				},
+			}
+
+			processor.collectXRDiffs(t.Context(), xrs, comp)
+
+			if diff := gcmp.Diff(tt.want, stderr.String()); diff != "" {
+				t.Errorf("\n%s\ncollectXRDiffs(...) stderr: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffRenderings(t *testing.T) {
+	res := func(name, value string) *un.Unstructured {
+		return tu.NewResource("example.org/v1", "ComposedResource", name).WithSpecField("value", value).Build()
+	}
+
+	rendered := func(diffType dt.DiffType, desired *un.Unstructured) *dt.ResourceDiff {
+		return &dt.ResourceDiff{DiffType: diffType, Desired: dt.ResourceViews{Raw: desired}}
+	}
+
+	tests := map[string]struct {
+		reason string
+		before map[string]*dt.ResourceDiff
+		after  map[string]*dt.ResourceDiff
+		want   map[string]dt.DiffType
+	}{
+		"Unchanged": {
+			reason: "A resource both compositions render the same way should be equal, even if it differs from the cluster.",
+			before: map[string]*dt.ResourceDiff{"a": rendered(dt.DiffTypeModified, res("a", "x"))},
+			after:  map[string]*dt.ResourceDiff{"a": rendered(dt.DiffTypeModified, res("a", "x"))},
+			want:   map[string]dt.DiffType{"a": dt.DiffTypeEqual},
+		},
+		"Modified": {
+			reason: "A resource the compositions render differently should be modified.",
+			before: map[string]*dt.ResourceDiff{"a": rendered(dt.DiffTypeEqual, res("a", "x"))},
+			after:  map[string]*dt.ResourceDiff{"a": rendered(dt.DiffTypeModified, res("a", "y"))},
+			want:   map[string]dt.DiffType{"a": dt.DiffTypeModified},
+		},
+		"Added": {
+			reason: "A resource only the updated composition renders should be added.",
+			before: map[string]*dt.ResourceDiff{},
+			after:  map[string]*dt.ResourceDiff{"b": rendered(dt.DiffTypeAdded, res("b", "x"))},
+			want:   map[string]dt.DiffType{"b": dt.DiffTypeAdded},
+		},
+		"Removed": {
+			reason: "A resource the updated composition would remove from the cluster should be removed.",
+			before: map[string]*dt.ResourceDiff{"b": rendered(dt.DiffTypeEqual, res("b", "x"))},
+			after:  map[string]*dt.ResourceDiff{"b": {DiffType: dt.DiffTypeRemoved, Current: dt.ResourceViews{Raw: res("b", "x")}}},
+			want:   map[string]dt.DiffType{"b": dt.DiffTypeRemoved},
+		},
+		"RemovedByBoth": {
+			reason: "A resource neither composition renders should be left out.",
+			before: map[string]*dt.ResourceDiff{"c": {DiffType: dt.DiffTypeRemoved, Current: dt.ResourceViews{Raw: res("c", "x")}}},
+			after:  map[string]*dt.ResourceDiff{"c": {DiffType: dt.DiffTypeRemoved, Current: dt.ResourceViews{Raw: res("c", "x")}}},
+			want:   map[string]dt.DiffType{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := diffRenderings(t.Context(), tt.before, tt.after, tu.TestLogger(t, false), renderer.DefaultDiffOptions())
+			if err != nil {
+				t.Fatalf("\n%s\ndiffRenderings(...): unexpected error: %v", tt.reason, err)
+			}
+
+			got := make(map[string]dt.DiffType, len(diffs))
+			for key, d := range diffs {
+				got[key] = d.DiffType
+			}
+
+			if diff := gcmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\ndiffRenderings(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultCompDiffProcessor_originalComposition(t *testing.T) {
+	clusterComp := tu.NewComposition("test-composition").
+		WithCompositeTypeRef("example.org/v1", "XResource").
+		Build()
+	againstComp := tu.NewComposition("test-composition").
+		WithCompositeTypeRef("example.org/v1", "XOther").
+		BuildAsUnstructured()
+
+	processor := &DefaultCompDiffProcessor{
+		compositionClient: tu.NewMockCompositionClient().
+			WithSuccessfulCompositionFetch(clusterComp).
+			Build(),
+		config: ProcessorConfig{Logger: tu.TestLogger(t, false)},
+	}
+
+	got, err := processor.originalComposition(t.Context(), "test-composition")
+	if err != nil {
+		t.Fatalf("originalComposition(...): unexpected error: %v", err)
+	}
+
+	if got.Spec.CompositeTypeRef.Kind != "XResource" {
+		t.Errorf("originalComposition(...): without --against want the cluster composition, got type %q", got.Spec.CompositeTypeRef.Kind)
+	}
+
+	WithAgainstCompositions([]*un.Unstructured{againstComp})(&processor.config)
+
+	got, err = processor.originalComposition(t.Context(), "test-composition")
+	if err != nil {
+		t.Fatalf("originalComposition(...): unexpected error: %v", err)
+	}
+
+	if got.Spec.CompositeTypeRef.Kind != "XOther" {
+		t.Errorf("originalComposition(...): with --against want the supplied composition, got type %q", got.Spec.CompositeTypeRef.Kind)
+	}
+}
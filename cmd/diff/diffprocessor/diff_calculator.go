@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"maps"
 
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
@@ -12,6 +13,7 @@ import (
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
@@ -118,12 +120,18 @@ func (c *DefaultDiffCalculator) CalculateDiff(ctx context.Context, composite *un
 	// Determine what the resource would look like after application
 	wouldBeResult := desired
 
+	var warnings []string
+
 	if current != nil {
 		// Extract the Crossplane field owner from the existing object's managedFields.
 		// This ensures our dry-run apply uses the same field owner as Crossplane,
 		// which correctly handles field removal detection (SSA removes fields that
 		// are owned by this manager but not present in the apply request).
+		// --field-manager overrides it to simulate another owner.
 		fieldOwner := k8.GetComposedFieldOwner(current)
+		if c.diffOptions.FieldManager != "" {
+			fieldOwner = c.diffOptions.FieldManager
+		}
 
 		// Deep-copy before stripping ownerReferences so the rendered desired (used
 		// for downstream diff comparison) isn't mutated.
@@ -140,20 +148,45 @@ func (c *DefaultDiffCalculator) CalculateDiff(ctx context.Context, composite *un
 		// ownerRefs still surface in the diff output.
 		un.RemoveNestedField(applyDesired.Object, "metadata", "ownerReferences")
 
-		// Perform a dry-run apply to get the result after we'd apply
-		c.logger.Debug("Performing dry-run apply",
-			"resource", resourceID,
-			"name", desired.GetName(),
-			"fieldOwner", fieldOwner,
-			"desired", applyDesired)
+		if !c.dryRunEnabled(desired.GroupVersionKind()) {
+			// The kind is excluded from dry-run, so approximate the apply by
+			// overlaying the desired object on the current one locally. Fields
+			// the API server would default or remove aren't reflected.
+			c.logger.Debug("Skipping dry-run apply for kind; merging locally", "resource", resourceID)
 
-		wouldBeResult, err = c.applyClient.DryRunApply(ctx, applyDesired, fieldOwner)
-		if err != nil {
-			c.logger.Debug("Dry-run apply failed", "resource", resourceID, "error", err)
-			return nil, errors.Wrap(err, "cannot dry-run apply desired object")
+			wouldBeResult, err = mergeUnstructured(current, applyDesired)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot merge desired object onto current object")
+			}
+		} else {
+			// Perform a dry-run apply to get the result after we'd apply
+			c.logger.Debug("Performing dry-run apply",
+				"resource", resourceID,
+				"name", desired.GetName(),
+				"fieldOwner", fieldOwner,
+				"desired", applyDesired)
+
+			applyCtx, recorder := core.WithWarningRecorder(ctx)
+
+			wouldBeResult, err = c.applyClient.DryRunApply(applyCtx, applyDesired, fieldOwner, c.diffOptions.ForceConflicts)
+			if err != nil {
+				c.logger.Debug("Dry-run apply failed", "resource", resourceID, "error", err)
+				return nil, errors.Wrap(err, "cannot dry-run apply desired object")
+			}
+
+			warnings = recorder.Warnings()
+			if len(warnings) > 0 {
+				c.logger.Debug("Dry-run apply returned warnings", "resource", resourceID, "warnings", warnings)
+			}
+
+			c.logger.Debug("Dry-run apply succeeded", "resource", resourceID, "result", wouldBeResult)
 		}
 
-		c.logger.Debug("Dry-run apply succeeded", "resource", resourceID, "result", wouldBeResult)
+		if c.diffOptions.IgnoreManagedFields {
+			if kept := PreserveForeignFields(current, wouldBeResult, fieldOwner); len(kept) > 0 {
+				c.logger.Debug("Kept fields owned by other field managers", "resource", resourceID, "paths", kept)
+			}
+		}
 	}
 
 	// Generate diff with the configured options
@@ -163,6 +196,10 @@ func (c *DefaultDiffCalculator) CalculateDiff(ctx context.Context, composite *un
 		return nil, err
 	}
 
+	if diff != nil && c.diffOptions.ShowWarnings {
+		diff.Warnings = warnings
+	}
+
 	// Log the outcome
 	if diff != nil {
 		c.logger.Debug("Diff generated",
@@ -174,6 +211,16 @@ func (c *DefaultDiffCalculator) CalculateDiff(ctx context.Context, composite *un
 	return diff, nil
 }
 
+// dryRunEnabled reports whether resources of the given kind go through a dry-run
+// apply, per the DryRunKinds and NoDryRunKinds diff options.
+func (c *DefaultDiffCalculator) dryRunEnabled(gvk schema.GroupVersionKind) bool {
+	if len(c.diffOptions.DryRunKinds) > 0 && !renderer.MatchesKind(gvk, c.diffOptions.DryRunKinds) {
+		return false
+	}
+
+	return !renderer.MatchesKind(gvk, c.diffOptions.NoDryRunKinds)
+}
+
 // CalculateNonRemovalDiffs computes diffs for modified/added resources and returns
 // the set of rendered resource keys for removal detection.
 //
@@ -374,10 +421,16 @@ func (c *DefaultDiffCalculator) CalculateRemovedResourceDiffs(ctx context.Contex
 		return nil, errors.Wrap(err, "cannot get resource tree")
 	}
 
-	// Create a handler function to recursively traverse the tree and find composed resources
-	var findRemovedResources func(node *resource.Resource)
+	// Create a handler function to recursively traverse the tree and find composed resources.
+	// owner is the diff key of the composed resource the node was found under, if any, and
+	// ownerRemoved whether that resource would itself be removed.
+	var findRemovedResources func(node *resource.Resource, owner string, ownerRemoved bool)
+
+	findRemovedResources = func(node *resource.Resource, owner string, ownerRemoved bool) {
+		var key string
+
+		removed := false
 
-	findRemovedResources = func(node *resource.Resource) {
 		// Skip the root (XR) node
 		if _, hasAnno := node.Unstructured.GetAnnotations()["crossplane.io/composition-resource-name"]; hasAnno {
 			apiVersion := node.Unstructured.GetAPIVersion()
@@ -386,11 +439,17 @@ func (c *DefaultDiffCalculator) CalculateRemovedResourceDiffs(ctx context.Contex
 			resourceID := fmt.Sprintf("%s/%s", kind, name)
 
 			// Use the same key format as in CalculateDiffs to check if this resource was rendered
-			key := dt.MakeDiffKey(apiVersion, kind, node.Unstructured.GetNamespace(), name)
+			key = dt.MakeDiffKey(apiVersion, kind, node.Unstructured.GetNamespace(), name)
 
 			if !renderedResources[key] {
 				// This resource exists but wasn't rendered - it will be removed
-				c.logger.Debug("Resource will be removed", "resource", resourceID)
+				removed = true
+				reason := dt.RemovalReasonNoLongerComposed
+				if ownerRemoved {
+					reason = dt.RemovalReasonOwnerRemoved
+				}
+
+				c.logger.Debug("Resource will be removed", "resource", resourceID, "reason", reason)
 
 				diff, err := renderer.GenerateDiffWithOptions(ctx, &node.Unstructured, nil, c.logger, c.diffOptions)
 				if err != nil {
@@ -402,6 +461,8 @@ func (c *DefaultDiffCalculator) CalculateRemovedResourceDiffs(ctx context.Contex
 				}
 
 				if diff != nil {
+					diff.Owner = owner
+					diff.RemovalReason = reason
 					diffKey := diff.GetDiffKey()
 					removedDiffs[diffKey] = diff
 				}
@@ -410,13 +471,13 @@ func (c *DefaultDiffCalculator) CalculateRemovedResourceDiffs(ctx context.Contex
 
 		// Continue recursively traversing children
 		for _, child := range node.Children {
-			findRemovedResources(child)
+			findRemovedResources(child, key, removed)
 		}
 	}
 
 	// Start the traversal from the root's children to skip the XR itself
 	for _, child := range resourceTree.Children {
-		findRemovedResources(child)
+		findRemovedResources(child, "", false)
 	}
 
 	c.logger.Debug("Found resources to be removed", "count", len(removedDiffs))
@@ -5,11 +5,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
 	gcmp "github.com/google/go-cmp/cmp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -264,7 +266,7 @@ func TestDefaultDiffCalculator_CalculateDiff(t *testing.T) {
 
 				// Create mock apply client that captures and verifies the field owner
 				applyClient := tu.NewMockApplyClient().
-					WithDryRunApply(func(_ context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error) {
+					WithDryRunApply(func(_ context.Context, obj *un.Unstructured, fieldOwner string, _ bool) (*un.Unstructured, error) {
 						// Verify the field owner was correctly extracted
 						if fieldOwner != expectedFieldOwner {
 							t.Errorf("DryRunApply called with wrong field owner: got %q, want %q", fieldOwner, expectedFieldOwner)
@@ -312,7 +314,7 @@ func TestDefaultDiffCalculator_CalculateDiff(t *testing.T) {
 				// Create mock apply client that captures and verifies the field owner is empty
 				// (which means the default will be used)
 				applyClient := tu.NewMockApplyClient().
-					WithDryRunApply(func(_ context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error) {
+					WithDryRunApply(func(_ context.Context, obj *un.Unstructured, fieldOwner string, _ bool) (*un.Unstructured, error) {
 						// Verify no Crossplane field owner was extracted (defaults to empty)
 						if fieldOwner != "" {
 							t.Errorf("DryRunApply called with unexpected field owner: got %q, want empty string", fieldOwner)
@@ -500,6 +502,278 @@ func TestDefaultDiffCalculator_CalculateDiff(t *testing.T) {
 	}
 }
 
+func TestDefaultDiffCalculator_CalculateDiff_Warnings(t *testing.T) {
+	existing := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "old-value").
+		Build()
+
+	desired := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "new-value").
+		Build()
+
+	tests := map[string]struct {
+		reason       string
+		showWarnings bool
+		want         []string
+	}{
+		"Shown": {
+			reason:       "Warnings returned by the dry-run apply should be attached to the diff, deduplicated.",
+			showWarnings: true,
+			want:         []string{"example.org/v1 TestResource is deprecated"},
+		},
+		"Hidden": {
+			reason: "Warnings should not be attached unless requested.",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			applyClient := tu.NewMockApplyClient().
+				WithDryRunApply(func(ctx context.Context, obj *un.Unstructured, _ string, _ bool) (*un.Unstructured, error) {
+					for range 2 {
+						core.ContextWarningHandler{}.HandleWarningHeaderWithContext(ctx, 299, "-", "example.org/v1 TestResource is deprecated")
+					}
+
+					return obj, nil
+				}).
+				Build()
+
+			resourceManager := NewResourceManager(
+				tu.NewMockResourceClient().WithResourcesExist(existing).Build(),
+				tu.NewMockDefinitionClient().Build(),
+				tu.NewMockResourceTreeClient().Build(),
+				tu.TestLogger(t, false),
+			)
+
+			opts := renderer.DefaultDiffOptions()
+			opts.ShowWarnings = tt.showWarnings
+
+			calculator := NewDiffCalculator(applyClient, tu.NewMockResourceTreeClient().Build(), resourceManager, tu.TestLogger(t, false), opts)
+
+			diff, err := calculator.CalculateDiff(t.Context(), nil, desired)
+			if err != nil {
+				t.Fatalf("\n%s\nCalculateDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if d := gcmp.Diff(tt.want, diff.Warnings); d != "" {
+				t.Errorf("\n%s\nCalculateDiff(...): -want warnings, +got warnings:\n%s", tt.reason, d)
+			}
+		})
+	}
+}
+
+func TestDefaultDiffCalculator_CalculateDiff_DryRunKinds(t *testing.T) {
+	existing := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "old-value").
+		WithSpecField("defaulted", "by-server").
+		Build()
+
+	desired := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "new-value").
+		Build()
+
+	tests := map[string]struct {
+		reason        string
+		dryRunKinds   []string
+		noDryRunKinds []string
+		wantDryRun    bool
+	}{
+		"NoFilters": {
+			reason:     "Without filters every kind should go through a dry-run apply.",
+			wantDryRun: true,
+		},
+		"AllowedByKind": {
+			reason:      "A kind in the allowlist should go through a dry-run apply.",
+			dryRunKinds: []string{"TestResource"},
+			wantDryRun:  true,
+		},
+		"AllowedByKindAndGroup": {
+			reason:      "A Kind.group entry should match the kind in that group.",
+			dryRunKinds: []string{"TestResource.example.org"},
+			wantDryRun:  true,
+		},
+		"NotInAllowlist": {
+			reason:      "A kind missing from the allowlist should be diffed locally.",
+			dryRunKinds: []string{"TestResource.other.org", "Bucket"},
+		},
+		"Excluded": {
+			reason:        "An excluded kind should be diffed locally.",
+			noDryRunKinds: []string{"TestResource"},
+		},
+		"ExcludedWinsOverAllowed": {
+			reason:        "An exclusion should take precedence over the allowlist.",
+			dryRunKinds:   []string{"TestResource"},
+			noDryRunKinds: []string{"TestResource.example.org"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dryRun := false
+			applyClient := tu.NewMockApplyClient().
+				WithDryRunApply(func(_ context.Context, obj *un.Unstructured, _ string, _ bool) (*un.Unstructured, error) {
+					dryRun = true
+					return obj, nil
+				}).
+				Build()
+
+			resourceManager := NewResourceManager(
+				tu.NewMockResourceClient().WithResourcesExist(existing).Build(),
+				tu.NewMockDefinitionClient().Build(),
+				tu.NewMockResourceTreeClient().Build(),
+				tu.TestLogger(t, false),
+			)
+
+			opts := renderer.DefaultDiffOptions()
+			opts.DryRunKinds = tt.dryRunKinds
+			opts.NoDryRunKinds = tt.noDryRunKinds
+
+			calculator := NewDiffCalculator(applyClient, tu.NewMockResourceTreeClient().Build(), resourceManager, tu.TestLogger(t, false), opts)
+
+			diff, err := calculator.CalculateDiff(t.Context(), nil, desired)
+			if err != nil {
+				t.Fatalf("\n%s\nCalculateDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if dryRun != tt.wantDryRun {
+				t.Errorf("\n%s\nCalculateDiff(...): dry-run apply called = %t, want %t", tt.reason, dryRun, tt.wantDryRun)
+			}
+
+			if diff.DiffType != dt.DiffTypeModified {
+				t.Errorf("\n%s\nCalculateDiff(...): diff type = %s, want %s", tt.reason, diff.DiffType, dt.DiffTypeModified)
+			}
+
+			if tt.wantDryRun {
+				return
+			}
+
+			// A local merge keeps fields from the current object that the desired object omits.
+			got, _, _ := un.NestedString(diff.Desired.Raw.Object, "spec", "defaulted")
+			if got != "by-server" {
+				t.Errorf("\n%s\nCalculateDiff(...): spec.defaulted = %q, want %q", tt.reason, got, "by-server")
+			}
+		})
+	}
+}
+
+func TestDefaultDiffCalculator_CalculateDiff_FieldManager(t *testing.T) {
+	const composedOwner = "apiextensions.crossplane.io/composed/abc123def456"
+
+	existing := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "old-value").
+		WithFieldManagers(composedOwner).
+		Build()
+
+	desired := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "new-value").
+		Build()
+
+	tests := map[string]struct {
+		reason       string
+		fieldManager string
+		want         string
+	}{
+		"Default": {
+			reason: "Without --field-manager the resource's Crossplane field owner should be used.",
+			want:   composedOwner,
+		},
+		"Override": {
+			reason:       "--field-manager should replace the resource's Crossplane field owner.",
+			fieldManager: "argocd-controller",
+			want:         "argocd-controller",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got string
+
+			applyClient := tu.NewMockApplyClient().
+				WithDryRunApply(func(_ context.Context, obj *un.Unstructured, fieldOwner string, _ bool) (*un.Unstructured, error) {
+					got = fieldOwner
+					return obj, nil
+				}).
+				Build()
+
+			resourceManager := NewResourceManager(
+				tu.NewMockResourceClient().WithResourcesExist(existing).Build(),
+				tu.NewMockDefinitionClient().Build(),
+				tu.NewMockResourceTreeClient().Build(),
+				tu.TestLogger(t, false),
+			)
+
+			opts := renderer.DefaultDiffOptions()
+			opts.FieldManager = tt.fieldManager
+
+			calculator := NewDiffCalculator(applyClient, tu.NewMockResourceTreeClient().Build(), resourceManager, tu.TestLogger(t, false), opts)
+
+			if _, err := calculator.CalculateDiff(t.Context(), nil, desired); err != nil {
+				t.Fatalf("\n%s\nCalculateDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("\n%s\nCalculateDiff(...): dry-run field manager = %q, want %q", tt.reason, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultDiffCalculator_CalculateDiff_ForceConflicts(t *testing.T) {
+	existing := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "old-value").
+		Build()
+
+	desired := tu.NewResource("example.org/v1", "TestResource", "existing-resource").
+		WithSpecField("field", "new-value").
+		Build()
+
+	tests := map[string]struct {
+		reason string
+		force  bool
+	}{
+		"Default": {
+			reason: "Without --force-conflicts the dry-run apply shouldn't force conflicts.",
+		},
+		"Forced": {
+			reason: "--force-conflicts should be passed to the dry-run apply.",
+			force:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got bool
+
+			applyClient := tu.NewMockApplyClient().
+				WithDryRunApply(func(_ context.Context, obj *un.Unstructured, _ string, force bool) (*un.Unstructured, error) {
+					got = force
+					return obj, nil
+				}).
+				Build()
+
+			resourceManager := NewResourceManager(
+				tu.NewMockResourceClient().WithResourcesExist(existing).Build(),
+				tu.NewMockDefinitionClient().Build(),
+				tu.NewMockResourceTreeClient().Build(),
+				tu.TestLogger(t, false),
+			)
+
+			opts := renderer.DefaultDiffOptions()
+			opts.ForceConflicts = tt.force
+
+			calculator := NewDiffCalculator(applyClient, tu.NewMockResourceTreeClient().Build(), resourceManager, tu.TestLogger(t, false), opts)
+
+			if _, err := calculator.CalculateDiff(t.Context(), nil, desired); err != nil {
+				t.Fatalf("\n%s\nCalculateDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if got != tt.force {
+				t.Errorf("\n%s\nCalculateDiff(...): dry-run force = %t, want %t", tt.reason, got, tt.force)
+			}
+		})
+	}
+}
+
 func TestDefaultDiffCalculator_CalculateDiffs(t *testing.T) {
 	ctx := t.Context()
 
@@ -1001,6 +1275,62 @@ func TestDefaultDiffCalculator_CalculateRemovedResourceDiffs(t *testing.T) {
 	}
 }
 
+func TestDefaultDiffCalculator_CalculateRemovedResourceDiffs_Owner(t *testing.T) {
+	xr := tu.NewResource("example.org/v1", "XR", "test-xr").Build()
+
+	parent := tu.NewResource("example.org/v1", "Composed", "parent").
+		WithCompositeOwner("test-xr").
+		WithCompositionResourceName("parent").
+		Build()
+
+	child := tu.NewResource("example.org/v1", "Composed", "child").
+		WithCompositeOwner("test-xr").
+		WithCompositionResourceName("child").
+		Build()
+
+	tree := &resource.Resource{
+		Unstructured: *xr,
+		Children: []*resource.Resource{{
+			Unstructured: *parent,
+			Children:     []*resource.Resource{{Unstructured: *child}},
+		}},
+	}
+
+	calculator := NewDiffCalculator(
+		tu.NewMockApplyClient().Build(),
+		tu.NewMockResourceTreeClient().WithSuccessfulResourceTreeFetch(tree).Build(),
+		NewResourceManager(tu.NewMockResourceClient().Build(), tu.NewMockDefinitionClient().Build(), tu.NewMockResourceTreeClient().Build(), tu.TestLogger(t, false)),
+		tu.TestLogger(t, false),
+		renderer.DefaultDiffOptions(),
+	)
+
+	diffs, err := calculator.CalculateRemovedResourceDiffs(t.Context(), xr, map[string]bool{})
+	if err != nil {
+		t.Fatalf("CalculateRemovedResourceDiffs(...): unexpected error: %v", err)
+	}
+
+	type removal struct {
+		Owner  string
+		Reason dt.RemovalReason
+	}
+
+	parentKey := dt.MakeDiffKeyFromResource(parent)
+
+	want := map[string]removal{
+		parentKey:                         {Reason: dt.RemovalReasonNoLongerComposed},
+		dt.MakeDiffKeyFromResource(child): {Owner: parentKey, Reason: dt.RemovalReasonOwnerRemoved},
+	}
+
+	got := make(map[string]removal, len(diffs))
+	for key, diff := range diffs {
+		got[key] = removal{Owner: diff.Owner, Reason: diff.RemovalReason}
+	}
+
+	if diff := gcmp.Diff(want, got); diff != "" {
+		t.Errorf("CalculateRemovedResourceDiffs(...): removed resources' owners and reasons should follow the resource tree, -want, +got:\n%s", diff)
+	}
+}
+
 func TestDefaultDiffCalculator_preserveCompositeLabel(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -2,7 +2,9 @@
 package diffprocessor
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"os"
@@ -20,10 +22,15 @@ import (
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
 	clixrgen "github.com/crossplane/cli/v2/cmd/crossplane/xr"
 	clixr "github.com/crossplane/cli/v2/pkg/xr"
+	v1 "github.com/crossplane/function-sdk-go/proto/v1"
+	"github.com/sergi/go-diff/diffmatchpatch"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
@@ -37,17 +44,25 @@ import (
 const (
 	// XR/Claim spec field names used for field-filtered copying during Claim backing XR merge.
 	// These constants prevent typos and make refactoring safer.
-	fieldClaimRef                   = "claimRef"
-	fieldResourceRefs               = "resourceRefs"
-	fieldCompositionRef             = "compositionRef"
-	fieldCompositionSelector        = "compositionSelector"
-	fieldWriteConnectionSecretToRef = "writeConnectionSecretToRef"
-	fieldCompositionRevisionRef     = "compositionRevisionRef"
+	fieldClaimRef                    = "claimRef"
+	fieldResourceRefs                = "resourceRefs"
+	fieldCompositionRef              = "compositionRef"
+	fieldCompositionSelector         = "compositionSelector"
+	fieldWriteConnectionSecretToRef  = "writeConnectionSecretToRef"
+	fieldCompositionRevisionRef      = "compositionRevisionRef"
+	fieldCompositionUpdatePolicy     = "compositionUpdatePolicy"
+	fieldCompositionRevisionSelector = "compositionRevisionSelector"
+	fieldPublishConnectionDetailsTo  = "publishConnectionDetailsTo"
+	fieldEnvironmentConfigRefs       = "environmentConfigRefs"
 
 	// Composition update policy values, mirroring Crossplane's CompositionUpdatePolicy.
 	compositionUpdatePolicyManual = "Manual"
 )
 
+// AnnotationSourceFile records the input file a resource was loaded from. Loaders that track
+// sources set it; PerformDiff strips it before diffing and copies it to each resulting diff.
+const AnnotationSourceFile = "diff.crossplane.io/source-file"
+
 // DiffProcessor interface for processing resources.
 type DiffProcessor interface {
 	// PerformDiff processes resources using a composition provider function.
@@ -71,6 +86,7 @@ type DefaultDiffProcessor struct {
 	credentialClient     xp.CredentialClient
 	defClient            xp.DefinitionClient
 	schemaClient         k8.SchemaClient
+	resourceClient       k8.ResourceClient
 	resourceManager      ResourceManager
 	config               ProcessorConfig
 	functionProvider     FunctionProvider
@@ -100,15 +116,17 @@ func NewDiffProcessor(k8cs k8.Clients, xpcs xp.Clients, opts ...ProcessorOption)
 	}
 
 	// Default the render function to an engine-backed implementation if the
-	// caller didn't override it. Serialization is handled inside engineRenderFn.
+	// caller didn't override it. Render concurrency is bounded inside EngineRenderFn.
 	// We retain the pointer so Cleanup can release the Docker network and
 	// function runtimes owned by the engine.
 	var defaultEngineFn *EngineRenderFn
 	if config.RenderFunc == nil {
-		defaultEngineFn = NewEngineRenderFn(config.Logger, config.CrossplaneRenderBinary, config.CrossplaneVersion, config.CrossplaneImage)
+		defaultEngineFn = NewEngineRenderFn(config.Logger, config.CrossplaneRenderBinary, config.CrossplaneVersion, config.CrossplaneImage, config.MaxConcurrentRenders)
 		config.RenderFunc = defaultEngineFn.Render
 	}
 
+	config.RenderFunc = logRenderResults(config.RenderFunc)
+
 	// Set default factory functions if not provided
 	config.SetDefaultFactories()
 
@@ -118,8 +136,21 @@ func NewDiffProcessor(k8cs k8.Clients, xpcs xp.Clients, opts ...ProcessorOption)
 	// Create components using factories
 	resourceManager := config.Factories.ResourceManager(k8cs.Resource, xpcs.Definition, xpcs.ResourceTree, config.Logger)
 	schemaValidator := config.Factories.SchemaValidator(k8cs.Schema, xpcs.Definition, config.Logger)
-	requirementsProvider := config.Factories.RequirementsProvider(k8cs.Resource, xpcs.Environment, config.Logger)
-	diffCalculator := config.Factories.DiffCalculator(k8cs.Apply, xpcs.ResourceTree, resourceManager, config.Logger, diffOpts)
+
+	// Required resources come from --external-resources-dir when given, rather than the cluster.
+	requirementsClient := k8cs.Resource
+	if len(config.ExternalResources) > 0 {
+		requirementsClient = k8.NewFixtureResourceClient(requirementsClient, config.ExternalResources)
+	}
+
+	requirementsProvider := config.Factories.RequirementsProvider(requirementsClient, xpcs.Environment, config.Logger)
+
+	applyClient := k8cs.Apply
+	if config.DryRunNamespace != "" {
+		applyClient = k8.NewDryRunNamespaceApplyClient(applyClient, k8cs.Resource, config.DryRunNamespace, config.Logger)
+	}
+
+	diffCalculator := config.Factories.DiffCalculator(applyClient, xpcs.ResourceTree, resourceManager, config.Logger, diffOpts)
 	diffRenderer := config.Factories.DiffRenderer(config.Logger, diffOpts)
 
 	functionProvider := config.Factories.FunctionProvider(xpcs.Function, config.Logger)
@@ -132,6 +163,7 @@ func NewDiffProcessor(k8cs k8.Clients, xpcs xp.Clients, opts ...ProcessorOption)
 		credentialClient:     xpcs.Credential,
 		defClient:            xpcs.Definition,
 		schemaClient:         k8cs.Schema,
+		resourceClient:       k8cs.Resource,
 		resourceManager:      resourceManager,
 		config:               config,
 		functionProvider:     functionProvider,
@@ -209,6 +241,10 @@ func (p *DefaultDiffProcessor) PerformDiff(ctx context.Context, resources []*un.
 		return false, nil
 	}
 
+	if p.config.ValidateOnly {
+		return p.performValidation(ctx, resources, compositionProvider)
+	}
+
 	// Collect all diffs across all resources
 	allDiffs := make(map[string]*dt.ResourceDiff)
 
@@ -217,10 +253,36 @@ func (p *DefaultDiffProcessor) PerformDiff(ctx context.Context, resources []*un.
 
 	var errs []error
 
+	// Diff keys of the input resources, which --filter-kind never hides.
+	inputKeys := make(map[string]bool, len(resources))
+
 	for _, res := range resources {
 		resourceID := fmt.Sprintf("%s/%s", res.GetKind(), res.GetName())
 
-		diffs, err := p.DiffSingleResource(ctx, res, compositionProvider)
+		xr, sourceFile := stripSourceFile(res)
+		inputKeys[dt.MakeDiffKeyFromResource(xr)] = true
+
+		diffs, err := p.DiffSingleResource(ctx, xr, compositionProvider)
+		setSourceFile(diffs, sourceFile)
+
+		var partial *PartialNestedError
+		if errors.As(err, &partial) {
+			// The XR itself was diffed; only some nested subtrees failed. Keep the diffs
+			// and report each failed subtree.
+			maps.Copy(allDiffs, diffs)
+
+			for _, subtree := range partial.Subtrees {
+				p.config.Logger.Info("Failed to process nested XR",
+					"resource", resourceID,
+					"nestedXR", subtree.ResourceID,
+					"error", subtree.Err)
+				errs = append(errs, errors.Wrapf(subtree, "unable to process resource %s", resourceID))
+				outputErrors = append(outputErrors, NewOutputError(subtree.ResourceID, subtree.Err))
+			}
+
+			continue
+		}
+
 		if err != nil {
 			// Log at Info level so errors are visible without -v 4
 			p.config.Logger.Info("Failed to process resource",
@@ -240,6 +302,39 @@ func (p *DefaultDiffProcessor) PerformDiff(ctx context.Context, resources []*un.
 		}
 	}
 
+	// Only drift that is new since the baseline is rendered, or counts as a diff. Compare before
+	// any filter, so baseline changes that are only filtered out aren't reported as resolved.
+	var drift *renderer.DriftReport
+
+	if p.config.Baseline != nil {
+		var report renderer.DriftReport
+
+		allDiffs, report = p.config.Baseline.Compare(allDiffs)
+		drift = &report
+	}
+
+	// Narrow the output to one namespace only after the full tree has been diffed, so resources in
+	// other namespaces still feed rendering and removal detection. Errors are never filtered.
+	if p.config.FilterNamespace != "" {
+		allDiffs = filterDiffsByNamespace(allDiffs, p.config.FilterNamespace)
+	}
+
+	// Likewise narrow it to the requested kinds, keeping the input XRs.
+	if len(p.config.FilterKinds) > 0 {
+		total := len(allDiffs)
+		allDiffs = filterDiffsByKind(allDiffs, p.config.FilterKinds, inputKeys)
+
+		p.config.Logger.Debug("Filtered diffs by kind", "kinds", p.config.FilterKinds, "shown", len(allDiffs), "total", total)
+	}
+
+	if p.config.OnlyChanged {
+		allDiffs = filterUnchangedDiffs(allDiffs)
+	}
+
+	if p.config.Provenance != nil {
+		p.config.Provenance.Record(allDiffs, time.Now())
+	}
+
 	// Always render (even if only errors exist) to ensure valid structured output
 	// The renderer will include errors in the structured output and write them to stderr
 	err := p.diffRenderer.RenderDiffs(allDiffs, outputErrors)
@@ -248,12 +343,18 @@ func (p *DefaultDiffProcessor) PerformDiff(ctx context.Context, resources []*un.
 		errs = append(errs, errors.Wrap(err, "failed to render diffs"))
 	}
 
-	// Count only non-equal diffs as "having diffs".
+	if drift != nil {
+		if err := p.writeDriftReport(*drift); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Count only non-equal diffs of the --fail-on types as "having diffs".
 	// The diffs map may contain DiffTypeEqual entries (e.g., XR stored for removal detection).
 	hasDiffs := false
 
 	for _, diff := range allDiffs {
-		if diff.DiffType != dt.DiffTypeEqual {
+		if p.config.failsOn(diff.DiffType) {
 			hasDiffs = true
 			break
 		}
@@ -272,11 +373,158 @@ func (p *DefaultDiffProcessor) PerformDiff(ctx context.Context, resources []*un.
 	return hasDiffs, nil
 }
 
+// writeDriftReport writes the report of drift since the baseline after the human-readable diff,
+// or to stderr when the output is machine-readable, so it stays valid.
+func (p *DefaultDiffProcessor) writeDriftReport(drift renderer.DriftReport) error {
+	w, sep := p.config.Stderr, ""
+
+	switch p.config.OutputFormat {
+	case renderer.OutputFormatDiff, renderer.OutputFormatTextNoANSI, renderer.OutputFormatGitHub, "":
+		w, sep = p.config.Stdout, "\n"
+	case renderer.OutputFormatJSON, renderer.OutputFormatYAML, renderer.OutputFormatCSV, renderer.OutputFormatDesired,
+		renderer.OutputFormatDOT, renderer.OutputFormatMarkdown, renderer.OutputFormatJUnit:
+	}
+
+	if _, err := fmt.Fprint(w, sep+drift.String()); err != nil {
+		return errors.Wrap(err, "failed to write drift report")
+	}
+
+	return nil
+}
+
+// filterDiffsByNamespace returns the subset of diffs for resources in namespace. Cluster-scoped
+// resources have no namespace and are always dropped.
+func filterDiffsByNamespace(diffs map[string]*dt.ResourceDiff, namespace string) map[string]*dt.ResourceDiff {
+	filtered := make(map[string]*dt.ResourceDiff, len(diffs))
+
+	for key, diff := range diffs {
+		if diff.Namespace == namespace {
+			filtered[key] = diff
+		}
+	}
+
+	return filtered
+}
+
+// filterUnchangedDiffs returns the subset of diffs that change something: every added and removed
+// resource, and modified resources with at least one inserted or deleted line.
+func filterUnchangedDiffs(diffs map[string]*dt.ResourceDiff) map[string]*dt.ResourceDiff {
+	filtered := make(map[string]*dt.ResourceDiff, len(diffs))
+
+	for key, diff := range diffs {
+		switch diff.DiffType {
+		case dt.DiffTypeAdded, dt.DiffTypeRemoved:
+			filtered[key] = diff
+		case dt.DiffTypeModified:
+			if slices.ContainsFunc(diff.LineDiffs, func(d diffmatchpatch.Diff) bool { return d.Type != diffmatchpatch.DiffEqual }) {
+				filtered[key] = diff
+			}
+		case dt.DiffTypeEqual:
+			// Nothing changed.
+		}
+	}
+
+	return filtered
+}
+
+// filterDiffsByKind returns the subset of diffs for resources of the given kinds, each Kind or
+// Kind.group, along with the diffs whose keys are in keep.
+func filterDiffsByKind(diffs map[string]*dt.ResourceDiff, kinds []string, keep map[string]bool) map[string]*dt.ResourceDiff {
+	filtered := make(map[string]*dt.ResourceDiff, len(diffs))
+
+	for key, diff := range diffs {
+		if keep[key] || renderer.MatchesKind(diff.Gvk, kinds) {
+			filtered[key] = diff
+		}
+	}
+
+	return filtered
+}
+
+// stripSourceFile returns res without the AnnotationSourceFile annotation, along with its value.
+// res is returned unchanged when it carries no source file, and is copied otherwise so the
+// caller's object is not modified.
+func stripSourceFile(res *un.Unstructured) (*un.Unstructured, string) {
+	annotations := res.GetAnnotations()
+
+	sourceFile, ok := annotations[AnnotationSourceFile]
+	if !ok {
+		return res, ""
+	}
+
+	delete(annotations, AnnotationSourceFile)
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	stripped := res.DeepCopy()
+	stripped.SetAnnotations(annotations)
+
+	return stripped, sourceFile
+}
+
+// setSourceFile records sourceFile on every diff produced for a top-level resource.
+func setSourceFile(diffs map[string]*dt.ResourceDiff, sourceFile string) {
+	if sourceFile == "" {
+		return
+	}
+
+	for _, diff := range diffs {
+		diff.SourceFile = sourceFile
+	}
+}
+
 // DiffSingleResource handles one resource at a time and returns its diffs.
 // The compositionProvider function is called to obtain the composition to use for rendering.
 // This is the public method for top-level XR diffing, which enables removal detection.
+//
+// With a ResourceTimeout, the resource gets its own deadline within ctx's, so one slow render fails
+// only this resource. With Strict, any rendering warning recorded while diffing it fails it too.
 func (p *DefaultDiffProcessor) DiffSingleResource(ctx context.Context, res *un.Unstructured, compositionProvider types.CompositionProvider) (map[string]*dt.ResourceDiff, error) {
-	diffs, _, err := p.diffSingleResourceInternal(ctx, res, compositionProvider, nil, true)
+	ctx, warnings := WithRenderWarnings(ctx)
+
+	// Resolve each required resource selector once for this XR, however many renders request it.
+	ctx = WithSelectorCache(ctx)
+
+	// Hold the XR and its nested XRs to MaxResources between them.
+	ctx, count := WithResourceCount(ctx, p.config.MaxResources)
+
+	diffs, err := p.diffSingleResourceWithTimeout(ctx, res, compositionProvider)
+
+	// A nested XR over the limit fails the whole XR, even with --partial-nested.
+	if countErr := count.Err(); countErr != nil {
+		return nil, countErr
+	}
+
+	// Diffs are returned, and so may be rendered, on success and with failed nested subtrees alike.
+	var partial *PartialNestedError
+	if (err != nil && !errors.As(err, &partial)) || !p.config.Strict {
+		return diffs, err
+	}
+
+	if w := warnings.Warnings(); len(w) > 0 {
+		return nil, errors.Errorf("rendering warnings are errors with --strict: %s", strings.Join(w, "; "))
+	}
+
+	return diffs, err
+}
+
+// diffSingleResourceWithTimeout diffs res as DiffSingleResource does, within ResourceTimeout if set.
+func (p *DefaultDiffProcessor) diffSingleResourceWithTimeout(ctx context.Context, res *un.Unstructured, compositionProvider types.CompositionProvider) (map[string]*dt.ResourceDiff, error) {
+	if p.config.ResourceTimeout <= 0 {
+		diffs, _, err := p.diffSingleResourceInternal(ctx, res, compositionProvider, nil, true)
+		return diffs, err
+	}
+
+	resCtx, cancel := context.WithTimeout(ctx, p.config.ResourceTimeout)
+	defer cancel()
+
+	diffs, _, err := p.diffSingleResourceInternal(resCtx, res, compositionProvider, nil, true)
+	if err != nil && ctx.Err() == nil && errors.Is(resCtx.Err(), context.DeadlineExceeded) {
+		return nil, errors.Wrapf(err, "timed out after %s (--timeout-per-resource)", p.config.ResourceTimeout)
+	}
+
 	return diffs, err
 }
 
@@ -287,13 +535,32 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 	resourceID := fmt.Sprintf("%s/%s", res.GetKind(), res.GetName())
 	p.config.Logger.Debug("Processing resource", "resource", resourceID, "namespace", res.GetNamespace())
 
+	// With --allow-managed, a top-level resource no XRD defines has no composition to render;
+	// diff it as it is. A failed XRD lookup fails the resource rather than risk diffing an XR bare.
+	if parentXR == nil && p.config.AllowManaged {
+		isXR, _, err := p.getCompositeResourceXRD(ctx, res)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "cannot determine whether the resource is an XR or claim")
+		}
+
+		if !isXR {
+			diffs, err := p.diffManagedResource(ctx, res, resourceID)
+			return diffs, nil, err
+		}
+	}
+
 	xr, done, err := p.SanitizeXR(res, resourceID)
 	if done {
 		return nil, nil, err
 	}
 
-	// Get the composition using the provided function
-	comp, err := compositionProvider(ctx, res)
+	// Get the composition using the provided function, recording the revision it was resolved from
+	// for the XR's diff below, and any missing pinned revision it replaced with the latest one
+	// (--skip-missing-revisions) so it's noted on the XR's diff.
+	providerCtx, resolved := xp.WithResolvedRevision(ctx)
+	providerCtx, skipped := xp.WithSkippedRevisions(providerCtx)
+
+	comp, err := compositionProvider(providerCtx, res)
 	if err != nil {
 		p.config.Logger.Debug("Failed to get composition", "resource", resourceID, "namespace", res.GetNamespace(), "error", err)
 		return nil, nil, errors.Wrap(err, "cannot get composition")
@@ -301,6 +568,24 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 
 	p.config.Logger.Debug("Resource setup complete", "resource", resourceID, "composition", comp.GetName())
 
+	// Check the EnvironmentConfigs the composition references up front: a missing one doesn't fail the
+	// render, it just leaves the environment incomplete, so the output could be silently partial.
+	missingEnvConfigs, err := p.requirementsProvider.MissingEnvironmentConfigs(ctx, comp)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot check required environment configs")
+	}
+
+	if len(missingEnvConfigs) > 0 {
+		p.config.Logger.Info("Warning: required EnvironmentConfigs are missing; the render may be incomplete",
+			"resource", resourceID, "composition", comp.GetName(), "missing", missingEnvConfigs)
+		recordRenderWarning(ctx, "%s: composition %s requires EnvironmentConfigs that don't exist: %s",
+			resourceID, comp.GetName(), strings.Join(missingEnvConfigs, ", "))
+	}
+
+	for _, note := range skipped.Notes() {
+		recordRenderWarning(ctx, "%s: %s", resourceID, note)
+	}
+
 	// Get functions for this composition (provider handles caching internally)
 	fns, err := p.functionProvider.GetFunctionsForComposition(comp)
 	if err != nil {
@@ -369,12 +654,27 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 	// Perform iterative rendering with requirements resolution.
 	// When EventualState is enabled, also synthesizes Ready status between iterations
 	// to reveal all stages that function-sequencer would eventually render.
-	desired, err := p.RenderToStableState(ctx, xrForRendering, comp, fns, resourceID, observedResources, p.config.EventualState)
+	//
+	// With --show-external-resources, record the required resources this XR's renders are given.
+	renderCtx := ctx
+
+	var required *requiredResources
+	if p.config.ShowExternalResources {
+		renderCtx, required = withRequiredResources(ctx)
+	}
+
+	desired, err := p.RenderToStableState(renderCtx, xrForRendering, comp, fns, resourceID, observedResources, p.config.EventualState)
 	if err != nil {
 		p.config.Logger.Debug("Resource rendering failed", "resource", resourceID, "error", err)
 		return nil, nil, errors.Wrap(err, "cannot render resources with requirements")
 	}
 
+	// Stop before diffing a runaway render. Observed resources count too: an XR that renders
+	// nothing may still have thousands to remove.
+	if err := countResources(ctx, resourceID, max(len(desired.ComposedResources), len(observedResources))); err != nil {
+		return nil, nil, err
+	}
+
 	// Prepare the top-level XR for diff calculation
 	p.config.Logger.Debug("Preparing XR for diff calculation",
 		"resource", resourceID,
@@ -438,6 +738,25 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 	var existingXR *cmp.Unstructured
 
 	xrDiffKey := dt.MakeDiffKeyFromResource(&xr.Unstructured)
+	if xrDiff, ok := diffs[xrDiffKey]; ok {
+		xrDiff.Composition = comp.GetName()
+
+		if name, number := resolved.Get(); name != "" {
+			xrDiff.Revision = &dt.RevisionRef{Name: name, Number: number}
+		}
+
+		xrDiff.Warnings = append(xrDiff.Warnings, skipped.Notes()...)
+
+		if required != nil {
+			xrDiff.RequiredResources = required.Resources()
+		}
+
+		if len(missingEnvConfigs) > 0 {
+			xrDiff.Warnings = append(xrDiff.Warnings, fmt.Sprintf("composition %s requires EnvironmentConfigs that don't exist: %s",
+				comp.GetName(), strings.Join(missingEnvConfigs, ", ")))
+		}
+	}
+
 	if xrDiff, ok := diffs[xrDiffKey]; ok && xrDiff.Current.Raw != nil {
 		// Convert from unstructured.Unstructured to composite.Unstructured
 		existingXR = cmp.New()
@@ -452,6 +771,13 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 	}
 
 	nestedDiffs, nestedRenderedResources, err := p.ProcessNestedXRs(ctx, desired.ComposedResources, compositionProvider, resourceID, existingXR, observedResources, 1)
+
+	// Failed nested subtrees are returned to the caller with the rest of the diff.
+	var partial *PartialNestedError
+	if errors.As(err, &partial) {
+		err = nil
+	}
+
 	if err != nil {
 		p.config.Logger.Debug("Error processing nested XRs", "resource", resourceID, "error", err)
 		return nil, nil, errors.Wrap(err, "cannot process nested XRs")
@@ -491,14 +817,40 @@ func (p *DefaultDiffProcessor) diffSingleResourceInternal(ctx context.Context, r
 		}
 	}
 
+	// Everything not already claimed by a nested XR was composed by this one, including the
+	// nested XRs themselves. Renderers use this to rebuild the composition tree.
+	for key, diff := range diffs {
+		if key != xrDiffKey && diff.Composite == "" {
+			diff.Composite = xrDiffKey
+		}
+	}
+
 	p.config.Logger.Debug("Resource processing complete",
 		"resource", resourceID,
 		"diffCount", len(diffs),
 		"nestedDiffCount", len(nestedDiffs))
 
+	if partial != nil {
+		return diffs, renderedResources, partial
+	}
+
 	return diffs, renderedResources, nil
 }
 
+// diffManagedResource diffs a resource that isn't composed, such as a managed resource created
+// directly, by dry-run applying it as it is. Nothing is rendered, so there are no composed
+// resources and no removals to detect.
+func (p *DefaultDiffProcessor) diffManagedResource(ctx context.Context, res *un.Unstructured, resourceID string) (map[string]*dt.ResourceDiff, error) {
+	p.config.Logger.Debug("Resource is not an XR or claim; diffing it directly", "resource", resourceID)
+
+	diff, err := p.diffCalculator.CalculateDiff(ctx, nil, res)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot calculate diff")
+	}
+
+	return map[string]*dt.ResourceDiff{diff.GetDiffKey(): diff}, nil
+}
+
 // fetchObservedResourcesFromClusterXR fetches observed resources using the cluster XR.
 // We must use the cluster XR (not the input XR) because the XRM client uses spec.resourceRefs
 // to find children. The input XR doesn't have resourceRefs, but the cluster XR does.
@@ -810,12 +1162,30 @@ func (p *DefaultDiffProcessor) prepareXRForDiff(xr *cmp.Unstructured, desired re
 		// We rendered from backing XR for correct composed resource labels, but we want
 		// to diff against the original Claim that the user provided - not the backing XR.
 		// The composed resources already have correct labels; only the top-level needs
-		// to show the Claim identity.
+		// to show the Claim identity, plus any spec fields the pipeline changed on the
+		// backing XR (which Crossplane syncs back to the Claim).
 		p.config.Logger.Debug("Using original Claim for top-level diff (rendered from backing XR)",
 			"resource", resourceID,
 			"claim", xr.GetName())
 
-		return xr.GetUnstructured().DeepCopy(), nil
+		claim := xr.GetUnstructured().DeepCopy()
+
+		if desired.CompositeResource != nil {
+			changed, err := propagateRenderedSpecToClaim(claim,
+				backingXRResolution.xrForRendering.GetUnstructured(),
+				desired.CompositeResource.GetUnstructured())
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot propagate rendered XR spec to Claim")
+			}
+
+			if len(changed) > 0 {
+				p.config.Logger.Debug("Propagated rendered backing XR spec fields to Claim",
+					"resource", resourceID,
+					"fields", changed)
+			}
+		}
+
+		return claim, nil
 	}
 
 	// Normal case: merge rendered XR with input
@@ -832,6 +1202,90 @@ func (p *DefaultDiffProcessor) prepareXRForDiff(xr *cmp.Unstructured, desired re
 	return xrUnstructured, nil
 }
 
+// propagateRenderedSpecToClaim copies top-level spec fields that rendering changed on the backing XR
+// (e.g. written by a function such as a ToCompositeFieldPath patch) onto the Claim, mirroring
+// Crossplane's claim syncer. Crossplane-managed XR fields are never propagated. Values are compared
+// by their JSON encoding so render's numeric normalization (int64 vs float64) isn't mistaken for a
+// change. Returns the names of the propagated fields.
+func propagateRenderedSpecToClaim(claim, renderedFrom, rendered *un.Unstructured) ([]string, error) {
+	renderedSpec, _, _ := un.NestedFieldNoCopy(rendered.Object, "spec")
+
+	renderedSpecMap, ok := renderedSpec.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	inputSpec, _, _ := un.NestedFieldNoCopy(renderedFrom.Object, "spec")
+	inputSpecMap, _ := inputSpec.(map[string]any)
+
+	claimSpec := map[string]any{}
+
+	if existing, _, _ := un.NestedFieldNoCopy(claim.Object, "spec"); existing != nil {
+		existingMap, ok := existing.(map[string]any)
+		if !ok {
+			return nil, errors.New("claim spec is not an object")
+		}
+
+		maps.Copy(claimSpec, existingMap)
+	}
+
+	xrOnlyFields := map[string]bool{
+		fieldClaimRef:                    true,
+		fieldResourceRefs:                true,
+		fieldCompositionRef:              true,
+		fieldCompositionSelector:         true,
+		fieldCompositionRevisionRef:      true,
+		fieldWriteConnectionSecretToRef:  true,
+		fieldCompositionUpdatePolicy:     true,
+		fieldCompositionRevisionSelector: true,
+		fieldPublishConnectionDetailsTo:  true,
+		fieldEnvironmentConfigRefs:       true,
+	}
+
+	var changed []string
+
+	for name, val := range renderedSpecMap {
+		if xrOnlyFields[name] {
+			continue
+		}
+
+		same, err := jsonEqual(inputSpecMap[name], val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot compare spec.%s", name)
+		}
+
+		if same {
+			continue
+		}
+
+		claimSpec[name] = val
+		changed = append(changed, name)
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(changed)
+
+	return changed, un.SetNestedField(claim.Object, claimSpec, "spec")
+}
+
+// jsonEqual reports whether two unstructured values have the same JSON encoding.
+func jsonEqual(a, b any) (bool, error) {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(aj, bj), nil
+}
+
 // findExistingNestedXR locates an existing nested XR in the observed resources by matching
 // the composition-resource-name annotation and kind.
 func findExistingNestedXR(nestedXR *un.Unstructured, observedResources []cpd.Unstructured) *un.Unstructured {
@@ -903,11 +1357,13 @@ func (p *DefaultDiffProcessor) ProcessNestedXRs(
 	allDiffs := make(map[string]*dt.ResourceDiff)
 	allRenderedResources := make(map[string]bool)
 
+	var subtreeErrs []*NestedXRError
+
 	for _, composed := range composedResources {
 		nestedXR := &un.Unstructured{Object: composed.UnstructuredContent()}
 
 		// Check if this composed resource is itself an XR
-		isXR, _ := p.getCompositeResourceXRD(ctx, nestedXR)
+		isXR, _, _ := p.getCompositeResourceXRD(ctx, nestedXR)
 
 		if !isXR {
 			// Skip non-XR resources
@@ -960,6 +1416,14 @@ func (p *DefaultDiffProcessor) ProcessNestedXRs(
 		// Use detectRemovals=false for nested XRs since they don't own their composed resources
 		// (resources are owned by the top-level parent XR in Crossplane's ownership model)
 		nestedDiffs, nestedRenderedResources, err := p.diffSingleResourceInternal(ctx, nestedXR, compositionProvider, parentXR, false)
+
+		// A nested XR that rendered but has failed subtrees of its own still contributes its diffs.
+		var partial *PartialNestedError
+		if errors.As(err, &partial) {
+			subtreeErrs = append(subtreeErrs, partial.Subtrees...)
+			err = nil
+		}
+
 		if err != nil {
 			// Check if the error is due to missing composition
 			// Note: It's valid to have an XRD in Crossplane without a composition attached to it.
@@ -975,6 +1439,22 @@ func (p *DefaultDiffProcessor) ProcessNestedXRs(
 				continue
 			}
 
+			// With partial nested processing, record the failed subtree and keep going. The
+			// failure is still reported, so this doesn't silently continue past it.
+			if p.config.PartialNested {
+				p.config.Logger.Info("Failed to process nested XR; continuing with the rest of the tree",
+					"nestedXR", nestedResourceID,
+					"parentXR", parentResourceID,
+					"error", err)
+
+				subtreeErrs = append(subtreeErrs, &NestedXRError{ResourceID: nestedResourceID, Err: err})
+
+				// The failed subtree's existing resources weren't rendered, but they aren't being removed either.
+				p.markNestedSubtreeRendered(ctx, existingNestedXR, allRenderedResources, depth)
+
+				continue
+			}
+
 			// For other errors, fail per Guiding Principles: "never silently continue in the face of failures"
 			p.config.Logger.Debug("Error processing nested XR",
 				"nestedXR", nestedResourceID,
@@ -1000,11 +1480,49 @@ func (p *DefaultDiffProcessor) ProcessNestedXRs(
 		"parentResource", parentResourceID,
 		"totalNestedDiffs", len(allDiffs),
 		"totalRenderedResourcesCount", len(allRenderedResources),
+		"failedSubtreeCount", len(subtreeErrs),
 		"depth", depth)
 
+	if len(subtreeErrs) > 0 {
+		return allDiffs, allRenderedResources, &PartialNestedError{Subtrees: subtreeErrs}
+	}
+
 	return allDiffs, allRenderedResources, nil
 }
 
+// markNestedSubtreeRendered marks the existing composed resources under a nested XR
+// whose subtree failed to render as rendered, so removal detection doesn't report
+// them as removed. Nested XRs found under it are followed up to the maximum depth.
+func (p *DefaultDiffProcessor) markNestedSubtreeRendered(ctx context.Context, existingNestedXR *un.Unstructured, rendered map[string]bool, depth int) {
+	if existingNestedXR == nil || depth > p.config.MaxNestedDepth {
+		return
+	}
+
+	xr := cmp.New()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existingNestedXR.Object, xr); err != nil {
+		p.config.Logger.Debug("Cannot convert nested XR to mark its subtree", "nestedXR", existingNestedXR.GetName(), "error", err)
+		return
+	}
+
+	observed, err := p.resourceManager.FetchObservedResources(ctx, xr)
+	if err != nil {
+		p.config.Logger.Info("Cannot fetch resources under failed nested XR; they may be reported as removed",
+			"nestedXR", existingNestedXR.GetName(),
+			"error", err)
+
+		return
+	}
+
+	for i := range observed {
+		res := &un.Unstructured{Object: observed[i].UnstructuredContent()}
+		rendered[dt.MakeDiffKeyFromResource(res)] = true
+
+		if isXR, _, _ := p.getCompositeResourceXRD(ctx, res); isXR {
+			p.markNestedSubtreeRendered(ctx, res, rendered, depth+1)
+		}
+	}
+}
+
 // SanitizeXR makes an XR into a valid unstructured object that we can use in a dry-run apply.
 func (p *DefaultDiffProcessor) SanitizeXR(res *un.Unstructured, resourceID string) (*cmp.Unstructured, bool, error) {
 	// Convert the unstructured resource to a composite unstructured for rendering
@@ -1090,6 +1608,11 @@ func (p *DefaultDiffProcessor) RenderToStableState(
 	// downstream.
 	xr.Schema = xrSchema
 
+	comp, err := p.applyFunctionInputs(comp, resourceID)
+	if err != nil {
+		return render.CompositionOutputs{}, err
+	}
+
 	functionCredentials := p.resolveFunctionCredentials(ctx, comp, resourceID)
 
 	// Track required resources with deduplication
@@ -1102,6 +1625,10 @@ func (p *DefaultDiffProcessor) RenderToStableState(
 
 	var lastOutput render.CompositionOutputs
 
+	// The requirements of the last render that resolved to new resources, to explain a failure to
+	// stabilize.
+	var pending []*v1.ResourceSelector
+
 	for iteration := 1; iteration <= maxIterations; iteration++ {
 		p.config.Logger.Debug("Render iteration",
 			"resource", resourceID,
@@ -1119,6 +1646,7 @@ func (p *DefaultDiffProcessor) RenderToStableState(
 			RequiredResources:   slices.Collect(maps.Values(requiredResources)),
 			ObservedResources:   observed,
 			XRD:                 xrdForRender,
+			Context:             p.config.ContextResources,
 		})
 
 		lastOutput = output
@@ -1139,6 +1667,11 @@ func (p *DefaultDiffProcessor) RenderToStableState(
 			}
 		}
 
+		pending = nil
+		if newReqCount > 0 {
+			pending = output.RequiredResources
+		}
+
 		// Render error AND no new requirements means we have nothing left to try.
 		// Surface the error.
 		//
@@ -1169,15 +1702,59 @@ func (p *DefaultDiffProcessor) RenderToStableState(
 		}
 
 		if result.stable {
+			recordWarningResults(ctx, resourceID, lastOutput)
 			return lastOutput, nil
 		}
 
 		observed = result.nextObserved
 	}
 
+	if len(pending) > 0 {
+		return render.CompositionOutputs{}, errors.Errorf("did not stabilize after %d iterations: the final render still required new resources (%s); "+
+			"a function may be requesting a different resource every time, or try increasing --max-iterations if your pipeline requires more cycles",
+			maxIterations, describeSelectors(pending))
+	}
+
 	return render.CompositionOutputs{}, errors.Errorf("did not stabilize after %d iterations; try increasing --max-iterations if your pipeline requires more cycles", maxIterations)
 }
 
+// describeSelectors describes required resource selectors for error messages, e.g.
+// "ConfigMap default/settings, ClusterRole with labels team=a".
+func describeSelectors(selectors []*v1.ResourceSelector) string {
+	out := make([]string, 0, len(selectors))
+
+	for _, sel := range selectors {
+		switch {
+		case sel.GetMatchName() != "":
+			name := sel.GetMatchName()
+			if ns := sel.GetNamespace(); ns != "" {
+				name = ns + "/" + name
+			}
+
+			out = append(out, sel.GetKind()+" "+name)
+		default:
+			out = append(out, sel.GetKind()+" with labels "+formatLabels(sel.GetMatchLabels().GetLabels()))
+		}
+	}
+
+	return strings.Join(out, ", ")
+}
+
+// recordWarningResults records each function result of severity Warning in the final render of
+// resourceID as a rendering warning. Earlier renders are skipped: their warnings may only reflect
+// requirements that hadn't been resolved yet.
+func recordWarningResults(ctx context.Context, resourceID string, out render.CompositionOutputs) {
+	for _, r := range out.Results {
+		if severity, _, _ := un.NestedString(r.Object, "severity"); severity != corev1.EventTypeWarning {
+			continue
+		}
+
+		reason, _, _ := un.NestedString(r.Object, "reason")
+		message, _, _ := un.NestedString(r.Object, "message")
+		recordRenderWarning(ctx, "%s: function returned a warning (%s): %s", resourceID, reason, message)
+	}
+}
+
 // resolveSchemaAndXRDForRender returns the canonical composite Schema (Legacy
 // vs Modern) and the XRD object the render binary uses when picking its
 // internal wrapper schema. One XRD lookup serves both jobs: the spec.scope
@@ -1218,6 +1795,81 @@ func (p *DefaultDiffProcessor) resolveFunctionCredentials(ctx context.Context, c
 	return merged
 }
 
+// applyFunctionInputs returns comp with the configured extra function inputs merged into the input
+// of each pipeline step they name. Steps the composition doesn't have are ignored, since one run
+// can render several compositions. comp itself is never modified.
+func (p *DefaultDiffProcessor) applyFunctionInputs(comp *apiextensionsv1.Composition, resourceID string) (*apiextensionsv1.Composition, error) {
+	if len(p.config.FunctionInputs) == 0 {
+		return comp, nil
+	}
+
+	var merged *apiextensionsv1.Composition
+
+	for i, step := range comp.Spec.Pipeline {
+		extra, ok := p.config.FunctionInputs[step.Step]
+		if !ok {
+			continue
+		}
+
+		if merged == nil {
+			merged = comp.DeepCopy()
+		}
+
+		input, err := mergeFunctionInput(step.Input, extra)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot merge extra input into pipeline step %q of composition %s", step.Step, comp.GetName())
+		}
+
+		merged.Spec.Pipeline[i].Input = input
+
+		p.config.Logger.Debug("Merged extra function input into pipeline step",
+			"resource", resourceID,
+			"composition", comp.GetName(),
+			"step", step.Step)
+	}
+
+	if merged == nil {
+		return comp, nil
+	}
+
+	return merged, nil
+}
+
+// mergeFunctionInput deep-merges extra into a pipeline step's input. Values in extra win: nested
+// objects are merged and everything else, including lists, is replaced.
+func mergeFunctionInput(input *runtime.RawExtension, extra map[string]any) (*runtime.RawExtension, error) {
+	base := make(map[string]any)
+
+	if input != nil && len(input.Raw) > 0 {
+		if err := json.Unmarshal(input.Raw, &base); err != nil {
+			return nil, errors.Wrap(err, "cannot parse step input")
+		}
+	}
+
+	// Round-trip extra through JSON so merging never aliases the shared configuration into a
+	// rendered composition.
+	extraJSON, err := json.Marshal(extra)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal extra input")
+	}
+
+	overlay := make(map[string]any)
+	if err := json.Unmarshal(extraJSON, &overlay); err != nil {
+		return nil, errors.Wrap(err, "cannot parse extra input")
+	}
+
+	if err := mergo.Merge(&base, overlay, mergo.WithOverride); err != nil {
+		return nil, errors.Wrap(err, "cannot merge step input")
+	}
+
+	raw, err := json.Marshal(base)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal step input")
+	}
+
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
 // stabilityResult holds the result of a stability check iteration.
 type stabilityResult struct {
 	stable       bool
@@ -1450,16 +2102,15 @@ func (p *DefaultDiffProcessor) removeNamespacesFromClusterScopedResources(ctx co
 		}
 
 		// Check if resource is cluster-scoped
-		// We must be able to determine scope to proceed - if we can't get the CRD,
-		// validation will fail anyway, so fail fast with a clear error message.
+		// We must be able to determine scope to proceed, so fail fast with a clear error message.
 		gvk := resource.GroupVersionKind()
 
-		crd, err := p.schemaClient.GetCRD(ctx, gvk)
+		namespaced, err := p.isNamespacedResource(ctx, gvk)
 		if err != nil {
-			return errors.Wrapf(err, "cannot determine scope for resource %s (GVK %s): CRD not found", resourceID, gvk.String())
+			return errors.Wrapf(err, "cannot determine scope for resource %s (GVK %s)", resourceID, gvk.String())
 		}
 
-		if crd.Spec.Scope == "Cluster" {
+		if !namespaced {
 			p.config.Logger.Debug("Removing namespace from cluster-scoped resource",
 				"resource", resourceID,
 				"gvk", gvk.String(),
@@ -1474,10 +2125,27 @@ func (p *DefaultDiffProcessor) removeNamespacesFromClusterScopedResources(ctx co
 	return nil
 }
 
+// isNamespacedResource reports whether gvk is namespaced. The CRD is checked first since it is usually
+// cached; kinds without a CRD, such as a ClusterRole composed by a namespaced v2 XR, are looked up via
+// discovery instead.
+func (p *DefaultDiffProcessor) isNamespacedResource(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	crd, crdErr := p.schemaClient.GetCRD(ctx, gvk)
+	if crdErr == nil {
+		return crd.Spec.Scope != extv1.ClusterScoped, nil
+	}
+
+	namespaced, err := p.resourceClient.IsNamespacedResource(ctx, gvk)
+	if err != nil {
+		return false, errors.Wrap(errors.Join(crdErr, err), "CRD not found and kind not found via discovery")
+	}
+
+	return namespaced, nil
+}
+
 // getCompositeResourceXRD checks if a resource is a Composite Resource (XR) by looking it up in XRDs.
-// Returns true if the resource is an XR, along with its XRD.
-// Returns false if it's not an XR or if there's an error (errors are logged but not returned).
-func (p *DefaultDiffProcessor) getCompositeResourceXRD(ctx context.Context, resource *un.Unstructured) (bool, *un.Unstructured) {
+// Returns true if the resource is an XR or claim, along with its XRD, and false if no XRD defines it.
+// Returns an error if an XRD lookup failed for any other reason, in which case it's unknown.
+func (p *DefaultDiffProcessor) getCompositeResourceXRD(ctx context.Context, resource *un.Unstructured) (bool, *un.Unstructured, error) {
 	gvk := resource.GroupVersionKind()
 
 	p.config.Logger.Debug("Checking if resource is a composite resource",
@@ -1486,29 +2154,35 @@ func (p *DefaultDiffProcessor) getCompositeResourceXRD(ctx context.Context, reso
 
 	// Check if there's an XRD that defines this GVK as an XR
 	xrd, err := p.defClient.GetXRDForXR(ctx, gvk)
-	if err == nil && xrd != nil {
+	switch {
+	case err == nil && xrd != nil:
 		p.config.Logger.Debug("Resource is a composite resource (XR)",
 			"resource", fmt.Sprintf("%s/%s", resource.GetKind(), resource.GetName()),
 			"xrd", xrd.GetName())
 
-		return true, xrd
+		return true, xrd, nil
+	case err != nil && !apierrors.IsNotFound(err):
+		return false, nil, errors.Wrapf(err, "cannot get XRD for XR type %s", gvk.String())
 	}
 
 	// Check if there's an XRD that defines this GVK as a claim
 	xrd, err = p.defClient.GetXRDForClaim(ctx, gvk)
-	if err == nil && xrd != nil {
+	switch {
+	case err == nil && xrd != nil:
 		p.config.Logger.Debug("Resource is a composite resource (Claim)",
 			"resource", fmt.Sprintf("%s/%s", resource.GetKind(), resource.GetName()),
 			"xrd", xrd.GetName())
 
-		return true, xrd
+		return true, xrd, nil
+	case err != nil && !apierrors.IsNotFound(err):
+		return false, nil, errors.Wrapf(err, "cannot get XRD for claim type %s", gvk.String())
 	}
 
 	// Not a composite resource
 	p.config.Logger.Debug("Resource is not a composite resource",
 		"resource", fmt.Sprintf("%s/%s", resource.GetKind(), resource.GetName()))
 
-	return false, nil
+	return false, nil, nil
 }
 
 // applyXRDDefaults applies default values from the XRD schema to the XR.
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
@@ -14,6 +16,7 @@ import (
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
+	pkgvalidate "github.com/crossplane/cli/v2/pkg/validate"
 	v1 "github.com/crossplane/function-sdk-go/proto/v1"
 	gcmp "github.com/google/go-cmp/cmp"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -21,6 +24,7 @@ import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
@@ -118,6 +122,7 @@ func TestDefaultDiffProcessor_PerformDiff(t *testing.T) {
 		resources       []*un.Unstructured
 		processorOpts   []ProcessorOption
 		verifyOutput    func(t *testing.T, output string)
+		verifyHasDiffs  func(t *testing.T, hasDiffs bool)
 		want            error
 		validationError bool
 	}{
@@ -595,6 +600,187 @@ func TestDefaultDiffProcessor_PerformDiff(t *testing.T) {
 		},
 	}
 
+	// With --strict, the successful diff fails once a function returns a warning.
+	strict := tests["SuccessfulDiff"]
+	strict.processorOpts = append(slices.Clone(strict.processorOpts),
+		WithStrict(true),
+		WithRenderFunc(func(_ context.Context, _ logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+			return render.CompositionOutputs{
+				CompositeResource: in.CompositeResource,
+				Results: []un.Unstructured{
+					{Object: map[string]any{"severity": "Warning", "reason": "NoConfig", "message": "using defaults"}},
+				},
+			}, nil
+		}),
+	)
+	strict.verifyOutput = nil
+	strict.want = errors.New("unable to process resource XR1/my-xr-1: rendering warnings are errors with --strict: " +
+		"XR1/my-xr-1: function returned a warning (NoConfig): using defaults")
+	tests["StrictRenderWarning"] = strict
+
+	// With --partial-nested, the XR's diffs are kept when a nested XR fails, but --strict still
+	// fails it for its rendering warnings.
+	strictPartial := tests["SuccessfulDiff"]
+	strictPartial.processorOpts = append(slices.Clone(strictPartial.processorOpts),
+		WithStrict(true),
+		WithPartialNested(true),
+		WithRenderFunc(func(_ context.Context, _ logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+			if in.CompositeResource.GetKind() != testKind {
+				return render.CompositionOutputs{}, errors.New("nested render failed")
+			}
+
+			return render.CompositionOutputs{
+				CompositeResource: in.CompositeResource,
+				ComposedResources: []cpd.Unstructured{{Unstructured: *composedResource}},
+				Results: []un.Unstructured{
+					{Object: map[string]any{"severity": "Warning", "reason": "NoConfig", "message": "using defaults"}},
+				},
+			}, nil
+		}),
+	)
+	strictPartial.verifyOutput = nil
+	strictPartial.want = strict.want
+	tests["StrictRenderWarningPartialNested"] = strictPartial
+
+	// The XR composes a nested XR, which composes one more resource: two between them, one over
+	// the limit. The nested XR's render should fail the whole XR before anything is diffed.
+	limited := tests["SuccessfulDiff"]
+	limited.processorOpts = append(slices.Clone(limited.processorOpts),
+		WithMaxResources(1),
+		WithRenderFunc(func(_ context.Context, _ logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+			composed := composedResource
+			if in.CompositeResource.GetKind() != testKind {
+				composed = tu.NewResource("cpd.org/v1", "Leaf", "leaf").Build()
+			}
+
+			return render.CompositionOutputs{
+				CompositeResource: in.CompositeResource,
+				ComposedResources: []cpd.Unstructured{{Unstructured: *composed}},
+			}, nil
+		}),
+	)
+	limited.verifyOutput = nil
+	limited.want = errors.New("unable to process resource XR1/my-xr-1: ComposedResource/resource1 brought the rendered resource count to 2, " +
+		"more than the limit of 1 (--max-resources)")
+	tests["MaxResourcesExceeded"] = limited
+
+	// An unchanged XR that would add its composed resource.
+	addsComposed := WithDiffCalculatorFactory(func(k8.ApplyClient, xp.ResourceTreeClient, ResourceManager, logging.Logger, renderer.DiffOptions) DiffCalculator {
+		return &tu.MockDiffCalculator{
+			CalculateNonRemovalDiffsFn: func(_ context.Context, xr *cmp.Unstructured, _ *un.Unstructured, _ render.CompositionOutputs) (map[string]*dt.ResourceDiff, map[string]bool, error) {
+				diffs := map[string]*dt.ResourceDiff{
+					dt.MakeDiffKeyFromResource(xr.GetUnstructured()): {Gvk: xr.GroupVersionKind(), ResourceName: xr.GetName(), DiffType: dt.DiffTypeEqual},
+					dt.MakeDiffKeyFromResource(composedResource):     {Gvk: composedResource.GroupVersionKind(), ResourceName: composedResource.GetName(), DiffType: dt.DiffTypeAdded},
+				}
+
+				return diffs, map[string]bool{}, nil
+			},
+		}
+	})
+
+	// Only the composed resource fails schema validation, which fails the XR.
+	composedInvalid := WithSchemaValidatorFactory(func(k8.SchemaClient, xp.DefinitionClient, logging.Logger) SchemaValidator {
+		return &tu.MockSchemaValidator{
+			ValidateResourcesFn: func(context.Context, *un.Unstructured, []cpd.Unstructured) error {
+				result := &pkgvalidate.ValidationResult{Resources: []pkgvalidate.ResourceValidationResult{{
+					APIVersion: "cpd.org/v1",
+					Kind:       "ComposedResource",
+					Name:       "resource1",
+					Status:     pkgvalidate.ValidationStatusInvalid,
+					Errors: []pkgvalidate.FieldValidationError{
+						{Type: pkgvalidate.FieldErrorTypeUnknownField, Field: "spec.parm", Message: `spec.parm: Unknown field: "parm"`},
+					},
+				}}}
+
+				return NewSchemaValidationError("", formatValidationErrors(result), errors.New("invalid")).WithResult(result)
+			},
+		}
+	})
+
+	composedInvalidErr := errors.New("unable to process resource XR1/my-xr-1: cannot validate resources: cpd.org/v1/ComposedResource resource1:\n" +
+		`  spec.parm: Unknown field: "parm" [unknownField]`)
+
+	invalidSchema := tests["SuccessfulDiff"]
+	invalidSchema.processorOpts = append(slices.Clone(invalidSchema.processorOpts), composedInvalid, addsComposed)
+	invalidSchema.verifyOutput = nil
+	invalidSchema.want = composedInvalidErr
+	invalidSchema.validationError = true
+	tests["ComposedSchemaError"] = invalidSchema
+
+	graph := tests["SuccessfulDiff"]
+	graph.processorOpts = append(slices.Clone(graph.processorOpts), addsComposed, WithDiffRendererFactory(renderer.NewDOTDiffRenderer))
+	graph.verifyOutput = func(t *testing.T, output string) {
+		t.Helper()
+
+		edge := `  "example.org/v1/XR1//my-xr-1" -> "cpd.org/v1/ComposedResource//resource1";`
+		if !strings.Contains(output, edge) {
+			t.Errorf("PerformDiff(...): want the XR linked to its composed resource with %s, got:\n%s", edge, output)
+		}
+	}
+	tests["DOTOutput"] = graph
+
+	baseline := tests["SuccessfulDiff"]
+	baseline.processorOpts = append(slices.Clone(baseline.processorOpts), WithBaseline(renderer.NewBaseline(nil)))
+	baseline.verifyOutput = func(t *testing.T, output string) {
+		t.Helper()
+
+		want := "Drift since baseline: 2 new, 0 resolved, 0 unchanged\n  new drift: ComposedResource/resource-a, XR1/test-xr\n"
+		if !strings.HasSuffix(output, want) {
+			t.Errorf("PerformDiff(...): want the output to end with the drift report %q, got:\n%s", want, output)
+		}
+	}
+	tests["BaselineDriftReport"] = baseline
+
+	// A baseline change that --filter-kind hides still occurs, so it isn't resolved drift.
+	filteredBaseline := tests["SuccessfulDiff"]
+	filteredBaseline.processorOpts = append(slices.Clone(filteredBaseline.processorOpts), WithFilterKinds([]string{"XR1"}),
+		WithBaseline(renderer.NewBaseline([]renderer.ChangeDetail{
+			{Type: "modified", APIVersion: "example.org/v1", Kind: "ComposedResource", Name: "resource-a"},
+		})))
+	filteredBaseline.verifyOutput = func(t *testing.T, output string) {
+		t.Helper()
+
+		want := "Drift since baseline: 2 new, 0 resolved, 0 unchanged\n  new drift: ComposedResource/resource-a, XR1/test-xr\n"
+		if !strings.HasSuffix(output, want) {
+			t.Errorf("PerformDiff(...): want the output to end with the drift report %q, got:\n%s", want, output)
+		}
+	}
+	tests["BaselineFilterKind"] = filteredBaseline
+
+	provenance := tests["SuccessfulDiff"]
+	provenance.processorOpts = append(slices.Clone(provenance.processorOpts), addsComposed,
+		WithProvenance(&renderer.Provenance{Server: "https://127.0.0.1:6443"}), WithDiffRendererFactory(renderer.NewDiffRenderer))
+	provenance.verifyOutput = func(t *testing.T, output string) {
+		t.Helper()
+
+		want := "Provenance:\n  server: https://127.0.0.1:6443\n  compositions: test-comp\n  timestamp: "
+		if !strings.HasPrefix(output, want) {
+			t.Errorf("PerformDiff(...): want the output to lead with the provenance %q, got:\n%s", want, output)
+		}
+	}
+	tests["Provenance"] = provenance
+
+	// --fail-on only counts the listed diff types towards the exit code.
+	for name, failOn := range map[string]struct {
+		types []dt.DiffType
+		want  bool
+	}{
+		"FailOnAdded":   {types: []dt.DiffType{dt.DiffTypeAdded}, want: true},
+		"FailOnRemoved": {types: []dt.DiffType{dt.DiffTypeRemoved}, want: false},
+		"FailOnNone":    {types: []dt.DiffType{}, want: false},
+	} {
+		tt := tests["SuccessfulDiff"]
+		tt.processorOpts = append(slices.Clone(tt.processorOpts), addsComposed, WithFailOn(failOn.types))
+		tt.verifyHasDiffs = func(t *testing.T, hasDiffs bool) {
+			t.Helper()
+
+			if hasDiffs != failOn.want {
+				t.Errorf("PerformDiff(...): want hasDiffs %t for an added resource with --fail-on=%v, got %t", failOn.want, failOn.types, hasDiffs)
+			}
+		}
+		tests[name] = tt
+	}
+
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create components for testing
@@ -613,13 +799,17 @@ func TestDefaultDiffProcessor_PerformDiff(t *testing.T) {
 			compositionProvider := func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
 				return xpClients.Composition.FindMatchingComposition(ctx, res)
 			}
-			_, err := processor.PerformDiff(ctx, tt.resources, compositionProvider)
+			hasDiffs, err := processor.PerformDiff(ctx, tt.resources, compositionProvider)
 
 			// Check output if verification function is provided (do this first, before error checks)
 			if tt.verifyOutput != nil {
 				tt.verifyOutput(t, stdout.String())
 			}
 
+			if tt.verifyHasDiffs != nil {
+				tt.verifyHasDiffs(t, hasDiffs)
+			}
+
 			if tt.want != nil {
 				if err == nil {
 					t.Errorf("PerformDiff(...): expected error but got none")
@@ -710,6 +900,153 @@ func TestDefaultDiffProcessor_PerformDiff_StderrErrorOutput(t *testing.T) {
 	}
 }
 
+func TestDefaultDiffProcessor_PerformDiff_ResourceTimeout(t *testing.T) {
+	slow := tu.NewResource("example.org/v1", "XR1", "slow-xr").Build()
+	fast := tu.NewResource("example.org/v1", "XR1", "fast-xr").Build()
+
+	var stderrBuf bytes.Buffer
+
+	k8sClients := k8.Clients{
+		Apply:    tu.NewMockApplyClient().Build(),
+		Resource: tu.NewMockResourceClient().Build(),
+		Schema:   tu.NewMockSchemaClient().Build(),
+		Type:     tu.NewMockTypeConverter().Build(),
+	}
+
+	xpClients := xp.Clients{
+		Composition:  tu.NewMockCompositionClient().Build(),
+		Credential:   &tu.MockCredentialClient{},
+		Definition:   tu.NewMockDefinitionClient().Build(),
+		Environment:  tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(),
+		Function:     tu.NewMockFunctionClient().Build(),
+		ResourceTree: tu.NewMockResourceTreeClient().Build(),
+	}
+
+	processor := NewDiffProcessor(k8sClients, xpClients,
+		append(testProcessorOptions(t),
+			WithStderr(&stderrBuf),
+			WithResourceTimeout(50*time.Millisecond),
+		)...,
+	)
+
+	// The slow XR hangs until its deadline; the fast one fails straight away,
+	// which shows it was still processed after the slow one timed out.
+	compositionProvider := func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+		if res.GetName() == "slow-xr" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		return nil, errors.New("composition not found")
+	}
+
+	_, err := processor.PerformDiff(t.Context(), []*un.Unstructured{slow, fast}, compositionProvider)
+	if err == nil {
+		t.Fatal("PerformDiff(): expected error but got none")
+	}
+
+	stderrOutput := stderrBuf.String()
+
+	for _, want := range []string{"ERROR: XR1/slow-xr:", "timed out after 50ms", "ERROR: XR1/fast-xr:", "composition not found"} {
+		if !strings.Contains(stderrOutput, want) {
+			t.Errorf("PerformDiff(): want stderr to contain %q, got: %q", want, stderrOutput)
+		}
+	}
+}
+
+func TestDefaultDiffProcessor_PerformDiff_AllowManaged(t *testing.T) {
+	bucket := tu.NewResource("s3.aws.upbound.io/v1beta1", "Bucket", "my-bucket").
+		WithSpecField("forProvider", map[string]any{"region": "us-east-1"}).
+		Build()
+
+	noXRD := tu.NewMockDefinitionClient().WithXRDForXRNotFound().WithXRDForClaimNotFound().Build()
+
+	tests := map[string]struct {
+		reason       string
+		defClient    xp.DefinitionClient
+		allowManaged bool
+		wantErr      string
+		wantStdout   string
+	}{
+		"Allowed": {
+			reason:       "A resource no XRD defines should be dry-run diffed directly with --allow-managed.",
+			defClient:    noXRD,
+			allowManaged: true,
+			wantStdout:   "+++ Bucket/my-bucket",
+		},
+		"NotAllowed": {
+			reason:    "Without --allow-managed a resource no XRD defines should still fail to find its composition.",
+			defClient: noXRD,
+			wantErr:   "composition not found",
+		},
+		"XRDLookupError": {
+			reason:       "A failed XRD lookup should fail the resource rather than diff a possible XR as a managed resource.",
+			defClient:    tu.NewMockDefinitionClient().WithXRDForXRError(errors.New("connection refused")).Build(),
+			allowManaged: true,
+			wantErr:      "cannot determine whether the resource is an XR or claim: cannot get XRD for XR type s3.aws.upbound.io/v1beta1, Kind=Bucket: connection refused",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			calculator := &tu.MockDiffCalculator{
+				CalculateDiffFn: func(ctx context.Context, _ *un.Unstructured, desired *un.Unstructured) (*dt.ResourceDiff, error) {
+					return renderer.GenerateDiffWithOptions(ctx, nil, desired, tu.TestLogger(t, false), renderer.DefaultDiffOptions())
+				},
+			}
+
+			processor := NewDiffProcessor(
+				k8.Clients{
+					Apply:    tu.NewMockApplyClient().Build(),
+					Resource: tu.NewMockResourceClient().Build(),
+					Schema:   tu.NewMockSchemaClient().Build(),
+					Type:     tu.NewMockTypeConverter().Build(),
+				},
+				xp.Clients{
+					Composition:  tu.NewMockCompositionClient().Build(),
+					Credential:   &tu.MockCredentialClient{},
+					Definition:   tt.defClient,
+					Environment:  tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(),
+					Function:     tu.NewMockFunctionClient().Build(),
+					ResourceTree: tu.NewMockResourceTreeClient().Build(),
+				},
+				append(testProcessorOptions(t),
+					WithStdout(&stdout),
+					WithStderr(&bytes.Buffer{}),
+					WithAllowManaged(tt.allowManaged),
+					WithDiffCalculatorFactory(func(k8.ApplyClient, xp.ResourceTreeClient, ResourceManager, logging.Logger, renderer.DiffOptions) DiffCalculator {
+						return calculator
+					}),
+				)...,
+			)
+
+			compositionProvider := func(context.Context, *un.Unstructured) (*apiextensionsv1.Composition, error) {
+				return nil, errors.New("composition not found")
+			}
+
+			_, err := processor.PerformDiff(t.Context(), []*un.Unstructured{bucket}, compositionProvider)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("\n%s\nPerformDiff(...): want error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nPerformDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("\n%s\nPerformDiff(...): want stdout to contain %q, got:\n%s", tt.reason, tt.wantStdout, stdout.String())
+			}
+		})
+	}
+}
+
 func TestDefaultDiffProcessor_Initialize(t *testing.T) {
 	// Setup test context
 	ctx := t.Context()
@@ -904,8 +1241,97 @@ func TestDefaultDiffProcessor_RenderToStableState(t *testing.T) {
 		setupRenderFunc        func() RenderFn
 		wantComposedCount      int
 		wantRenderIterations   int
+		wantWarnings           []string
 		wantErr                bool
+		wantErrContains        string
 	}{
+		"RequirementsNeverSettle": {
+			xr:          xr,
+			composition: composition,
+			functions:   functions,
+			resourceID:  "XR/test-xr",
+			setupResourceClient: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().
+					WithNamespacedResource(
+						schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"},
+					).
+					WithGetResource(func(_ context.Context, _ schema.GroupVersionKind, ns, name string) (*un.Unstructured, error) {
+						return tu.NewResource("v1", ConfigMap, name).InNamespace(ns).Build(), nil
+					}).
+					Build()
+			},
+			setupEnvironmentClient: func() *tu.MockEnvironmentClient {
+				return tu.NewMockEnvironmentClient().
+					WithNoEnvironmentConfigs().
+					Build()
+			},
+			setupRenderFunc: func() RenderFn {
+				iteration := 0
+
+				return func(_ context.Context, _ logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+					iteration++
+
+					// Every render asks for a ConfigMap it hasn't asked for before.
+					return render.CompositionOutputs{
+						CompositeResource: in.CompositeResource,
+						RequiredResources: []*v1.ResourceSelector{
+							{
+								ApiVersion: "v1",
+								Kind:       ConfigMap,
+								Namespace:  new("default"),
+								Match:      &v1.ResourceSelector_MatchName{MatchName: fmt.Sprintf("cm-%d", iteration)},
+							},
+						},
+					}, nil
+				}
+			},
+			wantErr:         true,
+			wantErrContains: "the final render still required new resources (ConfigMap default/cm-20)",
+		},
+		"WarningsRecorded": {
+			xr:          xr,
+			composition: composition,
+			functions:   functions,
+			resourceID:  "XR/test-xr",
+			setupResourceClient: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().
+					WithNamespacedResource(
+						schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"},
+					).
+					WithResourceNotFound().
+					Build()
+			},
+			setupEnvironmentClient: func() *tu.MockEnvironmentClient {
+				return tu.NewMockEnvironmentClient().
+					WithNoEnvironmentConfigs().
+					Build()
+			},
+			setupRenderFunc: func() RenderFn {
+				return func(_ context.Context, _ logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+					// A requirement that can't be met, and one result of each severity.
+					return render.CompositionOutputs{
+						CompositeResource: in.CompositeResource,
+						RequiredResources: []*v1.ResourceSelector{
+							{
+								ApiVersion: "v1",
+								Kind:       ConfigMap,
+								Namespace:  new("default"),
+								Match:      &v1.ResourceSelector_MatchName{MatchName: "missing"},
+							},
+						},
+						Results: []un.Unstructured{
+							{Object: map[string]any{"severity": "Normal", "reason": "Ready", "message": "all good"}},
+							{Object: map[string]any{"severity": "Warning", "reason": "NoConfig", "message": "using defaults"}},
+						},
+					}, nil
+				}
+			},
+			wantRenderIterations: 1,
+			wantWarnings: []string{
+				"required ConfigMap default/missing not found",
+				"XR/test-xr: function returned a warning (NoConfig): using defaults",
+			},
+		},
 		"NoRequirements": {
 			xr:          xr,
 			composition: composition,
@@ -1336,6 +1762,7 @@ func TestDefaultDiffProcessor_RenderToStableState(t *testing.T) {
 			},
 			wantComposedCount:    0,
 			wantRenderIterations: 1,
+			wantWarnings:         []string{"required ConfigMap missing-config not found"},
 			wantErr:              false,
 		},
 		"ObservedResourcesPassedToRenderFunc": {
@@ -1447,12 +1874,15 @@ func TestDefaultDiffProcessor_RenderToStableState(t *testing.T) {
 			processor := NewDiffProcessor(k8.Clients{}, xp.Clients{Definition: tu.NewMockDefinitionClient().Build()}, baseOpts...)
 
 			// Call the method under test
-			output, err := processor.(*DefaultDiffProcessor).RenderToStableState(ctx, tt.xr, tt.composition, tt.functions, tt.resourceID, tt.observedResources, false)
+			renderCtx, warnings := WithRenderWarnings(ctx)
+			output, err := processor.(*DefaultDiffProcessor).RenderToStableState(renderCtx, tt.xr, tt.composition, tt.functions, tt.resourceID, tt.observedResources, false)
 
 			// Check error expectations
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("RenderToStableState() expected error but got none")
+				} else if !strings.Contains(err.Error(), tt.wantErrContains) {
+					t.Errorf("RenderToStableState() want error containing %q, got %v", tt.wantErrContains, err)
 				}
 
 				return
@@ -1474,6 +1904,10 @@ func TestDefaultDiffProcessor_RenderToStableState(t *testing.T) {
 				t.Errorf("RenderToStableState() returned %d composed resources, want %d",
 					len(output.ComposedResources), tt.wantComposedCount)
 			}
+
+			if diff := gcmp.Diff(tt.wantWarnings, warnings.Warnings()); diff != "" {
+				t.Errorf("RenderToStableState() recorded warnings -want, +got:\n%s", diff)
+			}
 		})
 	}
 }
@@ -1744,10 +2178,12 @@ func TestDefaultDiffProcessor_getCompositeResourceXRD(t *testing.T) {
 		resource    *un.Unstructured
 		wantIsXR    bool
 		wantXRDName string
+		wantErr     bool
 	}{
 		"ManagedResourceIsNotXR": {
 			defClient: tu.NewMockDefinitionClient().
 				WithXRDForXRNotFound().
+				WithXRDForClaimNotFound().
 				Build(),
 			resource: tu.NewResource("nop.example.org/v1alpha1", "NopResource", "test-managed").
 				WithSpecField("forProvider", map[string]any{
@@ -1777,7 +2213,7 @@ func TestDefaultDiffProcessor_getCompositeResourceXRD(t *testing.T) {
 			wantIsXR:    true,
 			wantXRDName: "xchildresources.nested.example.org",
 		},
-		"ErrorFromDefinitionClientHandled": {
+		"ErrorFromDefinitionClientReturned": {
 			defClient: tu.NewMockDefinitionClient().
 				WithXRDForXRError(errors.New("cluster connection error")).
 				Build(),
@@ -1786,6 +2222,7 @@ func TestDefaultDiffProcessor_getCompositeResourceXRD(t *testing.T) {
 				Build(),
 			wantIsXR:    false,
 			wantXRDName: "",
+			wantErr:     true,
 		},
 	}
 
@@ -1802,7 +2239,11 @@ func TestDefaultDiffProcessor_getCompositeResourceXRD(t *testing.T) {
 			}
 
 			// Call the method under test
-			isXR, xrd := processor.getCompositeResourceXRD(ctx, tt.resource)
+			isXR, xrd, err := processor.getCompositeResourceXRD(ctx, tt.resource)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getCompositeResourceXRD() error = %v, wantErr %t", err, tt.wantErr)
+			}
 
 			// Check isXR result
 			if diff := gcmp.Diff(tt.wantIsXR, isXR); diff != "" {
@@ -2268,6 +2709,149 @@ func TestDefaultDiffProcessor_ProcessNestedXRs(t *testing.T) {
 	}
 }
 
+func TestDefaultDiffProcessor_ProcessNestedXRs_PartialNested(t *testing.T) {
+	ctx := t.Context()
+
+	healthyXR := tu.NewResource("nested.example.org/v1alpha1", "XChildResource", "healthy-child").
+		WithSpecField("childField", "value").
+		WithCompositionResourceName("healthy").
+		Build()
+
+	brokenXR := tu.NewResource("nested.example.org/v1alpha1", "XChildResource", "broken-child").
+		WithSpecField("childField", "value").
+		WithCompositionResourceName("broken").
+		Build()
+
+	childXRD := tu.NewXRD("xchildresources.nested.example.org", "nested.example.org", "XChildResource").
+		WithVersion("v1alpha1", true, true).
+		Build()
+
+	childCRD := tu.NewCRD("xchildresources.nested.example.org", "nested.example.org", "XChildResource").
+		WithListKind("XChildResourceList").
+		WithPlural("xchildresources").
+		WithSingular("xchildresource").
+		WithVersion("v1alpha1", true, true).
+		WithStandardSchema("childField").
+		Build()
+
+	childComposition := tu.NewComposition("child-composition").
+		WithCompositeTypeRef("nested.example.org/v1alpha1", "XChildResource").
+		WithPipelineMode().
+		Build()
+
+	// The broken child's composition lookup fails with an error that isn't "no composition found".
+	compositionProvider := func(_ context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+		if res.GetName() == brokenXR.GetName() {
+			return nil, errors.New("composition function failed")
+		}
+
+		return childComposition, nil
+	}
+
+	tests := map[string]struct {
+		reason           string
+		partial          bool
+		wantDiffCount    int
+		wantSubtrees     []string
+		wantErrContain   string
+		wantPartialError bool
+	}{
+		"Disabled": {
+			reason:         "Should fail the whole tree when one nested XR fails",
+			partial:        false,
+			wantErrContain: "cannot process nested XR XChildResource/broken-child",
+		},
+		"Enabled": {
+			reason:           "Should keep the healthy sibling's diff and record the failed subtree",
+			partial:          true,
+			wantDiffCount:    1,
+			wantSubtrees:     []string{"XChildResource/broken-child (nested depth 1)"},
+			wantPartialError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			xpClients := xp.Clients{
+				Credential: &tu.MockCredentialClient{},
+				Definition: tu.NewMockDefinitionClient().
+					WithXRD(childXRD).
+					Build(),
+				Environment: tu.NewMockEnvironmentClient().
+					WithNoEnvironmentConfigs().
+					Build(),
+				Function: tu.NewMockFunctionClient().
+					WithSuccessfulFunctionsFetch(nil).
+					Build(),
+				ResourceTree: tu.NewMockResourceTreeClient().Build(),
+			}
+
+			k8sClients := k8.Clients{
+				Apply:    tu.NewMockApplyClient().Build(),
+				Resource: tu.NewMockResourceClient().Build(),
+				Schema: tu.NewMockSchemaClient().
+					WithFoundCRD("nested.example.org", "XChildResource", childCRD).
+					WithSuccessfulCRDByNameFetch("xchildresources.nested.example.org", childCRD).
+					Build(),
+				Type: tu.NewMockTypeConverter().Build(),
+			}
+
+			opts := append(testProcessorOptions(t),
+				WithPartialNested(tt.partial),
+				WithSchemaValidatorFactory(func(k8.SchemaClient, xp.DefinitionClient, logging.Logger) SchemaValidator {
+					return &tu.MockSchemaValidator{
+						ValidateResourcesFn: func(context.Context, *un.Unstructured, []cpd.Unstructured) error {
+							return nil
+						},
+					}
+				}),
+				WithDiffCalculatorFactory(func(k8.ApplyClient, xp.ResourceTreeClient, ResourceManager, logging.Logger, renderer.DiffOptions) DiffCalculator {
+					return &tu.MockDiffCalculator{
+						CalculateNonRemovalDiffsFn: func(_ context.Context, xr *cmp.Unstructured, _ *un.Unstructured, _ render.CompositionOutputs) (map[string]*dt.ResourceDiff, map[string]bool, error) {
+							key := xr.GetKind() + "/" + xr.GetName()
+
+							return map[string]*dt.ResourceDiff{
+								key: {Gvk: xr.GroupVersionKind(), ResourceName: xr.GetName(), DiffType: dt.DiffTypeAdded},
+							}, map[string]bool{key: true}, nil
+						},
+					}
+				}),
+			)
+			processor := NewDiffProcessor(k8sClients, xpClients, opts...).(*DefaultDiffProcessor)
+
+			composed := []cpd.Unstructured{{Unstructured: *healthyXR}, {Unstructured: *brokenXR}}
+
+			diffs, _, err := processor.ProcessNestedXRs(ctx, composed, compositionProvider, "XParentResource/test-parent", nil, nil, 1)
+
+			if tt.wantErrContain != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("\n%s\nProcessNestedXRs(): want error containing %q, got %v", tt.reason, tt.wantErrContain, err)
+				}
+
+				return
+			}
+
+			var partial *PartialNestedError
+			if got := errors.As(err, &partial); got != tt.wantPartialError {
+				t.Fatalf("\n%s\nProcessNestedXRs(): want PartialNestedError %t, got error %v", tt.reason, tt.wantPartialError, err)
+			}
+
+			got := make([]string, 0, len(partial.Subtrees))
+			for _, s := range partial.Subtrees {
+				got = append(got, s.ResourceID)
+			}
+
+			if diff := gcmp.Diff(tt.wantSubtrees, got); diff != "" {
+				t.Errorf("\n%s\nProcessNestedXRs(): failed subtrees -want +got:\n%s", tt.reason, diff)
+			}
+
+			if diff := gcmp.Diff(tt.wantDiffCount, len(diffs)); diff != "" {
+				t.Errorf("\n%s\nProcessNestedXRs(): diff count -want +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestDefaultDiffProcessor_DiffSingleResource_WithObservedResources(t *testing.T) {
 	ctx := t.Context()
 
@@ -3158,6 +3742,107 @@ func TestDefaultDiffProcessor_resolveBackingXRForClaim_SpecMerge(t *testing.T) {
 	}
 }
 
+// TestDefaultDiffProcessor_prepareXRForDiff_ClaimSpecPropagation tests that spec fields the pipeline
+// changes on a Claim's backing XR surface in the Claim's own diff, as Crossplane syncs them back.
+func TestDefaultDiffProcessor_prepareXRForDiff_ClaimSpecPropagation(t *testing.T) {
+	claimRef := map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "TestClaim",
+		"name":       "test-claim",
+		"namespace":  "default",
+	}
+
+	tests := map[string]struct {
+		reason        string
+		claimSpec     map[string]any
+		backingXRSpec map[string]any
+		renderedSpec  map[string]any
+		wantClaimSpec map[string]any
+	}{
+		"NewFieldWrittenByPipeline": {
+			reason:        "A spec field the new composition writes to the backing XR should appear on the Claim",
+			claimSpec:     map[string]any{"coolField": "value"},
+			backingXRSpec: map[string]any{"coolField": "value", "claimRef": claimRef},
+			renderedSpec:  map[string]any{"coolField": "value", "claimRef": claimRef, "tier": "premium"},
+			wantClaimSpec: map[string]any{"coolField": "value", "tier": "premium"},
+		},
+		"ExistingFieldChangedByPipeline": {
+			reason:        "A Claim spec field the pipeline overwrites should show the rendered value",
+			claimSpec:     map[string]any{"coolField": "value", "tier": "basic"},
+			backingXRSpec: map[string]any{"coolField": "value", "tier": "basic", "claimRef": claimRef},
+			renderedSpec:  map[string]any{"coolField": "value", "tier": "premium", "claimRef": claimRef},
+			wantClaimSpec: map[string]any{"coolField": "value", "tier": "premium"},
+		},
+		"NumericNormalizationIsNotAChange": {
+			reason:        "Render's float64 numbers should not be mistaken for changes to int64 fields",
+			claimSpec:     map[string]any{"replicas": int64(3)},
+			backingXRSpec: map[string]any{"replicas": int64(3), "claimRef": claimRef},
+			renderedSpec:  map[string]any{"replicas": float64(3), "claimRef": claimRef},
+			wantClaimSpec: map[string]any{"replicas": int64(3)},
+		},
+		"XROnlyFieldsNotPropagated": {
+			reason:        "Crossplane-managed XR fields set during render should never appear on the Claim",
+			claimSpec:     map[string]any{"coolField": "value"},
+			backingXRSpec: map[string]any{"coolField": "value", "claimRef": claimRef},
+			renderedSpec: map[string]any{
+				"coolField":               "value",
+				"claimRef":                claimRef,
+				"compositionRef":          map[string]any{"name": "test-comp"},
+				"compositionUpdatePolicy": "Automatic",
+				"resourceRefs":            []any{map[string]any{"kind": "Bucket", "name": "b"}},
+			},
+			wantClaimSpec: map[string]any{"coolField": "value"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			claim := cmp.New()
+			claim.SetUnstructuredContent(tu.NewResource("example.org/v1", "TestClaim", "test-claim").
+				InNamespace("default").
+				WithSpec(tt.claimSpec).
+				Build().Object)
+
+			backingXR := cmp.New()
+			backingXR.SetUnstructuredContent(tu.NewResource("example.org/v1", "XTestClaim", "test-claim-abc").
+				WithSpec(tt.backingXRSpec).
+				Build().Object)
+
+			rendered := cmp.New()
+			rendered.SetUnstructuredContent(tu.NewResource("example.org/v1", "XTestClaim", "test-claim-abc").
+				WithSpec(tt.renderedSpec).
+				Build().Object)
+
+			processor := &DefaultDiffProcessor{
+				config: ProcessorConfig{Logger: tu.TestLogger(t, false)},
+			}
+
+			got, err := processor.prepareXRForDiff(claim,
+				render.CompositionOutputs{CompositeResource: rendered},
+				backingXRInfo{xrForRendering: backingXR},
+				"test-claim")
+			if err != nil {
+				t.Fatalf("\n%s\nprepareXRForDiff(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := gcmp.Diff("TestClaim", got.GetKind()); diff != "" {
+				t.Errorf("\n%s\nprepareXRForDiff(...): -want kind, +got kind:\n%s", tt.reason, diff)
+			}
+
+			gotSpec, _, _ := un.NestedFieldNoCopy(got.Object, "spec")
+			if diff := gcmp.Diff(tt.wantClaimSpec, gotSpec); diff != "" {
+				t.Errorf("\n%s\nprepareXRForDiff(...): -want claim spec, +got claim spec:\n%s", tt.reason, diff)
+			}
+
+			// The input Claim must not be mutated.
+			inputSpec, _, _ := un.NestedFieldNoCopy(claim.Object, "spec")
+			if diff := gcmp.Diff(tt.claimSpec, inputSpec); diff != "" {
+				t.Errorf("\n%s\nprepareXRForDiff(...): input claim mutated:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 // TestDefaultDiffProcessor_resolveBackingXRForClaim_CompositionRevisionRef tests the compositionRevisionRef
 // preservation logic based on the compositionUpdatePolicy field.
 func TestDefaultDiffProcessor_resolveBackingXRForClaim_CompositionRevisionRef(t *testing.T) {
@@ -3368,6 +4053,374 @@ func TestDefaultDiffProcessor_resolveBackingXRForClaim_CompositionRevisionRef(t
 	}
 }
 
+func TestFilterDiffsByNamespace(t *testing.T) {
+	teamA := &dt.ResourceDiff{ResourceName: "a", Namespace: "team-a", DiffType: dt.DiffTypeModified}
+	teamB := &dt.ResourceDiff{ResourceName: "b", Namespace: "team-b", DiffType: dt.DiffTypeAdded}
+	cluster := &dt.ResourceDiff{ResourceName: "c", DiffType: dt.DiffTypeModified}
+
+	diffs := map[string]*dt.ResourceDiff{
+		"team-a/a": teamA,
+		"team-b/b": teamB,
+		"c":        cluster,
+	}
+
+	tests := map[string]struct {
+		reason    string
+		namespace string
+		want      map[string]*dt.ResourceDiff
+	}{
+		"MatchingNamespace": {
+			reason:    "Only diffs for resources in the requested namespace should be kept.",
+			namespace: "team-a",
+			want:      map[string]*dt.ResourceDiff{"team-a/a": teamA},
+		},
+		"NoMatches": {
+			reason:    "A namespace with no diffed resources should yield an empty result, not nil.",
+			namespace: "team-c",
+			want:      map[string]*dt.ResourceDiff{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := filterDiffsByNamespace(diffs, tt.namespace)
+			if diff := gcmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s\nfilterDiffsByNamespace(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFilterUnchangedDiffs(t *testing.T) {
+	added := &dt.ResourceDiff{ResourceName: "a", DiffType: dt.DiffTypeAdded}
+	removed := &dt.ResourceDiff{ResourceName: "r", DiffType: dt.DiffTypeRemoved}
+	equal := &dt.ResourceDiff{ResourceName: "e", DiffType: dt.DiffTypeEqual}
+	changed := &dt.ResourceDiff{ResourceName: "c", DiffType: dt.DiffTypeModified, LineDiffs: []diffmatchpatch.Diff{
+		{Type: diffmatchpatch.DiffEqual, Text: "a: 1\n"},
+		{Type: diffmatchpatch.DiffInsert, Text: "b: 2\n"},
+	}}
+	noLines := &dt.ResourceDiff{ResourceName: "n", DiffType: dt.DiffTypeModified, LineDiffs: []diffmatchpatch.Diff{
+		{Type: diffmatchpatch.DiffEqual, Text: "a: 1\n"},
+	}}
+
+	diffs := map[string]*dt.ResourceDiff{
+		"a": added,
+		"r": removed,
+		"e": equal,
+		"c": changed,
+		"n": noLines,
+	}
+
+	want := map[string]*dt.ResourceDiff{
+		"a": added,
+		"r": removed,
+		"c": changed,
+	}
+
+	got := filterUnchangedDiffs(diffs)
+	if diff := gcmp.Diff(want, got); diff != "" {
+		t.Errorf("Unchanged resources and modified ones with no changed lines should be dropped, while added and removed resources are kept.\nfilterUnchangedDiffs(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestFilterDiffsByKind(t *testing.T) {
+	xr := &dt.ResourceDiff{Gvk: schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"}, ResourceName: "xr", DiffType: dt.DiffTypeModified}
+	bucket := &dt.ResourceDiff{Gvk: schema.GroupVersionKind{Group: "s3.example.org", Version: "v1", Kind: "Bucket"}, ResourceName: "b", DiffType: dt.DiffTypeAdded}
+	role := &dt.ResourceDiff{Gvk: schema.GroupVersionKind{Group: "iam.example.org", Version: "v1", Kind: "Role"}, ResourceName: "r", DiffType: dt.DiffTypeModified}
+
+	diffs := map[string]*dt.ResourceDiff{
+		"xr":     xr,
+		"bucket": bucket,
+		"role":   role,
+	}
+
+	tests := map[string]struct {
+		reason string
+		kinds  []string
+		keep   map[string]bool
+		want   map[string]*dt.ResourceDiff
+	}{
+		"MatchingKind": {
+			reason: "Diffs for resources of a requested kind in any group should be kept.",
+			kinds:  []string{"Bucket"},
+			want:   map[string]*dt.ResourceDiff{"bucket": bucket},
+		},
+		"MatchingKindAndGroup": {
+			reason: "A Kind.group entry should only match resources in that group.",
+			kinds:  []string{"Bucket.s3.example.org", "Role.other.example.org"},
+			want:   map[string]*dt.ResourceDiff{"bucket": bucket},
+		},
+		"KeepsInputs": {
+			reason: "Diffs of the input resources should be kept whatever their kind.",
+			kinds:  []string{"Role"},
+			keep:   map[string]bool{"xr": true},
+			want:   map[string]*dt.ResourceDiff{"xr": xr, "role": role},
+		},
+		"NoMatches": {
+			reason: "Kinds with no diffed resources should yield an empty result, not nil.",
+			kinds:  []string{"Database"},
+			want:   map[string]*dt.ResourceDiff{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := filterDiffsByKind(diffs, tt.kinds, tt.keep)
+			if diff := gcmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s\nfilterDiffsByKind(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+// TestRemoveNamespacesFromClusterScopedResources covers a namespaced v2 XR whose render output
+// contains cluster-scoped resources. render copies the XR's namespace onto every composed resource,
+// so the namespace must be dropped from cluster-scoped ones before they're looked up in the cluster
+// or keyed for removal detection.
+func TestRemoveNamespacesFromClusterScopedResources(t *testing.T) {
+	ctx := t.Context()
+
+	bucketGVK := schema.GroupVersionKind{Group: "s3.example.org", Version: "v1", Kind: "Bucket"}
+	regionGVK := schema.GroupVersionKind{Group: "infra.example.org", Version: "v1", Kind: "Region"}
+	clusterRoleGVK := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}
+	unknownGVK := schema.GroupVersionKind{Group: "unknown.example.org", Version: "v1", Kind: "Thing"}
+
+	crds := map[schema.GroupVersionKind]*extv1.CustomResourceDefinition{
+		bucketGVK: tu.NewCRD("buckets.s3.example.org", "s3.example.org", "Bucket").WithNamespaceScope().Build(),
+		regionGVK: tu.NewCRD("regions.infra.example.org", "infra.example.org", "Region").WithClusterScope().Build(),
+	}
+
+	composed := func(gvk schema.GroupVersionKind, name string) cpd.Unstructured {
+		return cpd.Unstructured{Unstructured: *tu.NewResource(gvk.GroupVersion().String(), gvk.Kind, name).InNamespace("team-a").Build()}
+	}
+
+	tests := map[string]struct {
+		reason        string
+		composed      cpd.Unstructured
+		wantNamespace string
+		wantErr       string
+	}{
+		"NamespacedCRD": {
+			reason:        "A namespaced resource should keep the XR's namespace.",
+			composed:      composed(bucketGVK, "bucket"),
+			wantNamespace: "team-a",
+		},
+		"ClusterScopedCRD": {
+			reason:   "A cluster-scoped CRD resource should lose the XR's namespace.",
+			composed: composed(regionGVK, "region"),
+		},
+		"ClusterScopedBuiltIn": {
+			reason:   "A built-in cluster-scoped kind has no CRD, so its scope should come from discovery.",
+			composed: composed(clusterRoleGVK, "reader"),
+		},
+		"UnknownScope": {
+			reason:   "A kind with neither a CRD nor a discovery entry should be an error, not a guess.",
+			composed: composed(unknownGVK, "thing"),
+			wantErr:  "cannot determine scope for resource Thing/thing",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &DefaultDiffProcessor{
+				schemaClient: tu.NewMockSchemaClient().
+					WithGetCRD(func(_ context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error) {
+						if crd, ok := crds[gvk]; ok {
+							return crd, nil
+						}
+
+						return nil, errors.Errorf("CRD not found for %v", gvk)
+					}).
+					Build(),
+				resourceClient: tu.NewMockResourceClient().
+					WithClusterScopedResource(clusterRoleGVK).
+					Build(),
+				config: ProcessorConfig{Logger: tu.TestLogger(t, false)},
+			}
+
+			resources := []cpd.Unstructured{tt.composed}
+
+			err := p.removeNamespacesFromClusterScopedResources(ctx, resources)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("%s\nremoveNamespacesFromClusterScopedResources(): want error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nremoveNamespacesFromClusterScopedResources(): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := gcmp.Diff(tt.wantNamespace, resources[0].GetNamespace()); diff != "" {
+				t.Errorf("%s\nremoveNamespacesFromClusterScopedResources(): -want namespace, +got namespace:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFunctionInputs(t *testing.T) {
+	pipeline := func(inputs ...string) *apiextensionsv1.Composition {
+		comp := &apiextensionsv1.Composition{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-comp"},
+		}
+
+		for i, in := range inputs {
+			step := apiextensionsv1.PipelineStep{
+				Step:        fmt.Sprintf("step-%d", i),
+				FunctionRef: apiextensionsv1.FunctionReference{Name: "function-test"},
+			}
+			if in != "" {
+				step.Input = &runtime.RawExtension{Raw: []byte(in)}
+			}
+
+			comp.Spec.Pipeline = append(comp.Spec.Pipeline, step)
+		}
+
+		return comp
+	}
+
+	tests := map[string]struct {
+		reason     string
+		comp       *apiextensionsv1.Composition
+		inputs     map[string]map[string]any
+		wantInputs []string
+		wantErr    bool
+	}{
+		"NoInputs": {
+			reason:     "Without configured inputs the composition should be used as is.",
+			comp:       pipeline(`{"a":1}`),
+			wantInputs: []string{`{"a":1}`},
+		},
+		"DeepMerge": {
+			reason: "Nested objects should be merged, with values from the file winning and lists replaced.",
+			comp:   pipeline(`{"kind":"Input","cfg":{"region":"us-east-1","size":"small","zones":["a","b"]}}`),
+			inputs: map[string]map[string]any{
+				"step-0": {"cfg": map[string]any{"region": "eu-west-1", "zones": []any{"c"}}},
+			},
+			wantInputs: []string{`{"cfg":{"region":"eu-west-1","size":"small","zones":["c"]},"kind":"Input"}`},
+		},
+		"OnlyNamedStep": {
+			reason: "Input should only be merged into the step it names.",
+			comp:   pipeline(`{"a":1}`, `{"b":2}`),
+			inputs: map[string]map[string]any{
+				"step-1": {"c": 3},
+			},
+			wantInputs: []string{`{"a":1}`, `{"b":2,"c":3}`},
+		},
+		"StepWithoutInput": {
+			reason: "A step with no input should receive the file's input as is.",
+			comp:   pipeline(""),
+			inputs: map[string]map[string]any{
+				"step-0": {"a": "b"},
+			},
+			wantInputs: []string{`{"a":"b"}`},
+		},
+		"UnknownStep": {
+			reason: "Input for a step the composition doesn't have should be ignored.",
+			comp:   pipeline(`{"a":1}`),
+			inputs: map[string]map[string]any{
+				"missing": {"a": 2},
+			},
+			wantInputs: []string{`{"a":1}`},
+		},
+		"InvalidStepInput": {
+			reason: "A step input that isn't a JSON object should be reported.",
+			comp:   pipeline(`["a"]`),
+			inputs: map[string]map[string]any{
+				"step-0": {"a": 2},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			original := tt.comp.DeepCopy()
+
+			processor := &DefaultDiffProcessor{
+				config: ProcessorConfig{
+					Logger:         tu.TestLogger(t, false),
+					FunctionInputs: tt.inputs,
+				},
+			}
+
+			got, err := processor.applyFunctionInputs(tt.comp, "XR1/test-xr")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("%s\napplyFunctionInputs(...): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\napplyFunctionInputs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			gotInputs := make([]string, 0, len(got.Spec.Pipeline))
+			for _, step := range got.Spec.Pipeline {
+				gotInputs = append(gotInputs, string(step.Input.Raw))
+			}
+
+			if diff := gcmp.Diff(tt.wantInputs, gotInputs); diff != "" {
+				t.Errorf("%s\napplyFunctionInputs(...): -want inputs, +got inputs:\n%s", tt.reason, diff)
+			}
+
+			if diff := gcmp.Diff(original, tt.comp); diff != "" {
+				t.Errorf("%s\napplyFunctionInputs(...) modified the original composition: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStripSourceFile(t *testing.T) {
+	tests := map[string]struct {
+		reason          string
+		res             *un.Unstructured
+		wantAnnotations map[string]string
+		wantSourceFile  string
+	}{
+		"NoSourceFile": {
+			reason:          "A resource without the annotation should be returned as-is.",
+			res:             tu.NewResource("example.org/v1", "XR", "my-xr").WithAnnotations(map[string]string{"team": "a"}).Build(),
+			wantAnnotations: map[string]string{"team": "a"},
+		},
+		"OnlySourceFile": {
+			reason:         "Removing the only annotation should leave no annotations, so it doesn't show up in the diff.",
+			res:            tu.NewResource("example.org/v1", "XR", "my-xr").WithAnnotations(map[string]string{AnnotationSourceFile: "xrs/a.yaml"}).Build(),
+			wantSourceFile: "xrs/a.yaml",
+		},
+		"OtherAnnotations": {
+			reason:          "Other annotations should be kept.",
+			res:             tu.NewResource("example.org/v1", "XR", "my-xr").WithAnnotations(map[string]string{AnnotationSourceFile: "xrs/a.yaml", "team": "a"}).Build(),
+			wantAnnotations: map[string]string{"team": "a"},
+			wantSourceFile:  "xrs/a.yaml",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			original := tt.res.DeepCopy()
+
+			got, sourceFile := stripSourceFile(tt.res)
+
+			if diff := gcmp.Diff(tt.wantSourceFile, sourceFile); diff != "" {
+				t.Errorf("%s\nstripSourceFile(): -want source file, +got source file:\n%s", tt.reason, diff)
+			}
+
+			if diff := gcmp.Diff(tt.wantAnnotations, got.GetAnnotations()); diff != "" {
+				t.Errorf("%s\nstripSourceFile(): -want annotations, +got annotations:\n%s", tt.reason, diff)
+			}
+
+			if diff := gcmp.Diff(original, tt.res); diff != "" {
+				t.Errorf("%s\nstripSourceFile(): input was modified:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestMergeCredentials(t *testing.T) {
 	// Define common test secrets
 	var secret1NS1 corev1.Secret
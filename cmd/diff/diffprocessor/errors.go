@@ -91,6 +91,46 @@ func (e *SchemaValidationError) WithResult(result *pkgvalidate.ValidationResult)
 	return e
 }
 
+// NestedXRError records a nested XR whose subtree could not be diffed.
+type NestedXRError struct {
+	ResourceID string
+	Err        error
+}
+
+// Error implements the error interface.
+func (e *NestedXRError) Error() string {
+	return fmt.Sprintf("cannot process nested XR %s: %v", e.ResourceID, e.Err)
+}
+
+// Unwrap returns the wrapped error for errors.Is/As compatibility.
+func (e *NestedXRError) Unwrap() error {
+	return e.Err
+}
+
+// PartialNestedError is returned together with a diff when partial nested
+// processing is enabled and one or more nested XR subtrees failed. The diff
+// covers the parent and every subtree that succeeded; Subtrees lists the
+// ones that did not.
+type PartialNestedError struct {
+	Subtrees []*NestedXRError
+}
+
+// Error implements the error interface.
+func (e *PartialNestedError) Error() string {
+	return errors.Join(e.Unwrap()...).Error()
+}
+
+// Unwrap returns the subtree errors so exit code classification sees each
+// of them.
+func (e *PartialNestedError) Unwrap() []error {
+	errs := make([]error, len(e.Subtrees))
+	for i, s := range e.Subtrees {
+		errs[i] = s
+	}
+
+	return errs
+}
+
 // NewOutputError builds a structured-output entry for err, tagged with
 // resourceID. When err contains a *SchemaValidationError that carries a
 // pkgvalidate.ValidationResult, the returned OutputError also exposes a
@@ -23,6 +23,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
@@ -94,8 +95,10 @@ func (p *DefaultFunctionProvider) Cleanup(_ context.Context) error {
 // CachedFunctionProvider lazy-loads and caches functions with reuse annotations.
 // This is appropriate for the comp command where many XRs use the same composition,
 // allowing Docker containers to be reused across renders.
+// It is safe for concurrent use.
 type CachedFunctionProvider struct {
 	fnClient       xp.FunctionClient
+	mu             sync.Mutex // Guards cache and containerNames
 	cache          map[string][]pkgv1.Function
 	containerNames []string // Track container names for cleanup
 	instanceID     string   // Unique identifier for this provider instance
@@ -130,6 +133,11 @@ func generateInstanceID() string {
 func (p *CachedFunctionProvider) GetFunctionsForComposition(comp *apiextensionsv1.Composition) ([]pkgv1.Function, error) {
 	compName := comp.GetName()
 
+	// Hold the lock across the fetch so concurrent renders of the same composition share one set
+	// of container names.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if cached, ok := p.cache[compName]; ok {
 		p.logger.Debug("Using cached functions", "composition", compName, "count", len(cached))
 		return cached, nil
@@ -181,6 +189,9 @@ func (p *CachedFunctionProvider) GetFunctionsForComposition(comp *apiextensionsv
 
 // Cleanup stops and removes Docker containers created during function execution.
 func (p *CachedFunctionProvider) Cleanup(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if len(p.containerNames) == 0 {
 		p.logger.Debug("No containers to clean up")
 		return nil
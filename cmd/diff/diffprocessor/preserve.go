@@ -1,7 +1,12 @@
 package diffprocessor
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
+
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // Crossplane label keys used for resource identity and ownership.
@@ -67,3 +72,90 @@ func CopyCompositionRef(source, target *un.Unstructured) {
 		_ = un.SetNestedMap(target.Object, crossplane, "spec", "crossplane")
 	}
 }
+
+// PreserveForeignFields copies into desired the fields of current that field
+// managers other than ownManager own and that desired lacks, so fields set by
+// other controllers or defaulted by the API server aren't reported as removed.
+// Fields desired sets are never touched, nor are fields ownManager also owns, so
+// removing a field from a composition still shows. Only fields reached through
+// maps are considered; list items are left alone. It does nothing when
+// ownManager is empty, since then no field can be told apart as foreign. It
+// returns the dot-separated paths of the fields it copied.
+func PreserveForeignFields(current, desired *un.Unstructured, ownManager string) []string {
+	if current == nil || desired == nil || ownManager == "" {
+		return nil
+	}
+
+	var own, foreign [][]string
+
+	for _, mf := range current.GetManagedFields() {
+		if mf.FieldsV1 == nil {
+			continue
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(mf.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		if mf.Manager == ownManager {
+			own = append(own, managedFieldPaths(fields, nil)...)
+		} else {
+			foreign = append(foreign, managedFieldPaths(fields, nil)...)
+		}
+	}
+
+	var copied []string
+
+	for _, path := range foreign {
+		if slices.ContainsFunc(own, func(o []string) bool { return hasPathPrefix(path, o) || hasPathPrefix(o, path) }) {
+			continue
+		}
+
+		if _, found, _ := un.NestedFieldNoCopy(desired.Object, path...); found {
+			continue
+		}
+
+		value, found, _ := un.NestedFieldNoCopy(current.Object, path...)
+		if !found {
+			continue
+		}
+
+		if err := un.SetNestedField(desired.Object, runtime.DeepCopyJSONValue(value), path...); err == nil {
+			copied = append(copied, strings.Join(path, "."))
+		}
+	}
+
+	return copied
+}
+
+// managedFieldPaths returns the paths of the fields a managedFields FieldsV1
+// set owns outright: those with no owned children, or marked with ".". Paths
+// into list items (k:, v: and i: keys) are skipped.
+func managedFieldPaths(fields map[string]any, prefix []string) [][]string {
+	var paths [][]string
+
+	for key, child := range fields {
+		name, ok := strings.CutPrefix(key, "f:")
+		if !ok {
+			continue
+		}
+
+		path := append(slices.Clone(prefix), name)
+
+		children, _ := child.(map[string]any)
+		if _, whole := children["."]; whole || len(children) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+
+		paths = append(paths, managedFieldPaths(children, path)...)
+	}
+
+	return paths
+}
+
+// hasPathPrefix reports whether path starts with prefix.
+func hasPathPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && slices.Equal(path[:len(prefix)], prefix)
+}
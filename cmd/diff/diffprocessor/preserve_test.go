@@ -1,10 +1,12 @@
 package diffprocessor
 
 import (
+	"slices"
 	"testing"
 
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -318,3 +320,95 @@ func TestCopyCompositionRef_V2PreservesOtherCrossplaneFields(t *testing.T) {
 		t.Errorf("spec.crossplane mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestPreserveForeignFields(t *testing.T) {
+	const own = "apiextensions.crossplane.io/composed/abc"
+
+	managed := func(manager, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: manager, FieldsV1: &metav1.FieldsV1{Raw: []byte(fields)}}
+	}
+
+	current := func() *un.Unstructured {
+		res := tu.NewResource("example.org/v1", "Bucket", "bucket").
+			WithAnnotations(map[string]string{"controller.example.org/id": "123", "team": "a"}).
+			WithSpecField("region", "us-east-1").
+			WithSpecField("tier", "hot").
+			WithSpecField("ports", []any{int64(80)}).
+			Build()
+		res.SetManagedFields([]metav1.ManagedFieldsEntry{
+			managed(own, `{"f:metadata":{"f:annotations":{"f:team":{}}},"f:spec":{"f:region":{},"f:ports":{}}}`),
+			managed("bucket-controller", `{"f:metadata":{"f:annotations":{"f:controller.example.org/id":{}}},"f:spec":{"f:tier":{},"f:ports":{"k:{\"port\":80}":{}}}}`),
+		})
+
+		return res
+	}
+
+	tests := map[string]struct {
+		reason     string
+		ownManager string
+		desired    *un.Unstructured
+		wantPaths  []string
+		wantSpec   map[string]any
+		wantAnnots map[string]string
+	}{
+		"KeepsForeignFields": {
+			reason:     "Fields only another manager owns should be copied into desired when it lacks them.",
+			ownManager: own,
+			desired: tu.NewResource("example.org/v1", "Bucket", "bucket").
+				WithAnnotations(map[string]string{"team": "a"}).
+				WithSpecField("region", "us-east-1").
+				WithSpecField("ports", []any{int64(80)}).
+				Build(),
+			wantPaths:  []string{"metadata.annotations.controller.example.org/id", "spec.tier"},
+			wantSpec:   map[string]any{"region": "us-east-1", "tier": "hot", "ports": []any{int64(80)}},
+			wantAnnots: map[string]string{"controller.example.org/id": "123", "team": "a"},
+		},
+		"NeverHidesOwnRemovals": {
+			reason:     "Fields our manager owns should stay removed when desired drops them.",
+			ownManager: own,
+			desired: tu.NewResource("example.org/v1", "Bucket", "bucket").
+				WithSpecField("tier", "hot").
+				Build(),
+			wantPaths:  []string{"metadata.annotations.controller.example.org/id"},
+			wantSpec:   map[string]any{"tier": "hot"},
+			wantAnnots: map[string]string{"controller.example.org/id": "123"},
+		},
+		"NeverOverridesDesired": {
+			reason:     "A value desired sets should win even when another manager owns the field.",
+			ownManager: own,
+			desired: tu.NewResource("example.org/v1", "Bucket", "bucket").
+				WithAnnotations(map[string]string{"controller.example.org/id": "456", "team": "a"}).
+				WithSpecField("region", "us-east-1").
+				WithSpecField("tier", "cold").
+				WithSpecField("ports", []any{int64(80)}).
+				Build(),
+			wantSpec:   map[string]any{"region": "us-east-1", "tier": "cold", "ports": []any{int64(80)}},
+			wantAnnots: map[string]string{"controller.example.org/id": "456", "team": "a"},
+		},
+		"NoOwnManager": {
+			reason:   "Without a known field manager of our own nothing should be copied.",
+			desired:  tu.NewResource("example.org/v1", "Bucket", "bucket").WithSpecField("region", "us-east-1").Build(),
+			wantSpec: map[string]any{"region": "us-east-1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := PreserveForeignFields(current(), tt.desired, tt.ownManager)
+			slices.Sort(got)
+
+			if diff := cmp.Diff(tt.wantPaths, got); diff != "" {
+				t.Errorf("\n%s\nPreserveForeignFields(...): -want paths, +got paths:\n%s", tt.reason, diff)
+			}
+
+			spec, _, _ := un.NestedMap(tt.desired.Object, "spec")
+			if diff := cmp.Diff(tt.wantSpec, spec); diff != "" {
+				t.Errorf("\n%s\nPreserveForeignFields(...): -want spec, +got spec:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantAnnots, tt.desired.GetAnnotations()); diff != "" {
+				t.Errorf("\n%s\nPreserveForeignFields(...): -want annotations, +got annotations:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -2,11 +2,15 @@ package diffprocessor
 
 import (
 	"io"
+	"slices"
+	"time"
 
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	corev1 "k8s.io/api/core/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 )
@@ -22,20 +26,49 @@ type ProcessorConfig struct {
 	// Compact determines whether to show a compact diff format
 	Compact bool
 
+	// ContextLines is the number of unchanged lines shown around each change in compact diffs
+	ContextLines int
+
 	// OutputFormat specifies the output format for diffs (diff, json, yaml)
 	OutputFormat renderer.OutputFormat
 
 	// MaxNestedDepth is the maximum depth for recursive nested XR processing
 	MaxNestedDepth int
 
+	// MaxResources is the most resources an XR and its nested XRs may render, composed or
+	// observed, before the XR fails. Zero means no limit.
+	MaxResources int
+
+	// PartialNested, when true, records a failing nested XR subtree as an error and keeps
+	// diffing the rest of the tree instead of failing the whole XR.
+	PartialNested bool
+
 	// IncludeManual determines whether to include XRs with Manual update policy in composition diffs
 	IncludeManual bool
 
+	// AgainstCompositions, keyed by name, replace the cluster's compositions as the baseline
+	// a composition diff compares against. Each affected XR is then rendered with both the
+	// baseline and the updated composition, and the two renderings are diffed.
+	AgainstCompositions map[string]*un.Unstructured
+
 	// MinimizeComposition collapses composition changes to a single marker line per
 	// composition, omitting the full YAML diff body. Human renderer only; structured
 	// output always includes full compositionChanges.
 	MinimizeComposition bool
 
+	// DiffStyle selects how human-readable diffs are laid out, unified or side-by-side.
+	DiffStyle renderer.DiffStyle
+
+	// DiffWidth is the width side-by-side diffs are laid out in, usually the terminal width.
+	DiffWidth int
+
+	// Quiet limits human-readable output to actual changes and their summary, or a single
+	// "No changes." line when nothing changed.
+	Quiet bool
+
+	// SummaryOnly prints only the counts of changes instead of the diffs themselves.
+	SummaryOnly bool
+
 	// EventualState enables iterative simulation to show eventual state after all reconciliation
 	// cycles complete. Useful with function-sequencer which hides later stage resources.
 	EventualState bool
@@ -44,15 +77,136 @@ type ProcessorConfig struct {
 	// or simulating eventual state. Higher values may be needed for complex pipelines.
 	MaxRenderIterations int
 
+	// ResourceTimeout bounds how long diffing each input XR, including its nested XRs, may take.
+	// An XR that runs out of time fails on its own while the rest carry on. Zero means no limit.
+	ResourceTimeout time.Duration
+
+	// MaxConcurrentRenders bounds how many renders the default engine-backed RenderFn runs at once,
+	// independently of how many resources are processed concurrently. Values below one serialize.
+	MaxConcurrentRenders int
+
+	// MaxConcurrentXRs bounds how many affected XRs the comp command diffs at once. Values below
+	// one diff them one at a time.
+	MaxConcurrentXRs int
+
+	// Progress writes an "Analyzing N/M XRs..." line to Stderr, updated in place, as the comp
+	// command diffs the affected XRs of each composition. The CLI only sets it when stderr is a
+	// terminal.
+	Progress bool
+
 	// IgnorePaths is a list of paths to ignore when calculating diffs
 	IgnorePaths []string
 
+	// FilterNamespace, when set, limits the rendered XR diff output to resources in this namespace.
+	// The full resource tree is still rendered and diffed.
+	FilterNamespace string
+
+	// FilterKinds, when set, limits the rendered XR diff output to resources of these kinds, each
+	// Kind or Kind.group, plus the input XRs themselves. The full resource tree is still rendered
+	// and diffed.
+	FilterKinds []string
+
+	// OnlyChanged drops unchanged resources, and modified ones with no changed lines, from the
+	// rendered XR diff output. Added and removed resources are always kept.
+	OnlyChanged bool
+
+	// Baseline, when set, holds the changes of an earlier run. Only changes that are new or
+	// different since then are rendered, followed by a report of new, resolved, and unchanged
+	// drift.
+	Baseline *renderer.Baseline
+
+	// AllowManaged diffs input resources that aren't XRs or claims, such as managed resources
+	// created directly, with a plain dry-run apply instead of failing to find a composition.
+	AllowManaged bool
+
+	// SkipMissingRevisions renders an XR pinned to a CompositionRevision that no longer exists
+	// against the composition's latest revision, noting it on the XR's diff, instead of failing.
+	SkipMissingRevisions bool
+
+	// LatestRevision renders every XR with a compositionRef against its composition's latest
+	// revision, whatever its update policy or pin, and keeps Manual XRs in composition diffs.
+	LatestRevision bool
+
+	// Strict fails a resource whose diff recorded rendering warnings, such as a missing
+	// EnvironmentConfig, instead of diffing it on a best-effort basis.
+	Strict bool
+
+	// Provenance, when set, is filled in with the compositions the XRs were rendered with and the
+	// time of the diff, and rendered with the output.
+	Provenance *renderer.Provenance
+
+	// FailOn lists the diff types that count as differences for the exit code. Nil counts every
+	// change; an empty, non-nil list counts none.
+	FailOn []dt.DiffType
+
+	// OwnerController, when true, only matches existing composed resources whose
+	// controller owner reference points at the expected composite.
+	OwnerController bool
+
+	// ShowWarnings, when true, surfaces API server warnings from dry-run applies per resource.
+	ShowWarnings bool
+
+	// ShowExternalResources, when true, records the required resources each XR's functions were
+	// given on the XR's diff, so they're listed in the output.
+	ShowExternalResources bool
+
+	// Normalize treats scalars that differ only in representation as equal when diffing.
+	Normalize bool
+
+	// ShowLabelsDiffOnly limits rendered diffs to label and annotation changes.
+	ShowLabelsDiffOnly bool
+
+	// IgnoreManagedFields keeps fields owned by other field managers from showing as removed.
+	IgnoreManagedFields bool
+
+	// FieldManager overrides the field manager used for dry-run applies.
+	FieldManager string
+
+	// ForceConflicts makes dry-run applies take ownership of fields other field managers own.
+	ForceConflicts bool
+
+	// MaxDiffFieldSize is the size in bytes above which string fields are compared by digest
+	// instead of line-diffed. Zero disables the limit.
+	MaxDiffFieldSize int
+
 	// FunctionCredentials holds Secret credentials to pass to Functions during rendering
 	FunctionCredentials []corev1.Secret
 
+	// NormalizationRules are per-kind normalizations applied to both sides of each diff.
+	NormalizationRules []renderer.NormalizationRule
+
+	// Inspect, in Kind/name form, prints the observed and desired objects of that resource
+	// instead of rendering diffs.
+	Inspect string
+
+	// ValidateOnly skips rendering and diffing: each resource's composition and functions are
+	// resolved and the resource is schema validated, and a JSON validation report is written.
+	ValidateOnly bool
+
 	// FunctionRegistryOverride overrides the registry in all function package refs.
 	FunctionRegistryOverride string
 
+	// DryRunNamespace is the namespace used for dry-run applies of namespaced resources
+	// that have no namespace after render.
+	DryRunNamespace string
+
+	// DryRunKinds, when set, limits dry-run applies to these kinds; NoDryRunKinds excludes
+	// kinds from dry-run. Excluded kinds are diffed locally.
+	DryRunKinds   []string
+	NoDryRunKinds []string
+
+	// ContextResources seeds the function pipeline context before every render, keyed by
+	// context key.
+	ContextResources map[string]any
+
+	// ExternalResources, when set, are the resources functions' requirements are resolved from,
+	// in place of the cluster's.
+	ExternalResources []*un.Unstructured
+
+	// FunctionInputs holds extra input merged into pipeline steps before render, keyed by
+	// step name.
+	FunctionInputs map[string]map[string]any
+
 	// Stdout is the writer for diff output (defaults to os.Stdout)
 	Stdout io.Writer
 
@@ -148,6 +302,21 @@ func WithMaxNestedDepth(depth int) ProcessorOption {
 	}
 }
 
+// WithMaxResources sets the most resources an XR and its nested XRs may render.
+func WithMaxResources(limit int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.MaxResources = limit
+	}
+}
+
+// WithPartialNested sets whether a failing nested XR subtree is reported as an error
+// while the rest of the tree is still diffed.
+func WithPartialNested(partial bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.PartialNested = partial
+	}
+}
+
 // WithIncludeManual sets whether to include XRs with Manual update policy in composition diffs.
 func WithIncludeManual(includeManual bool) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -155,6 +324,17 @@ func WithIncludeManual(includeManual bool) ProcessorOption {
 	}
 }
 
+// WithAgainstCompositions sets the compositions a composition diff compares against in
+// place of the cluster's, matched by name.
+func WithAgainstCompositions(comps []*un.Unstructured) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.AgainstCompositions = make(map[string]*un.Unstructured, len(comps))
+		for _, comp := range comps {
+			config.AgainstCompositions[comp.GetName()] = comp
+		}
+	}
+}
+
 // WithMinimizeComposition sets whether to collapse composition changes to a single marker line.
 func WithMinimizeComposition(minimize bool) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -162,6 +342,41 @@ func WithMinimizeComposition(minimize bool) ProcessorOption {
 	}
 }
 
+// WithContextLines sets the number of unchanged lines shown around each change in compact diffs.
+func WithContextLines(lines int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ContextLines = lines
+	}
+}
+
+// WithDiffStyle sets how human-readable diffs are laid out.
+func WithDiffStyle(style renderer.DiffStyle) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.DiffStyle = style
+	}
+}
+
+// WithDiffWidth sets the width side-by-side diffs are laid out in.
+func WithDiffWidth(width int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.DiffWidth = width
+	}
+}
+
+// WithSummaryOnly sets whether to print only the counts of changes.
+func WithSummaryOnly(summaryOnly bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.SummaryOnly = summaryOnly
+	}
+}
+
+// WithQuiet sets whether to print only actual changes and their summary.
+func WithQuiet(quiet bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Quiet = quiet
+	}
+}
+
 // WithEventualState sets whether to show eventual state after all reconciliation cycles complete.
 // When enabled, the processor runs an iterative simulation that synthesizes Ready status on
 // rendered resources until no new resources appear. This is useful with function-sequencer
@@ -180,6 +395,13 @@ func WithMaxRenderIterations(maxIterations int) ProcessorOption {
 	}
 }
 
+// WithResourceTimeout bounds how long diffing each input XR may take. Zero means no limit.
+func WithResourceTimeout(timeout time.Duration) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ResourceTimeout = timeout
+	}
+}
+
 // WithIgnorePaths sets the paths to ignore when calculating diffs.
 func WithIgnorePaths(ignorePaths []string) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -187,6 +409,208 @@ func WithIgnorePaths(ignorePaths []string) ProcessorOption {
 	}
 }
 
+// WithMaxConcurrentRenders bounds how many renders the default engine-backed RenderFn runs at once.
+func WithMaxConcurrentRenders(n int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.MaxConcurrentRenders = n
+	}
+}
+
+// WithOwnerController restricts owner-based resource matching to resources
+// controlled by the expected composite.
+func WithOwnerController(enabled bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.OwnerController = enabled
+	}
+}
+
+// WithMaxConcurrentXRs bounds how many affected XRs the comp command diffs at once.
+func WithMaxConcurrentXRs(n int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.MaxConcurrentXRs = n
+	}
+}
+
+// WithProgress sets whether to report progress through the affected XRs on stderr.
+func WithProgress(progress bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Progress = progress
+	}
+}
+
+// WithShowExternalResources lists the required resources each XR's functions were given in the
+// diff output.
+func WithShowExternalResources(show bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ShowExternalResources = show
+	}
+}
+
+// WithShowWarnings surfaces API server warnings from dry-run applies in the diff output.
+func WithShowWarnings(show bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ShowWarnings = show
+	}
+}
+
+// WithDryRunKinds limits dry-run applies to the given kinds (Kind or Kind.group).
+// Resources of other kinds are diffed locally.
+func WithDryRunKinds(kinds []string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.DryRunKinds = kinds
+	}
+}
+
+// WithNoDryRunKinds diffs resources of the given kinds (Kind or Kind.group) locally
+// instead of through a dry-run apply.
+func WithNoDryRunKinds(kinds []string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.NoDryRunKinds = kinds
+	}
+}
+
+// WithFilterNamespace limits the rendered XR diff output to resources in the given namespace.
+func WithFilterNamespace(namespace string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.FilterNamespace = namespace
+	}
+}
+
+// WithFilterKinds limits the rendered XR diff output to the input XRs and resources of the given
+// kinds, each Kind or Kind.group.
+func WithFilterKinds(kinds []string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.FilterKinds = kinds
+	}
+}
+
+// WithOnlyChanged sets whether to drop resources with no changed lines from the XR diff output.
+func WithOnlyChanged(onlyChanged bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.OnlyChanged = onlyChanged
+	}
+}
+
+// WithBaseline sets the changes of an earlier run to report drift against.
+func WithBaseline(baseline *renderer.Baseline) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Baseline = baseline
+	}
+}
+
+// WithProvenance sets the provenance to record and render with the output.
+func WithProvenance(provenance *renderer.Provenance) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Provenance = provenance
+	}
+}
+
+// WithAllowManaged sets whether input resources that aren't XRs or claims are diffed directly
+// with a dry-run apply, rather than failing because they have no composition.
+func WithAllowManaged(allow bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.AllowManaged = allow
+	}
+}
+
+// WithSkipMissingRevisions sets whether an XR pinned to a missing CompositionRevision falls back
+// to the latest revision, with a note on its diff, rather than failing.
+func WithSkipMissingRevisions(skip bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.SkipMissingRevisions = skip
+	}
+}
+
+// WithLatestRevision sets whether every XR is rendered against its composition's latest revision,
+// as if its update policy were Automatic.
+func WithLatestRevision(latest bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.LatestRevision = latest
+	}
+}
+
+// WithStrict sets whether rendering warnings fail the resource they were found for.
+func WithStrict(strict bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Strict = strict
+	}
+}
+
+// WithFailOn sets the diff types that count as differences for the exit code.
+func WithFailOn(types []dt.DiffType) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.FailOn = types
+	}
+}
+
+// WithNormalizationRules sets per-kind normalization rules applied to both sides of each diff.
+func WithNormalizationRules(rules []renderer.NormalizationRule) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.NormalizationRules = rules
+	}
+}
+
+// WithValidateOnly validates resources and writes a JSON validation report instead of diffing.
+func WithValidateOnly(validateOnly bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ValidateOnly = validateOnly
+	}
+}
+
+// WithInspect prints the observed and desired objects of the resource named Kind/name instead
+// of rendering diffs.
+func WithInspect(resource string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Inspect = resource
+	}
+}
+
+// WithShowLabelsDiffOnly sets whether rendered diffs are limited to changes under
+// metadata.labels and metadata.annotations. Resources changed elsewhere are still counted.
+func WithShowLabelsDiffOnly(only bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ShowLabelsDiffOnly = only
+	}
+}
+
+// WithNormalize sets whether scalars that differ only in representation are treated as equal.
+func WithNormalize(normalize bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.Normalize = normalize
+	}
+}
+
+// WithIgnoreManagedFields sets whether fields owned by other field managers are kept from
+// showing as removed.
+func WithIgnoreManagedFields(ignore bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.IgnoreManagedFields = ignore
+	}
+}
+
+// WithFieldManager sets the field manager used for dry-run applies. Empty keeps the default:
+// the resource's Crossplane field owner, or crossplane-diff when it has none.
+func WithFieldManager(manager string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.FieldManager = manager
+	}
+}
+
+// WithForceConflicts makes dry-run applies force ownership of fields other field managers
+// own, so the diff reflects a forced apply. Without it such conflicts fail the diff.
+func WithForceConflicts(force bool) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ForceConflicts = force
+	}
+}
+
+// WithMaxDiffFieldSize sets the size in bytes above which string fields are compared by digest.
+func WithMaxDiffFieldSize(size int) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.MaxDiffFieldSize = size
+	}
+}
+
 // WithFunctionCredentials sets the credentials to pass to Functions during rendering.
 func WithFunctionCredentials(creds []corev1.Secret) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -194,6 +618,30 @@ func WithFunctionCredentials(creds []corev1.Secret) ProcessorOption {
 	}
 }
 
+// WithContextResources seeds the function pipeline context with the supplied values,
+// keyed by context key, before every render.
+func WithContextResources(resources map[string]any) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ContextResources = resources
+	}
+}
+
+// WithExternalResources resolves functions' required resources from the supplied resources
+// instead of the cluster.
+func WithExternalResources(resources []*un.Unstructured) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.ExternalResources = resources
+	}
+}
+
+// WithFunctionInputs merges the supplied input into the pipeline steps they name, keyed by
+// step name, before every render.
+func WithFunctionInputs(inputs map[string]map[string]any) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.FunctionInputs = inputs
+	}
+}
+
 // WithFunctionRegistryOverride overrides the registry in all function package refs.
 func WithFunctionRegistryOverride(registry string) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -201,6 +649,14 @@ func WithFunctionRegistryOverride(registry string) ProcessorOption {
 	}
 }
 
+// WithDryRunNamespace sets the namespace used for dry-run applies of namespaced
+// resources that have no namespace after render.
+func WithDryRunNamespace(namespace string) ProcessorOption {
+	return func(config *ProcessorConfig) {
+		config.DryRunNamespace = namespace
+	}
+}
+
 // WithStdout sets the writer for diff output.
 func WithStdout(w io.Writer) ProcessorOption {
 	return func(config *ProcessorConfig) {
@@ -301,14 +757,51 @@ func WithFunctionProviderFactory(factory func(xp.FunctionClient, logging.Logger)
 	}
 }
 
+// UseColors reports whether diff output should be colorized. The text-no-ansi
+// output format never is, regardless of Colorize.
+func (c *ProcessorConfig) UseColors() bool {
+	return c.Colorize && c.OutputFormat != renderer.OutputFormatTextNoANSI
+}
+
+// failsOn reports whether a diff of type t counts as a difference for the exit code under FailOn.
+// Unchanged diffs never do.
+func (c *ProcessorConfig) failsOn(t dt.DiffType) bool {
+	if t == dt.DiffTypeEqual {
+		return false
+	}
+
+	return c.FailOn == nil || slices.Contains(c.FailOn, t)
+}
+
 // GetDiffOptions returns DiffOptions based on the ProcessorConfig.
 func (c *ProcessorConfig) GetDiffOptions() renderer.DiffOptions {
 	opts := renderer.DefaultDiffOptions()
-	opts.UseColors = c.Colorize
+	opts.UseColors = c.UseColors()
 	opts.Compact = c.Compact
+	opts.ContextLines = c.ContextLines
 	opts.MinimizeComposition = c.MinimizeComposition
+	opts.Quiet = c.Quiet
+	opts.SummaryOnly = c.SummaryOnly
+	opts.Width = c.DiffWidth
+
+	if c.DiffStyle != "" {
+		opts.Style = c.DiffStyle
+	}
 
 	opts.IgnorePaths = c.IgnorePaths
+	opts.MaxFieldSize = c.MaxDiffFieldSize
+	opts.Normalize = c.Normalize
+	opts.ShowLabelsDiffOnly = c.ShowLabelsDiffOnly
+	opts.IgnoreManagedFields = c.IgnoreManagedFields
+	opts.FieldManager = c.FieldManager
+	opts.ForceConflicts = c.ForceConflicts
+	opts.ShowWarnings = c.ShowWarnings
+	opts.DryRunKinds = c.DryRunKinds
+	opts.NoDryRunKinds = c.NoDryRunKinds
+	opts.Inspect = c.Inspect
+	opts.NormalizationRules = c.NormalizationRules
+	opts.Provenance = c.Provenance
+
 	if c.OutputFormat != "" {
 		opts.Format = c.OutputFormat
 	}
@@ -328,7 +821,10 @@ func (c *ProcessorConfig) GetDiffOptions() renderer.DiffOptions {
 // SetDefaultFactories sets default component factory functions if not already set.
 func (c *ProcessorConfig) SetDefaultFactories() {
 	if c.Factories.ResourceManager == nil {
-		c.Factories.ResourceManager = NewResourceManager
+		requireController := c.OwnerController
+		c.Factories.ResourceManager = func(client k8.ResourceClient, defClient xp.DefinitionClient, treeClient xp.ResourceTreeClient, logger logging.Logger) ResourceManager {
+			return NewResourceManager(client, defClient, treeClient, logger, WithRequireController(requireController))
+		}
 	}
 
 	if c.Factories.SchemaValidator == nil {
@@ -339,12 +835,29 @@ func (c *ProcessorConfig) SetDefaultFactories() {
 		c.Factories.DiffCalculator = NewDiffCalculator
 	}
 
+	// Inspecting a resource replaces diff rendering whatever the output format.
+	if c.Factories.DiffRenderer == nil && c.Inspect != "" {
+		c.Factories.DiffRenderer = renderer.NewInspectDiffRenderer
+	}
+
 	if c.Factories.DiffRenderer == nil {
 		// Set the appropriate renderer factory based on output format
 		switch c.OutputFormat {
 		case renderer.OutputFormatJSON, renderer.OutputFormatYAML:
 			c.Factories.DiffRenderer = renderer.NewStructuredDiffRenderer
-		case renderer.OutputFormatDiff:
+		case renderer.OutputFormatCSV:
+			c.Factories.DiffRenderer = renderer.NewCSVDiffRenderer
+		case renderer.OutputFormatDesired:
+			c.Factories.DiffRenderer = renderer.NewDesiredStateRenderer
+		case renderer.OutputFormatGitHub:
+			c.Factories.DiffRenderer = renderer.NewGitHubDiffRenderer
+		case renderer.OutputFormatDOT:
+			c.Factories.DiffRenderer = renderer.NewDOTDiffRenderer
+		case renderer.OutputFormatMarkdown:
+			c.Factories.DiffRenderer = renderer.NewMarkdownDiffRenderer
+		case renderer.OutputFormatJUnit:
+			c.Factories.DiffRenderer = renderer.NewJUnitDiffRenderer
+		case renderer.OutputFormatDiff, renderer.OutputFormatTextNoANSI:
 			c.Factories.DiffRenderer = renderer.NewDiffRenderer
 		default:
 			c.Factories.DiffRenderer = renderer.NewDiffRenderer
@@ -358,7 +871,7 @@ func (c *ProcessorConfig) SetDefaultFactories() {
 			c.Factories.CompDiffRenderer = func(logger logging.Logger, _ renderer.DiffRenderer, opts renderer.DiffOptions) renderer.CompDiffRenderer {
 				return renderer.NewStructuredCompDiffRenderer(logger, opts)
 			}
-		case renderer.OutputFormatDiff:
+		case renderer.OutputFormatDiff, renderer.OutputFormatTextNoANSI, renderer.OutputFormatCSV, renderer.OutputFormatDesired, renderer.OutputFormatGitHub, renderer.OutputFormatDOT, renderer.OutputFormatMarkdown, renderer.OutputFormatJUnit:
 			fallthrough
 		default:
 			c.Factories.CompDiffRenderer = renderer.NewDefaultCompDiffRenderer
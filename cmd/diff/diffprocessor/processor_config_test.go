@@ -83,6 +83,33 @@ func TestDiffOptions(t *testing.T) {
 				opts.UseColors = true
 				opts.Compact = true
 
+				return opts
+			}(),
+		},
+		{
+			name: "TextNoANSIOverridesColorize",
+			config: ProcessorConfig{
+				Colorize:     true,
+				OutputFormat: renderer.OutputFormatTextNoANSI,
+			},
+			expected: func() renderer.DiffOptions {
+				opts := renderer.DefaultDiffOptions()
+				opts.UseColors = false
+
+				return opts
+			}(),
+		},
+		{
+			name: "MaxDiffFieldSize",
+			config: ProcessorConfig{
+				Colorize:         true,
+				MaxDiffFieldSize: 1024,
+			},
+			expected: func() renderer.DiffOptions {
+				opts := renderer.DefaultDiffOptions()
+				opts.UseColors = true
+				opts.MaxFieldSize = 1024
+
 				return opts
 			}(),
 		},
@@ -99,6 +126,10 @@ func TestDiffOptions(t *testing.T) {
 			if diff := gcmp.Diff(tt.expected.Compact, got.Compact); diff != "" {
 				t.Errorf("GetDiffOptions().Compact mismatch (-want +got):\n%s", diff)
 			}
+
+			if diff := gcmp.Diff(tt.expected.MaxFieldSize, got.MaxFieldSize); diff != "" {
+				t.Errorf("GetDiffOptions().MaxFieldSize mismatch (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
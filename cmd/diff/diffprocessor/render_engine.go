@@ -24,6 +24,7 @@ import (
 	"sync"
 
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
+	"github.com/crossplane/cli/v2/cmd/crossplane/render/contextfn"
 	corev1 "k8s.io/api/core/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/kube-openapi/pkg/spec3"
@@ -42,6 +43,34 @@ import (
 // engine and FunctionAddresses lifecycle.
 type RenderFn func(ctx context.Context, log logging.Logger, in RenderInputs) (render.CompositionOutputs, error)
 
+// logRenderResults wraps fn so the results each render's pipeline returns, and
+// any render error with the runtime output it carries, are logged with the
+// logger passed to the render. The CLI only binds a logger that prints with
+// --verbose, so non-verbose output is unchanged.
+func logRenderResults(fn RenderFn) RenderFn {
+	return func(ctx context.Context, log logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+		out, err := fn(ctx, log, in)
+
+		keys := []any{"composition", in.Composition.GetName()}
+		if in.CompositeResource != nil {
+			keys = append(keys, "xr", in.CompositeResource.GetKind()+"/"+in.CompositeResource.GetName())
+		}
+
+		for _, r := range out.Results {
+			severity, _, _ := kunstructured.NestedString(r.Object, "severity")
+			reason, _, _ := kunstructured.NestedString(r.Object, "reason")
+			message, _, _ := kunstructured.NestedString(r.Object, "message")
+			log.Info("Function result", append(slices.Clone(keys), "severity", severity, "reason", reason, "message", message)...)
+		}
+
+		if err != nil {
+			log.Info("Render failed", append(slices.Clone(keys), "error", err.Error())...)
+		}
+
+		return out, err
+	}
+}
+
 // RenderInputs carries what the diff processor already holds. It deliberately
 // omits FunctionAddrs — that's engine state, not caller state.
 type RenderInputs struct {
@@ -62,11 +91,18 @@ type RenderInputs struct {
 	// comparable against cluster state. Optional; when nil the binary
 	// falls back to SchemaModern.
 	XRD *kunstructured.Unstructured
+
+	// Context seeds the function pipeline context before the first step
+	// runs, keyed by context key. Used to supply values that a real control
+	// plane would have placed there (e.g. an EnvironmentConfig under
+	// apiextensions.crossplane.io/environment). Optional; when empty the
+	// pipeline starts with an empty context as usual.
+	Context map[string]any
 }
 
 // EngineRenderFn is the default RenderFn implementation. It lazily starts
 // function runtimes on first use, reuses them across subsequent calls, and
-// serializes concurrent renders with an internal mutex.
+// bounds concurrent renders (serializing them by default).
 //
 // A single `xr` invocation can render against XRs from multiple compositions
 // whose function pipelines overlap but aren't identical. Engine.Setup
@@ -93,12 +129,38 @@ type EngineRenderFn struct {
 	mu    sync.Mutex
 	log   logging.Logger
 
+	// renderSlots bounds how many renders run at once; its capacity is the
+	// maximum. Runtime setup is serialized separately by mu.
+	renderSlots chan struct{}
+
 	// startRuntimes / stopRuntimes are seams for testing. They default to the
 	// real render package functions.
 	startRuntimes func(ctx context.Context, log logging.Logger, fns []pkgv1.Function) (*render.FunctionAddresses, error)
 	stopRuntimes  func(log logging.Logger, fa *render.FunctionAddresses)
 }
 
+// seedContext starts the upstream in-process context function for one render
+// and returns a copy of comp with its seed step prepended. The caller must
+// Stop the returned handle once the render completes. The context function is
+// hosted by this process on a unix socket, so the engine never needs to Setup
+// or start a runtime for it; the docker engine bind-mounts the socket
+// directory into the render container.
+func (e *EngineRenderFn) seedContext(ctx context.Context, log logging.Logger, comp *apiextensionsv1.Composition, data map[string]any) (*contextfn.Handle, *apiextensionsv1.Composition, error) {
+	if err := e.engine.CheckContextSupport(); err != nil {
+		return nil, nil, errors.Wrap(err, "render engine cannot seed the function pipeline context")
+	}
+
+	h, err := contextfn.Start(ctx, log, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot start context function")
+	}
+
+	seeded := comp.DeepCopy()
+	seeded.Spec.Pipeline = append([]apiextensionsv1.PipelineStep{h.CompositeSeedStep()}, seeded.Spec.Pipeline...)
+
+	return h, seeded, nil
+}
+
 // NewEngineRenderFn constructs an EngineRenderFn.
 //
 // binaryPath, version, and image select the render backend via
@@ -123,7 +185,10 @@ type EngineRenderFn struct {
 // annotates each fn at Setup time so its container joins it too — closing
 // both halves of the "crossplane-diff inside a container" case
 // (crossplane/cli#75). For the local engine the flag is a no-op.
-func NewEngineRenderFn(log logging.Logger, binaryPath, version, image string) *EngineRenderFn {
+//
+// maxConcurrentRenders bounds how many Render calls may run at once; values
+// below one are treated as one, which fully serializes rendering.
+func NewEngineRenderFn(log logging.Logger, binaryPath, version, image string, maxConcurrentRenders int) *EngineRenderFn {
 	return &EngineRenderFn{
 		engine: render.NewEngineFromFlags(&render.EngineFlags{
 			CrossplaneBinary:        binaryPath,
@@ -132,17 +197,91 @@ func NewEngineRenderFn(log logging.Logger, binaryPath, version, image string) *E
 			CrossplaneDockerNetwork: os.Getenv(EnvDockerNetwork),
 		}, log),
 		log:           log,
+		renderSlots:   make(chan struct{}, max(maxConcurrentRenders, 1)),
 		startRuntimes: render.StartFunctionRuntimes,
 		stopRuntimes:  render.StopFunctionRuntimes,
 	}
 }
 
-// Render performs one render. It is safe for concurrent use — calls are
-// serialized internally. Setup runs only on invocations that introduce
-// previously-unseen functions; renders whose fns are all already running
-// skip straight to building the request. See the EngineRenderFn docstring
-// for the per-batch Setup contract this relies on.
+// Render performs one render. It is safe for concurrent use — at most
+// maxConcurrentRenders calls (one by default) run at a time, and function
+// runtime setup is always serialized. Setup runs only on invocations that
+// introduce previously-unseen functions; renders whose fns are all already
+// running skip straight to building the request. See the EngineRenderFn
+// docstring for the per-batch Setup contract this relies on.
 func (e *EngineRenderFn) Render(ctx context.Context, log logging.Logger, in RenderInputs) (render.CompositionOutputs, error) {
+	select {
+	case e.renderSlots <- struct{}{}:
+	case <-ctx.Done():
+		return render.CompositionOutputs{}, errors.Wrap(ctx.Err(), "cannot acquire render slot")
+	}
+	defer func() { <-e.renderSlots }()
+
+	fnAddrs, err := e.ensureRuntimes(ctx, log, in.Functions)
+	if err != nil {
+		return render.CompositionOutputs{}, err
+	}
+
+	comp := in.Composition
+	if len(in.Context) > 0 {
+		h, seeded, err := e.seedContext(ctx, log, comp, in.Context)
+		if err != nil {
+			return render.CompositionOutputs{}, err
+		}
+		defer h.Stop()
+
+		comp = seeded
+		fnAddrs[contextfn.FunctionName] = h.Target
+	}
+
+	req, err := render.BuildCompositeRequest(render.CompositionInputs{
+		CompositeResource:   in.CompositeResource,
+		Composition:         comp,
+		FunctionAddrs:       fnAddrs,
+		FunctionCredentials: in.FunctionCredentials,
+		ObservedResources:   in.ObservedResources,
+		RequiredResources:   in.RequiredResources,
+		RequiredSchemas:     in.RequiredSchemas,
+		XRD:                 in.XRD,
+	})
+	if err != nil {
+		return render.CompositionOutputs{}, errors.Wrap(err, "cannot build render request")
+	}
+
+	rsp, renderErr := e.engine.Render(ctx, req)
+	// When a pipeline step returns SEVERITY_FATAL, the binary exits with a
+	// distinct code, surfaces a populated rsp, and returns an error. Treat
+	// this case as "we have partial output; preserve it AND the error so
+	// the caller (RenderToStableState) can iterate on RequiredResources
+	// even when the pipeline fataled."
+	//
+	// Anything else with rsp == nil (including a hypothetical (nil, nil) from
+	// a misbehaving Engine implementation) is a hard failure — we can't parse
+	// what we don't have.
+	if rsp == nil {
+		if renderErr == nil {
+			return render.CompositionOutputs{}, errors.New("render engine returned nil response with no error")
+		}
+
+		return render.CompositionOutputs{}, errors.Wrap(renderErr, "cannot render")
+	}
+
+	out, err := render.ParseCompositeResponse(rsp.GetComposite())
+	if err != nil {
+		return render.CompositionOutputs{}, errors.Wrap(err, "cannot parse render response")
+	}
+
+	if renderErr != nil {
+		return out, errors.Wrap(renderErr, "render returned partial output after pipeline fatal")
+	}
+
+	return out, nil
+}
+
+// ensureRuntimes starts runtimes for any of fns not already running and
+// returns the addresses for fns. It holds the engine mutex throughout, so
+// concurrent renders never Setup or start the same function twice.
+func (e *EngineRenderFn) ensureRuntimes(ctx context.Context, log logging.Logger, fns []pkgv1.Function) (map[string]string, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -157,13 +296,13 @@ func (e *EngineRenderFn) Render(ctx context.Context, log logging.Logger, in Rend
 	// Identify functions we haven't started yet. Dedup is by function name —
 	// independent of whether the address map has an entry, so test stubs that
 	// return an empty *FunctionAddresses don't mistakenly re-trigger Start.
-	newFns := make([]pkgv1.Function, 0, len(in.Functions))
-	for i := range in.Functions {
-		if _, ok := e.startedNames[in.Functions[i].GetName()]; ok {
+	newFns := make([]pkgv1.Function, 0, len(fns))
+	for i := range fns {
+		if _, ok := e.startedNames[fns[i].GetName()]; ok {
 			continue
 		}
 
-		newFns = append(newFns, in.Functions[i])
+		newFns = append(newFns, fns[i])
 	}
 
 	// Only call Setup when there's actually a new batch to integrate.
@@ -177,7 +316,7 @@ func (e *EngineRenderFn) Render(ctx context.Context, log logging.Logger, in Rend
 		// whatever cleanup it gives back and let Cleanup walk them LIFO.
 		cleanup, err := e.engine.Setup(ctx, newFns)
 		if err != nil {
-			return render.CompositionOutputs{}, errors.Wrap(err, "cannot setup render engine")
+			return nil, errors.Wrap(err, "cannot setup render engine")
 		}
 
 		fa, startErr := e.startRuntimes(ctx, log, newFns)
@@ -186,7 +325,7 @@ func (e *EngineRenderFn) Render(ctx context.Context, log logging.Logger, in Rend
 			// cleanup releases it; otherwise cleanup is a no-op and no
 			// harm is done.
 			cleanup()
-			return render.CompositionOutputs{}, errors.Wrap(startErr, "cannot start function runtimes")
+			return nil, errors.Wrap(startErr, "cannot start function runtimes")
 		}
 
 		e.addrsList = append(e.addrsList, fa)
@@ -198,59 +337,18 @@ func (e *EngineRenderFn) Render(ctx context.Context, log logging.Logger, in Rend
 		e.cleanups = append(e.cleanups, cleanup)
 	}
 
-	// Build request with addresses for in.Functions only — the binary needs
+	// Build request with addresses for fns only — the binary needs
 	// addresses for this render's pipeline, not for every function we've
 	// ever started.
-	fnAddrs := make(map[string]string, len(in.Functions))
-	for i := range in.Functions {
-		name := in.Functions[i].GetName()
+	fnAddrs := make(map[string]string, len(fns))
+	for i := range fns {
+		name := fns[i].GetName()
 		if a, ok := e.addrs[name]; ok {
 			fnAddrs[name] = a
 		}
 	}
 
-	req, err := render.BuildCompositeRequest(render.CompositionInputs{
-		CompositeResource:   in.CompositeResource,
-		Composition:         in.Composition,
-		FunctionAddrs:       fnAddrs,
-		FunctionCredentials: in.FunctionCredentials,
-		ObservedResources:   in.ObservedResources,
-		RequiredResources:   in.RequiredResources,
-		RequiredSchemas:     in.RequiredSchemas,
-		XRD:                 in.XRD,
-	})
-	if err != nil {
-		return render.CompositionOutputs{}, errors.Wrap(err, "cannot build render request")
-	}
-
-	rsp, renderErr := e.engine.Render(ctx, req)
-	// When a pipeline step returns SEVERITY_FATAL, the binary exits with a
-	// distinct code, surfaces a populated rsp, and returns an error. Treat
-	// this case as "we have partial output; preserve it AND the error so
-	// the caller (RenderToStableState) can iterate on RequiredResources
-	// even when the pipeline fataled."
-	//
-	// Anything else with rsp == nil (including a hypothetical (nil, nil) from
-	// a misbehaving Engine implementation) is a hard failure — we can't parse
-	// what we don't have.
-	if rsp == nil {
-		if renderErr == nil {
-			return render.CompositionOutputs{}, errors.New("render engine returned nil response with no error")
-		}
-
-		return render.CompositionOutputs{}, errors.Wrap(renderErr, "cannot render")
-	}
-
-	out, err := render.ParseCompositeResponse(rsp.GetComposite())
-	if err != nil {
-		return render.CompositionOutputs{}, errors.Wrap(err, "cannot parse render response")
-	}
-
-	if renderErr != nil {
-		return out, errors.Wrap(renderErr, "render returned partial output after pipeline fatal")
-	}
-
-	return out, nil
+	return fnAddrs, nil
 }
 
 // Cleanup stops every function runtime started across the engine's lifetime
@@ -21,11 +21,17 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/crossplane/cli/v2/cmd/crossplane/render"
+	"github.com/crossplane/cli/v2/cmd/crossplane/render/contextfn"
 	renderv1alpha1 "github.com/crossplane/cli/v2/proto/render/v1alpha1"
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	ucomposite "github.com/crossplane/crossplane-runtime/v2/pkg/resource/unstructured/composite"
 
@@ -38,8 +44,9 @@ import (
 // stopRuntimes counter ticks once per invocation so tests can assert cleanup.
 func newTestRenderFn(mock *render.MockEngine, startCalls, stopCalls *int32) *EngineRenderFn {
 	return &EngineRenderFn{
-		engine: mock,
-		log:    logging.NewNopLogger(),
+		engine:      mock,
+		log:         logging.NewNopLogger(),
+		renderSlots: make(chan struct{}, 1),
 		startRuntimes: func(_ context.Context, _ logging.Logger, _ []pkgv1.Function) (*render.FunctionAddresses, error) {
 			atomic.AddInt32(startCalls, 1)
 			// Empty FunctionAddresses — Addresses() returns nil, which is fine for BuildCompositeRequest.
@@ -286,6 +293,71 @@ func TestEngineRenderFn_Serialization(t *testing.T) {
 	}
 }
 
+// TestEngineRenderFn_BoundedParallelism asserts that raising the render
+// bound lets that many renders run inside engine.Render at once, while
+// function runtimes are still started only once.
+func TestEngineRenderFn_BoundedParallelism(t *testing.T) {
+	ctx := t.Context()
+
+	const parallel = 2
+
+	var (
+		inFlight    atomic.Int32
+		bothEntered = make(chan struct{})
+		allowReturn = make(chan struct{})
+	)
+
+	mock := &render.MockEngine{
+		MockRender: func(_ context.Context, req *renderv1alpha1.RenderRequest) (*renderv1alpha1.RenderResponse, error) {
+			if inFlight.Add(1) == parallel {
+				close(bothEntered)
+			}
+
+			<-allowReturn
+
+			return &renderv1alpha1.RenderResponse{
+				Output: &renderv1alpha1.RenderResponse_Composite{
+					Composite: &renderv1alpha1.CompositeOutput{
+						CompositeResource: req.GetComposite().GetCompositeResource(),
+					},
+				},
+			}, nil
+		},
+	}
+
+	var startCalls, stopCalls int32
+
+	e := newTestRenderFn(mock, &startCalls, &stopCalls)
+	e.renderSlots = make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+	wg.Add(parallel)
+
+	for range parallel {
+		go func() {
+			defer wg.Done()
+
+			if _, err := e.Render(ctx, logging.NewNopLogger(), minimalRenderInputs()); err != nil {
+				t.Errorf("Render: %v", err)
+			}
+		}()
+	}
+
+	// Both renders must be able to enter engine.Render before either returns.
+	select {
+	case <-bothEntered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("renders in flight = %d, want %d concurrently", inFlight.Load(), parallel)
+	}
+
+	close(allowReturn)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&startCalls); got != 1 {
+		t.Fatalf("startRuntimes calls = %d, want 1", got)
+	}
+}
+
 // TestEngineRenderFn_MultiCompositionFunctionSet asserts that EngineRenderFn
 // correctly handles renders whose RenderInputs.Functions slice differs across
 // calls — the case where one `xr` invocation processes XRs that resolve to
@@ -346,8 +418,9 @@ func TestEngineRenderFn_MultiCompositionFunctionSet(t *testing.T) {
 	)
 
 	e := &EngineRenderFn{
-		engine: mock,
-		log:    logging.NewNopLogger(),
+		engine:      mock,
+		log:         logging.NewNopLogger(),
+		renderSlots: make(chan struct{}, 1),
 		startRuntimes: func(_ context.Context, _ logging.Logger, fns []pkgv1.Function) (*render.FunctionAddresses, error) {
 			startCallsMu.Lock()
 			defer startCallsMu.Unlock()
@@ -465,8 +538,9 @@ func TestEngineRenderFn_PreservesExistingNetworkAnnotation(t *testing.T) {
 	)
 
 	e := &EngineRenderFn{
-		engine: mock,
-		log:    logging.NewNopLogger(),
+		engine:      mock,
+		log:         logging.NewNopLogger(),
+		renderSlots: make(chan struct{}, 1),
 		startRuntimes: func(_ context.Context, _ logging.Logger, fns []pkgv1.Function) (*render.FunctionAddresses, error) {
 			seenMu.Lock()
 			defer seenMu.Unlock()
@@ -554,8 +628,9 @@ func TestEngineRenderFn_CleanupStopsAllFunctionAddresses(t *testing.T) {
 	var stopCalls atomic.Int32
 
 	e := &EngineRenderFn{
-		engine: mock,
-		log:    logging.NewNopLogger(),
+		engine:      mock,
+		log:         logging.NewNopLogger(),
+		renderSlots: make(chan struct{}, 1),
 		startRuntimes: func(_ context.Context, _ logging.Logger, _ []pkgv1.Function) (*render.FunctionAddresses, error) {
 			return &render.FunctionAddresses{}, nil
 		},
@@ -614,3 +689,171 @@ func equalStartCall(a, b struct {
 
 	return true
 }
+
+func TestEngineRenderFn_SeedsContext(t *testing.T) {
+	tests := map[string]struct {
+		reason       string
+		context      map[string]any
+		checkErr     error
+		wantSeedStep bool
+		wantErr      bool
+	}{
+		"NoContext": {
+			reason: "Without context data the composition pipeline should be passed through untouched.",
+		},
+		"WithContext": {
+			reason:       "Context data should prepend the context function's seed step and route it to the in-process function.",
+			context:      map[string]any{"apiextensions.crossplane.io/environment": map[string]any{"region": "us-east-1"}},
+			wantSeedStep: true,
+		},
+		"EngineCannotSeed": {
+			reason:   "An engine that cannot reach the in-process function should fail the render.",
+			context:  map[string]any{"key": "value"},
+			checkErr: errors.New("context not supported"),
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var req *renderv1alpha1.RenderRequest
+
+			mock := &render.MockEngine{
+				MockCheckContextSupport: func() error { return tt.checkErr },
+				MockRender: func(_ context.Context, r *renderv1alpha1.RenderRequest) (*renderv1alpha1.RenderResponse, error) {
+					req = r
+
+					return &renderv1alpha1.RenderResponse{
+						Output: &renderv1alpha1.RenderResponse_Composite{
+							Composite: &renderv1alpha1.CompositeOutput{
+								CompositeResource: r.GetComposite().GetCompositeResource(),
+							},
+						},
+					}, nil
+				},
+			}
+
+			var startCalls, stopCalls int32
+
+			e := newTestRenderFn(mock, &startCalls, &stopCalls)
+
+			in := minimalRenderInputs()
+			in.Composition.Spec.Pipeline = []apiextensionsv1.PipelineStep{
+				{Step: "user-step", FunctionRef: apiextensionsv1.FunctionReference{Name: "fn-default"}},
+			}
+			in.Context = tt.context
+
+			_, err := e.Render(t.Context(), logging.NewNopLogger(), in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("%s\nexpected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nunexpected error: %v", tt.reason, err)
+			}
+
+			pipeline := req.GetComposite().GetComposition().GetFields()["spec"].GetStructValue().GetFields()["pipeline"].GetListValue().GetValues()
+
+			wantSteps := 1
+			if tt.wantSeedStep {
+				wantSteps = 2
+			}
+
+			if len(pipeline) != wantSteps {
+				t.Fatalf("%s\npipeline steps = %d, want %d", tt.reason, len(pipeline), wantSteps)
+			}
+
+			first := pipeline[0].GetStructValue().GetFields()["functionRef"].GetStructValue().GetFields()["name"].GetStringValue()
+			if got := first == contextfn.FunctionName; got != tt.wantSeedStep {
+				t.Errorf("%s\nfirst step function = %q, seed step expected: %t", tt.reason, first, tt.wantSeedStep)
+			}
+
+			var routed bool
+
+			for _, fn := range req.GetComposite().GetFunctions() {
+				if fn.GetName() == contextfn.FunctionName {
+					routed = true
+				}
+			}
+
+			if routed != tt.wantSeedStep {
+				t.Errorf("%s\ncontext function address present = %t, want %t", tt.reason, routed, tt.wantSeedStep)
+			}
+
+			if len(in.Composition.Spec.Pipeline) != 1 {
+				t.Errorf("%s\ninput composition was mutated: %d steps", tt.reason, len(in.Composition.Spec.Pipeline))
+			}
+		})
+	}
+}
+
+func TestLogRenderResults(t *testing.T) {
+	result := func(severity, reason, message string) kunstructured.Unstructured {
+		return kunstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "render.crossplane.io/v1beta1",
+			"kind":       "Result",
+			"severity":   severity,
+			"reason":     reason,
+			"message":    message,
+		}}
+	}
+
+	tests := map[string]struct {
+		reason   string
+		out      render.CompositionOutputs
+		err      error
+		wantLogs []string
+	}{
+		"NoResults": {
+			reason: "A render without results or errors should log nothing.",
+		},
+		"Results": {
+			reason: "Each function result should be logged with its severity, reason and message.",
+			out: render.CompositionOutputs{Results: []kunstructured.Unstructured{
+				result("Warning", "TemplateWarning", "field .spec.size is deprecated"),
+			}},
+			wantLogs: []string{`"level"=0 "msg"="Function result" "composition"="comp" "xr"="XExample/test-xr" "severity"="Warning" "reason"="TemplateWarning" "message"="field .spec.size is deprecated"`},
+		},
+		"FatalWithPartialOutput": {
+			reason: "A fatal render should log both the results returned before it and the error with the runtime output.",
+			out: render.CompositionOutputs{Results: []kunstructured.Unstructured{
+				result("Warning", "Fatal", "template: main:3: unexpected EOF"),
+			}},
+			err: errors.New("pipeline returned fatal: function-go-templating: template: main:3: unexpected EOF"),
+			wantLogs: []string{
+				`"level"=0 "msg"="Function result" "composition"="comp" "xr"="XExample/test-xr" "severity"="Warning" "reason"="Fatal" "message"="template: main:3: unexpected EOF"`,
+				`"level"=0 "msg"="Render failed" "composition"="comp" "xr"="XExample/test-xr" "error"="pipeline returned fatal: function-go-templating: template: main:3: unexpected EOF"`,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logs []string
+
+			log := logging.NewLogrLogger(funcr.New(func(_, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{}))
+
+			in := minimalRenderInputs()
+			in.Composition.SetName("comp")
+
+			fn := logRenderResults(func(context.Context, logging.Logger, RenderInputs) (render.CompositionOutputs, error) {
+				return tt.out, tt.err
+			})
+
+			_, err := fn(t.Context(), log, in)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("\n%s\nlogRenderResults(...): want error %v, got %v", tt.reason, tt.err, err)
+			}
+
+			if diff := cmp.Diff(tt.wantLogs, logs); diff != "" {
+				t.Errorf("\n%s\nlogRenderResults(...): -want logs, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package diffprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+)
+
+// renderWarningsKey is the context key under which the rendering warnings recorder is stored.
+type renderWarningsKey struct{}
+
+// WithRenderWarnings returns a context that records the rendering warnings found while diffing with
+// it, and the recorder that collects them. Rendering warnings are the problems that don't stop the
+// diff but may leave it incomplete, such as a missing EnvironmentConfig or a function result of
+// severity Warning. With --strict they fail the resource.
+func WithRenderWarnings(ctx context.Context) (context.Context, *core.WarningRecorder) {
+	w := &core.WarningRecorder{}
+	return context.WithValue(ctx, renderWarningsKey{}, w), w
+}
+
+// recordRenderWarning records a warning with the rendering warnings recorder in ctx, if any.
+func recordRenderWarning(ctx context.Context, format string, args ...any) {
+	if w, ok := ctx.Value(renderWarningsKey{}).(*core.WarningRecorder); ok {
+		w.Record(fmt.Sprintf(format, args...))
+	}
+}
@@ -2,6 +2,10 @@ package diffprocessor
 
 import (
 	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -9,6 +13,7 @@ import (
 	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
 	v1 "github.com/crossplane/function-sdk-go/proto/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -110,6 +115,135 @@ func (p *RequirementsProvider) getCachedResource(apiVersion, kind, namespace, na
 	return p.resourceCache[key]
 }
 
+// selectorCacheKey is the context key under which a selectorCache is stored.
+type selectorCacheKey struct{}
+
+// selectorCache holds what each required resource selector resolved to, so a selector a pipeline
+// requests on every iteration of a render loop is only fetched once.
+type selectorCache struct {
+	mu       sync.Mutex
+	resolved map[string][]*un.Unstructured
+}
+
+// WithSelectorCache returns a context in which ResolveSelectors resolves each distinct selector
+// once. Scope it to a single top-level XR: another XR may legitimately need different matches.
+func WithSelectorCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, selectorCacheKey{}, &selectorCache{resolved: make(map[string][]*un.Unstructured)})
+}
+
+// selectorCacheFrom returns the selectorCache in ctx, or nil if there is none.
+func selectorCacheFrom(ctx context.Context) *selectorCache {
+	c, _ := ctx.Value(selectorCacheKey{}).(*selectorCache)
+	return c
+}
+
+func (c *selectorCache) get(key string) ([]*un.Unstructured, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res, ok := c.resolved[key]
+
+	return res, ok
+}
+
+func (c *selectorCache) put(key string, res []*un.Unstructured) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resolved[key] = res
+}
+
+// selectorCacheKeyFor identifies what selector resolves to for an XR in xrNamespace: its
+// apiVersion, kind, namespace and match criteria, and the namespace a name match defaults to.
+func selectorCacheKeyFor(selector *v1.ResourceSelector, xrNamespace string) string {
+	match := "name=" + selector.GetMatchName()
+	if selector.GetMatchLabels() != nil {
+		match = "labels=" + formatLabels(selector.GetMatchLabels().GetLabels())
+	}
+
+	return strings.Join([]string{selector.GetApiVersion(), selector.GetKind(), selector.GetNamespace(), match, xrNamespace}, "|")
+}
+
+// requiredResourcesKey is the context key under which a requiredResources recorder is stored.
+type requiredResourcesKey struct{}
+
+// requiredResources records the resources ResolveSelectors supplies for an XR, for
+// --show-external-resources.
+type requiredResources struct {
+	mu        sync.Mutex
+	resources map[string]dt.RequiredResource
+}
+
+// withRequiredResources returns a context in which ResolveSelectors records what it resolves, and
+// the recorder.
+func withRequiredResources(ctx context.Context) (context.Context, *requiredResources) {
+	r := &requiredResources{resources: make(map[string]dt.RequiredResource)}
+	return context.WithValue(ctx, requiredResourcesKey{}, r), r
+}
+
+// recordRequiredResources records res, which selector matched, with the recorder in ctx, if any.
+// A resource matched more than once is recorded once, with the first match.
+func recordRequiredResources(ctx context.Context, selector *v1.ResourceSelector, res []*un.Unstructured) {
+	r, ok := ctx.Value(requiredResourcesKey{}).(*requiredResources)
+	if !ok {
+		return
+	}
+
+	match := "name"
+	if selector.GetMatchLabels() != nil {
+		match = "labels " + formatLabels(selector.GetMatchLabels().GetLabels())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range res {
+		key := dt.MakeDiffKeyFromResource(u)
+		if _, exists := r.resources[key]; exists {
+			continue
+		}
+
+		r.resources[key] = dt.RequiredResource{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+			Match:      match,
+		}
+	}
+}
+
+// Resources returns the recorded resources, ordered by key.
+func (r *requiredResources) Resources() []dt.RequiredResource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]dt.RequiredResource, 0, len(r.resources))
+	for _, k := range slices.Sorted(maps.Keys(r.resources)) {
+		out = append(out, r.resources[k])
+	}
+
+	return out
+}
+
+// formatLabels formats labels as comma-separated key=value pairs, sorted by key.
+func formatLabels(l map[string]string) string {
+	pairs := make([]string, 0, len(l))
+	for _, k := range slices.Sorted(maps.Keys(l)) {
+		pairs = append(pairs, k+"="+l[k])
+	}
+
+	return strings.Join(pairs, ",")
+}
+
 // ResolveSelectors resolves a flat list of ResourceSelector entries into their
 // backing resources. Checks the cache first; on a miss it defers to the
 // per-selector fetcher and caches the result.
@@ -127,12 +261,26 @@ func (p *RequirementsProvider) ResolveSelectors(ctx context.Context, selectors [
 		newlyFetchedResources []*un.Unstructured
 	)
 
+	cache := selectorCacheFrom(ctx)
+
 	for i, selector := range selectors {
+		key := selectorCacheKeyFor(selector, xrNamespace)
+		if res, ok := cache.get(key); ok {
+			recordRequiredResources(ctx, selector, res)
+
+			allResources = append(allResources, res...)
+
+			continue
+		}
+
 		res, fetched, err := p.processSelector(ctx, strconv.Itoa(i), selector, xrNamespace)
 		if err != nil {
 			return nil, err
 		}
 
+		cache.put(key, res)
+		recordRequiredResources(ctx, selector, res)
+
 		allResources = append(allResources, res...)
 		newlyFetchedResources = append(newlyFetchedResources, fetched...)
 	}
@@ -230,6 +378,23 @@ func (p *RequirementsProvider) resolveNamespace(ctx context.Context, gvk schema.
 func (p *RequirementsProvider) processNameSelector(ctx context.Context, selector *v1.ResourceSelector, gvk schema.GroupVersionKind, xrNamespace string) ([]*un.Unstructured, bool, error) {
 	name := selector.GetMatchName()
 
+	// EnvironmentConfigs come from the environment client, so configs given with --env-config-file
+	// take the place of the cluster's whatever apiVersion the requirement names.
+	if isEnvironmentConfig(gvk) {
+		config, err := p.envClient.GetEnvironmentConfig(ctx, name)
+		switch {
+		case apierrors.IsNotFound(err), err == nil && config == nil:
+			p.logger.Debug("Required environment config not found; treating as unmet requirement", "name", name)
+			recordRenderWarning(ctx, "required EnvironmentConfig %s not found", name)
+
+			return nil, false, nil
+		case err != nil:
+			return nil, false, errors.Wrapf(err, "cannot get environment config %s", name)
+		}
+
+		return []*un.Unstructured{config}, true, nil
+	}
+
 	// Resolve namespace FIRST so we can check cache correctly.
 	// This prevents returning wrong resources when same-named resources exist
 	// in different namespaces (e.g., ConfigMap/my-config in both ns-a and ns-b).
@@ -268,6 +433,14 @@ func (p *RequirementsProvider) processNameSelector(ctx context.Context, selector
 				"namespace", ns,
 				"error", err)
 
+			// With --strict an unmet requirement fails the resource; the function never saw it.
+			id := name
+			if ns != "" {
+				id = ns + "/" + name
+			}
+
+			recordRenderWarning(ctx, "required %s %s not found", gvk.Kind, id)
+
 			return nil, false, nil
 		}
 
@@ -300,6 +473,14 @@ func (p *RequirementsProvider) processLabelSelector(ctx context.Context, selecto
 		MatchLabels: selector.GetMatchLabels().GetLabels(),
 	}
 
+	// EnvironmentConfigs are selected from the environment client, which already holds them all, the
+	// same way Crossplane resolves a function's EnvironmentConfig selector.
+	if isEnvironmentConfig(gvk) {
+		p.logger.Debug("Selecting environment configs by label", "labels", labelSelector.MatchLabels)
+
+		return p.envClient.GetEnvironmentConfigsBySelector(ctx, labelSelector)
+	}
+
 	isNamespaced, err := p.client.IsNamespacedResource(ctx, gvk)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot determine namespace scope for resource %s", gvk.String())
@@ -321,6 +502,85 @@ func (p *RequirementsProvider) processLabelSelector(ctx context.Context, selecto
 	return p.client.GetResourcesByLabel(ctx, gvk, ns, labelSelector)
 }
 
+// environmentConfigsInputGroup is the API group of function-environment-configs' input, which is
+// where a composition names the EnvironmentConfigs it requires.
+const environmentConfigsInputGroup = "environmentconfigs.fn.crossplane.io"
+
+// MissingEnvironmentConfigs returns the names of the EnvironmentConfigs comp's pipeline references by
+// name that the environment client can't find, sorted. References by label selector are skipped;
+// matching nothing isn't an error for them.
+func (p *RequirementsProvider) MissingEnvironmentConfigs(ctx context.Context, comp *apiextensionsv1.Composition) ([]string, error) {
+	var missing []string
+
+	seen := make(map[string]bool)
+
+	for _, step := range comp.Spec.Pipeline {
+		for _, name := range environmentConfigReferences(step) {
+			if seen[name] {
+				continue
+			}
+
+			seen[name] = true
+
+			config, err := p.envClient.GetEnvironmentConfig(ctx, name)
+			switch {
+			case apierrors.IsNotFound(err), err == nil && config == nil:
+				missing = append(missing, name)
+			case err != nil:
+				return nil, errors.Wrapf(err, "cannot get environment config %s required by step %s", name, step.Step)
+			}
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// environmentConfigReferences returns the EnvironmentConfig names a function-environment-configs
+// step references by name. Other steps, and inputs that can't be parsed, reference none.
+func environmentConfigReferences(step apiextensionsv1.PipelineStep) []string {
+	if step.Input == nil || len(step.Input.Raw) == 0 {
+		return nil
+	}
+
+	input := &un.Unstructured{}
+	if err := json.Unmarshal(step.Input.Raw, &input.Object); err != nil {
+		return nil
+	}
+
+	if parseGroupFromAPIVersion(input.GetAPIVersion()) != environmentConfigsInputGroup {
+		return nil
+	}
+
+	configs, _, _ := un.NestedSlice(input.Object, "spec", "environmentConfigs")
+
+	var names []string
+
+	for _, c := range configs {
+		cfg, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// An entry without a type is a Reference, the input's default.
+		if t, _, _ := un.NestedString(cfg, "type"); t != "" && t != "Reference" {
+			continue
+		}
+
+		if name, _, _ := un.NestedString(cfg, "ref", "name"); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// isEnvironmentConfig reports whether gvk is a Crossplane EnvironmentConfig.
+func isEnvironmentConfig(gvk schema.GroupVersionKind) bool {
+	return gvk.Group == xp.CrossplaneAPIExtGroup && gvk.Kind == xp.EnvironmentConfigKind
+}
+
 // Helper to parse apiVersion into group and version.
 func parseAPIVersion(apiVersion string) (string, string) {
 	var group, version string
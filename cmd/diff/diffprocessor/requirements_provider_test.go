@@ -4,12 +4,15 @@ import (
 	"context"
 	"testing"
 
+	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
 	v1 "github.com/crossplane/function-sdk-go/proto/v1"
 	"github.com/google/go-cmp/cmp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
@@ -31,12 +34,21 @@ func TestRequirementsProvider_ResolveSelectors(t *testing.T) {
 		}
 	}
 
+	envSelector := func(labels map[string]string) *v1.ResourceSelector {
+		return &v1.ResourceSelector{
+			ApiVersion: "apiextensions.crossplane.io/v1beta1",
+			Kind:       "EnvironmentConfig",
+			Match:      &v1.ResourceSelector_MatchLabels{MatchLabels: &v1.MatchLabels{Labels: labels}},
+		}
+	}
+
 	tests := map[string]struct {
-		selectors []*v1.ResourceSelector
-		setupRes  func() *tu.MockResourceClient
-		wantCount int
-		wantNames []string
-		wantErr   bool
+		selectors  []*v1.ResourceSelector
+		setupRes   func() *tu.MockResourceClient
+		envConfigs []*un.Unstructured // served by the environment client's selector lookup
+		wantCount  int
+		wantNames  []string
+		wantErr    bool
 	}{
 		"Nil": {
 			selectors: nil,
@@ -196,6 +208,51 @@ func TestRequirementsProvider_ResolveSelectors(t *testing.T) {
 			},
 			wantCount: 0,
 		},
+		"EnvironmentConfigMatchLabels": {
+			// EnvironmentConfig label selectors are resolved by the environment
+			// client rather than listed through the resource client.
+			selectors: []*v1.ResourceSelector{envSelector(map[string]string{"env": "prod"})},
+			setupRes: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().Build()
+			},
+			envConfigs: []*un.Unstructured{
+				tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod").
+					WithLabels(map[string]string{"env": "prod"}).Build(),
+				tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "dev").
+					WithLabels(map[string]string{"env": "dev"}).Build(),
+			},
+			wantCount: 1,
+			wantNames: []string{"prod"},
+		},
+		"EnvironmentConfigMatchName": {
+			// EnvironmentConfigs named by a requirement also come from the
+			// environment client, so local configs replace the cluster's.
+			selectors: []*v1.ResourceSelector{{
+				ApiVersion: "apiextensions.crossplane.io/v1alpha1",
+				Kind:       "EnvironmentConfig",
+				Match:      &v1.ResourceSelector_MatchName{MatchName: "prod"},
+			}},
+			setupRes: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().Build()
+			},
+			envConfigs: []*un.Unstructured{
+				tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod").Build(),
+			},
+			wantCount: 1,
+			wantNames: []string{"prod"},
+		},
+		"EnvironmentConfigNoMatch": {
+			// No matching EnvironmentConfig is an unmet requirement, not an error.
+			selectors: []*v1.ResourceSelector{envSelector(map[string]string{"env": "staging"})},
+			setupRes: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().Build()
+			},
+			envConfigs: []*un.Unstructured{
+				tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "prod").
+					WithLabels(map[string]string{"env": "prod"}).Build(),
+			},
+			wantCount: 0,
+		},
 		"MatchLabelsFetchError": {
 			// Error path for the label-selector branch, parallel to FetchError.
 			selectors: []*v1.ResourceSelector{
@@ -221,11 +278,31 @@ func TestRequirementsProvider_ResolveSelectors(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			provider := NewRequirementsProvider(
-				tt.setupRes(),
-				tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(),
-				tu.TestLogger(t, false),
-			)
+			env := tu.NewMockEnvironmentClient().
+				WithNoEnvironmentConfigs().
+				WithGetEnvironmentConfigsBySelector(func(_ context.Context, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+					var matched []*un.Unstructured
+
+					for _, cfg := range tt.envConfigs {
+						if labels.SelectorFromSet(sel.MatchLabels).Matches(labels.Set(cfg.GetLabels())) {
+							matched = append(matched, cfg)
+						}
+					}
+
+					return matched, nil
+				}).
+				WithGetEnvironmentConfig(func(_ context.Context, name string) (*un.Unstructured, error) {
+					for _, cfg := range tt.envConfigs {
+						if cfg.GetName() == name {
+							return cfg, nil
+						}
+					}
+
+					return nil, apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.crossplane.io", Resource: "environmentconfigs"}, name)
+				}).
+				Build()
+
+			provider := NewRequirementsProvider(tt.setupRes(), env, tu.TestLogger(t, false))
 			if err := provider.Initialize(ctx); err != nil {
 				t.Fatalf("Initialize: %v", err)
 			}
@@ -473,3 +550,272 @@ func TestRequirementsProvider_NamespaceCollision(t *testing.T) {
 		t.Errorf("Namespace collision bug: expected data 'value-a', got %q (got resource from wrong namespace)", gotData)
 	}
 }
+
+func TestRequirementsProvider_MissingEnvironmentConfigs(t *testing.T) {
+	gr := schema.GroupResource{Group: "apiextensions.crossplane.io", Resource: "environmentconfigs"}
+	existing := tu.NewResource("apiextensions.crossplane.io/v1beta1", "EnvironmentConfig", "present").Build()
+
+	envInput := func(configs ...map[string]any) map[string]any {
+		entries := make([]any, 0, len(configs))
+		for _, c := range configs {
+			entries = append(entries, c)
+		}
+
+		return map[string]any{
+			"apiVersion": "environmentconfigs.fn.crossplane.io/v1beta1",
+			"kind":       "Input",
+			"spec":       map[string]any{"environmentConfigs": entries},
+		}
+	}
+	ref := func(name string) map[string]any {
+		return map[string]any{"type": "Reference", "ref": map[string]any{"name": name}}
+	}
+
+	tests := map[string]struct {
+		reason  string
+		input   map[string]any
+		getErr  error
+		want    []string
+		wantErr bool
+	}{
+		"AllPresent": {
+			reason: "Nothing should be reported when every referenced config exists.",
+			input:  envInput(ref("present")),
+		},
+		"Missing": {
+			reason: "Configs that can't be found should be reported once each, sorted.",
+			input:  envInput(ref("zeta"), ref("present"), map[string]any{"ref": map[string]any{"name": "alpha"}}, ref("zeta")),
+			want:   []string{"alpha", "zeta"},
+		},
+		"SelectorIgnored": {
+			reason: "Configs selected by label aren't required to exist.",
+			input: envInput(map[string]any{
+				"type":     "Selector",
+				"selector": map[string]any{"matchLabels": []any{map[string]any{"key": "env", "value": "prod"}}},
+			}),
+		},
+		"OtherFunction": {
+			reason: "Inputs of other functions should not be inspected.",
+			input:  map[string]any{"apiVersion": "pt.fn.crossplane.io/v1beta1", "kind": "Resources"},
+		},
+		"LookupError": {
+			reason:  "Errors other than NotFound should be returned.",
+			input:   envInput(ref("zeta")),
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := tu.NewMockEnvironmentClient().
+				WithGetEnvironmentConfig(func(_ context.Context, name string) (*un.Unstructured, error) {
+					switch {
+					case tt.getErr != nil:
+						return nil, tt.getErr
+					case name == existing.GetName():
+						return existing, nil
+					default:
+						return nil, apierrors.NewNotFound(gr, name)
+					}
+				}).
+				Build()
+
+			comp := tu.NewComposition("comp").
+				WithPipelineMode().
+				WithPipelineStep("environment", "function-environment-configs", tt.input).
+				Build()
+
+			provider := NewRequirementsProvider(tu.NewMockResourceClient().Build(), env, tu.TestLogger(t, false))
+
+			got, err := provider.MissingEnvironmentConfigs(t.Context(), comp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("\n%s\nMissingEnvironmentConfigs(...): want error %t, got %v", tt.reason, tt.wantErr, err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nMissingEnvironmentConfigs(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequirementsProvider_ExternalResources(t *testing.T) {
+	ctx := t.Context()
+
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	fixtures := []*un.Unstructured{
+		tu.NewResource("v1", "ConfigMap", "settings").InNamespace("xr-ns").Build(),
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "viewer").WithLabels(map[string]string{"team": "a"}).Build(),
+		tu.NewResource("rbac.authorization.k8s.io/v1", "ClusterRole", "editor").WithLabels(map[string]string{"team": "b"}).Build(),
+	}
+
+	// The cluster only answers questions about scope; any read from it is a failure.
+	cluster := tu.NewMockResourceClient().
+		WithIsNamespacedResource(func(_ context.Context, gvk schema.GroupVersionKind) (bool, error) {
+			return gvk == cmGVK, nil
+		}).
+		WithGetResource(func(context.Context, schema.GroupVersionKind, string, string) (*un.Unstructured, error) {
+			return nil, errors.New("read from cluster")
+		}).
+		Build()
+
+	provider := NewRequirementsProvider(
+		k8.NewFixtureResourceClient(cluster, fixtures),
+		tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(),
+		tu.TestLogger(t, false),
+	)
+
+	tests := map[string]struct {
+		reason   string
+		selector *v1.ResourceSelector
+		want     []string
+	}{
+		"MatchName": {
+			reason: "A matchName requirement should resolve from the fixtures, in the XR's namespace.",
+			selector: &v1.ResourceSelector{
+				ApiVersion: "v1",
+				Kind:       "ConfigMap",
+				Match:      &v1.ResourceSelector_MatchName{MatchName: "settings"},
+			},
+			want: []string{"settings"},
+		},
+		"MatchNameMissing": {
+			reason: "A matchName requirement no fixture has should be unmet, as for the cluster.",
+			selector: &v1.ResourceSelector{
+				ApiVersion: "v1",
+				Kind:       "ConfigMap",
+				Match:      &v1.ResourceSelector_MatchName{MatchName: "absent"},
+			},
+		},
+		"MatchLabels": {
+			reason: "A matchLabels requirement should resolve to the fixtures with those labels.",
+			selector: &v1.ResourceSelector{
+				ApiVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "ClusterRole",
+				Match: &v1.ResourceSelector_MatchLabels{
+					MatchLabels: &v1.MatchLabels{Labels: map[string]string{"team": "a"}},
+				},
+			},
+			want: []string{"viewer"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := provider.ResolveSelectors(ctx, []*v1.ResourceSelector{tt.selector}, "xr-ns")
+			if err != nil {
+				t.Fatalf("\n%s\nResolveSelectors(...): unexpected error: %v", tt.reason, err)
+			}
+
+			var names []string
+			for _, res := range got {
+				names = append(names, res.GetName())
+			}
+
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("\n%s\nResolveSelectors(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequirementsProvider_SelectorCache(t *testing.T) {
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	cm := tu.NewResource("v1", "ConfigMap", "settings").InNamespace("ns-a").WithLabels(map[string]string{"tier": "cache"}).Build()
+
+	byName := &v1.ResourceSelector{
+		ApiVersion: "v1",
+		Kind:       "ConfigMap",
+		Match:      &v1.ResourceSelector_MatchName{MatchName: "absent"},
+	}
+	byLabel := &v1.ResourceSelector{
+		ApiVersion: "v1",
+		Kind:       "ConfigMap",
+		Match: &v1.ResourceSelector_MatchLabels{
+			MatchLabels: &v1.MatchLabels{Labels: map[string]string{"tier": "cache"}},
+		},
+	}
+
+	gets, lists := 0, 0
+	resourceClient := tu.NewMockResourceClient().
+		WithNamespacedResource(cmGVK).
+		WithGetResource(func(_ context.Context, _ schema.GroupVersionKind, _, name string) (*un.Unstructured, error) {
+			gets++
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+		}).
+		WithGetResourcesByLabel(func(context.Context, schema.GroupVersionKind, string, metav1.LabelSelector) ([]*un.Unstructured, error) {
+			lists++
+			return []*un.Unstructured{cm}, nil
+		}).
+		Build()
+
+	provider := NewRequirementsProvider(resourceClient, tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(), tu.TestLogger(t, false))
+
+	// Three iterations of one XR's render loop, then a second XR in another namespace.
+	xrCtx := WithSelectorCache(t.Context())
+	for range 3 {
+		if _, err := provider.ResolveSelectors(xrCtx, []*v1.ResourceSelector{byName, byLabel}, "ns-a"); err != nil {
+			t.Fatalf("ResolveSelectors(...): unexpected error: %v", err)
+		}
+	}
+
+	if gets != 1 || lists != 1 {
+		t.Errorf("ResolveSelectors(...): want each selector fetched once per XR, got %d gets and %d label lists", gets, lists)
+	}
+
+	if _, err := provider.ResolveSelectors(WithSelectorCache(t.Context()), []*v1.ResourceSelector{byName, byLabel}, "ns-b"); err != nil {
+		t.Fatalf("ResolveSelectors(...): unexpected error: %v", err)
+	}
+
+	if gets != 2 || lists != 2 {
+		t.Errorf("ResolveSelectors(...): want another XR to fetch its own matches, got %d gets and %d label lists", gets, lists)
+	}
+}
+
+func TestRequirementsProvider_RecordsRequiredResources(t *testing.T) {
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	settings := tu.NewResource("v1", "ConfigMap", "settings").InNamespace("xr-ns").WithLabels(map[string]string{"tier": "cache"}).Build()
+	extra := tu.NewResource("v1", "ConfigMap", "extra").InNamespace("xr-ns").WithLabels(map[string]string{"tier": "cache"}).Build()
+
+	resourceClient := tu.NewMockResourceClient().
+		WithNamespacedResource(cmGVK).
+		WithResourcesExist(settings).
+		WithResourcesFoundByLabel([]*un.Unstructured{settings, extra}, "tier", "cache", "").
+		Build()
+
+	provider := NewRequirementsProvider(resourceClient, tu.NewMockEnvironmentClient().WithNoEnvironmentConfigs().Build(), tu.TestLogger(t, false))
+
+	selectors := []*v1.ResourceSelector{
+		{
+			ApiVersion: "v1",
+			Kind:       "ConfigMap",
+			Match:      &v1.ResourceSelector_MatchName{MatchName: "settings"},
+		},
+		{
+			ApiVersion: "v1",
+			Kind:       "ConfigMap",
+			Match: &v1.ResourceSelector_MatchLabels{
+				MatchLabels: &v1.MatchLabels{Labels: map[string]string{"tier": "cache"}},
+			},
+		},
+	}
+
+	ctx, recorded := withRequiredResources(t.Context())
+
+	// The same requirements on a second iteration shouldn't be listed again.
+	for range 2 {
+		if _, err := provider.ResolveSelectors(ctx, selectors, "xr-ns"); err != nil {
+			t.Fatalf("ResolveSelectors(...): unexpected error: %v", err)
+		}
+	}
+
+	want := []dt.RequiredResource{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "xr-ns", Name: "extra", Match: "labels tier=cache"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "xr-ns", Name: "settings", Match: "name"},
+	}
+	if diff := cmp.Diff(want, recorded.Resources()); diff != "" {
+		t.Errorf("ResolveSelectors(...): want each resource recorded once, with its first match, -want, +got:\n%s", diff)
+	}
+}
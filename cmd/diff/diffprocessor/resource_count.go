@@ -0,0 +1,54 @@
+package diffprocessor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// resourceCountKey is the context key under which a ResourceCount is stored.
+type resourceCountKey struct{}
+
+// ResourceCount tallies the resources rendered for an XR and its nested XRs, so a composition that
+// renders far more resources than expected fails the XR instead of exhausting memory.
+type ResourceCount struct {
+	mu       sync.Mutex
+	limit    int
+	count    int
+	exceeded error
+}
+
+// WithResourceCount returns a context that tallies the resources rendered while diffing with it
+// against limit, and the tally. A limit of zero or less never fails.
+func WithResourceCount(ctx context.Context, limit int) (context.Context, *ResourceCount) {
+	c := &ResourceCount{limit: limit}
+	return context.WithValue(ctx, resourceCountKey{}, c), c
+}
+
+// Err returns the error recorded when the tally first went over its limit, or nil.
+func (c *ResourceCount) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.exceeded
+}
+
+// countResources adds the n resources resourceID rendered to the ResourceCount in ctx, if any. It
+// returns an error once the tally is over its limit.
+func countResources(ctx context.Context, resourceID string, n int) error {
+	c, ok := ctx.Value(resourceCountKey{}).(*ResourceCount)
+	if !ok || c.limit <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count += n
+	if c.count > c.limit && c.exceeded == nil {
+		c.exceeded = errors.Errorf("%s brought the rendered resource count to %d, more than the limit of %d (--max-resources)", resourceID, c.count, c.limit)
+	}
+
+	return c.exceeded
+}
@@ -10,6 +10,7 @@ import (
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -41,16 +42,37 @@ type DefaultResourceManager struct {
 	defClient  xp.DefinitionClient
 	treeClient xp.ResourceTreeClient
 	logger     logging.Logger
+
+	// requireController restricts label-based matching to resources whose
+	// controller owner reference points at the expected composite.
+	requireController bool
+}
+
+// ResourceManagerOption configures a DefaultResourceManager.
+type ResourceManagerOption func(*DefaultResourceManager)
+
+// WithRequireController sets whether label-based matching only accepts resources whose controller
+// owner reference points at the expected composite.
+func WithRequireController(require bool) ResourceManagerOption {
+	return func(m *DefaultResourceManager) {
+		m.requireController = require
+	}
 }
 
 // NewResourceManager creates a new DefaultResourceManager.
-func NewResourceManager(client k8.ResourceClient, defClient xp.DefinitionClient, treeClient xp.ResourceTreeClient, logger logging.Logger) ResourceManager {
-	return &DefaultResourceManager{
+func NewResourceManager(client k8.ResourceClient, defClient xp.DefinitionClient, treeClient xp.ResourceTreeClient, logger logging.Logger, opts ...ResourceManagerOption) ResourceManager {
+	m := &DefaultResourceManager{
 		client:     client,
 		defClient:  defClient,
 		treeClient: treeClient,
 		logger:     logger,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 // FetchCurrentObject retrieves the current state of the object from the cluster
@@ -83,8 +105,9 @@ func (m *DefaultResourceManager) FetchCurrentObject(ctx context.Context, composi
 			return current, false, nil
 		}
 
-		// If it's not a NotFound error, propagate it
-		if err != nil && !apierrors.IsNotFound(err) {
+		// If it's not a NotFound error, propagate it. A kind the cluster doesn't serve yet (e.g. an XR
+		// whose XRD was supplied locally) has no objects, so it counts as not found.
+		if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 			m.logger.Debug("Error getting resource",
 				"resource", resourceID,
 				"error", err)
@@ -265,6 +288,17 @@ func (m *DefaultResourceManager) lookupByComposite(ctx context.Context, composit
 
 	// Look up resources with the appropriate label selector
 	resources, err := m.client.GetResourcesByLabel(ctx, gvk, namespace, labelSelector)
+	if apierrors.IsNotFound(err) {
+		// Nothing to match against (e.g. the target namespace doesn't exist yet), so
+		// everything rendered into it is new.
+		m.logger.Debug("Label lookup returned NotFound, treating resource as new",
+			"resource", resourceID,
+			"namespace", namespace,
+			"error", err)
+
+		return nil, false, nil
+	}
+
 	if err != nil {
 		return nil, false, errors.Wrapf(err, "cannot list resources for %s %s",
 			map[bool]string{true: "claim", false: "composite"}[isCompositeAClaim], lookupName)
@@ -282,10 +316,63 @@ func (m *DefaultResourceManager) lookupByComposite(ctx context.Context, composit
 		"resource", resourceID,
 		"matchCount", len(resources))
 
+	if m.requireController {
+		resources = m.filterByController(composite, isCompositeAClaim, resources)
+	}
+
 	// Find a resource with matching composition-resource-name
 	return m.findMatchingResource(resources, compResourceName, generateName)
 }
 
+// filterByController drops resources whose controller owner reference does not
+// point at the composite expected to own them. For an XR that is the composite
+// itself; for a claim it is the XR the claim references. Resources with no
+// controller reference at all are dropped as well.
+func (m *DefaultResourceManager) filterByController(composite *un.Unstructured, isClaim bool, resources []*un.Unstructured) []*un.Unstructured {
+	kind, name, uid := composite.GetKind(), composite.GetName(), composite.GetUID()
+
+	if isClaim {
+		kind, _, _ = un.NestedString(composite.Object, "spec", "resourceRef", "kind")
+		name, _, _ = un.NestedString(composite.Object, "spec", "resourceRef", "name")
+		uid = ""
+
+		if name == "" {
+			// The claim hasn't been bound to an XR yet, so no resource can be
+			// controlled by it.
+			m.logger.Debug("Claim has no resourceRef, no resource can match controller",
+				"claim", composite.GetName())
+
+			return nil
+		}
+	}
+
+	matched := make([]*un.Unstructured, 0, len(resources))
+
+	for _, res := range resources {
+		ref := metav1.GetControllerOf(res)
+		if ref == nil {
+			m.logger.Debug("Skipping resource without controller reference",
+				"resource", res.GetName())
+
+			continue
+		}
+
+		// Prefer UID when both sides carry one; otherwise fall back to kind and name.
+		if (uid != "" && ref.UID != "" && ref.UID != uid) || ref.Kind != kind || ref.Name != name {
+			m.logger.Debug("Skipping resource controlled by a different owner",
+				"resource", res.GetName(),
+				"controller", fmt.Sprintf("%s/%s", ref.Kind, ref.Name),
+				"expected", fmt.Sprintf("%s/%s", kind, name))
+
+			continue
+		}
+
+		matched = append(matched, res)
+	}
+
+	return matched
+}
+
 // getCompositionResourceName extracts the composition resource name from annotations.
 func (m *DefaultResourceManager) getCompositionResourceName(annotations map[string]string) string {
 	// First check standard annotation
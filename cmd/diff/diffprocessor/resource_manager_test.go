@@ -10,6 +10,7 @@ import (
 	"github.com/crossplane/cli/v2/cmd/crossplane/common/resource"
 	gcmp "github.com/google/go-cmp/cmp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -75,6 +76,16 @@ func TestDefaultResourceManager_FetchCurrentObject(t *testing.T) {
 		WithSpecField("field", "value").
 		Build()
 
+	// Cluster-scoped resource composed by a namespaced XR
+	clusterScopedResource := tu.NewResource("example.org/v1", "ClusterThing", "team-xr-abc12").
+		WithLabels(map[string]string{
+			"crossplane.io/composite": "team-xr",
+		}).
+		WithAnnotations(map[string]string{
+			"crossplane.io/composition-resource-name": "cluster-thing",
+		}).
+		Build()
+
 	tests := map[string]struct {
 		setupResourceClient func() *tu.MockResourceClient
 		defClient           *tu.MockDefinitionClient
@@ -123,6 +134,22 @@ func TestDefaultResourceManager_FetchCurrentObject(t *testing.T) {
 			wantResourceID: "",
 			wantErr:        false,
 		},
+		"KindNotServed_NewXR": {
+			setupResourceClient: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().
+					WithGetResource(func(_ context.Context, gvk schema.GroupVersionKind, _, _ string) (*un.Unstructured, error) {
+						// An XR whose XRD was supplied locally: the cluster doesn't serve the kind yet.
+						return nil, errors.Wrap(&meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}, "cannot get resource")
+					}).
+					Build()
+			},
+			defClient:      tu.NewMockDefinitionClient().Build(),
+			composite:      nil,
+			desired:        tu.NewResource("example.org/v1", "XNew", "new-xr").Build(),
+			wantIsNew:      true,
+			wantResourceID: "",
+			wantErr:        false,
+		},
 		"ResourceWithGenerateName_NotFound": {
 			setupResourceClient: func() *tu.MockResourceClient {
 				return tu.NewMockResourceClient().
@@ -281,6 +308,65 @@ func TestDefaultResourceManager_FetchCurrentObject(t *testing.T) {
 			wantIsNew: true,  // Fall back to creating a new resource
 			wantErr:   false, // We handle the error gracefully
 		},
+		"NamespaceDoesNotExist_NewResource": {
+			setupResourceClient: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().
+					WithResourceNotFound().
+					WithGetResourcesByLabel(func(_ context.Context, _ schema.GroupVersionKind, ns string, _ metav1.LabelSelector) ([]*un.Unstructured, error) {
+						return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, ns)
+					}).
+					Build()
+			},
+			defClient: tu.NewMockDefinitionClient().Build(),
+			composite: parentXR,
+			desired: tu.NewResource("example.org/v1", "ComposedResource", "composed-resource").
+				InNamespace("missing-namespace").
+				WithLabels(map[string]string{
+					"crossplane.io/composite": "parent-xr",
+				}).
+				WithAnnotations(map[string]string{
+					"crossplane.io/composition-resource-name": "resource-a",
+				}).
+				Build(),
+			wantIsNew: true, // A missing namespace means there's nothing to match against
+			wantErr:   false,
+		},
+		"NamespacedXR_ClusterScopedResourceFoundByLabel": {
+			// A namespaced v2 XR's cluster-scoped composed resource has no namespace once render
+			// output is cleaned up, so it must be listed cluster-wide rather than in the XR's namespace.
+			setupResourceClient: func() *tu.MockResourceClient {
+				return tu.NewMockResourceClient().
+					WithResourceNotFound().
+					WithGetResourcesByLabel(func(_ context.Context, _ schema.GroupVersionKind, namespace string, sel metav1.LabelSelector) ([]*un.Unstructured, error) {
+						if namespace != "" {
+							return []*un.Unstructured{}, nil
+						}
+
+						if owner, exists := sel.MatchLabels["crossplane.io/composite"]; exists && owner == "team-xr" {
+							return []*un.Unstructured{clusterScopedResource}, nil
+						}
+
+						return []*un.Unstructured{}, nil
+					}).
+					Build()
+			},
+			defClient: tu.NewMockDefinitionClient().Build(),
+			composite: tu.NewResource("example.org/v1", "XR", "team-xr").
+				InNamespace("team-a").
+				Build(),
+			desired: tu.NewResource("example.org/v1", "ClusterThing", "").
+				WithGenerateName("team-xr-").
+				WithLabels(map[string]string{
+					"crossplane.io/composite": "team-xr",
+				}).
+				WithAnnotations(map[string]string{
+					"crossplane.io/composition-resource-name": "cluster-thing",
+				}).
+				Build(),
+			wantIsNew:      false,
+			wantResourceID: "team-xr-abc12",
+			wantErr:        false,
+		},
 		"ClaimResource_FoundByClaimLabels": {
 			setupResourceClient: func() *tu.MockResourceClient {
 				// Create an existing resource with claim labels
@@ -380,6 +466,110 @@ func TestDefaultResourceManager_FetchCurrentObject(t *testing.T) {
 	}
 }
 
+func TestDefaultResourceManager_FetchCurrentObject_OwnerController(t *testing.T) {
+	ctx := t.Context()
+
+	parentXR := tu.NewResource("example.org/v1", "XR", "parent-xr").
+		WithUID("parent-uid").
+		Build()
+
+	// Both candidates carry the composite label and composition-resource-name, and
+	// both list parent-xr among their owner references. Only the second has it as
+	// the controller.
+	adopted := tu.NewResource("example.org/v1", "ComposedResource", "adopted").
+		WithCompositeOwner("parent-xr").
+		WithCompositionResourceName("resource-a").
+		WithOwnerReference("XR", "parent-xr", "example.org/v1", "parent-uid").
+		WithControllerReference("OtherXR", "other-xr", "example.org/v1", "other-uid").
+		Build()
+
+	controlled := tu.NewResource("example.org/v1", "ComposedResource", "controlled").
+		WithCompositeOwner("parent-xr").
+		WithCompositionResourceName("resource-a").
+		WithOwnerReference("Usage", "some-usage", "protection.crossplane.io/v1beta1", "usage-uid").
+		WithControllerReference("XR", "parent-xr", "example.org/v1", "parent-uid").
+		Build()
+
+	staleUID := tu.NewResource("example.org/v1", "ComposedResource", "stale").
+		WithCompositeOwner("parent-xr").
+		WithCompositionResourceName("resource-a").
+		WithControllerReference("XR", "parent-xr", "example.org/v1", "previous-uid").
+		Build()
+
+	desired := tu.NewResource("example.org/v1", "ComposedResource", "").
+		WithCompositeOwner("parent-xr").
+		WithCompositionResourceName("resource-a").
+		Build()
+
+	tests := map[string]struct {
+		reason            string
+		candidates        []*un.Unstructured
+		requireController bool
+		wantIsNew         bool
+		wantName          string
+	}{
+		"DisabledMatchesFirstCandidate": {
+			reason:     "Without the option the first labelled candidate wins regardless of owner references.",
+			candidates: []*un.Unstructured{adopted, controlled},
+			wantName:   "adopted",
+		},
+		"EnabledSkipsNonControllerOwner": {
+			reason:            "With the option a resource that merely lists the XR as an owner is skipped.",
+			candidates:        []*un.Unstructured{adopted, controlled},
+			requireController: true,
+			wantName:          "controlled",
+		},
+		"EnabledSkipsStaleUID": {
+			reason:            "A controller reference to an earlier incarnation of the XR does not match.",
+			candidates:        []*un.Unstructured{staleUID},
+			requireController: true,
+			wantIsNew:         true,
+		},
+		"EnabledNoControlledCandidate": {
+			reason:            "When no candidate is controlled by the XR the resource is treated as new.",
+			candidates:        []*un.Unstructured{adopted},
+			requireController: true,
+			wantIsNew:         true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := tu.NewMockResourceClient().
+				WithResourceNotFound().
+				WithGetResourcesByLabel(func(context.Context, schema.GroupVersionKind, string, metav1.LabelSelector) ([]*un.Unstructured, error) {
+					return tt.candidates, nil
+				}).
+				Build()
+
+			rm := NewResourceManager(
+				client,
+				tu.NewMockDefinitionClient().Build(),
+				tu.NewMockResourceTreeClient().Build(),
+				tu.TestLogger(t, false),
+				WithRequireController(tt.requireController),
+			)
+
+			current, isNew, err := rm.FetchCurrentObject(ctx, parentXR, desired.DeepCopy())
+			if err != nil {
+				t.Fatalf("\n%s\nFetchCurrentObject(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if isNew != tt.wantIsNew {
+				t.Errorf("\n%s\nFetchCurrentObject(...): isNew = %v, want %v", tt.reason, isNew, tt.wantIsNew)
+			}
+
+			if tt.wantIsNew {
+				return
+			}
+
+			if current == nil || current.GetName() != tt.wantName {
+				t.Errorf("\n%s\nFetchCurrentObject(...): got %v, want %s", tt.reason, current, tt.wantName)
+			}
+		})
+	}
+}
+
 func TestDefaultResourceManager_UpdateOwnerRefs(t *testing.T) {
 	ctx := t.Context()
 	// Create test resources
@@ -417,7 +417,10 @@ func formatInvalidBlock(r pkgvalidate.ResourceValidationResult) string {
 }
 
 // formatErrorLine renders one FieldValidationError as
-// "<message>[ (got <value>)] [<type>]". The bad value tail is omitted
+// "<field>: <message>[ (got <value>)] [<type>]". Kubernetes field
+// errors already lead with their JSON path, so the field prefix is
+// only added when the message doesn't start with it; errors with no
+// field keep their message as is. The bad value tail is omitted
 // when Value is nil or already present in the message. Duplication
 // detection is type-aware to avoid false positives:
 //
@@ -432,6 +435,10 @@ func formatInvalidBlock(r pkgvalidate.ResourceValidationResult) string {
 //     just because "42" is a prefix of "420".
 func formatErrorLine(e pkgvalidate.FieldValidationError) string {
 	msg := e.Message
+	if e.Field != "" && !strings.HasPrefix(msg, e.Field) {
+		msg = e.Field + ": " + msg
+	}
+
 	if rendered := renderBadValue(e.Value); rendered != "" && !valueAlreadyInMessage(msg, e.Value, rendered) {
 		msg = fmt.Sprintf("%s (got %s)", msg, rendered)
 	}
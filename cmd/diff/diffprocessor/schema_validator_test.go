@@ -120,7 +120,7 @@ func TestDefaultSchemaValidator_ValidateResources(t *testing.T) {
 			expectedErr:    true,
 			expectedErrMsg: "unable to find CRDs for",
 		},
-		"ValidationError": {
+		"MissingSchema": {
 			setupClients: func() (*tu.MockSchemaClient, *tu.MockDefinitionClient) {
 				sch := tu.NewMockSchemaClient().
 					// Add GetCRD implementation for typed CRDs
@@ -151,6 +151,58 @@ func TestDefaultSchemaValidator_ValidateResources(t *testing.T) {
 			expectedErr:    true,
 			expectedErrMsg: "missing schema",
 		},
+		"ValidationError": {
+			setupClients: func() (*tu.MockSchemaClient, *tu.MockDefinitionClient) {
+				sch := tu.NewMockSchemaClient().
+					WithFoundCRDs(map[schema.GroupKind]*extv1.CustomResourceDefinition{
+						{Group: testExampleOrg, Kind: "XR"}:               createCRDWithStringField(xrCRD),
+						{Group: testCpdOrg, Kind: "testComposedResource"}: composedCRD,
+					}).
+					WithAllResourcesRequiringCRDs().
+					WithCachingBehavior().
+					Build()
+
+				return sch, tu.NewMockDefinitionClient().Build()
+			},
+			xr: tu.NewResource(testExampleOrg+"/v1", "XR", "test-xr").
+				InNamespace("default").
+				WithSpecField("field", int64(123)).
+				Build(),
+			composed:       []cpd.Unstructured{*composedResource1, *composedResource2},
+			expectedErr:    true,
+			expectedErrMsg: "XR default/test-xr:\n  spec.field: Invalid value: \"number\": spec.field in body must be of type string: \"number\" [schema]",
+		},
+		"MultipleValidationErrors": {
+			setupClients: func() (*tu.MockSchemaClient, *tu.MockDefinitionClient) {
+				sch := tu.NewMockSchemaClient().
+					WithFoundCRDs(map[schema.GroupKind]*extv1.CustomResourceDefinition{
+						{Group: testExampleOrg, Kind: "XR"}:               xrCRD,
+						{Group: testCpdOrg, Kind: "testComposedResource"}: composedCRD,
+					}).
+					WithAllResourcesRequiringCRDs().
+					WithCachingBehavior().
+					Build()
+
+				return sch, tu.NewMockDefinitionClient().Build()
+			},
+			xr: tu.NewResource(testExampleOrg+"/v1", "XR", "test-xr").
+				InNamespace("default").
+				WithSpecField("field", int64(123)).
+				Build(),
+			composed: []cpd.Unstructured{
+				*composedResource1,
+				*tu.NewResource(testCpdOrg+"/v1", "testComposedResource", "resource2").
+					InNamespace("default").
+					WithCompositeOwner("test-xr").
+					WithCompositionResourceName("resource2").
+					WithSpecField("field", true).
+					BuildUComposed(),
+			},
+			expectedErr: true,
+			// Every violation is reported, not just the first.
+			expectedErrMsg: "XR default/test-xr:\n  spec.field: Invalid value: \"number\": spec.field in body must be of type string: \"number\" [schema]\n" +
+				"cpd.org/v1/testComposedResource default/resource2:\n  spec.field: Invalid value: \"boolean\": spec.field in body must be of type string: \"boolean\" [schema]",
+		},
 	}
 
 	for name, tt := range tests {
@@ -793,6 +845,23 @@ func TestFormatValidationErrors(t *testing.T) {
 			},
 			expected: "example.org/v1/XR my-xr:\n  cannot apply defaults [defaulting]",
 		},
+		"FieldMissingFromMessage": {
+			reason: "An error whose message doesn't lead with its field should be prefixed with the field's path, so every line says where the problem is.",
+			result: &pkgvalidate.ValidationResult{
+				Resources: []pkgvalidate.ResourceValidationResult{{
+					APIVersion: "example.org/v1",
+					Kind:       "XR",
+					Name:       "my-xr",
+					Status:     pkgvalidate.ValidationStatusInvalid,
+					Errors: []pkgvalidate.FieldValidationError{{
+						Type:    pkgvalidate.FieldErrorTypeCEL,
+						Field:   "spec.size",
+						Message: "size must not shrink",
+					}},
+				}},
+			},
+			expected: "example.org/v1/XR my-xr:\n  spec.size: size must not shrink [cel]",
+		},
 	}
 
 	for name, tt := range tests {
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diffprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
+	pkgvalidate "github.com/crossplane/cli/v2/pkg/validate"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// ValidationReport is one entry of the --validate-only output: whether a resource's composition
+// and functions resolved and the resource passed schema validation, with the errors if not.
+type ValidationReport struct {
+	Resource string   `json:"resource"`
+	OK       bool     `json:"ok"`
+	Errors   []string `json:"errors"`
+}
+
+// performValidation is PerformDiff with ValidateOnly set. It resolves each resource's composition
+// and functions and validates the resource against its schema, without rendering or diffing, then
+// writes a JSON array of ValidationReports to stdout. It never reports diffs; any failed resource
+// is returned as an error.
+func (p *DefaultDiffProcessor) performValidation(ctx context.Context, resources []*un.Unstructured, compositionProvider types.CompositionProvider) (bool, error) {
+	reports := make([]ValidationReport, 0, len(resources))
+
+	var errs []error
+
+	for _, res := range resources {
+		resourceID := fmt.Sprintf("%s/%s", res.GetKind(), res.GetName())
+
+		xr, _ := stripSourceFile(res)
+
+		report := ValidationReport{Resource: resourceID, OK: true, Errors: []string{}}
+
+		if err := p.validateSingleResource(ctx, xr, compositionProvider, resourceID); err != nil {
+			p.config.Logger.Info("Resource failed validation", "resource", resourceID, "error", err)
+			errs = append(errs, errors.Wrapf(err, "unable to validate resource %s", resourceID))
+
+			report.OK = false
+			report.Errors = validationErrorLines(err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err == nil {
+		_, err = fmt.Fprintln(p.config.Stdout, string(data))
+	}
+
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "cannot write validation reports"))
+	}
+
+	return false, errors.Join(errs...)
+}
+
+// validateSingleResource runs the steps of diffSingleResourceInternal that precede rendering:
+// composition matching, function resolution, and XRD defaulting, then validates the XR alone
+// against its schema.
+func (p *DefaultDiffProcessor) validateSingleResource(ctx context.Context, res *un.Unstructured, compositionProvider types.CompositionProvider, resourceID string) error {
+	xr, done, err := p.SanitizeXR(res, resourceID)
+	if done {
+		return err
+	}
+
+	comp, err := compositionProvider(ctx, res)
+	if err != nil {
+		return errors.Wrap(err, "cannot get composition")
+	}
+
+	if _, err := p.functionProvider.GetFunctionsForComposition(comp); err != nil {
+		return errors.Wrap(err, "cannot get functions for composition")
+	}
+
+	if err := p.applyXRDDefaults(ctx, xr, resourceID); err != nil {
+		return errors.Wrap(err, "cannot apply XRD defaults")
+	}
+
+	if err := p.schemaValidator.ValidateResources(ctx, xr.GetUnstructured(), nil); err != nil {
+		return errors.Wrap(err, "cannot validate resources")
+	}
+
+	return nil
+}
+
+// validationErrorLines breaks a validation failure into one line per problem. A schema validation
+// error with a structured result yields a line per failing field; anything else is a single line.
+func validationErrorLines(err error) []string {
+	var sve *SchemaValidationError
+	if !errors.As(err, &sve) || sve.Result == nil {
+		return []string{err.Error()}
+	}
+
+	var lines []string
+
+	for _, r := range sve.Result.Resources {
+		switch r.Status {
+		case pkgvalidate.ValidationStatusMissingSchema:
+			lines = append(lines, formatMissingSchemaBlock(r))
+		case pkgvalidate.ValidationStatusInvalid:
+			for _, e := range r.Errors {
+				if e.Type == pkgvalidate.FieldErrorTypeDefaulting {
+					continue
+				}
+
+				lines = append(lines, fmt.Sprintf("%s: %s", resourceHeader(r), formatErrorLine(e)))
+			}
+		case pkgvalidate.ValidationStatusValid,
+			pkgvalidate.ValidationStatusDefaultingFailed:
+			// Nothing to report; see formatValidationErrors.
+		}
+	}
+
+	if len(lines) == 0 {
+		return []string{err.Error()}
+	}
+
+	return lines
+}
@@ -0,0 +1,169 @@
+package diffprocessor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
+	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/crossplane/cli/v2/cmd/crossplane/render"
+	pkgvalidate "github.com/crossplane/cli/v2/pkg/validate"
+	gcmp "github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	cpd "github.com/crossplane/crossplane-runtime/v2/pkg/resource/unstructured/composed"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/v2/pkg/v1"
+)
+
+func TestDefaultDiffProcessor_PerformDiff_ValidateOnly(t *testing.T) {
+	ctx := t.Context()
+
+	valid := tu.NewResource("example.org/v1", testKind, "valid-xr").
+		WithSpecField("coolField", "ok").
+		Build()
+	invalid := tu.NewResource("example.org/v1", testKind, "invalid-xr").
+		WithSpecField("coolField", int64(5)).
+		Build()
+	unmatched := tu.NewResource("example.org/v1", "XOther", "unmatched-xr").Build()
+
+	composition := tu.NewComposition("test-comp").
+		WithCompositeTypeRef("example.org/v1", testKind).
+		WithPipelineMode().
+		WithPipelineStep("step1", "function-test", nil).
+		Build()
+
+	tests := map[string]struct {
+		reason      string
+		resources   []*un.Unstructured
+		wantReports []ValidationReport
+		wantErr     bool
+	}{
+		"AllValid": {
+			reason:      "A resource that resolves and validates should be reported ok with no errors.",
+			resources:   []*un.Unstructured{valid},
+			wantReports: []ValidationReport{{Resource: testKind + "/valid-xr", OK: true, Errors: []string{}}},
+		},
+		"SchemaInvalid": {
+			reason:    "A schema failure should be reported per field, in input order, and fail the run.",
+			resources: []*un.Unstructured{valid, invalid},
+			wantReports: []ValidationReport{
+				{Resource: testKind + "/valid-xr", OK: true, Errors: []string{}},
+				{Resource: testKind + "/invalid-xr", OK: false, Errors: []string{
+					"example.org/v1/XR1 invalid-xr: spec.coolField: Invalid value: 5 [schema]",
+				}},
+			},
+			wantErr: true,
+		},
+		"NoComposition": {
+			reason:    "A resource without a matching composition should be reported as a single error.",
+			resources: []*un.Unstructured{unmatched},
+			wantReports: []ValidationReport{
+				{Resource: "XOther/unmatched-xr", OK: false, Errors: []string{"cannot get composition: no composition for XOther"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8sClients := k8.Clients{
+				Apply:    tu.NewMockApplyClient().Build(),
+				Resource: tu.NewMockResourceClient().Build(),
+				Schema: tu.NewMockSchemaClient().
+					WithNoResourcesRequiringCRDs().
+					WithSuccessfulCRDByNameFetch(testCRDName, makeTestCRD(testCRDName, testKind, testGroup, testAPIVersion)).
+					Build(),
+				Type: tu.NewMockTypeConverter().Build(),
+			}
+
+			xpClients := xp.Clients{
+				Composition: tu.NewMockCompositionClient().Build(),
+				Credential:  &tu.MockCredentialClient{},
+				Definition: tu.NewMockDefinitionClient().
+					WithXRDForXR(tu.NewXRD(testXRDName, testGroup, testKind).
+						WithPlural(testPlural).
+						WithSingular(testSingular).
+						BuildAsUnstructured()).
+					Build(),
+				Environment: tu.NewMockEnvironmentClient().
+					WithNoEnvironmentConfigs().
+					Build(),
+				Function: tu.NewMockFunctionClient().
+					WithSuccessfulFunctionsFetch([]pkgv1.Function{{ObjectMeta: metav1.ObjectMeta{Name: "function-test"}}}).
+					Build(),
+				ResourceTree: tu.NewMockResourceTreeClient().Build(),
+			}
+
+			var stdout bytes.Buffer
+
+			opts := append(testProcessorOptions(t),
+				WithStdout(&stdout),
+				WithValidateOnly(true),
+				WithRenderFunc(func(context.Context, logging.Logger, RenderInputs) (render.CompositionOutputs, error) {
+					t.Errorf("%s\nPerformDiff(...): rendered in validate-only mode", tt.reason)
+					return render.CompositionOutputs{}, nil
+				}),
+				WithSchemaValidatorFactory(func(k8.SchemaClient, xp.DefinitionClient, logging.Logger) SchemaValidator {
+					return &tu.MockSchemaValidator{
+						ValidateResourcesFn: func(_ context.Context, xr *un.Unstructured, _ []cpd.Unstructured) error {
+							if xr.GetName() != "invalid-xr" {
+								return nil
+							}
+
+							result := &pkgvalidate.ValidationResult{Resources: []pkgvalidate.ResourceValidationResult{{
+								APIVersion: "example.org/v1",
+								Kind:       testKind,
+								Name:       "invalid-xr",
+								Status:     pkgvalidate.ValidationStatusInvalid,
+								Errors: []pkgvalidate.FieldValidationError{{
+									Type:    pkgvalidate.FieldErrorTypeSchema,
+									Field:   "spec.coolField",
+									Message: "spec.coolField: Invalid value: 5",
+									Value:   5,
+								}},
+							}}}
+
+							return NewSchemaValidationError("", "invalid", errors.New("invalid")).WithResult(result)
+						},
+					}
+				}),
+			)
+
+			processor := NewDiffProcessor(k8sClients, xpClients, opts...)
+
+			provider := func(_ context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+				if res.GetKind() != testKind {
+					return nil, errors.Errorf("no composition for %s", res.GetKind())
+				}
+
+				return composition, nil
+			}
+
+			hasDiffs, err := processor.PerformDiff(ctx, tt.resources, provider)
+			if hasDiffs {
+				t.Errorf("%s\nPerformDiff(...): reported diffs in validate-only mode", tt.reason)
+			}
+
+			if tt.wantErr != (err != nil) {
+				t.Errorf("%s\nPerformDiff(...): want error %t, got %v", tt.reason, tt.wantErr, err)
+			}
+
+			var got []ValidationReport
+			if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+				t.Fatalf("%s\nPerformDiff(...): output is not a JSON report: %v\n%s", tt.reason, err, stdout.String())
+			}
+
+			if diff := gcmp.Diff(tt.wantReports, got); diff != "" {
+				t.Errorf("%s\nPerformDiff(...): -want reports, +got reports:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/kubecfg"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
+	ld "github.com/crossplane/cli/v2/cmd/crossplane/common/load"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// ExplainCmd prints which composition and revision an XR or claim would use, without rendering or diffing.
+type ExplainCmd struct {
+	Context    KubeContext   `help:"Kubernetes context to use (defaults to current context)."                           name:"context"`
+	Kubeconfig string        `help:"Path to the kubeconfig file to use (defaults to $KUBECONFIG, then ~/.kube/config)." name:"kubeconfig"                         type:"path"`
+	InCluster  bool          `help:"Use the in-cluster ServiceAccount config, ignoring any kubeconfig."                 name:"in-cluster"`
+	Timeout    time.Duration `default:"1m"                                                                              help:"How long to run before timing out."`
+
+	Files []string `arg:"" help:"YAML files containing XRs or claims to explain." optional:""`
+}
+
+// Help returns help instructions for the explain command.
+func (c *ExplainCmd) Help() string {
+	return `
+This command prints the composition, composition revision, and selection reason that crossplane-diff would use for
+each provided XR or claim. Nothing is rendered or diffed.
+
+Examples:
+  # Show which composition and revision xr.yaml would be rendered against.
+  crossplane-diff explain xr.yaml
+
+  # Explain resources read from stdin.
+  cat xr.yaml | crossplane-diff explain -
+`
+}
+
+// GetKubeContext implements ContextProvider.
+func (c *ExplainCmd) GetKubeContext() KubeContext {
+	return c.Context
+}
+
+// GetKubeconfig implements kubecfg.KubeconfigProvider.
+func (c *ExplainCmd) GetKubeconfig() string {
+	return c.Kubeconfig
+}
+
+// GetInCluster implements kubecfg.InClusterProvider.
+func (c *ExplainCmd) GetInCluster() bool {
+	return c.InCluster
+}
+
+// GetUserAgent implements kubecfg.UserAgentProvider.
+func (c *ExplainCmd) GetUserAgent() string {
+	return kubecfg.DefaultUserAgent("explain")
+}
+
+// BeforeApply binds the ExplainCmd pointer via the ContextProvider interface, mirroring CommonCmdFields.
+func (c *ExplainCmd) BeforeApply(ctx *kong.Context) error { //nolint:unparam // BeforeApply requires this signature.
+	ctx.BindTo(c, (*ContextProvider)(nil))
+	return nil
+}
+
+// Run executes the explain command.
+func (c *ExplainCmd) Run(kongCtx *kong.Context, log logging.Logger, appCtx *AppContext, exitCode *ExitCode) error {
+	ctx, cancel, err := initializeAppContext(c.Timeout, appCtx, log)
+	if err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return err
+	}
+	defer cancel()
+
+	loader, err := ld.NewCompositeLoader(c.Files)
+	if err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return errors.Wrap(err, "cannot create resource loader")
+	}
+
+	resources, err := loader.Load()
+	if err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return errors.Wrap(err, "cannot load resources")
+	}
+
+	failed := 0
+
+	for _, res := range resources {
+		sel, err := appCtx.XpClients.Composition.ExplainCompositionSelection(ctx, res)
+		if err != nil {
+			failed++
+		}
+
+		if werr := writeCompositionSelection(kongCtx.Stdout, res, sel, err); werr != nil {
+			exitCode.Code = dp.ExitCodeToolError
+			return errors.Wrap(werr, "cannot write output")
+		}
+	}
+
+	if failed > 0 {
+		exitCode.Code = dp.ExitCodeToolError
+		return errors.Errorf("cannot resolve a composition for %d of %d resources", failed, len(resources))
+	}
+
+	return nil
+}
+
+// writeCompositionSelection writes one resource's composition selection, or the error that prevented it.
+func writeCompositionSelection(w io.Writer, res *un.Unstructured, sel *types.CompositionSelection, selErr error) error {
+	name := res.GetName()
+	if ns := res.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s\n", res.GetKind(), name)
+
+	switch {
+	case selErr != nil:
+		fmt.Fprintf(&b, "  Error:       %v\n", selErr)
+	default:
+		fmt.Fprintf(&b, "  Composition: %s\n", sel.Composition.GetName())
+
+		if sel.RevisionName != "" {
+			fmt.Fprintf(&b, "  Revision:    %s (revision %d)\n", sel.RevisionName, sel.RevisionNumber)
+		} else {
+			fmt.Fprintf(&b, "  Revision:    <none>\n")
+		}
+
+		fmt.Fprintf(&b, "  Reason:      %s\n", strings.Join(sel.Reasons, "; "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
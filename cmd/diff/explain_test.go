@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+func TestWriteCompositionSelection(t *testing.T) {
+	comp := tu.NewComposition("test-comp").Build()
+
+	tests := map[string]struct {
+		reason string
+		ns     string
+		sel    *types.CompositionSelection
+		err    error
+		want   string
+	}{
+		"WithRevision": {
+			reason: "Should print the composition, revision, and joined reasons",
+			sel: &types.CompositionSelection{
+				Composition:    comp,
+				RevisionName:   "test-comp-abc123",
+				RevisionNumber: 3,
+				Reasons:        []string{"referenced by compositionRef test-comp", "Automatic update policy uses the latest revision"},
+			},
+			want: `XR1 my-xr
+  Composition: test-comp
+  Revision:    test-comp-abc123 (revision 3)
+  Reason:      referenced by compositionRef test-comp; Automatic update policy uses the latest revision
+`,
+		},
+		"WithoutRevision": {
+			reason: "Should mark the revision as none when the Composition is used directly",
+			ns:     "team-a",
+			sel: &types.CompositionSelection{
+				Composition: comp,
+				Reasons:     []string{"only composition whose compositeTypeRef is example.org/v1 XR1"},
+			},
+			want: `XR1 team-a/my-xr
+  Composition: test-comp
+  Revision:    <none>
+  Reason:      only composition whose compositeTypeRef is example.org/v1 XR1
+`,
+		},
+		"Error": {
+			reason: "Should print the selection error in place of the selection",
+			err:    errors.New("no composition found for example.org/v1, Kind=XR1"),
+			want: `XR1 my-xr
+  Error:       no composition found for example.org/v1, Kind=XR1
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := tu.NewResource("example.org/v1", "XR1", "my-xr").InNamespace(tt.ns).Build()
+
+			var buf bytes.Buffer
+			if err := writeCompositionSelection(&buf, res, tt.sel, tt.err); err != nil {
+				t.Fatalf("\n%s\nwriteCompositionSelection(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("\n%s\nwriteCompositionSelection(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -24,9 +24,13 @@ package kubecfg
 import (
 	"fmt"
 	"os"
+	"runtime"
 
+	"github.com/crossplane-contrib/crossplane-diff/internal/versioninfo"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 )
 
 // Context is a kubeconfig context name. Kong binds flag values through this
@@ -39,15 +43,95 @@ type Provider interface {
 	GetKubeContext() Context
 }
 
+// UserAgentProvider is optionally implemented by a Provider to control the
+// User-Agent header sent with every API request, so cluster audit logs can
+// attribute traffic to crossplane-diff and the command that issued it.
+type UserAgentProvider interface {
+	GetUserAgent() string
+}
+
+// Default client-side rate limits, used unless the kubeconfig or provider
+// sets others.
+const (
+	defaultQPS   = 20
+	defaultBurst = 30
+)
+
+const (
+	errNoConfig          = "no kubeconfig found and not running in a cluster"
+	errInvalidKubeconfig = "invalid kubeconfig"
+)
+
+// KubeconfigProvider is optionally implemented by a Provider to name an
+// explicit kubeconfig file, which takes precedence over $KUBECONFIG and
+// $HOME/.kube/config. Commands implement this by exposing a --kubeconfig flag.
+type KubeconfigProvider interface {
+	GetKubeconfig() string
+}
+
+// InClusterProvider is optionally implemented by a Provider to skip kubeconfig
+// loading and use the in-cluster ServiceAccount config, as a Job or pod
+// running crossplane-diff would. Commands implement this by exposing an
+// --in-cluster flag.
+type InClusterProvider interface {
+	GetInCluster() bool
+}
+
+// RateLimitProvider is optionally implemented by a Provider to override the
+// client-side QPS and burst limits. Values that aren't positive keep the
+// defaults. Commands implement this by exposing --qps and --burst flags.
+type RateLimitProvider interface {
+	GetRateLimits() (qps float32, burst int)
+}
+
+// ImpersonationProvider is optionally implemented by a Provider to make every
+// API request as another user, so a diff previews what that identity may see
+// and do. Commands implement this by exposing --as, --as-group and --as-uid.
+type ImpersonationProvider interface {
+	GetImpersonation() rest.ImpersonationConfig
+}
+
+// DefaultUserAgent returns the User-Agent crossplane-diff identifies itself
+// with, e.g. "crossplane-diff/v0.4.0 (linux/amd64) xr". The command is
+// omitted when empty.
+func DefaultUserAgent(command string) string {
+	version := versioninfo.New().GetVersionString()
+	if version == "" {
+		version = "unknown"
+	}
+
+	ua := fmt.Sprintf("crossplane-diff/%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH)
+	if command != "" {
+		ua += " " + command
+	}
+
+	return ua
+}
+
 // Provide builds a *rest.Config using the provider's context.
 //
 // Resolution order:
-//  1. Standard clientcmd loading rules ($KUBECONFIG, then $HOME/.kube/config).
-//  2. If the provider supplies a non-empty context, it overrides the
+//  1. If the provider implements InClusterProvider and asks for it, the
+//     in-cluster ServiceAccount config is used and kubeconfig is ignored.
+//  2. Otherwise, the provider's explicit kubeconfig file, when it implements
+//     KubeconfigProvider and returns a non-empty path. A file that doesn't
+//     exist is an error.
+//  3. Otherwise the standard clientcmd loading rules ($KUBECONFIG, then
+//     $HOME/.kube/config).
+//  4. If the provider supplies a non-empty context, it overrides the
 //     kubeconfig's current-context.
-//  3. If no kubeconfig is available at all, fall back to the in-cluster
+//  5. If no kubeconfig is available at all, fall back to the in-cluster
 //     ServiceAccount config and emit a warning to stderr.
 //
+// The errors for "no kubeconfig and not in a cluster" and "kubeconfig invalid"
+// are worded differently so users can tell the two apart.
+//
+// The User-Agent is taken from the provider when it implements
+// UserAgentProvider and returns a non-empty value, else DefaultUserAgent.
+// Likewise the QPS and burst come from a RateLimitProvider, else defaults
+// of 20 and 30. An ImpersonationProvider naming a user sets the impersonation
+// config, overriding any the kubeconfig sets.
+//
 // This differs from controller-runtime's GetConfig, which prefers in-cluster
 // first — that behavior causes `crossplane-diff` running inside a pod to
 // ignore the user's kubeconfig context.
@@ -57,19 +141,78 @@ func Provide(p Provider) (*rest.Config, error) {
 	})
 }
 
-// provide is the testable core of Provide. It takes the in-cluster config
-// loader and a warning sink as seams.
-func provide(p Provider, inCluster func() (*rest.Config, error), warn func(msg string)) (*rest.Config, error) {
+// ContextNamespace returns the namespace set on the provider's kubeconfig
+// context, or an empty string if the context sets none or no kubeconfig can be
+// loaded. Unlike kubectl it doesn't fall back to "default", so callers can tell
+// an unset namespace apart.
+func ContextNamespace(p Provider) string {
+	raw, err := clientConfig(p).RawConfig()
+	if err != nil {
+		return ""
+	}
+
+	name := raw.CurrentContext
+	if kc := p.GetKubeContext(); kc != "" {
+		name = string(kc)
+	}
+
+	if kctx, ok := raw.Contexts[name]; ok {
+		return kctx.Namespace
+	}
+
+	return ""
+}
+
+// clientConfig loads the kubeconfig with the standard loading rules and the
+// provider's kubeconfig and context overrides.
+func clientConfig(p Provider) clientcmd.ClientConfig {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath(p)
 
 	overrides := &clientcmd.ConfigOverrides{}
 	if kc := p.GetKubeContext(); kc != "" {
 		overrides.CurrentContext = string(kc)
 	}
 
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
+
+// kubeconfigPath returns the provider's explicit kubeconfig file, if any.
+func kubeconfigPath(p Provider) string {
+	if kp, ok := p.(KubeconfigProvider); ok {
+		return kp.GetKubeconfig()
+	}
+
+	return ""
+}
+
+// provide is the testable core of Provide. It takes the in-cluster config
+// loader and a warning sink as seams.
+func provide(p Provider, inCluster func() (*rest.Config, error), warn func(msg string)) (*rest.Config, error) {
+	if ip, ok := p.(InClusterProvider); ok && ip.GetInCluster() {
+		if kubeconfigPath(p) != "" {
+			return nil, errors.New("--in-cluster cannot be combined with --kubeconfig")
+		}
+
+		icc, err := inCluster()
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot load in-cluster config")
+		}
+
+		applyDefaults(icc, p)
+
+		return icc, nil
+	}
+
+	// Check an explicit kubeconfig up front so a typo is reported plainly
+	// rather than as a clientcmd load error.
+	if path := kubeconfigPath(p); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, errors.Wrapf(err, "cannot read kubeconfig %s", path)
+		}
+	}
 
-	cfg, err := kubeConfig.ClientConfig()
+	cfg, err := clientConfig(p).ClientConfig()
 	if err != nil {
 		// IsEmptyConfig is true in two distinct scenarios: (a) no kubeconfig
 		// was found on disk, and (b) a kubeconfig was found but has no
@@ -81,30 +224,54 @@ func provide(p Provider, inCluster func() (*rest.Config, error), warn func(msg s
 			icc, iccErr := inCluster()
 			if iccErr == nil {
 				warn("no kubeconfig found, falling back to in-cluster config")
-				applyDefaults(icc)
+				applyDefaults(icc, p)
 
 				return icc, nil
 			}
-			// Return the original empty-config error — it's more actionable
+			// Wrap the original empty-config error — it's more actionable
 			// to a user who thinks they provided a kubeconfig than the
 			// in-cluster "token not found" error.
-			return nil, err
+			return nil, errors.Wrap(err, errNoConfig)
 		}
 
-		return nil, err
+		return nil, errors.Wrap(err, errInvalidKubeconfig)
 	}
 
-	applyDefaults(cfg)
+	applyDefaults(cfg, p)
 
 	return cfg, nil
 }
 
-func applyDefaults(cfg *rest.Config) {
+func applyDefaults(cfg *rest.Config, p Provider) {
+	cfg.UserAgent = DefaultUserAgent("")
+	if uap, ok := p.(UserAgentProvider); ok {
+		if ua := uap.GetUserAgent(); ua != "" {
+			cfg.UserAgent = ua
+		}
+	}
+
 	if cfg.QPS == 0 {
-		cfg.QPS = 20
+		cfg.QPS = defaultQPS
 	}
 
 	if cfg.Burst == 0 {
-		cfg.Burst = 30
+		cfg.Burst = defaultBurst
+	}
+
+	if ip, ok := p.(ImpersonationProvider); ok {
+		if imp := ip.GetImpersonation(); imp.UserName != "" {
+			cfg.Impersonate = imp
+		}
+	}
+
+	if rlp, ok := p.(RateLimitProvider); ok {
+		qps, burst := rlp.GetRateLimits()
+		if qps > 0 {
+			cfg.QPS = qps
+		}
+
+		if burst > 0 {
+			cfg.Burst = burst
+		}
 	}
 }
@@ -40,6 +40,7 @@ contexts:
   context:
     cluster: cluster-b
     user: user-b
+    namespace: team-b
 users:
 - name: user-a
   user: {}
@@ -136,8 +137,276 @@ func TestProvide_EmptyConfigAndInClusterFailsReturnsOriginal(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
-	// The error we surface should be the clientcmd empty-config error, not the in-cluster error.
-	if !clientcmd.IsEmptyConfig(err) {
-		t.Errorf("expected empty-config error to be preserved, got: %v", err)
+	// The error we surface should explain that neither a kubeconfig nor an
+	// in-cluster config was found, wrapping the clientcmd empty-config error
+	// rather than the in-cluster error.
+	if !strings.Contains(err.Error(), errNoConfig) || !strings.Contains(err.Error(), clientcmd.ErrEmptyConfig.Error()) ||
+		strings.Contains(err.Error(), inClusterErr.Error()) {
+		t.Errorf("expected a no-config error wrapping the empty-config error, got: %v", err)
+	}
+}
+
+func TestProvide_InvalidKubeconfig(t *testing.T) {
+	writeTempKubeconfig(t)
+
+	_, err := provide(staticProvider{ctx: "no-such-context"}, func() (*rest.Config, error) {
+		t.Fatal("in-cluster config should not be tried for an invalid kubeconfig")
+		return nil, nil
+	}, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), errInvalidKubeconfig) {
+		t.Errorf("expected an invalid-kubeconfig error, got: %v", err)
+	}
+}
+
+type inClusterProvider struct {
+	kubeconfigProvider
+}
+
+func (inClusterProvider) GetInCluster() bool { return true }
+
+func TestProvide_InCluster(t *testing.T) {
+	// A valid kubeconfig is present but must be ignored.
+	writeTempKubeconfig(t)
+
+	cases := map[string]struct {
+		p         Provider
+		inCluster func() (*rest.Config, error)
+		wantHost  string
+		wantErr   string
+	}{
+		"UsesInCluster": {
+			p:         inClusterProvider{},
+			inCluster: func() (*rest.Config, error) { return &rest.Config{Host: "https://in-cluster.example.com"}, nil },
+			wantHost:  "https://in-cluster.example.com",
+		},
+		"NotInCluster": {
+			p:         inClusterProvider{},
+			inCluster: func() (*rest.Config, error) { return nil, errors.New("no service account token") },
+			wantErr:   "cannot load in-cluster config",
+		},
+		"WithKubeconfig": {
+			p:         inClusterProvider{kubeconfigProvider{path: "kubeconfig"}},
+			inCluster: func() (*rest.Config, error) { return &rest.Config{}, nil },
+			wantErr:   "cannot be combined",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := provide(tc.p, tc.inCluster, func(msg string) {
+				t.Errorf("unexpected warning: %s", msg)
+			})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("provide: want error containing %q, got %v", tc.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("provide: %v", err)
+			}
+
+			if cfg.Host != tc.wantHost {
+				t.Errorf("Host = %q, want %q", cfg.Host, tc.wantHost)
+			}
+
+			// Rate-limit defaults apply to in-cluster config too.
+			if cfg.QPS == 0 || cfg.Burst == 0 {
+				t.Errorf("QPS/Burst = %v/%d, want defaults applied", cfg.QPS, cfg.Burst)
+			}
+		})
+	}
+}
+
+type userAgentProvider struct {
+	staticProvider
+
+	ua string
+}
+
+func (u userAgentProvider) GetUserAgent() string { return u.ua }
+
+func TestProvide_UserAgent(t *testing.T) {
+	writeTempKubeconfig(t)
+
+	cases := map[string]struct {
+		p    Provider
+		want string
+	}{
+		"Default":        {p: staticProvider{}, want: DefaultUserAgent("")},
+		"ProviderAgent":  {p: userAgentProvider{ua: "audit-bot/1.0"}, want: "audit-bot/1.0"},
+		"EmptyFallsBack": {p: userAgentProvider{}, want: DefaultUserAgent("")},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := Provide(tc.p)
+			if err != nil {
+				t.Fatalf("Provide: %v", err)
+			}
+
+			if cfg.UserAgent != tc.want {
+				t.Errorf("UserAgent = %q, want %q", cfg.UserAgent, tc.want)
+			}
+		})
+	}
+}
+
+type kubeconfigProvider struct {
+	staticProvider
+
+	path string
+}
+
+func (k kubeconfigProvider) GetKubeconfig() string { return k.path }
+
+func TestProvide_ExplicitKubeconfig(t *testing.T) {
+	path := writeTempKubeconfig(t)
+	// The explicit file must win over $KUBECONFIG.
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "does-not-exist"))
+
+	cases := map[string]struct {
+		p        Provider
+		wantHost string
+		wantErr  bool
+	}{
+		"CurrentContext": {p: kubeconfigProvider{path: path}, wantHost: "https://a.example.com"},
+		"WithContext":    {p: kubeconfigProvider{staticProvider: staticProvider{ctx: "ctx-b"}, path: path}, wantHost: "https://b.example.com"},
+		"Missing":        {p: kubeconfigProvider{path: filepath.Join(t.TempDir(), "missing")}, wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := provide(tc.p, func() (*rest.Config, error) {
+				return &rest.Config{Host: "https://in-cluster.example.com"}, nil
+			}, func(string) {})
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "missing") {
+					t.Errorf("provide: want error naming the missing kubeconfig, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("provide: %v", err)
+			}
+
+			if cfg.Host != tc.wantHost {
+				t.Errorf("Host = %q, want %q", cfg.Host, tc.wantHost)
+			}
+		})
+	}
+}
+
+type rateLimitProvider struct {
+	staticProvider
+
+	qps   float32
+	burst int
+}
+
+func (r rateLimitProvider) GetRateLimits() (float32, int) { return r.qps, r.burst }
+
+func TestProvide_RateLimits(t *testing.T) {
+	writeTempKubeconfig(t)
+
+	cases := map[string]struct {
+		p         Provider
+		wantQPS   float32
+		wantBurst int
+	}{
+		"Default":      {p: staticProvider{}, wantQPS: defaultQPS, wantBurst: defaultBurst},
+		"Override":     {p: rateLimitProvider{qps: 100, burst: 200}, wantQPS: 100, wantBurst: 200},
+		"ZeroFallback": {p: rateLimitProvider{}, wantQPS: defaultQPS, wantBurst: defaultBurst},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := Provide(tc.p)
+			if err != nil {
+				t.Fatalf("Provide: %v", err)
+			}
+
+			if cfg.QPS != tc.wantQPS || cfg.Burst != tc.wantBurst {
+				t.Errorf("QPS/Burst = %v/%d, want %v/%d", cfg.QPS, cfg.Burst, tc.wantQPS, tc.wantBurst)
+			}
+		})
+	}
+}
+
+type impersonationProvider struct {
+	staticProvider
+
+	imp rest.ImpersonationConfig
+}
+
+func (i impersonationProvider) GetImpersonation() rest.ImpersonationConfig { return i.imp }
+
+func TestProvide_Impersonation(t *testing.T) {
+	writeTempKubeconfig(t)
+
+	imp := rest.ImpersonationConfig{UserName: "system:serviceaccount:team-a:ci", Groups: []string{"dev"}, UID: "1234"}
+
+	cases := map[string]struct {
+		p    Provider
+		want rest.ImpersonationConfig
+	}{
+		"None":         {p: staticProvider{}},
+		"Impersonate":  {p: impersonationProvider{imp: imp}, want: imp},
+		"EmptyIgnored": {p: impersonationProvider{}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := Provide(tc.p)
+			if err != nil {
+				t.Fatalf("Provide: %v", err)
+			}
+
+			if cfg.Impersonate.UserName != tc.want.UserName || cfg.Impersonate.UID != tc.want.UID ||
+				strings.Join(cfg.Impersonate.Groups, ",") != strings.Join(tc.want.Groups, ",") {
+				t.Errorf("Impersonate = %+v, want %+v", cfg.Impersonate, tc.want)
+			}
+		})
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	got := DefaultUserAgent("xr")
+	if !strings.HasPrefix(got, "crossplane-diff/") || !strings.HasSuffix(got, ") xr") {
+		t.Errorf("DefaultUserAgent(%q) = %q, want crossplane-diff/<version> (<os>/<arch>) xr", "xr", got)
+	}
+}
+
+func TestContextNamespace(t *testing.T) {
+	writeTempKubeconfig(t)
+
+	cases := map[string]struct {
+		ctx  Context
+		want string
+	}{
+		"CurrentContextWithoutNamespace": {ctx: "", want: ""},
+		"ContextWithNamespace":           {ctx: "ctx-b", want: "team-b"},
+		"UnknownContext":                 {ctx: "ctx-missing", want: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ContextNamespace(staticProvider{ctx: tc.ctx}); got != tc.want {
+				t.Errorf("ContextNamespace(%q) = %q, want %q", tc.ctx, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContextNamespace_NoKubeconfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "does-not-exist"))
+	t.Setenv("HOME", t.TempDir())
+
+	if got := ContextNamespace(staticProvider{}); got != "" {
+		t.Errorf("ContextNamespace() = %q, want empty without a kubeconfig", got)
 	}
 }
@@ -20,19 +20,30 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/core"
+	k8 "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/kubernetes"
 	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/kubecfg"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/versioncmd"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/v2/pkg/v1"
 )
 
 var _ = kong.Must(&cli{})
@@ -49,21 +60,45 @@ type KubeContext = kubecfg.Context
 // the kubecfg package at every call site.
 type ContextProvider = kubecfg.Provider
 
+// DiskCacheProvider is optionally implemented by a ContextProvider to persist
+// XRDs and CRDs fetched from the cluster across runs.
+type DiskCacheProvider interface {
+	GetDiskCache(host string) (*core.DiskCache, error)
+}
+
+// ObservedDirProvider is optionally implemented by a ContextProvider to diff
+// against resources exported from a cluster instead of the cluster itself.
+type ObservedDirProvider interface {
+	GetObservedDir() string
+}
+
+// RetryPolicyProvider is optionally implemented by a ContextProvider to retry
+// cluster reads that fail with transient errors.
+type RetryPolicyProvider interface {
+	GetRetryPolicy() k8.RetryPolicy
+}
+
 // ExitCode tracks the exit code to return after command execution.
 // Commands set this based on their results (diffs found, validation errors, etc.).
 type ExitCode struct {
 	Code int
 }
 
-// FunctionCredentials holds Secret credentials loaded from a file path.
-// It implements kong.MapperValue to load secrets at CLI parse time.
-type FunctionCredentials struct {
-	Path    string          // Original path for logging/debugging
-	Secrets []corev1.Secret // Loaded secrets
+// pathLoader loads a flag's value from the file or directory at a path.
+type pathLoader[T any] interface {
+	Load(path string) (T, error)
 }
 
-// Decode implements kong.MapperValue to load secrets from the provided path.
-func (f *FunctionCredentials) Decode(ctx *kong.DecodeContext) error {
+// pathValue holds a value loaded from the file or directory at a path given on
+// the command line. It implements kong.MapperValue to load the value with L at
+// CLI parse time.
+type pathValue[T any, L pathLoader[T]] struct {
+	Path  string // Original path for logging/debugging
+	Value T      // Loaded value
+}
+
+// Decode implements kong.MapperValue to load the value from the provided path.
+func (v *pathValue[T, L]) Decode(ctx *kong.DecodeContext) error {
 	var path string
 	if err := ctx.Scan.PopValueInto("path", &path); err != nil {
 		return err
@@ -73,50 +108,290 @@ func (f *FunctionCredentials) Decode(ctx *kong.DecodeContext) error {
 		return nil
 	}
 
-	f.Path = path
+	var loader L
 
-	secrets, err := LoadFunctionCredentials(path)
+	value, err := loader.Load(path)
 	if err != nil {
 		return err
 	}
 
+	v.Path = path
+	v.Value = value
+
+	return nil
+}
+
+// FunctionCredentials holds Secret credentials loaded from a file path.
+type FunctionCredentials = pathValue[[]corev1.Secret, credentialsLoader]
+
+type credentialsLoader struct{}
+
+// Load loads the Secrets at path, failing if there are none.
+func (credentialsLoader) Load(path string) ([]corev1.Secret, error) {
+	secrets, err := LoadFunctionCredentials(path)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(secrets) == 0 {
-		return fmt.Errorf("no Secret resources found in %q - file must contain v1/Secret resources", path)
+		return nil, fmt.Errorf("no Secret resources found in %q - file must contain v1/Secret resources", path)
+	}
+
+	return secrets, nil
+}
+
+// LocalCRDs holds CRDs loaded from a file or directory, used in preference to
+// the cluster's.
+type LocalCRDs = pathValue[[]*extv1.CustomResourceDefinition, crdsLoader]
+
+type crdsLoader struct{}
+
+func (crdsLoader) Load(path string) ([]*extv1.CustomResourceDefinition, error) {
+	return LoadLocalCRDs(path)
+}
+
+// LocalXRDs holds XRDs loaded from a file or directory, used in preference to
+// the cluster's.
+type LocalXRDs = pathValue[[]*un.Unstructured, xrdsLoader]
+
+type xrdsLoader struct{}
+
+func (xrdsLoader) Load(path string) ([]*un.Unstructured, error) {
+	return LoadLocalXRDs(path)
+}
+
+// LocalCompositions holds compositions loaded from a file or directory, used in
+// preference to the cluster's.
+type LocalCompositions = pathValue[[]*apiextensionsv1.Composition, compositionsLoader]
+
+type compositionsLoader struct{}
+
+func (compositionsLoader) Load(path string) ([]*apiextensionsv1.Composition, error) {
+	return LoadLocalCompositions(path)
+}
+
+// LocalFunctions holds functions loaded from a file or directory, used in
+// preference to the cluster's when rendering.
+type LocalFunctions = pathValue[[]pkgv1.Function, functionsLoader]
+
+type functionsLoader struct{}
+
+func (functionsLoader) Load(path string) ([]pkgv1.Function, error) {
+	return LoadLocalFunctions(path)
+}
+
+// LocalEnvConfigs holds EnvironmentConfigs loaded from a file or directory,
+// used in preference to the cluster's of the same name when rendering.
+type LocalEnvConfigs = pathValue[[]*un.Unstructured, envConfigsLoader]
+
+type envConfigsLoader struct{}
+
+func (envConfigsLoader) Load(path string) ([]*un.Unstructured, error) {
+	return LoadLocalEnvironmentConfigs(path)
+}
+
+// ExternalResources holds resources loaded from a file or directory that
+// functions' required resources are resolved from instead of the cluster.
+type ExternalResources = pathValue[[]*un.Unstructured, externalResourcesLoader]
+
+type externalResourcesLoader struct{}
+
+func (externalResourcesLoader) Load(path string) ([]*un.Unstructured, error) {
+	return LoadExternalResources(path)
+}
+
+// ContextResources holds resources to seed into the function pipeline context,
+// keyed by context key. It implements kong.MapperValue; each occurrence of the
+// flag takes a KEY=FILE pair and adds one entry.
+type ContextResources struct {
+	Values map[string]any
+}
+
+// Decode implements kong.MapperValue to load the resource for one KEY=FILE pair.
+func (c *ContextResources) Decode(ctx *kong.DecodeContext) error {
+	var value string
+	if err := ctx.Scan.PopValueInto("value", &value); err != nil {
+		return err
+	}
+
+	key, path, ok := strings.Cut(value, "=")
+	if !ok || key == "" || path == "" {
+		return fmt.Errorf("expected KEY=FILE, got %q", value)
+	}
+
+	res, err := LoadContextResource(path)
+	if err != nil {
+		return err
+	}
+
+	if c.Values == nil {
+		c.Values = make(map[string]any)
+	}
+
+	c.Values[key] = res
+
+	return nil
+}
+
+// FunctionInputs holds extra input to merge into pipeline steps, keyed by step
+// name. It implements kong.MapperValue; each occurrence of the flag takes a
+// STEP=FILE pair and adds one entry.
+type FunctionInputs struct {
+	Values map[string]map[string]any
+}
+
+// Decode implements kong.MapperValue to load the input for one STEP=FILE pair.
+func (f *FunctionInputs) Decode(ctx *kong.DecodeContext) error {
+	var value string
+	if err := ctx.Scan.PopValueInto("value", &value); err != nil {
+		return err
+	}
+
+	step, path, ok := strings.Cut(value, "=")
+	if !ok || step == "" || path == "" {
+		return fmt.Errorf("expected STEP=FILE, got %q", value)
+	}
+
+	input, err := LoadFunctionInput(path)
+	if err != nil {
+		return err
 	}
 
-	f.Secrets = secrets
+	if f.Values == nil {
+		f.Values = make(map[string]map[string]any)
+	}
+
+	f.Values[step] = input
 
 	return nil
 }
 
+// CompositionMap holds per-kind composition overrides loaded from a YAML file
+// mapping resource kind to composition name, keyed by resource kind.
+type CompositionMap = pathValue[map[string]string, compositionMapLoader]
+
+type compositionMapLoader struct{}
+
+func (compositionMapLoader) Load(path string) (map[string]string, error) {
+	return LoadCompositionMap(path)
+}
+
+// BaselineFile holds the changes of an earlier run loaded from its JSON or YAML
+// output.
+type BaselineFile = pathValue[*renderer.Baseline, baselineLoader]
+
+type baselineLoader struct{}
+
+func (baselineLoader) Load(path string) (*renderer.Baseline, error) {
+	return LoadBaseline(path)
+}
+
+// IgnorePathsFile holds ignore paths loaded from a file with one path per line.
+type IgnorePathsFile = pathValue[[]string, ignorePathsLoader]
+
+type ignorePathsLoader struct{}
+
+func (ignorePathsLoader) Load(path string) ([]string, error) {
+	return LoadIgnorePathsFile(path)
+}
+
+// NormalizationFile holds per-kind normalization rules loaded from a YAML file.
+type NormalizationFile = pathValue[[]renderer.NormalizationRule, normalizationLoader]
+
+type normalizationLoader struct{}
+
+func (normalizationLoader) Load(path string) ([]renderer.NormalizationRule, error) {
+	config, err := LoadNormalizationConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Rules, nil
+}
+
 // CommonCmdFields contains common fields shared by both XR and Comp commands.
 // It implements ContextProvider to allow providers to access the context value
 // after flag parsing completes.
 type CommonCmdFields struct {
 	// Configuration options
-	Context                  KubeContext         `help:"Kubernetes context to use (defaults to current context)."                                   name:"context"`
-	Output                   string              `default:"diff"                                                                                    enum:"diff,json,yaml"                                                                                                                                        help:"Output format (diff, json, or yaml)." name:"output" short:"o"`
-	NoColor                  bool                `help:"Disable colorized output."                                                                  name:"no-color"`
-	Compact                  bool                `help:"Show compact diffs with minimal context."                                                   name:"compact"`
-	MaxNestedDepth           int                 `default:"10"                                                                                      help:"Maximum depth for nested XR recursion."                                                                                                                name:"max-nested-depth"`
-	MaxIterations            int                 `default:"20"                                                                                      help:"Maximum render iterations for requirements resolution or eventual-state simulation. Increase for complex pipelines that need more cycles to converge." name:"max-iterations"`
-	Timeout                  time.Duration       `default:"1m"                                                                                      help:"How long to run before timing out."`
-	IgnorePaths              []string            `help:"Paths to ignore in diffs (e.g., 'metadata.annotations[argocd.argoproj.io/tracking-id]')."   name:"ignore-paths"`
-	FunctionCredentials      FunctionCredentials `help:"A YAML file or directory of YAML files specifying Secret credentials to pass to Functions." name:"function-credentials"                                                                                                                                  placeholder:"PATH"`
-	FunctionRegistryOverride string              `help:"Override the registry for all function images (e.g., 'my-company.registry.io')."            name:"function-registry-override"`
-	EventualState            bool                `default:"false"                                                                                   help:"Show eventual state after all reconciliation cycles complete (useful with function-sequencer)."                                                        name:"eventual-state"`
+	Context                  KubeContext         `help:"Kubernetes context to use (defaults to current context)."                                                                                        name:"context"`
+	Kubeconfig               string              `help:"Path to the kubeconfig file to use (defaults to $KUBECONFIG, then ~/.kube/config)."                                                              name:"kubeconfig"                                                                                                                                            type:"path"`
+	InCluster                bool                `help:"Use the in-cluster ServiceAccount config, ignoring any kubeconfig."                                                                              name:"in-cluster"`
+	As                       string              `help:"Username to impersonate for every API request, to preview a diff under that identity's RBAC."                                                    name:"as"`
+	AsGroups                 []string            `help:"Group to impersonate along with --as. Repeat for multiple groups."                                                                               name:"as-group"`
+	AsUID                    string              `help:"UID to impersonate along with --as."                                                                                                             name:"as-uid"`
+	Output                   string              `default:"diff"                                                                                                                                         enum:"diff,text-no-ansi,json,yaml,csv,desired,github,dot,markdown,junit"                                                                                     help:"Output format (diff, text-no-ansi, json, yaml, csv, desired, github, dot, markdown, or junit). text-no-ansi is the diff without ANSI colors. github adds GitHub Actions annotations. desired prints the desired objects as YAML. dot prints a Graphviz graph, markdown a PR comment, junit a test report. Only xr supports csv, desired, dot, markdown or junit." name:"output"                   short:"o"`
+	OutputFile               string              `help:"Write the diff to this file, truncating it, instead of stdout. Colors are off unless --no-color=false is given."                                 name:"output-file"                                                                                                                                           placeholder:"PATH"`
+	NoColor                  bool                `help:"Disable colorized output."                                                                                                                       name:"no-color"`
+	Quiet                    bool                `help:"Only print actual changes and their summary, or a single 'No changes.' line when nothing changed. Human-readable output only."                   name:"quiet"`
+	SummaryOnly              bool                `help:"Only print the counts of added, modified, and removed resources, not the diffs. With --output=json or yaml, only the summary object is written." name:"summary-only"`
+	DiffStyle                string              `default:"unified"                                                                                                                                      enum:"unified,side-by-side"                                                                                                                                  help:"Layout of human-readable diffs: unified (- and + lines) or side-by-side (current and desired in two columns, sized to the terminal, or 80 columns when output isn't a terminal)."                                                                                                                                                                                 name:"diff-style"`
+	Compact                  bool                `help:"Show compact diffs with minimal context."                                                                                                        name:"compact"`
+	ContextLines             int                 `default:"3"                                                                                                                                            help:"Number of unchanged lines to show around each change in compact diffs. Implies --compact."                                                             name:"context-lines"`
+	MaxNestedDepth           int                 `default:"10"                                                                                                                                           help:"Maximum depth for nested XR recursion."                                                                                                                name:"max-nested-depth"`
+	MaxResources             int                 `default:"1000"                                                                                                                                         help:"Fail an XR whose renders, with those of its nested XRs, compose or observe more than this many resources, before diffing them (0 = no limit)."         name:"max-resources"`
+	MaxIterations            int                 `default:"20"                                                                                                                                           help:"Maximum render iterations for requirements resolution or eventual-state simulation. Increase for complex pipelines that need more cycles to converge." name:"max-iterations"`
+	MaxConcurrentRenders     int                 `default:"1"                                                                                                                                            help:"Maximum number of renders run at once, independent of resource concurrency. 1 serializes rendering."                                                   name:"max-concurrent-renders"`
+	Timeout                  time.Duration       `default:"1m"                                                                                                                                           help:"How long to run before timing out."`
+	TimeoutPerResource       time.Duration       `help:"How long each XR may take before it fails on its own and the rest carry on. Zero means no limit."                                                name:"timeout-per-resource"`
+	Retries                  int                 `default:"3"                                                                                                                                            help:"How many times to retry a cluster read that fails with a transient error such as throttling or a timeout. Zero disables retries."                      name:"retries"`
+	RetryBackoff             time.Duration       `default:"500ms"                                                                                                                                        help:"How long to wait before the first retry of a cluster read. The wait doubles for each retry after that."                                                name:"retry-backoff"`
+	QPS                      float32             `default:"20"                                                                                                                                           help:"Client-side rate limit for API requests, in queries per second. Raise it for large comp impact runs."                                                  name:"qps"`
+	Burst                    int                 `default:"30"                                                                                                                                           help:"Client-side burst allowance for API requests above --qps."                                                                                             name:"burst"`
+	IgnorePaths              []string            `help:"Paths to ignore in diffs (e.g., 'metadata.annotations[argocd.argoproj.io/*]' or 'spec.items[*].status')."                                        name:"ignore-paths"`
+	IgnorePathsFile          IgnorePathsFile     `help:"File listing paths to ignore in diffs, one per line, merged with --ignore-paths. Blank lines and lines starting with # are skipped."             name:"ignore-paths-file"                                                                                                                                     placeholder:"PATH"`
+	Normalization            NormalizationFile   `help:"YAML file of per-kind normalization rules (ignorePath, keyedArray, quantity, lateInit) applied before diffing."                                  name:"normalization-config"                                                                                                                                  placeholder:"PATH"`
+	Normalize                bool                `default:"false"                                                                                                                                        help:"Treat values that differ only in representation, like 30 and \"30\" or true and \"true\", as unchanged in every field."                                name:"normalize"`
+	ShowLabelsDiffOnly       bool                `default:"false"                                                                                                                                        help:"Only show changes to metadata.labels and metadata.annotations in each resource's diff. Resources changed elsewhere are still counted in the summary."  name:"show-labels-diff-only"`
+	IgnoreManagedFields      bool                `default:"false"                                                                                                                                        help:"Don't show fields that other controllers or the API server set as removed, going by the cluster object's managedFields."                               name:"ignore-managed-fields"`
+	FunctionCredentials      FunctionCredentials `help:"A YAML file or directory of YAML files specifying Secret credentials to pass to Functions."                                                      name:"function-credentials"                                                                                                                                  placeholder:"PATH"`
+	ContextResources         ContextResources    `help:"Seed the function pipeline context with the resource in FILE under KEY. Repeatable."                                                             name:"context-resource"                                                                                                                                      placeholder:"KEY=FILE"`
+	FunctionInputs           FunctionInputs      `help:"Merge the input in FILE into the input of pipeline step STEP before rendering. Values in FILE win. Repeatable."                                  name:"function-input"                                                                                                                                        placeholder:"STEP=FILE"`
+	FunctionRegistryOverride string              `help:"Override the registry for all function images (e.g., 'my-company.registry.io')."                                                                 name:"function-registry-override"`
+	DryRunNamespace          string              `help:"Namespace for dry-run applies of namespaced resources that render without one."                                                                  name:"dry-run-namespace"`
+	DryRunKinds              []string            `help:"Only dry-run apply resources of these kinds (Kind or Kind.group); diff other kinds locally."                                                     name:"dry-run-kinds"                                                                                                                                         placeholder:"KIND"`
+	NoDryRunKinds            []string            `help:"Diff resources of these kinds (Kind or Kind.group) locally instead of dry-run applying them, e.g. kinds with slow admission webhooks."           name:"no-dry-run-kinds"                                                                                                                                      placeholder:"KIND"`
+	FieldManager             string              `help:"Field manager for dry-run applies. Defaults to each resource's Crossplane field owner, or crossplane-diff if it has none."                       name:"field-manager"`
+	ForceConflicts           bool                `help:"Force ownership of fields other field managers own during dry-run applies, as a forced apply would, instead of failing on conflicts."            name:"force-conflicts"`
+	UserAgent                string              `help:"Override the User-Agent sent with API requests (defaults to crossplane-diff/<version>)."                                                         name:"user-agent"`
+	ObservedDir              string              `help:"Diff against resources exported from a cluster to YAML files in this directory instead of the cluster itself. No cluster connection is made."    name:"observed-dir"                                                                                                                                          placeholder:"DIR"`
+	LocalCRDs                LocalCRDs           `help:"YAML file or directory of CRDs to use in preference to the cluster's, e.g. for types that aren't installed yet."                                 name:"local-crds"                                                                                                                                            placeholder:"DIR"`
+	LocalXRDs                LocalXRDs           `help:"YAML file or directory of XRDs to use in preference to the cluster's."                                                                           name:"local-xrds"                                                                                                                                            placeholder:"DIR"`
+	LocalCompositions        LocalCompositions   `help:"YAML file or directory of compositions to use in preference to the cluster's."                                                                   name:"local-compositions"                                                                                                                                    placeholder:"DIR"`
+	FunctionsFile            LocalFunctions      `help:"YAML file or directory of Functions to render with in preference to the cluster's, e.g. to try another version."                                 name:"functions-file"                                                                                                                                        placeholder:"PATH"`
+	EnvConfigFile            LocalEnvConfigs     `help:"YAML file or directory of EnvironmentConfigs to render with, overriding cluster EnvironmentConfigs of the same name."                            name:"env-config-file"                                                                                                                                       placeholder:"PATH"`
+	ExternalResourcesDir     ExternalResources   `help:"YAML file or directory of resources to resolve functions' required resources from, instead of the cluster."                                      name:"external-resources-dir"                                                                                                                                placeholder:"DIR"`
+	CacheDir                 string              `help:"Persist XRDs and CRDs fetched from the cluster under this directory, keyed by API server URL, and reuse them on later runs while fresh."         name:"cache-dir"                                                                                                                                             placeholder:"PATH"`
+	CacheTTL                 time.Duration       `default:"1h"                                                                                                                                           help:"How long XRDs and CRDs cached by --cache-dir stay fresh."                                                                                              name:"cache-ttl"`
+	NoCache                  bool                `help:"Ignore --cache-dir and fetch XRDs and CRDs from the cluster without caching them."                                                               name:"no-cache"`
+	RefreshCache             bool                `help:"Discard the XRDs and CRDs cached for this cluster and fetch them again, e.g. after CRDs change within --cache-ttl."                              name:"refresh-cache"`
+	EventualState            bool                `default:"false"                                                                                                                                        help:"Show eventual state after all reconciliation cycles complete (useful with function-sequencer)."                                                        name:"eventual-state"`
+	PartialNested            bool                `default:"false"                                                                                                                                        help:"Keep diffing the rest of a nested XR tree when one nested XR fails, and report the failure."                                                           name:"partial-nested"`
+	OwnerController          bool                `default:"false"                                                                                                                                        help:"Only match existing resources whose controller owner reference points at the expected composite."                                                      name:"owner-controller"`
+	ShowWarnings             bool                `default:"false"                                                                                                                                        help:"Show warnings the API server returns for dry-run applies (e.g. deprecations) under each resource."                                                     name:"show-warnings"`
+	ShowExternalResources    bool                `help:"List the required resources each XR's functions were given, and how each matched, after the diff."                                               name:"show-external-resources"`
+	SkipMissingRevisions     bool                `help:"Render an XR whose pinned composition revision no longer exists against the latest revision, with a note on its diff, instead of failing it."    name:"skip-missing-revisions"`
+	LatestRevision           bool                `help:"Render every XR against its composition's latest revision, whatever its update policy or pin. The comp command keeps Manual XRs."                name:"latest-revision"`
+	FailOn                   []string            `default:"added,modified,removed"                                                                                                                       enum:"added,modified,removed,none"                                                                                                                           help:"Diff types that exit with the differences code, comma-separated: added, modified or removed. none never does, e.g. --fail-on=removed fails only on deletions."                                                                                                                                                                                                    name:"fail-on"                  placeholder:"TYPES"`
+	Strict                   bool                `help:"Fail a resource whose diff may be incomplete, e.g. a missing EnvironmentConfig or a function warning, instead of diffing it best-effort."        name:"strict"`
+	MaxDiffFieldSize         int                 `default:"0"                                                                                                                                            help:"Compare string fields larger than this many bytes by size and digest instead of a line diff (0 = no limit)."                                           name:"max-diff-field-size"                                                                                                                                                                                                                                                                                                                                              placeholder:"BYTES"`
 
 	// CrossplaneVersion / CrossplaneImage / CrossplaneRenderBinary select the
 	// crossplane render backend. They are mutually exclusive (kong "xor"
 	// group; upstream render.EngineFlags enforces the same). When none is set,
 	// the docker engine pulls xpkg.crossplane.io/crossplane/crossplane:stable.
-	CrossplaneVersion string `help:"Pin the crossplane render version (e.g. v2.3.4); the docker engine pulls xpkg.crossplane.io/crossplane/crossplane:<version>. Minimum v2.3.4." name:"crossplane-version" placeholder:"VERSION" xor:"crossplane-render-backend"`
-	CrossplaneImage   string `help:"Override the full crossplane render image reference (e.g. for a private mirror)."                                                             name:"crossplane-image"   placeholder:"IMAGE"   xor:"crossplane-render-backend"`
+	CrossplaneVersion string `help:"Pin the crossplane render version (e.g. v2.3.4); the docker engine pulls xpkg.crossplane.io/crossplane/crossplane:<version>. Minimum v2.3.4."    name:"crossplane-version"                                                                                                                                    placeholder:"VERSION"                                                                                                                                                                                                                                                                                                                                                   xor:"crossplane-render-backend"`
+	CrossplaneImage   string `help:"Override the full crossplane render image reference (e.g. for a private mirror)."                                                                name:"crossplane-image"                                                                                                                                      placeholder:"IMAGE"                                                                                                                                                                                                                                                                                                                                                     xor:"crossplane-render-backend"`
 
 	// CrossplaneRenderBinary is a hidden test-only override that points the
 	// render engine at a local `crossplane` binary. Production users leave
 	// this unset and the docker engine handles rendering.
-	CrossplaneRenderBinary string `help:"(test only) Path to a local crossplane binary used by the render engine instead of the docker image." hidden:"" name:"crossplane-render-binary" xor:"crossplane-render-backend"`
+	CrossplaneRenderBinary string `help:"(test only) Path to a local crossplane binary used by the render engine instead of the docker image."                                            hidden:""                                                                                                                                                    name:"crossplane-render-binary"                                                                                                                                                                                                                                                                                                                                         xor:"crossplane-render-backend"`
+
+	command    string   `kong:"-"` // selected subcommand name, recorded in BeforeApply for the User-Agent.
+	noColorSet bool     `kong:"-"` // whether --no-color was given explicitly, recorded in BeforeApply.
+	contextSet bool     `kong:"-"` // whether --context-lines was given explicitly, recorded in BeforeApply.
+	outputFile *os.File `kong:"-"` // the opened --output-file, closed by closeOutputFile.
 }
 
 // Validate enforces the minimum supported crossplane render version when a
@@ -125,7 +400,74 @@ type CommonCmdFields struct {
 // cluster connection or render. --crossplane-image is not checked: a full
 // image reference carries no comparable version. See
 // diffprocessor.MinCrossplaneRenderVersion / crossplane-diff#399.
+// It also rejects a --max-concurrent-renders below one, a negative
+// --max-diff-field-size, --max-resources, --context-lines, --cache-ttl, --timeout-per-resource,
+// --retries or --retry-backoff, a --qps or --burst that isn't positive, --as-group or --as-uid
+// without --as, --fail-on=none alongside other diff types, and --summary-only with an output
+// format that has no summary.
 func (c *CommonCmdFields) Validate() error {
+	if c.MaxConcurrentRenders < 1 {
+		return fmt.Errorf("--max-concurrent-renders must be at least 1, got %d", c.MaxConcurrentRenders)
+	}
+
+	if c.MaxDiffFieldSize < 0 {
+		return fmt.Errorf("--max-diff-field-size must not be negative, got %d", c.MaxDiffFieldSize)
+	}
+
+	if c.MaxResources < 0 {
+		return fmt.Errorf("--max-resources must not be negative, got %d", c.MaxResources)
+	}
+
+	if c.ContextLines < 0 {
+		return fmt.Errorf("--context-lines must not be negative, got %d", c.ContextLines)
+	}
+
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("--cache-ttl must not be negative, got %s", c.CacheTTL)
+	}
+
+	if c.Retries < 0 {
+		return fmt.Errorf("--retries must not be negative, got %d", c.Retries)
+	}
+
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("--retry-backoff must not be negative, got %s", c.RetryBackoff)
+	}
+
+	if c.TimeoutPerResource < 0 {
+		return fmt.Errorf("--timeout-per-resource must not be negative, got %s", c.TimeoutPerResource)
+	}
+
+	if c.As == "" && (len(c.AsGroups) > 0 || c.AsUID != "") {
+		return errors.New("--as-group and --as-uid require --as")
+	}
+
+	if c.QPS <= 0 {
+		return fmt.Errorf("--qps must be positive, got %v", c.QPS)
+	}
+
+	if c.Burst <= 0 {
+		return fmt.Errorf("--burst must be positive, got %d", c.Burst)
+	}
+
+	for _, p := range c.IgnorePaths {
+		if err := renderer.ValidateIgnorePath(p); err != nil {
+			return fmt.Errorf("invalid --ignore-paths: %w", err)
+		}
+	}
+
+	if slices.Contains(c.FailOn, "none") && len(c.FailOn) > 1 {
+		return errors.New("--fail-on=none cannot be combined with other diff types")
+	}
+
+	if c.SummaryOnly {
+		switch renderer.OutputFormat(c.Output) {
+		case renderer.OutputFormatCSV, renderer.OutputFormatDesired, renderer.OutputFormatGitHub, renderer.OutputFormatDOT, renderer.OutputFormatMarkdown, renderer.OutputFormatJUnit:
+			return fmt.Errorf("--summary-only cannot be used with --output=%s", c.Output)
+		case renderer.OutputFormatDiff, renderer.OutputFormatTextNoANSI, renderer.OutputFormatJSON, renderer.OutputFormatYAML:
+		}
+	}
+
 	if c.CrossplaneVersion == "" {
 		return nil
 	}
@@ -138,6 +480,67 @@ func (c *CommonCmdFields) GetKubeContext() KubeContext {
 	return c.Context
 }
 
+// GetKubeconfig implements kubecfg.KubeconfigProvider.
+func (c *CommonCmdFields) GetKubeconfig() string {
+	return c.Kubeconfig
+}
+
+// GetInCluster implements kubecfg.InClusterProvider.
+func (c *CommonCmdFields) GetInCluster() bool {
+	return c.InCluster
+}
+
+// GetUserAgent implements kubecfg.UserAgentProvider, returning --user-agent when
+// set and otherwise the default agent tagged with the running command.
+func (c *CommonCmdFields) GetUserAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+
+	return kubecfg.DefaultUserAgent(c.command)
+}
+
+// GetDiskCache implements DiskCacheProvider, returning the cache for the
+// cluster at host, or nil when --cache-dir is unset or --no-cache is given.
+// With --refresh-cache the cluster's existing entries are discarded first.
+func (c *CommonCmdFields) GetDiskCache(host string) (*core.DiskCache, error) {
+	if c.CacheDir == "" || c.NoCache {
+		return nil, nil
+	}
+
+	cache := core.NewDiskCache(c.CacheDir, host, c.CacheTTL)
+	if c.RefreshCache {
+		if err := cache.Clear(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cache, nil
+}
+
+// GetRetryPolicy implements RetryPolicyProvider, returning the policy set by
+// --retries and --retry-backoff.
+func (c *CommonCmdFields) GetRetryPolicy() k8.RetryPolicy {
+	return k8.RetryPolicy{Retries: c.Retries, Backoff: c.RetryBackoff}
+}
+
+// GetImpersonation implements kubecfg.ImpersonationProvider, returning the
+// identity set by --as, --as-group and --as-uid.
+func (c *CommonCmdFields) GetImpersonation() rest.ImpersonationConfig {
+	return rest.ImpersonationConfig{UserName: c.As, UID: c.AsUID, Groups: c.AsGroups}
+}
+
+// GetRateLimits implements kubecfg.RateLimitProvider, returning the limits set
+// by --qps and --burst.
+func (c *CommonCmdFields) GetRateLimits() (float32, int) {
+	return c.QPS, c.Burst
+}
+
+// GetObservedDir implements ObservedDirProvider.
+func (c *CommonCmdFields) GetObservedDir() string {
+	return c.ObservedDir
+}
+
 func (v verboseFlag) BeforeApply(ctx *kong.Context) error { //nolint:unparam // BeforeApply requires this signature.
 	zapLogger := zap.New(zap.UseDevMode(true))
 	log.SetLogger(zapLogger)
@@ -152,6 +555,23 @@ func (v verboseFlag) BeforeApply(ctx *kong.Context) error { //nolint:unparam //
 // The key insight is that we bind a POINTER here - when providers are resolved later
 // (in AfterApply or Run), they dereference the pointer and get the current field values.
 func (c *CommonCmdFields) BeforeApply(ctx *kong.Context) error { //nolint:unparam // BeforeApply requires this signature.
+	if cmd := ctx.Selected(); cmd != nil {
+		c.command = cmd.Name
+	}
+
+	for _, p := range ctx.Path {
+		if p.Flag == nil {
+			continue
+		}
+
+		switch p.Flag.Name {
+		case "no-color":
+			c.noColorSet = true
+		case "context-lines":
+			c.contextSet = true
+		}
+	}
+
 	ctx.BindTo(c, (*ContextProvider)(nil))
 	return nil
 }
@@ -162,8 +582,9 @@ type cli struct {
 	// order they're specified here. Keep them in alphabetical order.
 
 	// Subcommands.
-	Comp CompCmd `cmd:""         help:"Show impact of composition changes on existing XRs."`
-	XR   XRCmd   `aliases:"diff" cmd:""                                                     help:"See what changes will be made against a live cluster when a given Crossplane resource would be applied."`
+	Comp    CompCmd    `cmd:""         help:"Show impact of composition changes on existing XRs."`
+	Explain ExplainCmd `cmd:""         help:"Print the composition and revision an XR or claim would use, without diffing."`
+	XR      XRCmd      `aliases:"diff" cmd:""                                                     help:"See what changes will be made against a live cluster when a given Crossplane resource would be applied."`
 
 	Version versioncmd.Cmd `cmd:"" help:"Print the client and server version information for the current context."`
 
@@ -186,7 +607,7 @@ func main() {
 		kong.Bind(exitCode), // Bind exit code state
 		// Providers are resolved lazily when dependencies are needed.
 		// kubecfg.Provide depends on kubecfg.Provider (bound in CommonCmdFields.BeforeApply)
-		// provideAppContext depends on *rest.Config and logging.Logger
+		// provideAppContext depends on logging.Logger and ContextProvider
 		kong.BindToProvider(kubecfg.Provide),
 		kong.BindToProvider(provideAppContext),
 		kong.ConfigureHelp(kong.HelpOptions{
@@ -221,14 +642,28 @@ func main() {
 var cachedAppContext *AppContext
 
 // provideAppContext creates the application context with all initialized clients.
-// This provider depends on *rest.Config and logging.Logger, which Kong resolves first.
+// This provider depends on logging.Logger and ContextProvider, which Kong resolves first.
+// When the ContextProvider implements ObservedDirProvider and names a directory, the
+// clients serve the resources in it and no REST config is loaded. Otherwise the REST
+// config comes from kubecfg.Provide, and the disk cache and retry policy are used when the
+// ContextProvider implements DiskCacheProvider and RetryPolicyProvider.
 // The result is cached to ensure the same instance is used throughout the command lifecycle.
-func provideAppContext(config *rest.Config, log logging.Logger) (*AppContext, error) {
+func provideAppContext(log logging.Logger, p ContextProvider) (*AppContext, error) {
 	if cachedAppContext != nil {
 		return cachedAppContext, nil
 	}
 
-	appCtx, err := NewAppContext(config, log)
+	var (
+		appCtx *AppContext
+		err    error
+	)
+
+	if odp, ok := p.(ObservedDirProvider); ok && odp.GetObservedDir() != "" {
+		appCtx, err = NewObservedAppContext(odp.GetObservedDir(), log)
+	} else {
+		appCtx, err = newClusterAppContext(log, p)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -237,3 +672,29 @@ func provideAppContext(config *rest.Config, log logging.Logger) (*AppContext, er
 
 	return appCtx, nil
 }
+
+// newClusterAppContext creates the application context for the cluster the
+// ContextProvider selects.
+func newClusterAppContext(log logging.Logger, p ContextProvider) (*AppContext, error) {
+	config, err := kubecfg.Provide(p)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("Using client-side rate limits", "qps", config.QPS, "burst", config.Burst)
+
+	var cache *core.DiskCache
+
+	if dcp, ok := p.(DiskCacheProvider); ok {
+		if cache, err = dcp.GetDiskCache(config.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	var retry k8.RetryPolicy
+	if rpp, ok := p.(RetryPolicyProvider); ok {
+		retry = rpp.GetRetryPolicy()
+	}
+
+	return NewAppContext(config, cache, retry, log)
+}
@@ -126,6 +126,31 @@ func TestRenderBackendFlags(t *testing.T) {
 			wantErr:     true,
 			errContains: "crossplane-image",
 		},
+		"MaxConcurrentRendersDefault": {
+			args: []string{"xr", "<file>"},
+			check: func(t *testing.T, c *cli) {
+				t.Helper()
+
+				if got := c.XR.MaxConcurrentRenders; got != 1 {
+					t.Errorf("MaxConcurrentRenders = %d, want 1", got)
+				}
+			},
+		},
+		"MaxConcurrentRendersSet": {
+			args: []string{"comp", "--max-concurrent-renders", "4", "<file>"},
+			check: func(t *testing.T, c *cli) {
+				t.Helper()
+
+				if got := c.Comp.MaxConcurrentRenders; got != 4 {
+					t.Errorf("MaxConcurrentRenders = %d, want 4", got)
+				}
+			},
+		},
+		"MaxConcurrentRendersZeroRejected": {
+			args:        []string{"xr", "--max-concurrent-renders", "0", "<file>"},
+			wantErr:     true,
+			errContains: "--max-concurrent-renders",
+		},
 		"CompVersionBelowMinimumRejected": {
 			args:        []string{"comp", "--crossplane-version", "v2.0.0", "<file>"},
 			wantErr:     true,
@@ -0,0 +1,118 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+)
+
+// Baseline holds the changes reported by an earlier run with structured output, so a later run
+// can tell drift that appeared since from drift that was already there.
+type Baseline struct {
+	// fingerprints holds the canonical form of each baseline change, keyed by diff key.
+	fingerprints map[string]string
+}
+
+// NewBaseline creates a Baseline from the changes of a StructuredDiffOutput.
+func NewBaseline(changes []ChangeDetail) *Baseline {
+	b := &Baseline{fingerprints: make(map[string]string, len(changes))}
+
+	for i := range changes {
+		c := &changes[i]
+		b.fingerprints[dt.MakeDiffKey(c.APIVersion, c.Kind, c.Namespace, c.Name)] = changeFingerprint(c)
+	}
+
+	return b
+}
+
+// DriftReport sorts the changes of a run against a Baseline. Each entry is a resource's
+// Kind/name.
+type DriftReport struct {
+	New       []string // changes missing from the baseline or different from it
+	Resolved  []string // baseline changes that no longer occur
+	Unchanged []string // changes identical to the baseline
+}
+
+// Compare sorts diffs against the baseline. It returns diffs without the unchanged drift, so
+// only new drift is rendered, along with the report of all three kinds of drift. Unchanged
+// (DiffTypeEqual) diffs are kept.
+func (b *Baseline) Compare(diffs map[string]*dt.ResourceDiff) (map[string]*dt.ResourceDiff, DriftReport) {
+	var report DriftReport
+
+	filtered := make(map[string]*dt.ResourceDiff, len(diffs))
+	changed := make(map[string]bool, len(diffs))
+
+	for key, diff := range diffs {
+		if diff.DiffType == dt.DiffTypeEqual {
+			filtered[key] = diff
+			continue
+		}
+
+		// Baseline changes are keyed by their structured output identity, which may differ
+		// from the map key.
+		id := dt.MakeDiffKey(diff.Gvk.GroupVersion().String(), diff.Gvk.Kind, diff.Namespace, diff.ResourceName)
+		changed[id] = true
+
+		if fp, ok := b.fingerprints[id]; ok && fp != "" && fp == changeFingerprint(resourceDiffToChangeDetail(diff)) {
+			report.Unchanged = append(report.Unchanged, getKindName(diff))
+			continue
+		}
+
+		filtered[key] = diff
+		report.New = append(report.New, getKindName(diff))
+	}
+
+	for id := range b.fingerprints {
+		if !changed[id] {
+			report.Resolved = append(report.Resolved, dt.KindNameFromDiffKey(id))
+		}
+	}
+
+	slices.Sort(report.New)
+	slices.Sort(report.Resolved)
+	slices.Sort(report.Unchanged)
+
+	return filtered, report
+}
+
+// String formats the report as a "Drift since baseline" line with the counts of each kind of
+// drift, followed by a labelled line listing the resources of each kind there are any of.
+func (d DriftReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Drift since baseline: %d new, %d resolved, %d unchanged\n", len(d.New), len(d.Resolved), len(d.Unchanged))
+
+	for _, group := range []struct {
+		label     string
+		resources []string
+	}{
+		{"new drift", d.New},
+		{"resolved drift", d.Resolved},
+		{"unchanged drift", d.Unchanged},
+	} {
+		if len(group.resources) > 0 {
+			fmt.Fprintf(&b, "  %s: %s\n", group.label, strings.Join(group.resources, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// changeFingerprint returns the canonical form of a change: its type and diff as JSON, which
+// orders map keys. Warnings and other annotations are left out so they don't count as drift.
+func changeFingerprint(c *ChangeDetail) string {
+	data, err := json.Marshal(struct {
+		Type string         `json:"type"`
+		Diff map[string]any `json:"diff"`
+	}{Type: c.Type, Diff: c.Diff})
+	if err != nil {
+		// Unstructured content always marshals; should this ever fail, the change is treated
+		// as new drift.
+		return ""
+	}
+
+	return string(data)
+}
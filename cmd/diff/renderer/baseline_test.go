@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBaseline_Compare(t *testing.T) {
+	bucket := func(replicas int64) *un.Unstructured {
+		return tu.NewResource("s3.aws.upbound.io/v1beta1", "Bucket", "my-bucket").
+			InNamespace("default").
+			WithSpecField("replicas", replicas).
+			Build()
+	}
+
+	modified := func(replicas int64) *dt.ResourceDiff {
+		return &dt.ResourceDiff{
+			Gvk:          bucket(0).GroupVersionKind(),
+			Namespace:    "default",
+			ResourceName: "my-bucket",
+			DiffType:     dt.DiffTypeModified,
+			Current:      dt.ResourceViews{Clean: bucket(1)},
+			Desired:      dt.ResourceViews{Clean: bucket(replicas)},
+		}
+	}
+
+	role := tu.NewResource("iam.aws.upbound.io/v1beta1", "Role", "my-role").Build()
+	added := &dt.ResourceDiff{
+		Gvk:          role.GroupVersionKind(),
+		ResourceName: "my-role",
+		DiffType:     dt.DiffTypeAdded,
+		Desired:      dt.ResourceViews{Clean: role},
+	}
+	equalRole := &dt.ResourceDiff{
+		Gvk:          role.GroupVersionKind(),
+		ResourceName: "my-role",
+		DiffType:     dt.DiffTypeEqual,
+	}
+
+	tests := map[string]struct {
+		reason     string
+		baseline   map[string]*dt.ResourceDiff
+		diffs      map[string]*dt.ResourceDiff
+		wantKeys   []string
+		wantReport DriftReport
+	}{
+		"UnchangedDrift": {
+			reason:     "A change identical to the baseline's should be reported as unchanged drift and not rendered.",
+			baseline:   map[string]*dt.ResourceDiff{"b": modified(2), "r": added},
+			diffs:      map[string]*dt.ResourceDiff{"b": modified(2), "r": added},
+			wantReport: DriftReport{Unchanged: []string{"Bucket/my-bucket", "Role/my-role"}},
+		},
+		"NewDrift": {
+			reason:     "A change missing from the baseline, or different from it, should be new drift and rendered.",
+			baseline:   map[string]*dt.ResourceDiff{"b": modified(2)},
+			diffs:      map[string]*dt.ResourceDiff{"b": modified(3), "r": added},
+			wantKeys:   []string{"b", "r"},
+			wantReport: DriftReport{New: []string{"Bucket/my-bucket", "Role/my-role"}},
+		},
+		"ResolvedDrift": {
+			reason:     "A baseline change that no longer occurs, or whose resource is now unchanged, should be resolved drift.",
+			baseline:   map[string]*dt.ResourceDiff{"b": modified(2), "r": added},
+			diffs:      map[string]*dt.ResourceDiff{"r": equalRole},
+			wantKeys:   []string{"r"},
+			wantReport: DriftReport{Resolved: []string{"Bucket/my-bucket", "Role/my-role"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Build the baseline the way --baseline does: from a previous run's JSON output.
+			var out bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatJSON
+			opts.Stdout = &out
+
+			if err := NewStructuredDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.baseline, nil); err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			var previous StructuredDiffOutput
+			if err := json.Unmarshal(out.Bytes(), &previous); err != nil {
+				t.Fatalf("\n%s\njson.Unmarshal(...): unexpected error: %v", tt.reason, err)
+			}
+
+			got, report := NewBaseline(previous.Changes).Compare(tt.diffs)
+
+			gotKeys := make([]string, 0, len(got))
+			for k := range got {
+				gotKeys = append(gotKeys, k)
+			}
+
+			if diff := cmp.Diff(tt.wantKeys, gotKeys, cmpopts.SortSlices(func(a, b string) bool { return a < b }), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nCompare(...): -want diffs, +got diffs:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantReport, report, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nCompare(...): -want report, +got report:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDriftReport_String(t *testing.T) {
+	report := DriftReport{
+		New:       []string{"Bucket/a", "Bucket/b"},
+		Unchanged: []string{"Role/c"},
+	}
+
+	want := "Drift since baseline: 2 new, 0 resolved, 1 unchanged\n" +
+		"  new drift: Bucket/a, Bucket/b\n" +
+		"  unchanged drift: Role/c\n"
+
+	if diff := cmp.Diff(want, report.String()); diff != "" {
+		t.Errorf("String(): -want, +got:\n%s", diff)
+	}
+}
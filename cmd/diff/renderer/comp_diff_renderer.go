@@ -19,6 +19,7 @@ package renderer
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
@@ -62,19 +63,31 @@ func NewDefaultCompDiffRenderer(logger logging.Logger, diffRenderer DiffRenderer
 
 // RenderCompDiff renders the composition diff in human-readable format.
 // Top-level errors go to r.opts.Stderr. Per-composition output (diffs, status
-// messages, per-composition errors) goes to r.opts.Stdout.
+// messages, per-composition errors) goes to r.opts.Stdout. In quiet mode,
+// compositions without changes are skipped, and NoChangesMessage is printed
+// if nothing is left. In summary-only mode, each composition is reduced to its
+// change marker and the counts of affected XRs and downstream changes.
 func (r *DefaultCompDiffRenderer) RenderCompDiff(output *CompDiffOutput) error {
 	stdout := r.opts.Stdout
+	rendered := 0
 
-	for i, comp := range output.Compositions {
-		if i > 0 {
+	for _, comp := range output.Compositions {
+		if r.opts.Quiet && !compHasChanges(&comp) {
+			continue
+		}
+
+		if rendered > 0 {
 			if _, err := fmt.Fprint(stdout, "\n"+strings.Repeat("=", 80)+"\n\n"); err != nil {
 				return errors.Wrap(err, "cannot write composition separator")
 			}
 		}
 
+		rendered++
+
 		switch {
-		case r.opts.MinimizeComposition:
+		case r.opts.Quiet && comp.Error == nil && !compositionChanged(&comp):
+			// Nothing to say about an unchanged composition.
+		case r.opts.MinimizeComposition || r.opts.SummaryOnly:
 			if err := r.renderMinimizedCompositionChanges(&comp); err != nil {
 				return err
 			}
@@ -89,6 +102,12 @@ func (r *DefaultCompDiffRenderer) RenderCompDiff(output *CompDiffOutput) error {
 			continue
 		}
 
+		if comp.StepsReordered != nil && !r.opts.SummaryOnly {
+			if _, err := fmt.Fprintf(stdout, "%s\n\n", stepReorderMessage(comp.StepsReordered)); err != nil {
+				return errors.Wrap(err, "cannot write pipeline step reorder message")
+			}
+		}
+
 		// Render affected XRs list with status indicators
 		if err := r.renderAffectedResourcesList(&comp); err != nil {
 			return err
@@ -100,6 +119,12 @@ func (r *DefaultCompDiffRenderer) RenderCompDiff(output *CompDiffOutput) error {
 		}
 	}
 
+	if r.opts.Quiet && rendered == 0 && len(output.Errors) == 0 {
+		if _, err := fmt.Fprintln(stdout, NoChangesMessage); err != nil {
+			return errors.Wrap(err, "cannot write no changes message")
+		}
+	}
+
 	// Write top-level errors to stderr
 	for _, e := range output.Errors {
 		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
@@ -206,6 +231,12 @@ func (r *DefaultCompDiffRenderer) renderMinimizedCompositionChanges(comp *Compos
 func (r *DefaultCompDiffRenderer) renderAffectedResourcesList(comp *CompositionDiff) error {
 	stdout := r.opts.Stdout
 
+	if comp.RetargetedFrom != "" {
+		if _, err := fmt.Fprintf(stdout, "%s\n\n", retargetedMessage(comp)); err != nil {
+			return errors.Wrap(err, "cannot write retargeted composition message")
+		}
+	}
+
 	if len(comp.ImpactAnalysis) == 0 {
 		// No XRs surfaced. Either none were found, or all matched-by-name XRs were filtered out
 		// (by Manual policy and/or revision-selector mismatch); report the breakdown if so.
@@ -227,7 +258,14 @@ func (r *DefaultCompDiffRenderer) renderAffectedResourcesList(comp *CompositionD
 	}
 
 	// Build the XR list with status indicators
-	xrList := r.buildXRStatusList(comp.ImpactAnalysis)
+	impacts := comp.ImpactAnalysis
+	if r.opts.Quiet {
+		impacts = slices.DeleteFunc(slices.Clone(impacts), func(impact XRImpact) bool {
+			return impact.Status == XRStatusUnchanged
+		})
+	}
+
+	xrList := r.buildXRStatusList(impacts)
 
 	// Generate summary line
 	summary := formatXRStatusSummary(
@@ -236,6 +274,16 @@ func (r *DefaultCompDiffRenderer) renderAffectedResourcesList(comp *CompositionD
 		comp.AffectedResources.WithErrors,
 	)
 
+	if r.opts.SummaryOnly {
+		xrList, summary = "", strings.TrimPrefix(summary, "\n")
+	}
+
+	// Only call out re-reconciliation when some XRs won't pick up the change; when every XR
+	// auto-updates the summary line above already says everything.
+	if rec := comp.Reconciliation; rec != nil && rec.Manual+rec.NotSelected > 0 {
+		summary += reconciliationMessage(rec) + "\n"
+	}
+
 	// Write the XR list with summary
 	if _, err := fmt.Fprintf(stdout, headerAffectedResources+"\n\n%s%s\n", xrList, summary); err != nil {
 		return errors.Wrap(err, "cannot write XR list")
@@ -244,26 +292,27 @@ func (r *DefaultCompDiffRenderer) renderAffectedResourcesList(comp *CompositionD
 	return nil
 }
 
-// renderImpactAnalysis renders the impact analysis section with downstream diffs.
+// renderImpactAnalysis renders the impact analysis section with downstream diffs. In quiet and
+// summary-only modes the section is left out when there are no downstream diffs, and in
+// summary-only mode the diffs are replaced by their summary line.
 func (r *DefaultCompDiffRenderer) renderImpactAnalysis(comp *CompositionDiff) error {
 	stdout := r.opts.Stdout
 
+	allDiffs := impactDiffs(comp)
+	if (r.opts.Quiet || r.opts.SummaryOnly) && len(allDiffs) == 0 {
+		return nil
+	}
+
 	if _, err := fmt.Fprintf(stdout, headerImpactAnalysis+"\n\n"); err != nil {
 		return errors.Wrap(err, "cannot write impact analysis header")
 	}
 
-	// Collect all diffs from the impact analysis using stored ResourceDiffs.
-	allDiffs := make(map[string]*dt.ResourceDiff)
-
-	for _, impact := range comp.ImpactAnalysis {
-		if impact.Status == XRStatusChanged && impact.Diffs != nil {
-			for key, diff := range impact.Diffs {
-				// Skip equal diffs (may be stored for removal detection purposes)
-				if diff.DiffType != dt.DiffTypeEqual {
-					allDiffs[key] = diff
-				}
-			}
+	if r.opts.SummaryOnly {
+		if _, err := fmt.Fprintf(stdout, "%s\n\n", formatSummary(summarize(allDiffs))); err != nil {
+			return errors.Wrap(err, "cannot write impact analysis summary")
 		}
+
+		return nil
 	}
 
 	// Render all diffs if we found some, or show a message if empty
@@ -281,6 +330,41 @@ func (r *DefaultCompDiffRenderer) renderImpactAnalysis(comp *CompositionDiff) er
 	return nil
 }
 
+// impactDiffs collects the non-equal downstream diffs of every changed XR in the impact analysis.
+func impactDiffs(comp *CompositionDiff) map[string]*dt.ResourceDiff {
+	allDiffs := make(map[string]*dt.ResourceDiff)
+
+	for _, impact := range comp.ImpactAnalysis {
+		if impact.Status == XRStatusChanged && impact.Diffs != nil {
+			for key, diff := range impact.Diffs {
+				// Skip equal diffs (may be stored for removal detection purposes)
+				if diff.DiffType != dt.DiffTypeEqual {
+					allDiffs[key] = diff
+				}
+			}
+		}
+	}
+
+	return allDiffs
+}
+
+// compositionChanged reports whether the composition itself differs from the cluster.
+func compositionChanged(comp *CompositionDiff) bool {
+	return comp.CompositionDiff != nil && comp.CompositionDiff.DiffType != dt.DiffTypeEqual
+}
+
+// compHasChanges reports whether a composition has anything quiet mode shows: an error, a change
+// to the composition itself, or an affected XR that changed or failed.
+func compHasChanges(comp *CompositionDiff) bool {
+	if comp.Error != nil || compositionChanged(comp) {
+		return true
+	}
+
+	return slices.ContainsFunc(comp.ImpactAnalysis, func(impact XRImpact) bool {
+		return impact.Status == XRStatusChanged || impact.Status == XRStatusError
+	})
+}
+
 // allFilteredMessage builds the default-discovery summary line for the case where every
 // matched-by-name XR was filtered out, breaking the total down by reason so users understand why
 // nothing is shown and how to see more.
@@ -300,6 +384,41 @@ func allFilteredMessage(compName string, byPolicy, bySelector int) string {
 	}
 }
 
+// retargetedMessage explains that a compositeTypeRef change leaves the old type's XRs behind, with
+// guidance on migrating them, so retargeting never shows up as a silently empty impact report.
+func retargetedMessage(comp *CompositionDiff) string {
+	msg := fmt.Sprintf("Composition retargeted: no longer applies to %s's XRs.", comp.RetargetedFrom)
+
+	if n := comp.AffectedResources.Orphaned; n > 0 {
+		msg += fmt.Sprintf(" %d existing XR(s) would be orphaned on their current composition revision;"+
+			" point them at another composition for their type, or recreate them as the new type.", n)
+	}
+
+	return msg
+}
+
+// reconciliationMessage states how many XRs Crossplane will re-reconcile against the changed
+// composition and how many stay on their current revision, for rollout planning.
+func reconciliationMessage(r *ReconciliationSummary) string {
+	parts := []string{fmt.Sprintf("%d will auto-update", r.AutoUpdate)}
+
+	if r.Manual > 0 {
+		parts = append(parts, fmt.Sprintf("%d pinned (Manual)", r.Manual))
+	}
+
+	if r.NotSelected > 0 {
+		parts = append(parts, fmt.Sprintf("%d not selected (compositionRevisionSelector)", r.NotSelected))
+	}
+
+	return "Re-reconciliation: " + strings.Join(parts, ", ")
+}
+
+// stepReorderMessage states a pipeline step reorder explicitly, since the line diff of moved steps
+// is easy to misread as steps being added and removed.
+func stepReorderMessage(reorder *StepReorder) string {
+	return fmt.Sprintf("Pipeline steps reordered: [%s] → [%s]", strings.Join(reorder.From, ", "), strings.Join(reorder.To, ", "))
+}
+
 // filteredSuffix returns the human-readable explanation appended to a filtered XR line, chosen by
 // the XR's FilterReason. Selector-mismatch entries additionally surface the concrete FilterDetail
 // hint (which selector failed to match which labels) so users can self-diagnose the exclusion.
@@ -307,6 +426,12 @@ func filteredSuffix(impact XRImpact) string {
 	switch impact.FilterReason {
 	case FilterReasonManualPolicy:
 		return " — filtered: Manual update policy (use --include-manual to evaluate)"
+	case FilterReasonRetargeted:
+		if impact.FilterDetail != "" {
+			return fmt.Sprintf(" — orphaned: %s", impact.FilterDetail)
+		}
+
+		return " — orphaned: composition retargeted"
 	case FilterReasonRevisionSelectorMismatch:
 		if impact.FilterDetail != "" {
 			return fmt.Sprintf(" — filtered: revision selector mismatch (%s)", impact.FilterDetail)
@@ -367,6 +492,10 @@ func (r *DefaultCompDiffRenderer) buildXRStatusList(impacts []XRImpact) string {
 			suffix = filteredSuffix(impact)
 		}
 
+		if impact.ManualPolicy {
+			suffix = " (manual)" + suffix
+		}
+
 		fmt.Fprintf(&sb, "%s  %s %s/%s (%s)%s%s\n",
 			color,
 			indicator,
@@ -402,7 +531,10 @@ func NewStructuredCompDiffRenderer(logger logging.Logger, opts DiffOptions) Comp
 // structured output payload. Per-composition data goes to r.opts.Stdout.
 func (r *StructuredCompDiffRenderer) RenderCompDiff(output *CompDiffOutput) error {
 	// Convert internal representation to JSON output structure
-	jsonOutput := r.buildStructuredCompOutput(output)
+	var jsonOutput any = r.buildStructuredCompOutput(output)
+	if r.opts.SummaryOnly {
+		jsonOutput = buildCompSummaryOutput(output)
+	}
 
 	var (
 		data []byte
@@ -414,7 +546,7 @@ func (r *StructuredCompDiffRenderer) RenderCompDiff(output *CompDiffOutput) erro
 		data, err = json.MarshalIndent(jsonOutput, "", "  ")
 	case OutputFormatYAML:
 		data, err = sigsyaml.Marshal(jsonOutput)
-	case OutputFormatDiff:
+	case OutputFormatDiff, OutputFormatTextNoANSI, OutputFormatCSV, OutputFormatDesired, OutputFormatGitHub, OutputFormatDOT, OutputFormatMarkdown, OutputFormatJUnit:
 		fallthrough
 	default:
 		return errors.Errorf("unsupported format for structured comp diff renderer: %s", r.opts.Format)
@@ -449,6 +581,9 @@ func (r *StructuredCompDiffRenderer) buildStructuredCompOutput(output *CompDiffO
 	for _, comp := range output.Compositions {
 		jsonComp := compositionDiffJSON{
 			Name:              comp.Name,
+			RetargetedFrom:    comp.RetargetedFrom,
+			StepsReordered:    comp.StepsReordered,
+			Reconciliation:    comp.Reconciliation,
 			AffectedResources: comp.AffectedResources,
 			ImpactAnalysis:    make([]xrImpactJSON, 0, len(comp.ImpactAnalysis)),
 		}
@@ -470,6 +605,7 @@ func (r *StructuredCompDiffRenderer) buildStructuredCompOutput(output *CompDiffO
 				Status:          impact.Status,
 				FilterReason:    impact.FilterReason,
 				FilterDetail:    impact.FilterDetail,
+				ManualPolicy:    impact.ManualPolicy,
 			}
 			if impact.Error != nil {
 				jsonImpact.Error = impact.Error.Error()
@@ -488,6 +624,34 @@ func (r *StructuredCompDiffRenderer) buildStructuredCompOutput(output *CompDiffO
 	return result
 }
 
+// buildCompSummaryOutput reduces the composition diffs to their counts for --summary-only.
+func buildCompSummaryOutput(output *CompDiffOutput) *compSummaryJSONOutput {
+	result := &compSummaryJSONOutput{
+		Compositions: make([]compositionSummaryJSON, 0, len(output.Compositions)),
+		Errors:       output.Errors,
+	}
+
+	for _, comp := range output.Compositions {
+		jsonComp := compositionSummaryJSON{
+			Name:              comp.Name,
+			AffectedResources: comp.AffectedResources,
+			DownstreamChanges: summarize(impactDiffs(&comp)),
+		}
+
+		if comp.Error != nil {
+			jsonComp.Error = comp.Error.Error()
+		}
+
+		if compositionChanged(&comp) {
+			jsonComp.CompositionChange = comp.CompositionDiff.DiffType.ToWord()
+		}
+
+		result.Compositions = append(result.Compositions, jsonComp)
+	}
+
+	return result
+}
+
 // formatXRStatusSummary generates the summary line with correct pluralization.
 func formatXRStatusSummary(changedCount, unchangedCount, errorCount int) string {
 	parts := []string{}
@@ -9,6 +9,8 @@ import (
 
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
 	corev1 "k8s.io/api/core/v1"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -225,13 +227,162 @@ func TestStructuredCompDiffRenderer_RenderCompDiff(t *testing.T) {
 	}
 }
 
+// summaryOnlyCompOutput is a changed composition with one changed and one unchanged XR, whose
+// downstream diffs are one added and one modified resource.
+func summaryOnlyCompOutput() *CompDiffOutput {
+	return &CompDiffOutput{
+		Compositions: []CompositionDiff{{
+			Name:              "test-comp",
+			CompositionDiff:   &dt.ResourceDiff{Gvk: schema.GroupVersionKind{Kind: "Composition"}, ResourceName: "test-comp", DiffType: dt.DiffTypeModified},
+			AffectedResources: AffectedResourcesSummary{Total: 2, WithChanges: 1, Unchanged: 1},
+			ImpactAnalysis: []XRImpact{
+				{
+					ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-1"},
+					Status:          XRStatusChanged,
+					Diffs: map[string]*dt.ResourceDiff{
+						"bucket": {
+							Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+							ResourceName: "bucket-1",
+							DiffType:     dt.DiffTypeModified,
+							LineDiffs:    []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffInsert, Text: "spec: {}\n"}},
+						},
+						"policy": {
+							Gvk:          schema.GroupVersionKind{Group: "iam.aws.upbound.io", Version: "v1beta1", Kind: "Policy"},
+							ResourceName: "policy-1",
+							DiffType:     dt.DiffTypeAdded,
+							LineDiffs:    []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffInsert, Text: "spec: {}\n"}},
+						},
+					},
+				},
+				{ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-2"}, Status: XRStatusUnchanged},
+			},
+		}},
+	}
+}
+
+func TestStructuredCompDiffRenderer_SummaryOnly(t *testing.T) {
+	var buf bytes.Buffer
+
+	opts := DefaultDiffOptions()
+	opts.Format = OutputFormatJSON
+	opts.SummaryOnly = true
+	opts.Stdout = &buf
+	opts.Stderr = &bytes.Buffer{}
+
+	if err := NewStructuredCompDiffRenderer(tu.TestLogger(t, false), opts).RenderCompDiff(summaryOnlyCompOutput()); err != nil {
+		t.Fatalf("RenderCompDiff() failed: %v", err)
+	}
+
+	var got compSummaryJSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+	}
+
+	want := compSummaryJSONOutput{Compositions: []compositionSummaryJSON{{
+		Name:              "test-comp",
+		CompositionChange: dt.DiffTypeWordModified,
+		AffectedResources: AffectedResourcesSummary{Total: 2, WithChanges: 1, Unchanged: 1},
+		DownstreamChanges: Summary{Added: 1, Modified: 1},
+	}}}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RenderCompDiff(...): -want, +got:\n%s", diff)
+	}
+
+	if strings.Contains(buf.String(), "impactAnalysis") {
+		t.Errorf("summary-only output should not contain the impact analysis\nOutput: %s", buf.String())
+	}
+}
+
 func TestDefaultCompDiffRenderer_RenderCompDiff(t *testing.T) {
 	tests := map[string]struct {
-		output   *CompDiffOutput
-		colorize bool
-		minimize bool
-		validate func(t *testing.T, result string)
+		output      *CompDiffOutput
+		colorize    bool
+		minimize    bool
+		quiet       bool
+		summaryOnly bool
+		validate    func(t *testing.T, result string)
 	}{
+		"SummaryOnly": {
+			output:      summaryOnlyCompOutput(),
+			summaryOnly: true,
+			validate: func(t *testing.T, result string) {
+				t.Helper()
+
+				want := "=== Composition Changes ===\n\n" +
+					"~~~ Composition/test-comp (minimized)\n\n" +
+					"=== Affected Composite Resources ===\n\n" +
+					"Summary: 1 resource with changes, 1 resource unchanged\n\n" +
+					"=== Impact Analysis ===\n\n" +
+					"Summary: 1 added, 1 modified\n\n"
+				if diff := cmp.Diff(want, result); diff != "" {
+					t.Errorf("Expected only the summaries, -want, +got:\n%s", diff)
+				}
+			},
+		},
+		"QuietNoChanges": {
+			output: &CompDiffOutput{
+				Compositions: []CompositionDiff{{
+					Name:              "test-comp",
+					AffectedResources: AffectedResourcesSummary{Total: 1, Unchanged: 1},
+					ImpactAnalysis:    []XRImpact{{ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-1"}, Status: XRStatusUnchanged}},
+				}},
+			},
+			quiet: true,
+			validate: func(t *testing.T, result string) {
+				t.Helper()
+
+				if result != "No changes.\n" {
+					t.Errorf("Expected only the no changes line, got: %q", result)
+				}
+			},
+		},
+		"QuietOnlyChanges": {
+			output: &CompDiffOutput{
+				Compositions: []CompositionDiff{
+					{
+						Name:              "unchanged-comp",
+						AffectedResources: AffectedResourcesSummary{Total: 1, Unchanged: 1},
+						ImpactAnalysis:    []XRImpact{{ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-0"}, Status: XRStatusUnchanged}},
+					},
+					{
+						Name:              "test-comp",
+						AffectedResources: AffectedResourcesSummary{Total: 2, WithChanges: 1, Unchanged: 1},
+						ImpactAnalysis: []XRImpact{
+							{
+								ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-1"},
+								Status:          XRStatusChanged,
+								Diffs: map[string]*dt.ResourceDiff{
+									"bucket": {
+										Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+										ResourceName: "bucket-1",
+										DiffType:     dt.DiffTypeModified,
+										LineDiffs:    []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffInsert, Text: "spec: {}\n"}},
+									},
+								},
+							},
+							{ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XResource", Name: "xr-2"}, Status: XRStatusUnchanged},
+						},
+					},
+				},
+			},
+			quiet: true,
+			validate: func(t *testing.T, result string) {
+				t.Helper()
+
+				for _, unwanted := range []string{"unchanged-comp", "xr-0", "xr-2", "=== Composition Changes ===", "No changes detected", "up-to-date", strings.Repeat("=", 80)} {
+					if strings.Contains(result, unwanted) {
+						t.Errorf("Expected quiet output to omit %q, got:\n%s", unwanted, result)
+					}
+				}
+
+				for _, wanted := range []string{"XResource/xr-1", "~~~ Bucket/bucket-1", "Summary: 1 resource with changes, 1 resource unchanged"} {
+					if !strings.Contains(result, wanted) {
+						t.Errorf("Expected quiet output to contain %q, got:\n%s", wanted, result)
+					}
+				}
+			},
+		},
 		"EmptyCompositions": {
 			output:   &CompDiffOutput{Compositions: []CompositionDiff{}},
 			colorize: false,
@@ -424,6 +575,8 @@ func TestDefaultCompDiffRenderer_RenderCompDiff(t *testing.T) {
 			opts := DefaultDiffOptions()
 			opts.UseColors = tt.colorize
 			opts.MinimizeComposition = tt.minimize
+			opts.Quiet = tt.quiet
+			opts.SummaryOnly = tt.summaryOnly
 			opts.Stdout = &buf
 			opts.Stderr = &bytes.Buffer{} // discard stderr
 
@@ -804,6 +957,98 @@ func TestXRStatusFiltered_TextRenderer(t *testing.T) {
 	}
 }
 
+func TestXRManualPolicy_TextRenderer(t *testing.T) {
+	impacts := []XRImpact{
+		{
+			ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XR", Name: "manual-xr", Namespace: "ns"},
+			Status:          XRStatusChanged,
+			ManualPolicy:    true,
+		},
+		{
+			ObjectReference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XR", Name: "auto-xr", Namespace: "ns"},
+			Status:          XRStatusUnchanged,
+		},
+	}
+
+	opts := DefaultDiffOptions()
+	opts.UseColors = false
+
+	r := &DefaultCompDiffRenderer{logger: tu.TestLogger(t, false), opts: opts}
+	got := r.buildXRStatusList(impacts)
+
+	want := "  \u26a0 XR/manual-xr (namespace: ns) (manual)\n" +
+		"  \u2713 XR/auto-xr (namespace: ns)\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Manual-policy XRs included with --include-manual should be marked (manual): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRetargetedMessage(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		comp   CompositionDiff
+		want   string
+	}{
+		"NoOrphans": {
+			reason: "A retarget with no existing XRs should only name the old type.",
+			comp:   CompositionDiff{RetargetedFrom: "XOld (example.org/v1)"},
+			want:   "Composition retargeted: no longer applies to XOld (example.org/v1)'s XRs.",
+		},
+		"WithOrphans": {
+			reason: "A retarget that orphans XRs should count them and give migration guidance.",
+			comp: CompositionDiff{
+				RetargetedFrom:    "XOld (example.org/v1)",
+				AffectedResources: AffectedResourcesSummary{Total: 2, Orphaned: 2},
+			},
+			want: "Composition retargeted: no longer applies to XOld (example.org/v1)'s XRs. 2 existing XR(s) would be orphaned on their current composition revision; point them at another composition for their type, or recreate them as the new type.",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, retargetedMessage(&tt.comp)); diff != "" {
+				t.Errorf("%s\nretargetedMessage(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStepReorderMessage(t *testing.T) {
+	got := stepReorderMessage(&StepReorder{From: []string{"render", "ready"}, To: []string{"ready", "render"}})
+	want := "Pipeline steps reordered: [render, ready] → [ready, render]"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("stepReorderMessage(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReconciliationMessage(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		r      ReconciliationSummary
+		want   string
+	}{
+		"ManualOnly": {
+			reason: "Pinned XRs should be counted alongside the XRs that auto-update.",
+			r:      ReconciliationSummary{AutoUpdate: 12, Manual: 3},
+			want:   "Re-reconciliation: 12 will auto-update, 3 pinned (Manual)",
+		},
+		"AllBuckets": {
+			reason: "XRs whose revision selector won't match should be counted separately from Manual ones.",
+			r:      ReconciliationSummary{AutoUpdate: 0, Manual: 1, NotSelected: 2},
+			want:   "Re-reconciliation: 0 will auto-update, 1 pinned (Manual), 2 not selected (compositionRevisionSelector)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, reconciliationMessage(&tt.r)); diff != "" {
+				t.Errorf("%s\nreconciliationMessage(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionDiff_HasChanges_FilteredOnly(t *testing.T) {
 	c := &CompositionDiff{
 		ImpactAnalysis: []XRImpact{
@@ -0,0 +1,119 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCSVDiffRenderer_RenderDiffs(t *testing.T) {
+	header := "namespace,kind,name,diffType,addedLines,removedLines,sourceFile\n"
+
+	tests := map[string]struct {
+		reason     string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+	}{
+		"NoDiffs": {
+			reason:     "Should write only the header when there are no diffs.",
+			diffs:      map[string]*dt.ResourceDiff{},
+			wantStdout: header,
+		},
+		"AllDiffTypes": {
+			reason: "Should write one row per changed resource, sorted by kind and name, counting changed lines and skipping equal resources.",
+			diffs: map[string]*dt.ResourceDiff{
+				"added": {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "new-bucket",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffInsert, Text: "apiVersion: s3.aws.upbound.io/v1beta1\nkind: Bucket\nmetadata:\n  name: new-bucket\n"},
+					},
+					SourceFile: "xrs/bucket.yaml",
+				},
+				"modified": {
+					DiffType:     dt.DiffTypeModified,
+					ResourceName: "my-xr",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffEqual, Text: "spec:\n"},
+						{Type: diffmatchpatch.DiffDelete, Text: "  region: us-east-1\n"},
+						{Type: diffmatchpatch.DiffInsert, Text: "  region: us-west-2\n  versioning: true"},
+					},
+					SourceFile: "xrs/bucket.yaml",
+				},
+				"removed": {
+					DiffType:     dt.DiffTypeRemoved,
+					ResourceName: "old-role",
+					Gvk:          schema.GroupVersionKind{Group: "iam.aws.upbound.io", Version: "v1beta1", Kind: "Role"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffDelete, Text: "kind: Role\nmetadata:\n  name: old-role\n"},
+					},
+				},
+				"equal": {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "unchanged",
+					Gvk:          schema.GroupVersionKind{Kind: "Bucket"},
+				},
+			},
+			wantStdout: header +
+				"default,Bucket,new-bucket,added,4,0,xrs/bucket.yaml\n" +
+				",Role,old-role,removed,0,3,\n" +
+				"default,XBucket,my-xr,modified,2,1,xrs/bucket.yaml\n",
+		},
+		"QuotesFields": {
+			reason: "Should quote fields that contain commas.",
+			diffs: map[string]*dt.ResourceDiff{
+				"added": {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "cm",
+					Gvk:          schema.GroupVersionKind{Kind: "ConfigMap"},
+					SourceFile:   "a,b.yaml",
+				},
+			},
+			wantStdout: header + `,ConfigMap,cm,added,0,0,"a,b.yaml"` + "\n",
+		},
+		"ErrorsToStderr": {
+			reason: "Should write errors to stderr, keeping stdout valid CSV.",
+			diffs:  map[string]*dt.ResourceDiff{},
+			errs: []dt.OutputError{
+				{ResourceID: "XBucket/my-xr", Message: "cannot find composition"},
+			},
+			wantStdout: header,
+			wantStderr: "ERROR: XBucket/my-xr: cannot find composition\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatCSV
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			err := NewCSVDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs)
+			if err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
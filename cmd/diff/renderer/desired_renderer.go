@@ -0,0 +1,88 @@
+package renderer
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// DesiredStateRenderer writes the complete desired object of every diffed resource as a
+// multi-document YAML stream instead of a diff, so the rendered state can be fed to other tools
+// (e.g. kubectl apply --dry-run=server).
+type DesiredStateRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+}
+
+// NewDesiredStateRenderer creates a new DesiredStateRenderer.
+func NewDesiredStateRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	return &DesiredStateRenderer{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// RenderDiffs writes the desired object of each diff to stdout, ordered by group, version, kind,
+// namespace, and name, separated by "---". Removed resources have no desired state and are
+// skipped; unchanged ones are included, since they are part of the desired state too. Errors go
+// to stderr.
+func (r *DesiredStateRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	objs := make([]*un.Unstructured, 0, len(diffs))
+
+	for _, diff := range diffs {
+		if diff.DiffType == dt.DiffTypeRemoved || diff.Desired.Raw == nil {
+			continue
+		}
+
+		objs = append(objs, diff.Desired.Raw)
+	}
+
+	slices.SortFunc(objs, compareObjects)
+
+	r.logger.Debug("Rendering desired state", "diffCount", len(diffs), "objectCount", len(objs))
+
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := fmt.Fprintln(r.opts.Stdout, "---"); err != nil {
+				return errors.Wrap(err, "failed to write document separator")
+			}
+		}
+
+		data, err := sigsyaml.Marshal(obj.Object)
+		if err != nil {
+			return errors.Wrapf(err, "cannot marshal %s/%s to YAML", obj.GetKind(), obj.GetName())
+		}
+
+		if _, err := r.opts.Stdout.Write(data); err != nil {
+			return errors.Wrapf(err, "failed to write %s/%s", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// compareObjects orders objects by group, version, kind, namespace, and name.
+func compareObjects(a, b *un.Unstructured) int {
+	agvk, bgvk := a.GroupVersionKind(), b.GroupVersionKind()
+
+	return cmp.Or(
+		cmp.Compare(agvk.Group, bgvk.Group),
+		cmp.Compare(agvk.Version, bgvk.Version),
+		cmp.Compare(agvk.Kind, bgvk.Kind),
+		cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+		cmp.Compare(a.GetName(), b.GetName()),
+	)
+}
@@ -0,0 +1,95 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDesiredStateRenderer_RenderDiffs(t *testing.T) {
+	object := func(apiVersion, kind, name string) *un.Unstructured {
+		return &un.Unstructured{Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name},
+		}}
+	}
+
+	tests := map[string]struct {
+		reason     string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+	}{
+		"SortedByGVKThenName": {
+			reason: "Should print every desired object, including unchanged ones, ordered by group, version, kind, and name.",
+			diffs: map[string]*dt.ResourceDiff{
+				"b":  {DiffType: dt.DiffTypeModified, Desired: dt.ResourceViews{Raw: object("s3.aws.upbound.io/v1beta1", "Bucket", "b")}},
+				"a":  {DiffType: dt.DiffTypeEqual, Desired: dt.ResourceViews{Raw: object("s3.aws.upbound.io/v1beta1", "Bucket", "a")}},
+				"xr": {DiffType: dt.DiffTypeAdded, Desired: dt.ResourceViews{Raw: object("example.org/v1", "XBucket", "xr")}},
+			},
+			wantStdout: `apiVersion: example.org/v1
+kind: XBucket
+metadata:
+  name: xr
+---
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: a
+---
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: b
+`,
+		},
+		"RemovedSkipped": {
+			reason: "A removed resource has no desired state and should be left out.",
+			diffs: map[string]*dt.ResourceDiff{
+				"gone": {DiffType: dt.DiffTypeRemoved, Current: dt.ResourceViews{Raw: object("s3.aws.upbound.io/v1beta1", "Bucket", "gone")}},
+				"kept": {DiffType: dt.DiffTypeEqual, Desired: dt.ResourceViews{Raw: object("s3.aws.upbound.io/v1beta1", "Bucket", "kept")}},
+			},
+			wantStdout: `apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: kept
+`,
+		},
+		"ErrorsToStderr": {
+			reason: "Errors should go to stderr, leaving stdout a valid YAML stream.",
+			diffs:  map[string]*dt.ResourceDiff{},
+			errs: []dt.OutputError{
+				{ResourceID: "XBucket/other", Message: "cannot find composition"},
+			},
+			wantStderr: "ERROR: XBucket/other: cannot find composition\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			if err := NewDesiredStateRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs); err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -3,14 +3,18 @@ package renderer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	t "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	sigsyaml "sigs.k8s.io/yaml"
@@ -43,7 +47,8 @@ type DiffOptions struct {
 	// ContextPrefix is the prefix for unchanged lines (default " ")
 	ContextPrefix string
 
-	// ContextLines is the number of unchanged lines to show before/after changes in compact mode
+	// ContextLines is the number of unchanged lines to show before/after changes in compact mode.
+	// Longer runs of unchanged lines between changes collapse into a ChunkSeparator.
 	ContextLines int
 
 	// ChunkSeparator is the string used to separate chunks in compact mode
@@ -52,6 +57,12 @@ type DiffOptions struct {
 	// Compact determines whether to show a compact diff
 	Compact bool
 
+	// Style selects how human-readable diffs are laid out. Empty means DiffStyleUnified.
+	Style DiffStyle
+
+	// Width is the total width side-by-side diffs are laid out in. Zero means DefaultDiffWidth.
+	Width int
+
 	// IgnorePaths is a list of paths to ignore when calculating diffs
 	// Supports both simple paths (e.g., "metadata.annotations") and
 	// map key paths (e.g., "metadata.annotations[key.name/value]")
@@ -61,8 +72,81 @@ type DiffOptions struct {
 	// per composition, omitting the full YAML diff body. Only consumed by the
 	// human-readable composition diff renderer; structured output is unaffected.
 	MinimizeComposition bool
+
+	// Quiet omits unchanged resources and "no changes" messages from human-readable output, and
+	// prints NoChangesMessage instead when nothing changed at all. Structured output is unaffected.
+	Quiet bool
+
+	// SummaryOnly replaces the per-resource diffs with their counts: the summary line of
+	// human-readable output, or just the summary object of structured output.
+	SummaryOnly bool
+
+	// MaxFieldSize is the size in bytes above which a string field is replaced by its size and
+	// digest before diffing, so pathologically large values (e.g. big ConfigMap data) are flagged
+	// as changed or unchanged without a full line diff. Zero disables the limit.
+	MaxFieldSize int
+
+	// ShowWarnings records the API server warnings returned by dry-run applies and renders
+	// them alongside each resource's diff.
+	ShowWarnings bool
+
+	// DryRunKinds, when set, limits dry-run applies to resources of these kinds. Other kinds
+	// are diffed by merging the desired object onto the current one locally. Each entry is
+	// Kind or Kind.group.
+	DryRunKinds []string
+
+	// NormalizationRules are per-kind normalizations applied to both sides after cleanup and
+	// before the built-in normalizations.
+	NormalizationRules []NormalizationRule
+
+	// Inspect, in Kind/name form, selects the resource whose observed and desired objects the
+	// inspect renderer prints in place of a diff.
+	Inspect string
+
+	// NoDryRunKinds lists kinds that are diffed locally instead of through a dry-run apply.
+	// It takes precedence over DryRunKinds.
+	NoDryRunKinds []string
+
+	// FieldManager, when set, is the field manager dry-run applies use in place of the
+	// resource's own Crossplane field owner.
+	FieldManager string
+
+	// ForceConflicts makes dry-run applies take ownership of fields other field managers own,
+	// as a forced apply would, rather than fail with a conflict.
+	ForceConflicts bool
+
+	// IgnoreManagedFields keeps fields that field managers other than the resource's own
+	// (Crossplane's composed field owner) set in the cluster, when the desired object lacks
+	// them, so they aren't reported as removed.
+	IgnoreManagedFields bool
+
+	// Normalize treats scalars that differ only in representation, such as 30 and "30" or
+	// true and "true", as equal in every field, not just the quantity-like ones.
+	Normalize bool
+
+	// ShowLabelsDiffOnly limits each resource's line diff to its metadata.labels and
+	// metadata.annotations. Resources keep their diff type, so changes elsewhere are still
+	// counted in the summary.
+	ShowLabelsDiffOnly bool
+
+	// Provenance, when set, records what the diff was computed against. Structured output
+	// includes it, and human-readable output leads with it. It's filled in just before rendering.
+	Provenance *Provenance
 }
 
+// labelDiffPaths are the paths ShowLabelsDiffOnly limits line diffs to.
+var labelDiffPaths = []string{"metadata.labels", "metadata.annotations"} //nolint:gochecknoglobals // Constant paths.
+
+// DiffStyle selects how human-readable diffs are laid out.
+type DiffStyle string
+
+const (
+	// DiffStyleUnified prints removed and added lines one after the other with - and + prefixes.
+	DiffStyleUnified DiffStyle = "unified"
+	// DiffStyleSideBySide prints current and desired lines in two columns.
+	DiffStyleSideBySide DiffStyle = "side-by-side"
+)
+
 // DefaultDiffOptions returns the default options with colors enabled.
 func DefaultDiffOptions() DiffOptions {
 	return DiffOptions{
@@ -76,6 +160,7 @@ func DefaultDiffOptions() DiffOptions {
 		ContextLines:   3,
 		ChunkSeparator: "...",
 		Compact:        false,
+		Style:          DiffStyleUnified,
 	}
 }
 
@@ -101,6 +186,10 @@ func NewFormatter(compact bool) DiffFormatter {
 
 // FormatDiff formats a slice of diffs according to the provided options.
 func FormatDiff(diffs []diffmatchpatch.Diff, options DiffOptions) string {
+	if options.Style == DiffStyleSideBySide {
+		return (&SideBySideDiffFormatter{}).Format(diffs, options)
+	}
+
 	// Use the appropriate formatter
 	formatter := NewFormatter(options.Compact)
 	return formatter.Format(diffs, options)
@@ -212,16 +301,16 @@ func (f *CompactDiffFormatter) stringBlocksWithContext(changes []changeBlock, li
 			prevBlock := changes[blockIdx-1]
 			prevContextEnd := min(len(lines), prevBlock.EndIdx+contextLines+1)
 
-			// If there's a gap between the end of the previous context and the start of this context,
-			// add a separator
-			if contextStart > prevContextEnd {
-				// Add separator
-				fmt.Fprintf(&builder, "%s\n", opts.ChunkSeparator)
+			// If more than one line separates the end of the previous context from the start of this
+			// context, collapse them into a separator. A single line is cheaper to show than to hide.
+			if hidden := contextStart - prevContextEnd; hidden > 1 {
+				fmt.Fprintf(&builder, "%s\n", chunkSeparator(opts, hidden))
 
 				lastPrintedIdx = -1 // Reset to force printing of context lines
 			} else {
-				// Contexts overlap or are adjacent - adjust the start to avoid duplicate lines
-				contextStart = max(lastPrintedIdx+1, contextStart)
+				// Contexts overlap, are adjacent, or one line apart - adjust the start to avoid duplicate
+				// or skipped lines
+				contextStart = lastPrintedIdx + 1
 			}
 		}
 
@@ -243,8 +332,12 @@ func (f *CompactDiffFormatter) stringBlocksWithContext(changes []changeBlock, li
 			lastPrintedIdx = i
 		}
 
-		// Print context after the change
+		// Print context after the change, stopping at the next change so it isn't printed twice
 		contextEnd := min(len(lines), block.EndIdx+contextLines+1)
+		if blockIdx+1 < len(changes) {
+			contextEnd = min(contextEnd, changes[blockIdx+1].StartIdx)
+		}
+
 		for i := block.EndIdx + 1; i < contextEnd; i++ {
 			builder.WriteString(lines[i].Formatted)
 			builder.WriteString("\n")
@@ -256,6 +349,12 @@ func (f *CompactDiffFormatter) stringBlocksWithContext(changes []changeBlock, li
 	return builder.String()
 }
 
+// chunkSeparator returns the marker that replaces a run of hidden unchanged lines in compact
+// diffs, with the number of lines hidden.
+func chunkSeparator(opts DiffOptions, hidden int) string {
+	return fmt.Sprintf("%s (%d unchanged lines)", opts.ChunkSeparator, hidden)
+}
+
 // GetLineDiff performs a proper line-by-line diff and returns the raw diffs.
 func GetLineDiff(oldText, newText string) []diffmatchpatch.Diff {
 	patch := diffmatchpatch.New()
@@ -327,6 +426,75 @@ func GenerateDiffWithOptions(_ context.Context, current, desired *un.Unstructure
 		desiredClean = cleanupForDiff(desired.DeepCopy(), logger.WithValues("resourceStage", "desired", "before", desired), options.IgnorePaths)
 	}
 
+	// Apply the user's per-kind normalization rules before the built-in ones.
+	if len(options.NormalizationRules) > 0 {
+		gvk := schema.GroupVersionKind{}
+		if desired != nil {
+			gvk = desired.GroupVersionKind()
+		} else {
+			gvk = current.GroupVersionKind()
+		}
+
+		if applied := applyNormalizationRules(options.NormalizationRules, gvk, currentClean, desiredClean); len(applied) > 0 {
+			logger.Debug("Applied normalization rules",
+				"resource", resourceKey,
+				"namespace", resourceNamespace,
+				"changes", applied)
+		}
+	}
+
+	// Align quantity fields that differ only in notation (1000m vs 1, 1Gi vs
+	// 1073741824) so they don't show as changes.
+	if diffType == t.DiffTypeModified {
+		if paths := normalizeQuantities(currentClean.Object, desiredClean.Object, "", ""); len(paths) > 0 {
+			logger.Debug("Normalized equivalent quantities",
+				"resource", resourceKey,
+				"namespace", resourceNamespace,
+				"paths", paths)
+		}
+	}
+
+	// Align string fields holding JSON or YAML documents that differ only in key
+	// order or formatting (e.g. a policy document the server re-serialized).
+	if diffType == t.DiffTypeModified {
+		if paths := normalizeEmbeddedDocuments(currentClean.Object, desiredClean.Object, ""); len(paths) > 0 {
+			logger.Debug("Normalized equivalent embedded documents",
+				"resource", resourceKey,
+				"namespace", resourceNamespace,
+				"paths", paths)
+		}
+	}
+
+	// Align scalars that differ only in representation (30 vs "30", true vs
+	// "true", 0.5 vs 500m) wherever they appear, when asked to.
+	if options.Normalize && diffType == t.DiffTypeModified {
+		if paths := normalizeScalars(currentClean.Object, desiredClean.Object, ""); len(paths) > 0 {
+			logger.Debug("Normalized equivalent scalars",
+				"resource", resourceKey,
+				"namespace", resourceNamespace,
+				"paths", paths)
+		}
+	}
+
+	// Collapse oversized string fields to a size+digest placeholder so they
+	// are compared by content hash rather than line-diffed. This runs after
+	// every normalization, so a field that only differed in formatting is
+	// digested in the same form on both sides.
+	if options.MaxFieldSize > 0 {
+		for _, clean := range []*un.Unstructured{currentClean, desiredClean} {
+			if clean == nil {
+				continue
+			}
+
+			if paths := digestOversizedFields(clean.Object, options.MaxFieldSize, ""); len(paths) > 0 {
+				logger.Debug("Replaced oversized fields with digests",
+					"resource", resourceKey,
+					"namespace", resourceNamespace,
+					"paths", paths)
+			}
+		}
+	}
+
 	// For modifications, if the cleaned objects are equal the only differences
 	// were in ignored / server-side fields.
 	if diffType == t.DiffTypeModified && equality.Semantic.DeepEqual(currentClean.Object, desiredClean.Object) {
@@ -376,6 +544,16 @@ func GenerateDiffWithOptions(_ context.Context, current, desired *un.Unstructure
 		return equalDiff(current, desired), nil
 	}
 
+	// Render only label and annotation changes when asked to. The diff keeps its type, so a
+	// resource changed elsewhere is still counted, just with an empty body.
+	if options.ShowLabelsDiffOnly {
+		lineDiffs, err = isolatedLineDiff(currentClean, desiredClean, labelDiffPaths)
+		if err != nil {
+			logger.Debug("Error isolating label changes", "resource", resourceKey, "error", err)
+			return nil, errors.Wrap(err, "cannot isolate label and annotation changes")
+		}
+	}
+
 	logger.Debug("Diff calculation complete", "resource", resourceKey, "namespace", resourceNamespace, "diff_chunks", len(lineDiffs))
 
 	// Extract resource kind, namespace, and name
@@ -498,6 +676,32 @@ func processLines(diff diffmatchpatch.Diff, options DiffOptions) ([]string, bool
 	return result, hasTrailingNewline
 }
 
+// countLineChanges returns the number of inserted and deleted lines in a line diff. A chunk spans
+// as many lines as it has newlines, plus a final line without one.
+func countLineChanges(diffs []diffmatchpatch.Diff) (added, removed int) {
+	for _, diff := range diffs {
+		if diff.Text == "" {
+			continue
+		}
+
+		n := strings.Count(diff.Text, "\n")
+		if !strings.HasSuffix(diff.Text, "\n") {
+			n++
+		}
+
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			added += n
+		case diffmatchpatch.DiffDelete:
+			removed += n
+		case diffmatchpatch.DiffEqual:
+			// Unchanged lines aren't counted.
+		}
+	}
+
+	return added, removed
+}
+
 // formatLine applies the appropriate prefix and color to a single line.
 func formatLine(line string, diffType diffmatchpatch.Operation, options DiffOptions) string {
 	var (
@@ -531,66 +735,604 @@ func formatLine(line string, diffType diffmatchpatch.Operation, options DiffOpti
 
 // removeNestedPath removes a field from an object based on a path string.
 // Supports both simple paths (e.g., "metadata.annotations") and
-// map key paths (e.g., "metadata.annotations[key.name/value]").
-// Returns true if the field was found and removed, false otherwise.
+// map key paths (e.g., "metadata.annotations[key.name/value]"). A bracketed
+// map key may use * to match any run of characters, including dots and
+// slashes (e.g., "metadata.annotations[kubectl.kubernetes.io/*]"), and
+// bracketed array indexes may be a number or * for every element (e.g.,
+// "spec.items[*].status"). Keys without a * must match exactly.
+// Returns true if any field was found and removed, false otherwise.
 func removeNestedPath(obj map[string]any, path string) bool {
-	if path == "" {
+	segs, ok := parseIgnorePath(path)
+	if !ok {
 		return false
 	}
 
-	// Check if this is a map key path (contains brackets)
-	if strings.Contains(path, "[") && strings.HasSuffix(path, "]") {
-		// Parse path like "metadata.annotations[key]"
-		openBracket := strings.Index(path, "[")
-		closeBracket := strings.LastIndex(path, "]")
+	return removePathSegments(obj, segs)
+}
+
+// ValidateIgnorePath returns an error if path is not a valid ignore path in
+// the syntax removeNestedPath accepts.
+func ValidateIgnorePath(path string) error {
+	if _, ok := parseIgnorePath(path); !ok {
+		return errors.Errorf("malformed path %q: expected dot-separated fields with optional [key] or [index] brackets", path)
+	}
+
+	return nil
+}
+
+// ignorePathSegment is one step of an ignore path: a field name, or the
+// contents of a bracketed map key or array index.
+type ignorePathSegment struct {
+	name      string
+	bracketed bool
+}
+
+// matches reports whether the map key k is selected by the segment.
+func (s ignorePathSegment) matches(k string) bool {
+	if !s.bracketed || !strings.Contains(s.name, "*") {
+		return s.name == k
+	}
+
+	return matchKeyGlob(s.name, k)
+}
+
+// parseIgnorePath splits a path like "spec.items[*].metadata.labels[a.b/c]"
+// into its segments. Dots inside brackets are part of the key. Returns false
+// for an empty or malformed path.
+func parseIgnorePath(path string) ([]ignorePathSegment, bool) {
+	var segs []ignorePathSegment
+
+	for path != "" {
+		if path[0] == '[' {
+			end := strings.Index(path, "]")
+			if end < 2 {
+				return nil, false
+			}
+
+			segs = append(segs, ignorePathSegment{name: path[1:end], bracketed: true})
+			path = path[end+1:]
+
+			// A bracket ends the path or is followed by a dot or another bracket.
+			if path != "" && path[0] != '.' && path[0] != '[' {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+
+			if end == 0 {
+				return nil, false
+			}
+
+			segs = append(segs, ignorePathSegment{name: path[:end]})
+			path = path[end:]
+		}
 
-		if openBracket == -1 || closeBracket == -1 || closeBracket <= openBracket+1 {
-			return false // Invalid format
+		if strings.HasPrefix(path, ".") {
+			path = path[1:]
+			if path == "" {
+				return nil, false
+			}
 		}
+	}
 
-		// Extract the base path and the key
-		basePath := path[:openBracket]
-		key := path[openBracket+1 : closeBracket]
+	return segs, len(segs) > 0
+}
 
-		// Split the base path into parts and use k8s helper to get the map reference
-		parts := strings.Split(basePath, ".")
+// matchKeyGlob reports whether key matches pattern, in which each * matches
+// any run of characters. The whole key must match, so a pattern never selects
+// a key that merely shares its prefix.
+func matchKeyGlob(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
 
-		fieldValue, found, err := un.NestedFieldNoCopy(obj, parts...)
-		if !found || err != nil {
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+
+	key = key[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(key, part)
+		if i == -1 {
 			return false
 		}
 
-		// Ensure it's a map
-		parentMap, ok := fieldValue.(map[string]any)
-		if !ok {
+		key = key[i+len(part):]
+	}
+
+	return strings.HasSuffix(key, parts[len(parts)-1])
+}
+
+// removePathSegments removes every field selected by segs beneath node and
+// reports whether any was removed. A map left empty by removing bracketed keys
+// is removed too, so ignoring every annotation doesn't leave "annotations: {}".
+func removePathSegments(node any, segs []ignorePathSegment) bool {
+	seg, rest := segs[0], segs[1:]
+	removed := false
+
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if !seg.matches(k) {
+				continue
+			}
+
+			if len(rest) == 0 {
+				delete(v, k)
+
+				removed = true
+
+				continue
+			}
+
+			if !removePathSegments(child, rest) {
+				continue
+			}
+
+			removed = true
+
+			if m, ok := child.(map[string]any); ok && len(m) == 0 && len(rest) == 1 && rest[0].bracketed {
+				delete(v, k)
+			}
+		}
+	case []any:
+		// Array elements can only be descended into, not removed.
+		if !seg.bracketed || len(rest) == 0 {
 			return false
 		}
 
-		// Remove the specific key from the map
-		if _, keyExists := parentMap[key]; keyExists {
-			delete(parentMap, key)
+		for i, child := range v {
+			if (seg.name == "*" || seg.name == strconv.Itoa(i)) && removePathSegments(child, rest) {
+				removed = true
+			}
+		}
+	}
 
-			// If the parent map is now empty, remove the parent field entirely
-			if len(parentMap) == 0 {
-				un.RemoveNestedField(obj, parts...)
+	return removed
+}
+
+// selectPathSegments returns a copy of node holding only the fields selected by segs, the
+// counterpart of removePathSegments, and whether any field was selected.
+func selectPathSegments(node any, segs []ignorePathSegment) (any, bool) {
+	if len(segs) == 0 {
+		return node, true
+	}
+
+	seg, rest := segs[0], segs[1:]
+
+	switch v := node.(type) {
+	case map[string]any:
+		out := make(map[string]any)
+
+		for k, child := range v {
+			if !seg.matches(k) {
+				continue
 			}
 
-			return true
+			if selected, ok := selectPathSegments(child, rest); ok {
+				out[k] = selected
+			}
 		}
 
-		return false
+		return out, len(out) > 0
+	case []any:
+		if !seg.bracketed {
+			return nil, false
+		}
+
+		var out []any
+
+		for i, child := range v {
+			if seg.name != "*" && seg.name != strconv.Itoa(i) {
+				continue
+			}
+
+			if selected, ok := selectPathSegments(child, rest); ok {
+				out = append(out, selected)
+			}
+		}
+
+		return out, len(out) > 0
 	}
 
-	// Simple path without brackets - use k8s unstructured helper
-	parts := strings.Split(path, ".")
+	return nil, false
+}
 
-	_, found, _ := un.NestedFieldNoCopy(obj, parts...)
-	if found {
-		un.RemoveNestedField(obj, parts...)
+// isolatePaths returns the fields of obj selected by paths, in the syntax removeNestedPath
+// accepts, nested as they are in obj.
+func isolatePaths(obj map[string]any, paths []string) map[string]any {
+	out := make(map[string]any)
+
+	for _, path := range paths {
+		segs, ok := parseIgnorePath(path)
+		if !ok {
+			continue
+		}
+
+		if selected, ok := selectPathSegments(obj, segs); ok {
+			mergeSelected(out, selected.(map[string]any)) //nolint:forcetypeassert // obj is a map, so its selection is too.
+		}
+	}
+
+	return out
+}
+
+// mergeSelected merges the maps of src into dst, recursively.
+func mergeSelected(dst, src map[string]any) {
+	for k, v := range src {
+		dm, dok := dst[k].(map[string]any)
+		sm, sok := v.(map[string]any)
+
+		if dok && sok {
+			mergeSelected(dm, sm)
+			continue
+		}
+
+		dst[k] = v
+	}
+}
+
+// isolatedLineDiff returns the line diff between the fields of current and desired selected
+// by paths, or nil if those fields are equal. Either object may be nil.
+func isolatedLineDiff(current, desired *un.Unstructured, paths []string) ([]diffmatchpatch.Diff, error) {
+	asString := func(obj *un.Unstructured) (string, error) {
+		if obj == nil {
+			return "", nil
+		}
+
+		isolated := isolatePaths(obj.Object, paths)
+		if len(isolated) == 0 {
+			return "", nil
+		}
+
+		yaml, err := sigsyaml.Marshal(isolated)
+		if err != nil {
+			return "", err
+		}
+
+		return string(yaml), nil
+	}
+
+	currentStr, err := asString(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal current fields to YAML")
+	}
+
+	desiredStr, err := asString(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal desired fields to YAML")
+	}
+
+	if currentStr == desiredStr {
+		return nil, nil
+	}
+
+	return GetLineDiff(currentStr, desiredStr), nil
+}
+
+// digestOversizedFields replaces, in place, every string value longer than maxSize bytes with a
+// placeholder carrying its size and a SHA-256 digest prefix. Equal values produce equal placeholders,
+// so an unchanged oversized field diffs as unchanged and a changed one shows as a one-line change.
+// Returns the paths of the replaced fields.
+func digestOversizedFields(obj any, maxSize int, path string) []string {
+	var replaced []string
+
+	switch v := obj.(type) {
+	case map[string]any:
+		for k, val := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if str, ok := val.(string); ok && len(str) > maxSize {
+				v[k] = oversizedPlaceholder(str)
+				replaced = append(replaced, p)
+
+				continue
+			}
+
+			replaced = append(replaced, digestOversizedFields(val, maxSize, p)...)
+		}
+	case []any:
+		for i, val := range v {
+			p := fmt.Sprintf("%s[%d]", path, i)
+
+			if str, ok := val.(string); ok && len(str) > maxSize {
+				v[i] = oversizedPlaceholder(str)
+				replaced = append(replaced, p)
+
+				continue
+			}
+
+			replaced = append(replaced, digestOversizedFields(val, maxSize, p)...)
+		}
+	}
+
+	return replaced
+}
+
+// oversizedPlaceholder summarizes a large string value by its size and content digest.
+func oversizedPlaceholder(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return fmt.Sprintf("<omitted: %d bytes, sha256:%s>", len(s), hex.EncodeToString(sum[:8]))
+}
+
+// quantityParentKeys are map keys whose values are maps of resource quantities
+// (e.g. resources.limits, ResourceQuota hard, node capacity).
+var quantityParentKeys = map[string]bool{ //nolint:gochecknoglobals // read-only lookup table
+	"limits":      true,
+	"requests":    true,
+	"capacity":    true,
+	"allocatable": true,
+	"hard":        true,
+}
+
+// isQuantityField reports whether a field looks like a resource quantity. We
+// have no schema here, so this is a heuristic on the field name and the key of
+// the map that holds it.
+func isQuantityField(key, parent string) bool {
+	switch {
+	case quantityParentKeys[parent]:
+		return true
+	case key == "cpu", key == "memory", key == "storage", key == "ephemeral-storage":
 		return true
+	default:
+		return strings.HasPrefix(key, "hugepages-")
+	}
+}
+
+// parseQuantity parses a string or numeric scalar as a resource quantity.
+func parseQuantity(v any) (resource.Quantity, bool) {
+	var s string
+
+	switch q := v.(type) {
+	case string:
+		s = q
+	case int64, float64:
+		s = fmt.Sprint(q)
+	default:
+		return resource.Quantity{}, false
+	}
+
+	parsed, err := resource.ParseQuantity(s)
+
+	return parsed, err == nil
+}
+
+// normalizeQuantities walks current and desired in parallel and, for each
+// quantity-like field whose two values are semantically equal but spelled
+// differently, replaces the desired value in place with the current one.
+// Returns the paths of the normalized fields.
+func normalizeQuantities(current, desired any, path, parent string) []string {
+	var normalized []string
+
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for k, dv := range d {
+			cv, ok := c[k]
+			if !ok {
+				continue
+			}
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if isQuantityField(k, parent) && !equality.Semantic.DeepEqual(cv, dv) {
+				cq, cok := parseQuantity(cv)
+				dq, dok := parseQuantity(dv)
+
+				if cok && dok && cq.Cmp(dq) == 0 {
+					d[k] = cv
+					normalized = append(normalized, p)
+
+					continue
+				}
+			}
+
+			normalized = append(normalized, normalizeQuantities(cv, dv, p, k)...)
+		}
+	case []any:
+		c, ok := current.([]any)
+		if !ok {
+			return nil
+		}
+
+		for i := range min(len(c), len(d)) {
+			normalized = append(normalized, normalizeQuantities(c[i], d[i], fmt.Sprintf("%s[%d]", path, i), parent)...)
+		}
+	}
+
+	return normalized
+}
+
+// parseEmbeddedDocument parses s as a JSON or YAML document. Only documents
+// that hold a map or a list count; plain scalars are compared as strings.
+func parseEmbeddedDocument(s string) (any, bool) {
+	if !strings.ContainsAny(s, "{[:\n") {
+		return nil, false
+	}
+
+	var doc any
+	if err := sigsyaml.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, false
 	}
 
-	return false
+	switch doc.(type) {
+	case map[string]any, []any:
+		return doc, true
+	default:
+		return nil, false
+	}
+}
+
+// normalizeEmbeddedDocuments walks current and desired in parallel and, where
+// both hold different strings that parse to the same JSON or YAML document,
+// replaces the desired value with the current one so the field no longer
+// diffs. It returns the paths it normalized.
+func normalizeEmbeddedDocuments(current, desired any, path string) []string {
+	var normalized []string
+
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for k, dv := range d {
+			cv, ok := c[k]
+			if !ok {
+				continue
+			}
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if equivalentDocuments(cv, dv) {
+				d[k] = cv
+				normalized = append(normalized, p)
+
+				continue
+			}
+
+			normalized = append(normalized, normalizeEmbeddedDocuments(cv, dv, p)...)
+		}
+	case []any:
+		c, ok := current.([]any)
+		if !ok {
+			return nil
+		}
+
+		for i := range min(len(c), len(d)) {
+			p := fmt.Sprintf("%s[%d]", path, i)
+
+			if equivalentDocuments(c[i], d[i]) {
+				d[i] = c[i]
+				normalized = append(normalized, p)
+
+				continue
+			}
+
+			normalized = append(normalized, normalizeEmbeddedDocuments(c[i], d[i], p)...)
+		}
+	}
+
+	return normalized
+}
+
+// equivalentDocuments reports whether current and desired are different
+// strings that parse to equal JSON or YAML documents.
+func equivalentDocuments(current, desired any) bool {
+	cs, cok := current.(string)
+	ds, dok := desired.(string)
+
+	if !cok || !dok || cs == ds {
+		return false
+	}
+
+	cd, cok := parseEmbeddedDocument(cs)
+	dd, dok := parseEmbeddedDocument(ds)
+
+	return cok && dok && equality.Semantic.DeepEqual(cd, dd)
+}
+
+// normalizeScalars walks current and desired in parallel and, where both hold
+// scalars that differ only in representation, replaces the desired value with
+// the current one so the field no longer diffs. It returns the paths it
+// normalized. Map key order needs no normalizing: YAML output is always sorted.
+func normalizeScalars(current, desired any, path string) []string {
+	var normalized []string
+
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for k, dv := range d {
+			cv, ok := c[k]
+			if !ok {
+				continue
+			}
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if equivalentScalars(cv, dv) {
+				d[k] = cv
+				normalized = append(normalized, p)
+
+				continue
+			}
+
+			normalized = append(normalized, normalizeScalars(cv, dv, p)...)
+		}
+	case []any:
+		c, ok := current.([]any)
+		if !ok {
+			return nil
+		}
+
+		for i := range min(len(c), len(d)) {
+			p := fmt.Sprintf("%s[%d]", path, i)
+
+			if equivalentScalars(c[i], d[i]) {
+				d[i] = c[i]
+				normalized = append(normalized, p)
+
+				continue
+			}
+
+			normalized = append(normalized, normalizeScalars(c[i], d[i], p)...)
+		}
+	}
+
+	return normalized
+}
+
+// equivalentScalars reports whether current and desired are different
+// representations of the same boolean, number or quantity.
+func equivalentScalars(current, desired any) bool {
+	if equality.Semantic.DeepEqual(current, desired) {
+		return false
+	}
+
+	if cb, ok := parseBool(current); ok {
+		db, ok := parseBool(desired)
+		return ok && cb == db
+	}
+
+	cq, cok := parseQuantity(current)
+	dq, dok := parseQuantity(desired)
+
+	return cok && dok && cq.Cmp(dq) == 0
+}
+
+// parseBool parses a boolean or the string "true" or "false".
+func parseBool(v any) (value, ok bool) {
+	switch b := v.(type) {
+	case bool:
+		return b, true
+	case string:
+		return b == "true", b == "true" || b == "false"
+	default:
+		return false, false
+	}
 }
 
 // cleanupForDiff removes fields that shouldn't be included in the diff.
@@ -241,6 +241,453 @@ func TestGenerateDiffWithOptions(t *testing.T) {
 	}
 }
 
+func TestGenerateDiffWithOptions_MaxFieldSize(t *testing.T) {
+	big := strings.Repeat("a", 2048)
+	bigChanged := strings.Repeat("a", 2047) + "b"
+
+	configMap := func(data map[string]any, label string) *un.Unstructured {
+		res := tu.NewResource("v1", "ConfigMap", "big-config").
+			InNamespace("default").
+			WithLabels(map[string]string{"tier": label}).
+			Build()
+		res.Object["data"] = data
+
+		return res
+	}
+
+	tests := map[string]struct {
+		reason       string
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		maxFieldSize int
+		normalize    bool
+		wantType     types.DiffType
+		wantContains []string
+		wantAbsent   []string
+	}{
+		"OversizedFieldChanged": {
+			reason:       "A changed oversized field should be reported as a digest change, not line-diffed",
+			current:      configMap(map[string]any{"payload": big}, "a"),
+			desired:      configMap(map[string]any{"payload": bigChanged}, "a"),
+			maxFieldSize: 1024,
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"<omitted: 2048 bytes, sha256:"},
+			wantAbsent:   []string{big[:100]},
+		},
+		"OversizedFieldUnchanged": {
+			reason:       "An unchanged oversized field should produce identical placeholders and no diff",
+			current:      configMap(map[string]any{"payload": big, "small": "x"}, "a"),
+			desired:      configMap(map[string]any{"payload": big, "small": "x"}, "a"),
+			maxFieldSize: 1024,
+			wantType:     types.DiffTypeEqual,
+		},
+		"OversizedFieldUnchangedOtherFieldChanged": {
+			reason:       "Other changes should still diff normally alongside an unchanged oversized field",
+			current:      configMap(map[string]any{"payload": big}, "a"),
+			desired:      configMap(map[string]any{"payload": big}, "b"),
+			maxFieldSize: 1024,
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"tier: b", "<omitted: 2048 bytes, sha256:"},
+		},
+		"OversizedDocumentReformatted": {
+			reason:       "An oversized JSON document the server only reformatted should be normalized before it is digested",
+			current:      configMap(map[string]any{"payload": `{"statement":"` + big + `","version":1}`, "replicas": int64(3)}, "a"),
+			desired:      configMap(map[string]any{"payload": "{\n  \"version\": 1,\n  \"statement\": \"" + big + "\"\n}", "replicas": "3"}, "a"),
+			maxFieldSize: 1024,
+			normalize:    true,
+			wantType:     types.DiffTypeEqual,
+		},
+		"LimitDisabled": {
+			reason:       "With no limit the full value should be diffed",
+			current:      configMap(map[string]any{"payload": big}, "a"),
+			desired:      configMap(map[string]any{"payload": bigChanged}, "a"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{bigChanged},
+			wantAbsent:   []string{"<omitted:"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.MaxFieldSize = tt.maxFieldSize
+			opts.Normalize = tt.normalize
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(formatted, absent) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff unexpectedly contains %q", tt.reason, absent)
+				}
+			}
+
+			// The raw objects passed in must never be rewritten.
+			if got, _, _ := un.NestedString(tt.current.Object, "data", "payload"); len(got) < len(big) {
+				t.Errorf("\n%s\nGenerateDiffWithOptions(...): input object was mutated", tt.reason)
+			}
+		})
+	}
+}
+
+func TestGenerateDiffWithOptions_Quantities(t *testing.T) {
+	deployment := func(limits map[string]any, image string) *un.Unstructured {
+		res := tu.NewResource("apps/v1", "Deployment", "app").
+			InNamespace("default").
+			Build()
+
+		_ = un.SetNestedSlice(res.Object, []any{
+			map[string]any{
+				"name":      "app",
+				"image":     image,
+				"resources": map[string]any{"limits": limits},
+			},
+		}, "spec", "template", "spec", "containers")
+
+		return res
+	}
+
+	tests := map[string]struct {
+		reason       string
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		wantType     types.DiffType
+		wantContains []string
+		wantAbsent   []string
+	}{
+		"EquivalentNotation": {
+			reason:   "Quantities that differ only in notation should not produce a diff",
+			current:  deployment(map[string]any{"cpu": "1", "memory": "1073741824"}, "app:v1"),
+			desired:  deployment(map[string]any{"cpu": "1000m", "memory": "1Gi"}, "app:v1"),
+			wantType: types.DiffTypeEqual,
+		},
+		"NumericAndString": {
+			reason:   "A numeric quantity should compare equal to its string form",
+			current:  deployment(map[string]any{"cpu": int64(2)}, "app:v1"),
+			desired:  deployment(map[string]any{"cpu": "2000m"}, "app:v1"),
+			wantType: types.DiffTypeEqual,
+		},
+		"EquivalentWithOtherChange": {
+			reason:       "Equivalent quantities should be hidden while real changes still show",
+			current:      deployment(map[string]any{"cpu": "1", "memory": "512Mi"}, "app:v1"),
+			desired:      deployment(map[string]any{"cpu": "1000m", "memory": "1Gi"}, "app:v2"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"image: app:v2", "memory: 1Gi"},
+			wantAbsent:   []string{"1000m"},
+		},
+		"NotAQuantityField": {
+			reason:       "Fields outside quantity-like paths should be compared as written",
+			current:      tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("replicas", "1").Build(),
+			desired:      tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("replicas", "1000m").Build(),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"replicas: 1000m"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(formatted, absent) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff unexpectedly contains %q:\n%s", tt.reason, absent, formatted)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDiffWithOptions_Normalize(t *testing.T) {
+	settings := func(fields map[string]any) *un.Unstructured {
+		res := tu.NewResource("example.org/v1", "Database", "db").Build()
+		_ = un.SetNestedMap(res.Object, fields, "spec", "settings")
+
+		return res
+	}
+
+	tests := map[string]struct {
+		reason       string
+		normalize    bool
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		wantType     types.DiffType
+		wantContains []string
+	}{
+		"NumberAndString": {
+			reason:    "A number should compare equal to its string form with normalization on",
+			normalize: true,
+			current:   settings(map[string]any{"timeout": int64(30)}),
+			desired:   settings(map[string]any{"timeout": "30"}),
+			wantType:  types.DiffTypeEqual,
+		},
+		"IntegerAndFloat": {
+			reason:    "An integer should compare equal to the same float with normalization on",
+			normalize: true,
+			current:   settings(map[string]any{"ratio": int64(2)}),
+			desired:   settings(map[string]any{"ratio": float64(2)}),
+			wantType:  types.DiffTypeEqual,
+		},
+		"BoolAndString": {
+			reason:    "A boolean should compare equal to its string form with normalization on",
+			normalize: true,
+			current:   settings(map[string]any{"enabled": true}),
+			desired:   settings(map[string]any{"enabled": "true"}),
+			wantType:  types.DiffTypeEqual,
+		},
+		"QuantityOutsideQuantityField": {
+			reason:    "Equivalent quantities should compare equal in any field with normalization on",
+			normalize: true,
+			current:   settings(map[string]any{"share": "0.5"}),
+			desired:   settings(map[string]any{"share": "500m"}),
+			wantType:  types.DiffTypeEqual,
+		},
+		"InList": {
+			reason:    "Equivalent scalars in a list should compare equal with normalization on",
+			normalize: true,
+			current:   settings(map[string]any{"ports": []any{int64(80), int64(443)}}),
+			desired:   settings(map[string]any{"ports": []any{"80", "443"}}),
+			wantType:  types.DiffTypeEqual,
+		},
+		"RealChange": {
+			reason:       "Different values should still diff with normalization on",
+			normalize:    true,
+			current:      settings(map[string]any{"timeout": int64(30), "enabled": true}),
+			desired:      settings(map[string]any{"timeout": "60", "enabled": "false"}),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"timeout: \"60\"", "enabled: \"false\""},
+		},
+		"Off": {
+			reason:       "Without normalization a number and its string form should diff",
+			current:      settings(map[string]any{"timeout": int64(30)}),
+			desired:      settings(map[string]any{"timeout": "30"}),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"timeout: \"30\""},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.Normalize = tt.normalize
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDiffWithOptions_ShowLabelsDiffOnly(t *testing.T) {
+	resource := func(labels map[string]string, size string) *un.Unstructured {
+		return tu.NewResource("example.org/v1", "Bucket", "bucket").
+			WithLabels(labels).
+			WithSpecField("size", size).
+			Build()
+	}
+
+	tests := map[string]struct {
+		reason       string
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		wantType     types.DiffType
+		wantContains []string
+		wantAbsent   []string
+	}{
+		"LabelAndSpecChange": {
+			reason:       "Only the label change should be rendered when labels and spec both change",
+			current:      resource(map[string]string{"argocd.argoproj.io/instance": "old"}, "small"),
+			desired:      resource(map[string]string{"argocd.argoproj.io/instance": "new"}, "large"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"-     argocd.argoproj.io/instance: old", "+     argocd.argoproj.io/instance: new"},
+			wantAbsent:   []string{"size", "kind: Bucket"},
+		},
+		"SpecChangeOnly": {
+			reason:   "A resource changed only outside its labels should stay modified but render nothing",
+			current:  resource(map[string]string{"team": "a"}, "small"),
+			desired:  resource(map[string]string{"team": "a"}, "large"),
+			wantType: types.DiffTypeModified,
+		},
+		"Added": {
+			reason:       "An added resource should render only its labels",
+			desired:      resource(map[string]string{"team": "a"}, "small"),
+			wantType:     types.DiffTypeAdded,
+			wantContains: []string{"+     team: a"},
+			wantAbsent:   []string{"size"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.ShowLabelsDiffOnly = true
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			if len(tt.wantContains) == 0 && formatted != "" {
+				t.Errorf("\n%s\nGenerateDiffWithOptions(...): want empty diff, got:\n%s", tt.reason, formatted)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(formatted, absent) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff should not contain %q:\n%s", tt.reason, absent, formatted)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDiffWithOptions_EmbeddedDocuments(t *testing.T) {
+	policy := func(doc, region string) *un.Unstructured {
+		return tu.NewResource("iam.aws.upbound.io/v1beta1", "Policy", "policy").
+			WithSpecField("forProvider", map[string]any{"payload": doc, "region": region}).
+			Build()
+	}
+
+	tests := map[string]struct {
+		reason       string
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		wantType     types.DiffType
+		wantContains []string
+		wantAbsent   []string
+	}{
+		"ReorderedJSONKeys": {
+			reason:   "A JSON document whose keys were reordered by the server should not produce a diff",
+			current:  policy(`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow"}],"Version":"2012-10-17"}`, "us-east-1"),
+			desired:  policy(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject"}]}`, "us-east-1"),
+			wantType: types.DiffTypeEqual,
+		},
+		"ReformattedYAML": {
+			reason:   "A YAML document that differs only in key order and indentation should not produce a diff",
+			current:  tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("values", "a: 1\nb:\n  - x\n").Build(),
+			desired:  tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("values", "b:\n- x\na: 1\n").Build(),
+			wantType: types.DiffTypeEqual,
+		},
+		"ChangedJSONValue": {
+			reason:       "A JSON document with a changed value is a real change",
+			current:      policy(`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow"}],"Version":"2012-10-17"}`, "us-east-1"),
+			desired:      policy(`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:GetObject"}]}`, "us-east-1"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"Deny"},
+		},
+		"ReorderedJSONWithOtherChange": {
+			reason:       "An equivalent document should be hidden while real changes elsewhere still show",
+			current:      policy(`{"a":1,"b":2}`, "us-east-1"),
+			desired:      policy(`{"b":2,"a":1}`, "eu-west-1"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"region: eu-west-1"},
+			wantAbsent:   []string{`{"b":2,"a":1}`},
+		},
+		"ReorderedJSONList": {
+			reason:       "Reordering the items of a JSON list is a real change",
+			current:      policy(`["a","b"]`, "us-east-1"),
+			desired:      policy(`["b","a"]`, "us-east-1"),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{`["b","a"]`},
+		},
+		"PlainStrings": {
+			reason:       "Strings that aren't documents should be compared as written",
+			current:      tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("replicas", "1").Build(),
+			desired:      tu.NewResource("v1", "ConfigMap", "cfg").WithSpecField("replicas", "1.0").Build(),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"replicas: \"1.0\""},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(formatted, absent) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff unexpectedly contains %q:\n%s", tt.reason, absent, formatted)
+				}
+			}
+		})
+	}
+}
+
 func TestFormatDiff(t *testing.T) {
 	// Create test diffs
 	simpleDiffs := []diffmatchpatch.Diff{
@@ -384,6 +831,130 @@ func TestFormatDiff(t *testing.T) {
 	}
 }
 
+func TestCompactDiffFormatter_ContextLines(t *testing.T) {
+	// Two changes separated by five unchanged lines.
+	diffs := []diffmatchpatch.Diff{
+		{Type: diffmatchpatch.DiffEqual, Text: "a\nb\n"},
+		{Type: diffmatchpatch.DiffDelete, Text: "c\n"},
+		{Type: diffmatchpatch.DiffEqual, Text: "1\n2\n3\n4\n5\n"},
+		{Type: diffmatchpatch.DiffInsert, Text: "d\n"},
+		{Type: diffmatchpatch.DiffEqual, Text: "e\n"},
+	}
+
+	tests := map[string]struct {
+		reason       string
+		contextLines int
+		useColors    bool
+		want         string
+	}{
+		"ZeroContext": {
+			reason:       "With no context, the unchanged lines between changes should collapse into a counted marker.",
+			contextLines: 0,
+			want: "" +
+				"- c\n" +
+				"... (5 unchanged lines)\n" +
+				"+ d\n",
+		},
+		"CollapsesLongRun": {
+			reason:       "A run longer than 2*N+1 lines should keep N lines on each side of the marker.",
+			contextLines: 1,
+			want: "" +
+				"  b\n" +
+				"- c\n" +
+				"  1\n" +
+				"... (3 unchanged lines)\n" +
+				"  5\n" +
+				"+ d\n" +
+				"  e\n",
+		},
+		"KeepsShortRun": {
+			reason:       "A run of exactly 2*N+1 lines should be shown rather than hide a single line.",
+			contextLines: 2,
+			want: "" +
+				"  a\n" +
+				"  b\n" +
+				"- c\n" +
+				"  1\n" +
+				"  2\n" +
+				"  3\n" +
+				"  4\n" +
+				"  5\n" +
+				"+ d\n" +
+				"  e\n",
+		},
+		"Colored": {
+			reason:       "The marker should be plain text between colored lines.",
+			contextLines: 0,
+			useColors:    true,
+			want: "" +
+				types.ColorRed + "- c" + types.ColorReset + "\n" +
+				"... (5 unchanged lines)\n" +
+				types.ColorGreen + "+ d" + types.ColorReset + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = tt.useColors
+			opts.Compact = true
+			opts.ContextLines = tt.contextLines
+
+			got := FormatDiff(diffs, opts)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nFormatDiff(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCountLineChanges(t *testing.T) {
+	tests := map[string]struct {
+		reason      string
+		diffs       []diffmatchpatch.Diff
+		wantAdded   int
+		wantRemoved int
+	}{
+		"NoChanges": {
+			reason: "Unchanged lines should not be counted.",
+			diffs:  []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffEqual, Text: "a\nb\n"}},
+		},
+		"MultiLineChunks": {
+			reason: "Each line of a multi-line chunk should be counted.",
+			diffs: []diffmatchpatch.Diff{
+				{Type: diffmatchpatch.DiffEqual, Text: "a\n"},
+				{Type: diffmatchpatch.DiffDelete, Text: "b\n"},
+				{Type: diffmatchpatch.DiffInsert, Text: "c\nd\ne\n"},
+			},
+			wantAdded:   3,
+			wantRemoved: 1,
+		},
+		"NoTrailingNewline": {
+			reason: "The last line of a chunk should be counted even without a trailing newline.",
+			diffs: []diffmatchpatch.Diff{
+				{Type: diffmatchpatch.DiffDelete, Text: "b\nc"},
+				{Type: diffmatchpatch.DiffInsert, Text: "d"},
+			},
+			wantAdded:   1,
+			wantRemoved: 2,
+		},
+		"EmptyLine": {
+			reason:    "A chunk that is only a newline is one (empty) line.",
+			diffs:     []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffInsert, Text: "\n"}},
+			wantAdded: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			added, removed := countLineChanges(tt.diffs)
+			if added != tt.wantAdded || removed != tt.wantRemoved {
+				t.Errorf("\n%s\ncountLineChanges(...): want (+%d -%d), got (+%d -%d)", tt.reason, tt.wantAdded, tt.wantRemoved, added, removed)
+			}
+		})
+	}
+}
+
 func TestRemoveNestedPath(t *testing.T) {
 	tests := map[string]struct {
 		obj     map[string]any
@@ -484,6 +1055,127 @@ func TestRemoveNestedPath(t *testing.T) {
 			},
 			descr: "returns false for non-existent map key",
 		},
+		"MapKeyGlob": {
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": "large-json",
+						"kubectl.kubernetes.io/restartedAt":                "2026-01-01T00:00:00Z",
+						"kubectl.kubernetes.io.example.org/keep":           "value",
+						"keep-this":                                        "value",
+					},
+				},
+			},
+			path: "metadata.annotations[kubectl.kubernetes.io/*]",
+			want: true,
+			wantObj: map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io.example.org/keep": "value",
+						"keep-this":                              "value",
+					},
+				},
+			},
+			descr: "removes every key matching a glob, but not keys that only share a prefix",
+		},
+		"MapKeyGlobEmptiesMap": {
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"name": "test",
+					"annotations": map[string]any{
+						"example.org/a": "a",
+						"example.org/b": "b",
+					},
+				},
+			},
+			path: "metadata.annotations[example.org/*]",
+			want: true,
+			wantObj: map[string]any{
+				"metadata": map[string]any{
+					"name": "test",
+				},
+			},
+			descr: "removes a map emptied by a glob",
+		},
+		"ExactKeyIsNotPrefix": {
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"example.org/id":       "a",
+						"example.org/id-extra": "b",
+					},
+				},
+			},
+			path: "metadata.annotations[example.org/id]",
+			want: true,
+			wantObj: map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"example.org/id-extra": "b",
+					},
+				},
+			},
+			descr: "removes only the exact key when there is no glob",
+		},
+		"ArrayWildcard": {
+			obj: map[string]any{
+				"spec": map[string]any{
+					"items": []any{
+						map[string]any{"name": "a", "status": "ready"},
+						map[string]any{"name": "b", "status": "pending"},
+						map[string]any{"name": "c"},
+					},
+				},
+			},
+			path: "spec.items[*].status",
+			want: true,
+			wantObj: map[string]any{
+				"spec": map[string]any{
+					"items": []any{
+						map[string]any{"name": "a"},
+						map[string]any{"name": "b"},
+						map[string]any{"name": "c"},
+					},
+				},
+			},
+			descr: "removes a field from every element of an array",
+		},
+		"ArrayIndex": {
+			obj: map[string]any{
+				"spec": map[string]any{
+					"items": []any{
+						map[string]any{"name": "a", "status": "ready"},
+						map[string]any{"name": "b", "status": "pending"},
+					},
+				},
+			},
+			path: "spec.items[1].status",
+			want: true,
+			wantObj: map[string]any{
+				"spec": map[string]any{
+					"items": []any{
+						map[string]any{"name": "a", "status": "ready"},
+						map[string]any{"name": "b"},
+					},
+				},
+			},
+			descr: "removes a field from one element of an array",
+		},
+		"MalformedPath": {
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"name": "test",
+				},
+			},
+			path: "metadata.[name",
+			want: false,
+			wantObj: map[string]any{
+				"metadata": map[string]any{
+					"name": "test",
+				},
+			},
+			descr: "returns false for a malformed path",
+		},
 		"EmptyPath": {
 			obj: map[string]any{
 				"metadata": map[string]any{
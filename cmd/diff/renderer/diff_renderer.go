@@ -13,6 +13,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 )
 
+// NoChangesMessage is the line quiet mode prints when nothing changed, so scripts can detect it.
+const NoChangesMessage = "No changes."
+
 // DiffRenderer handles rendering diffs to output.
 type DiffRenderer interface {
 	// RenderDiffs formats and outputs diffs.
@@ -44,6 +47,16 @@ func getKindName(d *dt.ResourceDiff) string {
 	return fmt.Sprintf("%s/%s", d.Gvk.Kind, d.ResourceName)
 }
 
+// sortedDiffs returns the diffs ordered by getKindName, the order every renderer lists them in.
+func sortedDiffs(diffs map[string]*dt.ResourceDiff) []*dt.ResourceDiff {
+	d := slices.AppendSeq(make([]*dt.ResourceDiff, 0, len(diffs)), maps.Values(diffs))
+	slices.SortFunc(d, func(a, b *dt.ResourceDiff) int {
+		return cmp.Compare(getKindName(a), getKindName(b))
+	})
+
+	return d
+}
+
 // RenderDiffs formats and prints the diffs.
 // Diff output goes to r.diffOpts.Stdout, errors go to r.diffOpts.Stderr.
 func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
@@ -53,16 +66,25 @@ func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, err
 		"useColors", r.diffOpts.UseColors,
 		"compact", r.diffOpts.Compact)
 
+	// Lead with what the diff was computed against, for --show-provenance.
+	if r.diffOpts.Provenance != nil {
+		if _, err := fmt.Fprintf(r.diffOpts.Stdout, "%s---\n", r.diffOpts.Provenance); err != nil {
+			return errors.Wrap(err, "failed to write provenance to output")
+		}
+	}
+
+	if r.diffOpts.SummaryOnly {
+		return r.renderSummary(diffs, errs)
+	}
+
 	stdout := r.diffOpts.Stdout
 	stderr := r.diffOpts.Stderr
 
-	// Sort the keys to ensure a consistent output order
-	d := slices.AppendSeq(make([]*dt.ResourceDiff, 0, len(diffs)), maps.Values(diffs))
+	// Sort by kind and name, which is how they're displayed to the user
+	d := sortedDiffs(diffs)
 
-	// Sort by GetKindName which is how it's displayed to the user
-	slices.SortFunc(d, func(a, b *dt.ResourceDiff) int {
-		return cmp.Compare(getKindName(a), getKindName(b))
-	})
+	// Show removed resources beneath the removed resource that owns them
+	d, depths := nestRemoved(d)
 
 	// Track stats for summary logging
 	addedCount := 0
@@ -93,11 +115,12 @@ func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, err
 
 		switch diff.DiffType {
 		case dt.DiffTypeAdded:
-			header = fmt.Sprintf("+++ %s", resourceID)
+			header = fmt.Sprintf("+++ %s%s", resourceID, revisionNote(diff))
 		case dt.DiffTypeRemoved:
-			header = fmt.Sprintf("--- %s", resourceID)
+			header = fmt.Sprintf("--- %s%s", resourceID, removalNote(diff))
 		case dt.DiffTypeModified:
-			header = fmt.Sprintf("~~~ %s", resourceID)
+			added, removed := countLineChanges(diff.LineDiffs)
+			header = fmt.Sprintf("~~~ %s%s (+%d -%d)", resourceID, revisionNote(diff), added, removed)
 		case dt.DiffTypeEqual:
 			// should never get here
 			header = ""
@@ -105,8 +128,15 @@ func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, err
 
 		// Format the diff content
 		content := FormatDiff(diff.LineDiffs, r.diffOpts)
+		if content != "" && len(diff.Warnings) > 0 {
+			content = strings.TrimSuffix(content, "\n") + "\n" + formatWarnings(diff.Warnings)
+		}
 
 		if content != "" {
+			if depth := depths[diff]; depth > 0 {
+				header, content = indent(header, depth), indent(content, depth)
+			}
+
 			_, err := fmt.Fprintf(stdout, "%s\n%s\n---\n", header, content)
 			if err != nil {
 				r.logger.Debug("Error writing diff to output", "resource", resourceID, "error", err)
@@ -119,6 +149,17 @@ func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, err
 		}
 	}
 
+	// List the required resources recorded for --show-external-resources, whether or not the XR changed.
+	for _, diff := range d {
+		if len(diff.RequiredResources) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(stdout, "%s\n---\n", formatRequiredResources(diff)); err != nil {
+			return errors.Wrap(err, "failed to write required resources to output")
+		}
+	}
+
 	r.logger.Debug("Diff rendering complete",
 		"added", addedCount,
 		"removed", removedCount,
@@ -127,39 +168,221 @@ func (r *DefaultDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, err
 		"output", outputCount)
 
 	// Add a summary to the output if there were diffs
-	if outputCount > 0 {
-		summary := strings.Builder{}
-		summary.WriteString("\nSummary: ")
+	summary := formatSummary(Summary{Added: addedCount, Modified: modifiedCount, Removed: removedCount})
+
+	// With ShowLabelsDiffOnly, resources changed only outside their labels and annotations
+	// render no body but are still changes.
+	changed := outputCount > 0 || (r.diffOpts.ShowLabelsDiffOnly && summary != "")
+	if changed && summary != "" {
+		if _, err := fmt.Fprintln(stdout, "\n"+summary); err != nil {
+			return errors.Wrap(err, "failed to write summary to output")
+		}
+	}
 
-		if addedCount > 0 {
-			fmt.Fprintf(&summary, "%d added, ", addedCount)
+	if r.diffOpts.Quiet && !changed && len(errs) == 0 {
+		if _, err := fmt.Fprintln(stdout, NoChangesMessage); err != nil {
+			return errors.Wrap(err, "failed to write no changes message")
 		}
+	}
 
-		if modifiedCount > 0 {
-			fmt.Fprintf(&summary, "%d modified, ", modifiedCount)
+	// Write errors to stderr following Unix conventions
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
 		}
+	}
 
-		if removedCount > 0 {
-			fmt.Fprintf(&summary, "%d removed, ", removedCount)
+	return nil
+}
+
+// nestRemoved orders each removed resource directly after the removed resource that owns
+// it, and returns how deeply each diff is nested under removed owners. Other diffs keep
+// their order.
+func nestRemoved(sorted []*dt.ResourceDiff) ([]*dt.ResourceDiff, map[*dt.ResourceDiff]int) {
+	removed := make(map[string]bool)
+
+	for _, diff := range sorted {
+		if diff.DiffType == dt.DiffTypeRemoved {
+			removed[diff.GetDiffKey()] = true
 		}
+	}
 
-		// Remove trailing comma and space
-		summaryStr := strings.TrimSuffix(summary.String(), ", ")
+	owned := make(map[string][]*dt.ResourceDiff)
+	top := make([]*dt.ResourceDiff, 0, len(sorted))
 
-		if summaryStr != "\nSummary: " {
-			_, err := fmt.Fprintln(stdout, summaryStr)
-			if err != nil {
-				return errors.Wrap(err, "failed to write summary to output")
-			}
+	for _, diff := range sorted {
+		if diff.DiffType == dt.DiffTypeRemoved && removed[diff.Owner] {
+			owned[diff.Owner] = append(owned[diff.Owner], diff)
+			continue
+		}
+
+		top = append(top, diff)
+	}
+
+	out := make([]*dt.ResourceDiff, 0, len(sorted))
+	depths := make(map[*dt.ResourceDiff]int, len(sorted))
+
+	var add func(diff *dt.ResourceDiff, depth int)
+
+	add = func(diff *dt.ResourceDiff, depth int) {
+		out = append(out, diff)
+		depths[diff] = depth
+
+		for _, child := range owned[diff.GetDiffKey()] {
+			add(child, depth+1)
+		}
+	}
+
+	for _, diff := range top {
+		add(diff, 0)
+	}
+
+	return out, depths
+}
+
+// removalNote returns the parenthetical following a removed resource's header, giving why
+// it would be removed and the composed resource that owns it, if known.
+func removalNote(diff *dt.ResourceDiff) string {
+	var parts []string
+
+	if diff.RemovalReason != "" {
+		parts = append(parts, string(diff.RemovalReason))
+	}
+
+	if diff.Owner != "" {
+		parts = append(parts, "owned by "+dt.KindNameFromDiffKey(diff.Owner))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// revisionNote returns the parenthetical following an XR's header, naming the CompositionRevision
+// it was rendered against, if any.
+func revisionNote(diff *dt.ResourceDiff) string {
+	if diff.Revision == nil {
+		return ""
+	}
+
+	if diff.Revision.Number == 0 {
+		return fmt.Sprintf(" (revision %s)", diff.Revision.Name)
+	}
+
+	return fmt.Sprintf(" (revision %s, #%d)", diff.Revision.Name, diff.Revision.Number)
+}
+
+// indent prefixes each line of s with two spaces per level of depth.
+func indent(s string, depth int) string {
+	prefix := strings.Repeat("  ", depth)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSummary prints only the summary line of the diffs, or NoChangesMessage when nothing
+// changed and there were no errors.
+func (r *DefaultDiffRenderer) renderSummary(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	line := formatSummary(summarize(diffs))
+	if line == "" && len(errs) == 0 {
+		line = NoChangesMessage
+	}
+
+	if line != "" {
+		if _, err := fmt.Fprintln(r.diffOpts.Stdout, line); err != nil {
+			return errors.Wrap(err, "failed to write summary to output")
 		}
 	}
 
-	// Write errors to stderr following Unix conventions
 	for _, e := range errs {
-		if _, err := fmt.Fprintln(stderr, e.FormatError()); err != nil {
+		if _, err := fmt.Fprintln(r.diffOpts.Stderr, e.FormatError()); err != nil {
 			return errors.Wrap(err, "failed to write error to stderr")
 		}
 	}
 
 	return nil
 }
+
+// summarize counts the added, modified, and removed resources among diffs.
+func summarize(diffs map[string]*dt.ResourceDiff) Summary {
+	var s Summary
+
+	for _, diff := range diffs {
+		switch diff.DiffType {
+		case dt.DiffTypeAdded:
+			s.Added++
+		case dt.DiffTypeModified:
+			s.Modified++
+		case dt.DiffTypeRemoved:
+			s.Removed++
+		case dt.DiffTypeEqual:
+			// Unchanged resources aren't counted.
+		}
+	}
+
+	return s
+}
+
+// formatSummary returns the "Summary: N added, M modified, K removed" line for s, leaving out
+// zero counts, or an empty string if nothing changed.
+func formatSummary(s Summary) string {
+	var parts []string
+
+	if s.Added > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", s.Added))
+	}
+
+	if s.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", s.Modified))
+	}
+
+	if s.Removed > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", s.Removed))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "Summary: " + strings.Join(parts, ", ")
+}
+
+// formatRequiredResources lists the required resources recorded for an XR, with how each matched.
+func formatRequiredResources(diff *dt.ResourceDiff) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Required resources of %s:", getKindName(diff))
+
+	for _, res := range diff.RequiredResources {
+		name := res.Name
+		if res.Namespace != "" {
+			name = res.Namespace + "/" + name
+		}
+
+		fmt.Fprintf(&b, "\n  - %s %s %s (matched by %s)", res.APIVersion, res.Kind, name, res.Match)
+	}
+
+	return b.String()
+}
+
+// formatWarnings renders API server warnings as an indented "Warnings:" list.
+func formatWarnings(warnings []string) string {
+	var b strings.Builder
+
+	b.WriteString("Warnings:")
+
+	for _, w := range warnings {
+		b.WriteString("\n  - ")
+		b.WriteString(w)
+	}
+
+	return b.String()
+}
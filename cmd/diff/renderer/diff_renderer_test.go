@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
@@ -42,6 +43,17 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 		},
 	}
 
+	ownedRemovedDiff := &dt.ResourceDiff{
+		Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "TestResource"},
+		ResourceName: "child-resource",
+		DiffType:     dt.DiffTypeRemoved,
+		LineDiffs: []diffmatchpatch.Diff{
+			{Type: diffmatchpatch.DiffDelete, Text: "apiVersion: example.org/v1\nkind: TestResource\nmetadata:\n  name: child-resource"},
+		},
+		Owner:         removedDiff.GetDiffKey(),
+		RemovalReason: dt.RemovalReasonOwnerRemoved,
+	}
+
 	equalDiff := &dt.ResourceDiff{
 		Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "TestResource"},
 		ResourceName: "equal-resource",
@@ -49,6 +61,14 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 		LineDiffs:    []diffmatchpatch.Diff{},
 	}
 
+	warnedDiff := &dt.ResourceDiff{
+		Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "TestResource"},
+		ResourceName: "warned-resource",
+		DiffType:     dt.DiffTypeModified,
+		LineDiffs:    modifiedDiff.LineDiffs,
+		Warnings:     []string{"example.org/v1 TestResource is deprecated"},
+	}
+
 	tests := map[string]struct {
 		diffs           map[string]*dt.ResourceDiff
 		options         DiffOptions
@@ -74,7 +94,7 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 			expectedOutputs: []string{
 				"+++ TestResource/added-resource",
 				"--- TestResource/removed-resource",
-				"~~~ TestResource/modified-resource",
+				"~~~ TestResource/modified-resource (+2 -2)\n",
 				"+ apiVersion: example.org/v1",
 				"- spec:",
 				"-   field: old-value",
@@ -85,6 +105,80 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 				"TestResource/equal-resource", // Equal resources should not be rendered
 			},
 		},
+		"RemovedOwnerChain": {
+			diffs: map[string]*dt.ResourceDiff{
+				ownedRemovedDiff.GetDiffKey(): ownedRemovedDiff,
+				removedDiff.GetDiffKey():      removedDiff,
+			},
+			options: DiffOptions{
+				UseColors:     false,
+				AddPrefix:     "+ ",
+				DeletePrefix:  "- ",
+				ContextPrefix: "  ",
+			},
+			expectedOutputs: []string{
+				"--- TestResource/removed-resource\n",
+				"  field: value\n\n---\n  --- TestResource/child-resource (owner-removed, owned by TestResource/removed-resource)\n  - apiVersion: example.org/v1\n",
+			},
+		},
+		"RevisionInHeader": {
+			diffs: map[string]*dt.ResourceDiff{
+				"xr": {
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XNopResource"},
+					ResourceName: "test-resource",
+					DiffType:     dt.DiffTypeModified,
+					LineDiffs:    modifiedDiff.LineDiffs,
+					Revision:     &dt.RevisionRef{Name: "xnopresources.diff.example.org-abc123", Number: 2},
+				},
+			},
+			options: DiffOptions{
+				UseColors:     false,
+				AddPrefix:     "+ ",
+				DeletePrefix:  "- ",
+				ContextPrefix: "  ",
+			},
+			expectedOutputs: []string{
+				"~~~ XNopResource/test-resource (revision xnopresources.diff.example.org-abc123, #2) (+2 -2)\n",
+			},
+		},
+		"RequiredResourcesOfUnchangedXR": {
+			diffs: map[string]*dt.ResourceDiff{
+				"xr": {
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XNopResource"},
+					ResourceName: "test-resource",
+					DiffType:     dt.DiffTypeEqual,
+					RequiredResources: []dt.RequiredResource{
+						{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings", Match: "name"},
+						{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "viewer", Match: "labels team=a"},
+					},
+				},
+			},
+			options: DiffOptions{
+				UseColors: false,
+			},
+			expectedOutputs: []string{
+				"Required resources of XNopResource/test-resource:\n" +
+					"  - v1 ConfigMap default/settings (matched by name)\n" +
+					"  - rbac.authorization.k8s.io/v1 ClusterRole viewer (matched by labels team=a)\n---\n",
+			},
+			notExpected: []string{"~~~"},
+		},
+		"LabelsDiffOnlyStillSummarized": {
+			diffs: map[string]*dt.ResourceDiff{
+				"spec-only": {
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "TestResource"},
+					ResourceName: "spec-only-resource",
+					DiffType:     dt.DiffTypeModified,
+				},
+			},
+			options: DiffOptions{
+				UseColors:          false,
+				Quiet:              true,
+				ShowLabelsDiffOnly: true,
+			},
+			expectedOutputs: []string{"Summary: 1 modified"},
+			notExpected:     []string{"~~~", NoChangesMessage},
+		},
 		"CompactMode": {
 			diffs: map[string]*dt.ResourceDiff{
 				modifiedDiff.GetDiffKey(): modifiedDiff,
@@ -110,6 +204,23 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 				"  metadata:",
 			},
 		},
+		"Warnings": {
+			diffs: map[string]*dt.ResourceDiff{
+				warnedDiff.GetDiffKey(): warnedDiff,
+			},
+			options: DiffOptions{
+				UseColors:      false,
+				AddPrefix:      "+ ",
+				DeletePrefix:   "- ",
+				ContextPrefix:  "  ",
+				ContextLines:   3,
+				ChunkSeparator: "...",
+			},
+			expectedOutputs: []string{
+				"~~~ TestResource/warned-resource",
+				"+   field: new-value\nWarnings:\n  - example.org/v1 TestResource is deprecated\n---",
+			},
+		},
 		"EmptyDiffs": {
 			diffs: map[string]*dt.ResourceDiff{},
 			options: DiffOptions{
@@ -139,6 +250,39 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 			expectedOutputs: []string{},
 			notExpected:     []string{"TestResource/equal-resource"},
 		},
+		"QuietNoChanges": {
+			diffs: map[string]*dt.ResourceDiff{
+				equalDiff.GetDiffKey(): equalDiff,
+			},
+			options: DiffOptions{
+				UseColors:      false,
+				AddPrefix:      "+ ",
+				DeletePrefix:   "- ",
+				ContextPrefix:  "  ",
+				ContextLines:   3,
+				ChunkSeparator: "...",
+				Quiet:          true,
+			},
+			expectedOutputs: []string{"No changes."},
+			notExpected:     []string{"TestResource/equal-resource", "Summary:"},
+		},
+		"QuietWithChanges": {
+			diffs: map[string]*dt.ResourceDiff{
+				modifiedDiff.GetDiffKey(): modifiedDiff,
+				equalDiff.GetDiffKey():    equalDiff,
+			},
+			options: DiffOptions{
+				UseColors:      false,
+				AddPrefix:      "+ ",
+				DeletePrefix:   "- ",
+				ContextPrefix:  "  ",
+				ContextLines:   3,
+				ChunkSeparator: "...",
+				Quiet:          true,
+			},
+			expectedOutputs: []string{"~~~ TestResource/modified-resource", "Summary: 1 modified"},
+			notExpected:     []string{"No changes."},
+		},
 		"SummaryOutput": {
 			diffs: map[string]*dt.ResourceDiff{
 				addedDiff.GetDiffKey():    addedDiff,
@@ -158,6 +302,41 @@ func TestDefaultDiffRenderer_RenderDiffs(t *testing.T) {
 				"Summary:", "1 added", "1 modified", "1 removed",
 			},
 		},
+		"SummaryOnly": {
+			diffs: map[string]*dt.ResourceDiff{
+				addedDiff.GetDiffKey():    addedDiff,
+				modifiedDiff.GetDiffKey(): modifiedDiff,
+				removedDiff.GetDiffKey():  removedDiff,
+				equalDiff.GetDiffKey():    equalDiff,
+			},
+			options: DiffOptions{
+				UseColors:   false,
+				SummaryOnly: true,
+			},
+			expectedOutputs: []string{"Summary: 1 added, 1 modified, 1 removed\n"},
+			notExpected:     []string{"+++", "~~~", "---", "field: new-value", "\nSummary:"},
+		},
+		"SummaryOnlyNoChanges": {
+			diffs: map[string]*dt.ResourceDiff{
+				equalDiff.GetDiffKey(): equalDiff,
+			},
+			options: DiffOptions{
+				UseColors:   false,
+				SummaryOnly: true,
+			},
+			expectedOutputs: []string{"No changes."},
+			notExpected:     []string{"Summary:"},
+		},
+		"Provenance": {
+			diffs: map[string]*dt.ResourceDiff{
+				addedDiff.GetDiffKey(): addedDiff,
+			},
+			options: DiffOptions{
+				UseColors:  false,
+				Provenance: &Provenance{Server: "https://127.0.0.1:6443", Timestamp: time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC)},
+			},
+			expectedOutputs: []string{"Provenance:\n  server: https://127.0.0.1:6443\n  timestamp: 2026-01-10T11:00:00Z\n---\n+++ "},
+		},
 	}
 
 	for name, tt := range tests {
@@ -0,0 +1,104 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// dotFillColors are the node fill colors of each diff type in the DOT graph.
+//
+//nolint:gochecknoglobals // read-only color table
+var dotFillColors = map[dt.DiffType]string{
+	dt.DiffTypeAdded:    "palegreen",
+	dt.DiffTypeModified: "khaki",
+	dt.DiffTypeRemoved:  "lightcoral",
+	dt.DiffTypeEqual:    "lightgrey",
+}
+
+// DOTDiffRenderer renders the XRs and the resources they compose as a Graphviz DOT graph, so the
+// blast radius of a change can be seen at a glance.
+type DOTDiffRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+}
+
+// NewDOTDiffRenderer creates a new DOTDiffRenderer.
+func NewDOTDiffRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	return &DOTDiffRenderer{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// RenderDiffs writes a digraph to stdout with one node per resource, filled by diff type, and an
+// edge from each resource's owner to it. The owner is the composed resource named by
+// ResourceDiff.Owner when that is in the graph, and otherwise the XR named by
+// ResourceDiff.Composite. Unchanged resources are included so the tree stays connected. Errors go
+// to stderr.
+func (r *DOTDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	r.logger.Debug("Rendering diffs as DOT",
+		"diffCount", len(diffs),
+		"errorCount", len(errs))
+
+	keys := slices.Sorted(maps.Keys(diffs))
+
+	if err := writeDOT(r.opts.Stdout, keys, diffs); err != nil {
+		return errors.Wrap(err, "failed to write DOT output")
+	}
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	return nil
+}
+
+// writeDOT writes the graph of diffs, visiting nodes and edges in the order of keys.
+func writeDOT(w io.Writer, keys []string, diffs map[string]*dt.ResourceDiff) error {
+	if _, err := fmt.Fprint(w, "digraph crossplane_diff {\n  rankdir=LR;\n  node [shape=box, style=filled];\n"); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		diff := diffs[key]
+		label := getKindName(diff) + "\n" + diff.DiffType.ToWord()
+
+		if _, err := fmt.Fprintf(w, "  %q [label=%q, fillcolor=%q];\n", key, label, dotFillColors[diff.DiffType]); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		parent := dotParent(diffs, diffs[key])
+		if parent == "" {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "  %q -> %q;\n", parent, key); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprint(w, "}\n")
+
+	return err
+}
+
+// dotParent returns the diff key of the node diff hangs off, or an empty string for a top-level
+// resource.
+func dotParent(diffs map[string]*dt.ResourceDiff, diff *dt.ResourceDiff) string {
+	if _, ok := diffs[diff.Owner]; ok && diff.Owner != "" {
+		return diff.Owner
+	}
+
+	return diff.Composite
+}
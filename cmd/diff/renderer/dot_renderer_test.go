@@ -0,0 +1,136 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDOTDiffRenderer_RenderDiffs(t *testing.T) {
+	header := "digraph crossplane_diff {\n  rankdir=LR;\n  node [shape=box, style=filled];\n"
+
+	xrKey := "example.org/v1/XApp/default/app"
+	nestedKey := "example.org/v1/XDatabase/default/db"
+	bucketKey := "s3.aws.upbound.io/v1beta1/Bucket/default/bucket"
+	instanceKey := "rds.aws.upbound.io/v1beta1/Instance/default/instance"
+	policyKey := "iam.aws.upbound.io/v1beta1/Policy//policy"
+
+	tests := map[string]struct {
+		reason     string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+	}{
+		"NoDiffs": {
+			reason:     "Should write an empty graph when there are no diffs.",
+			diffs:      map[string]*dt.ResourceDiff{},
+			wantStdout: header + "}\n",
+		},
+		"NestedXR": {
+			reason: "Should draw a node per resource colored by diff type, with edges from each XR to what it composed and from a removed resource's owner to it.",
+			diffs: map[string]*dt.ResourceDiff{
+				xrKey: {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "app",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XApp"},
+				},
+				nestedKey: {
+					DiffType:     dt.DiffTypeModified,
+					ResourceName: "db",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"},
+					Composite:    xrKey,
+				},
+				bucketKey: {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "bucket",
+					Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+					Composite:    xrKey,
+				},
+				instanceKey: {
+					DiffType:     dt.DiffTypeRemoved,
+					ResourceName: "instance",
+					Gvk:          schema.GroupVersionKind{Group: "rds.aws.upbound.io", Version: "v1beta1", Kind: "Instance"},
+					Composite:    nestedKey,
+				},
+				policyKey: {
+					DiffType:     dt.DiffTypeRemoved,
+					ResourceName: "policy",
+					Gvk:          schema.GroupVersionKind{Group: "iam.aws.upbound.io", Version: "v1beta1", Kind: "Policy"},
+					Owner:        instanceKey,
+					Composite:    xrKey,
+				},
+			},
+			wantStdout: header +
+				`  "example.org/v1/XApp/default/app" [label="XApp/app\nequal", fillcolor="lightgrey"];` + "\n" +
+				`  "example.org/v1/XDatabase/default/db" [label="XDatabase/db\nmodified", fillcolor="khaki"];` + "\n" +
+				`  "iam.aws.upbound.io/v1beta1/Policy//policy" [label="Policy/policy\nremoved", fillcolor="lightcoral"];` + "\n" +
+				`  "rds.aws.upbound.io/v1beta1/Instance/default/instance" [label="Instance/instance\nremoved", fillcolor="lightcoral"];` + "\n" +
+				`  "s3.aws.upbound.io/v1beta1/Bucket/default/bucket" [label="Bucket/bucket\nadded", fillcolor="palegreen"];` + "\n" +
+				`  "example.org/v1/XApp/default/app" -> "example.org/v1/XDatabase/default/db";` + "\n" +
+				`  "rds.aws.upbound.io/v1beta1/Instance/default/instance" -> "iam.aws.upbound.io/v1beta1/Policy//policy";` + "\n" +
+				`  "example.org/v1/XDatabase/default/db" -> "rds.aws.upbound.io/v1beta1/Instance/default/instance";` + "\n" +
+				`  "example.org/v1/XApp/default/app" -> "s3.aws.upbound.io/v1beta1/Bucket/default/bucket";` + "\n" +
+				"}\n",
+		},
+		"OwnerNotInGraph": {
+			reason: "Should fall back to the composing XR when a removed resource's owner isn't in the graph.",
+			diffs: map[string]*dt.ResourceDiff{
+				xrKey: {
+					DiffType:     dt.DiffTypeModified,
+					ResourceName: "app",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XApp"},
+				},
+				policyKey: {
+					DiffType:     dt.DiffTypeRemoved,
+					ResourceName: "policy",
+					Gvk:          schema.GroupVersionKind{Group: "iam.aws.upbound.io", Version: "v1beta1", Kind: "Policy"},
+					Owner:        instanceKey,
+					Composite:    xrKey,
+				},
+			},
+			wantStdout: header +
+				`  "example.org/v1/XApp/default/app" [label="XApp/app\nmodified", fillcolor="khaki"];` + "\n" +
+				`  "iam.aws.upbound.io/v1beta1/Policy//policy" [label="Policy/policy\nremoved", fillcolor="lightcoral"];` + "\n" +
+				`  "example.org/v1/XApp/default/app" -> "iam.aws.upbound.io/v1beta1/Policy//policy";` + "\n" +
+				"}\n",
+		},
+		"ErrorsToStderr": {
+			reason: "Should write errors to stderr, keeping stdout a valid graph.",
+			diffs:  map[string]*dt.ResourceDiff{},
+			errs: []dt.OutputError{
+				{ResourceID: "XApp/app", Message: "cannot find composition"},
+			},
+			wantStdout: header + "}\n",
+			wantStderr: "ERROR: XApp/app: cannot find composition\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatDOT
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			err := NewDOTDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs)
+			if err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// githubAnnotationTitle is the title of every annotation, shown above the message on the PR.
+const githubAnnotationTitle = "crossplane-diff"
+
+// GitHubDiffRenderer prints a GitHub Actions workflow command for every changed resource, so the
+// changes show up as annotations on the workflow run and PR, followed by the human-readable diff
+// outside quiet mode.
+type GitHubDiffRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+	body   DiffRenderer
+}
+
+// NewGitHubDiffRenderer creates a new GitHubDiffRenderer.
+func NewGitHubDiffRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	return &GitHubDiffRenderer{
+		logger: logger,
+		opts:   opts,
+		body:   NewDiffRenderer(logger, opts),
+	}
+}
+
+// RenderDiffs writes one annotation per added (::notice), modified (::warning), or removed
+// (::error) resource to stdout, in the same order as the diff, then renders the diff itself
+// unless DiffOptions.Quiet is set. Unchanged resources get no annotation.
+func (r *GitHubDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	d := sortedDiffs(diffs)
+
+	annotations := 0
+
+	for _, diff := range d {
+		var level string
+
+		switch diff.DiffType {
+		case dt.DiffTypeAdded:
+			level = "notice"
+		case dt.DiffTypeModified:
+			level = "warning"
+		case dt.DiffTypeRemoved:
+			level = "error"
+		case dt.DiffTypeEqual:
+			continue
+		}
+
+		if _, err := fmt.Fprintf(r.opts.Stdout, "::%s title=%s::%s\n", level, githubAnnotationTitle, escapeGitHubData(githubAnnotationMessage(diff))); err != nil {
+			return errors.Wrapf(err, "failed to write annotation for %s", getKindName(diff))
+		}
+
+		annotations++
+	}
+
+	r.logger.Debug("Rendered GitHub annotations", "diffCount", len(diffs), "annotationCount", annotations)
+
+	if !r.opts.Quiet {
+		return r.body.RenderDiffs(diffs, errs)
+	}
+
+	// Quiet mode prints the annotations alone.
+	if annotations == 0 && len(errs) == 0 {
+		if _, err := fmt.Fprintln(r.opts.Stdout, NoChangesMessage); err != nil {
+			return errors.Wrap(err, "failed to write no changes message")
+		}
+	}
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	return nil
+}
+
+// githubAnnotationMessage describes a changed resource, e.g.
+// "s3.aws.upbound.io/v1beta1 Bucket/my-bucket in namespace team-a will be modified".
+func githubAnnotationMessage(diff *dt.ResourceDiff) string {
+	msg := fmt.Sprintf("%s %s", diff.Gvk.GroupVersion(), getKindName(diff))
+	if diff.Namespace != "" {
+		msg += " in namespace " + diff.Namespace
+	}
+
+	return fmt.Sprintf("%s will be %s", msg, diff.DiffType.ToWord())
+}
+
+// escapeGitHubData escapes the characters a workflow command message can't contain verbatim.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
@@ -0,0 +1,115 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGitHubDiffRenderer_RenderDiffs(t *testing.T) {
+	bucketGVK := schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	lines := []diffmatchpatch.Diff{{Type: diffmatchpatch.DiffInsert, Text: "spec: {}\n"}}
+
+	tests := map[string]struct {
+		reason          string
+		diffs           map[string]*dt.ResourceDiff
+		quiet           bool
+		wantAnnotations []string
+		wantBody        string
+	}{
+		"LevelPerDiffType": {
+			reason: "Added resources should be notices, modified warnings, and removed errors; unchanged ones get none.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Gvk: bucketGVK, ResourceName: "a", DiffType: dt.DiffTypeAdded, LineDiffs: lines},
+				"b": {Gvk: bucketGVK, ResourceName: "b", Namespace: "team-a", DiffType: dt.DiffTypeModified, LineDiffs: lines},
+				"c": {Gvk: bucketGVK, ResourceName: "c", DiffType: dt.DiffTypeRemoved, LineDiffs: lines},
+				"d": {Gvk: bucketGVK, ResourceName: "d", DiffType: dt.DiffTypeEqual},
+			},
+			wantAnnotations: []string{
+				"::notice title=crossplane-diff::s3.aws.upbound.io/v1beta1 Bucket/a will be added",
+				"::warning title=crossplane-diff::s3.aws.upbound.io/v1beta1 Bucket/b in namespace team-a will be modified",
+				"::error title=crossplane-diff::s3.aws.upbound.io/v1beta1 Bucket/c will be removed",
+			},
+			wantBody: "Summary:",
+		},
+		"QuietAnnotationsOnly": {
+			reason: "Quiet mode should print the annotations without the diff body.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Gvk: bucketGVK, ResourceName: "a", DiffType: dt.DiffTypeAdded, LineDiffs: lines},
+			},
+			quiet: true,
+			wantAnnotations: []string{
+				"::notice title=crossplane-diff::s3.aws.upbound.io/v1beta1 Bucket/a will be added",
+			},
+		},
+		"QuietNoChanges": {
+			reason: "Quiet mode should print the no changes line when there is nothing to annotate.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Gvk: bucketGVK, ResourceName: "a", DiffType: dt.DiffTypeEqual},
+			},
+			quiet:    true,
+			wantBody: "No changes.\n",
+		},
+		"Escaped": {
+			reason: "Characters that end or corrupt a workflow command should be escaped.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Gvk: bucketGVK, ResourceName: "100%", DiffType: dt.DiffTypeAdded, LineDiffs: lines},
+			},
+			wantAnnotations: []string{
+				"::notice title=crossplane-diff::s3.aws.upbound.io/v1beta1 Bucket/100%25 will be added",
+			},
+			wantBody: "Summary:",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.Quiet = tt.quiet
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			if err := NewGitHubDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, nil); err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			var (
+				gotAnnotations []string
+				body           strings.Builder
+			)
+
+			for line := range strings.Lines(stdout.String()) {
+				if strings.HasPrefix(line, "::") {
+					gotAnnotations = append(gotAnnotations, strings.TrimSuffix(line, "\n"))
+					continue
+				}
+
+				body.WriteString(line)
+			}
+
+			if diff := cmp.Diff(tt.wantAnnotations, gotAnnotations); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want annotations, +got annotations:\n%s", tt.reason, diff)
+			}
+
+			if !strings.HasPrefix(stdout.String(), strings.Join(tt.wantAnnotations, "\n")) {
+				t.Errorf("\n%s\nRenderDiffs(...): annotations should come before the diff:\n%s", tt.reason, stdout.String())
+			}
+
+			switch {
+			case tt.wantBody == "" && body.Len() > 0:
+				t.Errorf("\n%s\nRenderDiffs(...): want no diff body, got:\n%s", tt.reason, body.String())
+			case !strings.Contains(body.String(), tt.wantBody):
+				t.Errorf("\n%s\nRenderDiffs(...): want %q in the diff body, got:\n%s", tt.reason, tt.wantBody, body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package renderer
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// InspectDiffRenderer prints the observed and desired objects of a single resource, selected by
+// DiffOptions.Inspect, instead of a diff. It is a debugging aid for understanding why a resource
+// diffs the way it does.
+type InspectDiffRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+}
+
+// NewInspectDiffRenderer creates a new InspectDiffRenderer.
+func NewInspectDiffRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	return &InspectDiffRenderer{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// RenderDiffs writes the observed and desired objects of every diff matching DiffOptions.Inspect
+// to stdout as YAML. The objects are the normalized views the diff was computed from; unchanged
+// resources only carry the objects as fetched and rendered. Errors go to stderr. It returns an
+// error if no diff matches.
+func (r *InspectDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	r.logger.Debug("Rendering inspected resource",
+		"resource", r.opts.Inspect,
+		"diffCount", len(diffs))
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	var matched []*dt.ResourceDiff
+
+	for _, diff := range diffs {
+		if getKindName(diff) == r.opts.Inspect {
+			matched = append(matched, diff)
+		}
+	}
+
+	if len(matched) == 0 {
+		return errors.Errorf("resource %s not found among the diffed resources", r.opts.Inspect)
+	}
+
+	// The same Kind/name can exist in more than one namespace.
+	slices.SortFunc(matched, func(a, b *dt.ResourceDiff) int {
+		return cmp.Compare(a.Namespace, b.Namespace)
+	})
+
+	for i, diff := range matched {
+		if i > 0 {
+			if _, err := fmt.Fprintln(r.opts.Stdout, "---"); err != nil {
+				return errors.Wrap(err, "failed to write document separator")
+			}
+		}
+
+		if err := writeInspectedObject(r.opts.Stdout, "Observed", diff, diff.Current); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(r.opts.Stdout, "---"); err != nil {
+			return errors.Wrap(err, "failed to write document separator")
+		}
+
+		if err := writeInspectedObject(r.opts.Stdout, "Desired", diff, diff.Desired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeInspectedObject writes one side of an inspected diff as a YAML document headed by a
+// comment naming the side, resource, and diff type. A missing side (an added resource has
+// nothing observed, a removed one nothing desired) is written as a null document.
+func writeInspectedObject(w io.Writer, side string, diff *dt.ResourceDiff, views dt.ResourceViews) error {
+	header := fmt.Sprintf("# %s: %s", side, getKindName(diff))
+	if diff.Namespace != "" {
+		header += fmt.Sprintf(" (namespace: %s)", diff.Namespace)
+	}
+
+	header += fmt.Sprintf(" [%s]", diff.DiffType.ToWord())
+
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return errors.Wrapf(err, "failed to write %s header", side)
+	}
+
+	obj := views.Clean
+	if obj == nil {
+		obj = views.Raw
+	}
+
+	if obj == nil {
+		_, err := fmt.Fprintln(w, "null")
+		return errors.Wrapf(err, "failed to write %s object", side)
+	}
+
+	data, err := sigsyaml.Marshal(obj.Object)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal %s object to YAML", side)
+	}
+
+	_, err = w.Write(data)
+
+	return errors.Wrapf(err, "failed to write %s object", side)
+}
@@ -0,0 +1,144 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestInspectDiffRenderer_RenderDiffs(t *testing.T) {
+	bucketGVK := schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+
+	observed := &un.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "my-bucket"},
+		"spec":       map[string]any{"region": "us-east-1"},
+	}}
+	desired := &un.Unstructured{Object: map[string]any{
+		"apiVersion": "s3.aws.upbound.io/v1beta1",
+		"kind":       "Bucket",
+		"metadata":   map[string]any{"name": "my-bucket"},
+		"spec":       map[string]any{"region": "us-west-2"},
+	}}
+
+	tests := map[string]struct {
+		reason     string
+		inspect    string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+		wantErr    bool
+	}{
+		"Modified": {
+			reason:  "Should print the clean observed and desired objects of the matching resource only.",
+			inspect: "Bucket/my-bucket",
+			diffs: map[string]*dt.ResourceDiff{
+				"bucket": {
+					Gvk:          bucketGVK,
+					ResourceName: "my-bucket",
+					DiffType:     dt.DiffTypeModified,
+					Current:      dt.ResourceViews{Raw: &un.Unstructured{}, Clean: observed},
+					Desired:      dt.ResourceViews{Raw: &un.Unstructured{}, Clean: desired},
+				},
+				"other": {
+					Gvk:          bucketGVK,
+					ResourceName: "other-bucket",
+					DiffType:     dt.DiffTypeModified,
+					Current:      dt.ResourceViews{Clean: observed},
+					Desired:      dt.ResourceViews{Clean: desired},
+				},
+			},
+			wantStdout: `# Observed: Bucket/my-bucket [modified]
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: my-bucket
+spec:
+  region: us-east-1
+---
+# Desired: Bucket/my-bucket [modified]
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: my-bucket
+spec:
+  region: us-west-2
+`,
+		},
+		"AddedUsesRawWhenNotCleaned": {
+			reason:  "An added resource should print a null observed object, and fall back to the raw view.",
+			inspect: "Bucket/my-bucket",
+			diffs: map[string]*dt.ResourceDiff{
+				"bucket": {
+					Gvk:          bucketGVK,
+					Namespace:    "default",
+					ResourceName: "my-bucket",
+					DiffType:     dt.DiffTypeAdded,
+					Desired:      dt.ResourceViews{Raw: desired},
+				},
+			},
+			errs: []dt.OutputError{
+				{ResourceID: "XBucket/other", Message: "cannot find composition"},
+			},
+			wantStdout: `# Observed: Bucket/my-bucket (namespace: default) [added]
+null
+---
+# Desired: Bucket/my-bucket (namespace: default) [added]
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: my-bucket
+spec:
+  region: us-west-2
+`,
+			wantStderr: "ERROR: XBucket/other: cannot find composition\n",
+		},
+		"NotFound": {
+			reason:  "Should return an error when no resource matches.",
+			inspect: "Bucket/missing",
+			diffs: map[string]*dt.ResourceDiff{
+				"bucket": {Gvk: bucketGVK, ResourceName: "my-bucket", DiffType: dt.DiffTypeEqual},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Inspect = tt.inspect
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			err := NewInspectDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("\n%s\nRenderDiffs(...): expected error, got nil", tt.reason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package renderer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// junitSuitesName is the name of the root <testsuites> element.
+const junitSuitesName = "crossplane-diff"
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the testcases of one input file or XR.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is one resource. It passes when it has neither a failure nor an error.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+// junitProblem is the body of a <failure> or <error> element.
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitDiffRenderer renders a JUnit XML report with one testcase per resource, so CI systems that
+// gate on test reports can treat "no changes" as a passing test.
+type JUnitDiffRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+}
+
+// NewJUnitDiffRenderer creates a new JUnitDiffRenderer. Failure text is always a unified,
+// uncolored diff.
+func NewJUnitDiffRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	opts.UseColors = false
+	opts.Style = DiffStyleUnified
+
+	return &JUnitDiffRenderer{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// RenderDiffs writes a JUnit XML report to stdout. Unchanged resources are passing testcases,
+// added, modified, and removed ones are failures carrying their diff, and each processing error
+// is an errored testcase. Testcases are grouped into a testsuite per input file, or per top-level
+// XR when the file isn't known. Errors are also written to stderr.
+func (r *JUnitDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	r.logger.Debug("Rendering diffs as JUnit",
+		"diffCount", len(diffs),
+		"errorCount", len(errs))
+
+	suites := make(map[string]*junitTestSuite)
+	suite := func(name string) *junitTestSuite {
+		if _, ok := suites[name]; !ok {
+			suites[name] = &junitTestSuite{Name: name}
+		}
+
+		return suites[name]
+	}
+
+	// Sort the same way as the human-readable diff
+	d := sortedDiffs(diffs)
+
+	for _, diff := range d {
+		s := suite(junitSuiteName(diffs, diff))
+		tc := junitTestCase{Name: getKindName(diff), ClassName: diff.Gvk.GroupVersion().String()}
+
+		if diff.DiffType != dt.DiffTypeEqual {
+			tc.Failure = &junitProblem{
+				Message: fmt.Sprintf("%s would be %s", getKindName(diff), diff.DiffType.ToWord()),
+				Type:    diff.DiffType.ToWord(),
+				Text:    r.failureText(diff),
+			}
+			s.Failures++
+		}
+
+		s.TestCases = append(s.TestCases, tc)
+	}
+
+	for _, e := range errs {
+		name := e.ResourceID
+		if name == "" {
+			name = "<global>"
+		}
+
+		s := suite(name)
+		s.TestCases = append(s.TestCases, junitTestCase{
+			Name:  name,
+			Error: &junitProblem{Message: e.Message, Type: "error", Text: e.FormatError()},
+		})
+		s.Errors++
+	}
+
+	report := junitTestSuites{Name: junitSuitesName}
+
+	for _, name := range slices.Sorted(maps.Keys(suites)) {
+		s := suites[name]
+		s.Tests = len(s.TestCases)
+
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		report.Errors += s.Errors
+		report.Suites = append(report.Suites, *s)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal JUnit report")
+	}
+
+	if _, err := fmt.Fprintf(r.opts.Stdout, "%s%s\n", xml.Header, data); err != nil {
+		return errors.Wrap(err, "failed to write JUnit report")
+	}
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	return nil
+}
+
+// failureText returns the diff of a changed resource followed by any warnings recorded for it.
+func (r *JUnitDiffRenderer) failureText(diff *dt.ResourceDiff) string {
+	text := strings.TrimSuffix(FormatDiff(diff.LineDiffs, r.opts), "\n")
+	if len(diff.Warnings) > 0 {
+		text += "\n" + formatWarnings(diff.Warnings)
+	}
+
+	return text
+}
+
+// junitSuiteName returns the input file diff came from or, when that isn't known, the Kind/name
+// of the top-level XR that composed it.
+func junitSuiteName(diffs map[string]*dt.ResourceDiff, diff *dt.ResourceDiff) string {
+	if diff.SourceFile != "" {
+		return diff.SourceFile
+	}
+
+	root := diff
+	for root.Composite != "" {
+		parent, ok := diffs[root.Composite]
+		if !ok {
+			return dt.KindNameFromDiffKey(root.Composite)
+		}
+
+		root = parent
+	}
+
+	return getKindName(root)
+}
@@ -0,0 +1,133 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestJUnitDiffRenderer_RenderDiffs(t *testing.T) {
+	xrKey := "example.org/v1/XBucket/default/my-xr"
+
+	tests := map[string]struct {
+		reason     string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+	}{
+		"NoDiffs": {
+			reason:     "Should write an empty report when there are no diffs.",
+			diffs:      map[string]*dt.ResourceDiff{},
+			wantStdout: xml.Header + `<testsuites name="crossplane-diff" tests="0" failures="0" errors="0"></testsuites>` + "\n",
+		},
+		"GroupedBySourceFile": {
+			reason: "Should pass unchanged resources, fail changed ones with their diff, and group them by input file.",
+			diffs: map[string]*dt.ResourceDiff{
+				xrKey: {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "my-xr",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"},
+					SourceFile:   "xrs/bucket.yaml",
+				},
+				"bucket": {
+					DiffType:     dt.DiffTypeModified,
+					ResourceName: "my-bucket",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffDelete, Text: "  region: us-east-1\n"},
+						{Type: diffmatchpatch.DiffInsert, Text: "  region: us-west-2"},
+					},
+					SourceFile: "xrs/bucket.yaml",
+					Composite:  xrKey,
+				},
+			},
+			wantStdout: xml.Header + `<testsuites name="crossplane-diff" tests="2" failures="1" errors="0">
+  <testsuite name="xrs/bucket.yaml" tests="2" failures="1" errors="0">
+    <testcase name="Bucket/my-bucket" classname="s3.aws.upbound.io/v1beta1">
+      <failure message="Bucket/my-bucket would be modified" type="modified">-   region: us-east-1&#xA;+   region: us-west-2</failure>
+    </testcase>
+    <testcase name="XBucket/my-xr" classname="example.org/v1"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		"GroupedByXR": {
+			reason: "Should group resources under their top-level XR when the input file isn't known, and escape the diff.",
+			diffs: map[string]*dt.ResourceDiff{
+				xrKey: {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "my-xr",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"},
+				},
+				"cm": {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "cm",
+					Gvk:          schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffInsert, Text: `data: "<a & b>"`},
+					},
+					Composite: xrKey,
+				},
+			},
+			wantStdout: xml.Header + `<testsuites name="crossplane-diff" tests="2" failures="1" errors="0">
+  <testsuite name="XBucket/my-xr" tests="2" failures="1" errors="0">
+    <testcase name="ConfigMap/cm" classname="v1">
+      <failure message="ConfigMap/cm would be added" type="added">+ data: &#34;&lt;a &amp; b&gt;&#34;</failure>
+    </testcase>
+    <testcase name="XBucket/my-xr" classname="example.org/v1"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		"Errors": {
+			reason: "Should report each processing error as an errored testcase and write it to stderr.",
+			diffs:  map[string]*dt.ResourceDiff{},
+			errs: []dt.OutputError{
+				{ResourceID: "XBucket/my-xr", Message: "cannot find composition"},
+			},
+			wantStdout: xml.Header + `<testsuites name="crossplane-diff" tests="1" failures="0" errors="1">
+  <testsuite name="XBucket/my-xr" tests="1" failures="0" errors="1">
+    <testcase name="XBucket/my-xr" classname="">
+      <error message="cannot find composition" type="error">ERROR: XBucket/my-xr: cannot find composition</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+			wantStderr: "ERROR: XBucket/my-xr: cannot find composition\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatJUnit
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			err := NewJUnitDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs)
+			if err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package renderer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// MarkdownDiffRenderer renders a Markdown report of the changes, suitable for posting as a PR
+// comment: a table of the changed resources followed by a collapsible section per resource
+// holding its diff.
+type MarkdownDiffRenderer struct {
+	logger logging.Logger
+	opts   DiffOptions
+}
+
+// NewMarkdownDiffRenderer creates a new MarkdownDiffRenderer. Diff bodies are always unified
+// and uncolored so they highlight as diff code blocks.
+func NewMarkdownDiffRenderer(logger logging.Logger, opts DiffOptions) DiffRenderer {
+	opts.UseColors = false
+	opts.Style = DiffStyleUnified
+
+	return &MarkdownDiffRenderer{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// RenderDiffs writes a summary table of the added, modified, and removed resources, the summary
+// line, and a <details> section per resource with its diff in a fenced diff block to stdout.
+// Unchanged resources are skipped, and a single NoChangesMessage line is written when nothing
+// changed. Errors go to stderr.
+func (r *MarkdownDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff, errs []dt.OutputError) error {
+	r.logger.Debug("Rendering diffs as Markdown",
+		"diffCount", len(diffs),
+		"errorCount", len(errs))
+
+	// Sort the same way as the human-readable diff
+	d := sortedDiffs(diffs)
+
+	d = slices.DeleteFunc(d, func(diff *dt.ResourceDiff) bool {
+		return diff.DiffType == dt.DiffTypeEqual
+	})
+
+	var b strings.Builder
+
+	if len(d) == 0 {
+		b.WriteString(NoChangesMessage + "\n")
+	} else {
+		writeMarkdownTable(&b, d)
+		fmt.Fprintf(&b, "\n%s\n", formatSummary(summarize(diffs)))
+
+		for _, diff := range d {
+			b.WriteString("\n" + r.markdownSection(diff))
+		}
+	}
+
+	if _, err := fmt.Fprint(r.opts.Stdout, b.String()); err != nil {
+		return errors.Wrap(err, "failed to write Markdown output")
+	}
+
+	for _, e := range errs {
+		if _, err := fmt.Fprintln(r.opts.Stderr, e.FormatError()); err != nil {
+			return errors.Wrap(err, "failed to write error to stderr")
+		}
+	}
+
+	return nil
+}
+
+// writeMarkdownTable writes one table row per changed resource with its added and removed line
+// counts.
+func writeMarkdownTable(b *strings.Builder, diffs []*dt.ResourceDiff) {
+	b.WriteString("| Resource | Kind | Change | + | - |\n")
+	b.WriteString("| --- | --- | --- | ---: | ---: |\n")
+
+	for _, diff := range diffs {
+		name := diff.ResourceName
+		if diff.Namespace != "" {
+			name = diff.Namespace + "/" + name
+		}
+
+		added, removed := countLineChanges(diff.LineDiffs)
+		fmt.Fprintf(b, "| `%s` | %s | %s | %d | %d |\n", name, diff.Gvk.Kind, diff.DiffType.ToWord(), added, removed)
+	}
+}
+
+// markdownSection returns a collapsed <details> section holding the diff of a resource and any
+// warnings recorded for it.
+func (r *MarkdownDiffRenderer) markdownSection(diff *dt.ResourceDiff) string {
+	content := strings.TrimSuffix(FormatDiff(diff.LineDiffs, r.opts), "\n")
+	fence := markdownFence(content)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<details>\n<summary><code>%s</code> %s</summary>\n\n", getKindName(diff), diff.DiffType.ToWord())
+	fmt.Fprintf(&b, "%sdiff\n%s\n%s\n", fence, content, fence)
+
+	if len(diff.Warnings) > 0 {
+		b.WriteString("\n" + formatWarnings(diff.Warnings) + "\n")
+	}
+
+	b.WriteString("\n</details>\n")
+
+	return b.String()
+}
+
+// markdownFence returns a code fence longer than any run of backticks in content, so the content
+// can't close the block early.
+func markdownFence(content string) string {
+	longest, run := 0, 0
+
+	for _, c := range content {
+		if c != '`' {
+			run = 0
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
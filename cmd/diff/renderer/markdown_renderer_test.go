@@ -0,0 +1,131 @@
+package renderer
+
+import (
+	"bytes"
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMarkdownDiffRenderer_RenderDiffs(t *testing.T) {
+	tableHeader := "| Resource | Kind | Change | + | - |\n| --- | --- | --- | ---: | ---: |\n"
+
+	tests := map[string]struct {
+		reason     string
+		diffs      map[string]*dt.ResourceDiff
+		errs       []dt.OutputError
+		wantStdout string
+		wantStderr string
+	}{
+		"NoChanges": {
+			reason: "Should write a single no changes line when every resource is unchanged.",
+			diffs: map[string]*dt.ResourceDiff{
+				"equal": {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "unchanged",
+					Gvk:          schema.GroupVersionKind{Kind: "Bucket"},
+				},
+			},
+			wantStdout: NoChangesMessage + "\n",
+		},
+		"Changes": {
+			reason: "Should write a table of changed resources, the summary, and a collapsible diff block per resource, skipping unchanged ones.",
+			diffs: map[string]*dt.ResourceDiff{
+				"added": {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "new-bucket",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffInsert, Text: "kind: Bucket\nmetadata:\n  name: new-bucket"},
+					},
+				},
+				"modified": {
+					DiffType:     dt.DiffTypeModified,
+					ResourceName: "my-xr",
+					Namespace:    "default",
+					Gvk:          schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffEqual, Text: "spec:\n"},
+						{Type: diffmatchpatch.DiffDelete, Text: "  region: us-east-1\n"},
+						{Type: diffmatchpatch.DiffInsert, Text: "  region: us-west-2"},
+					},
+					Warnings: []string{"spec.region is deprecated"},
+				},
+				"equal": {
+					DiffType:     dt.DiffTypeEqual,
+					ResourceName: "unchanged",
+					Gvk:          schema.GroupVersionKind{Kind: "Bucket"},
+				},
+			},
+			wantStdout: tableHeader +
+				"| `default/new-bucket` | Bucket | added | 3 | 0 |\n" +
+				"| `default/my-xr` | XBucket | modified | 1 | 1 |\n" +
+				"\nSummary: 1 added, 1 modified\n" +
+				"\n<details>\n<summary><code>Bucket/new-bucket</code> added</summary>\n\n" +
+				"```diff\n+ kind: Bucket\n+ metadata:\n+   name: new-bucket\n```\n" +
+				"\n</details>\n" +
+				"\n<details>\n<summary><code>XBucket/my-xr</code> modified</summary>\n\n" +
+				"```diff\n  spec:\n-   region: us-east-1\n+   region: us-west-2\n```\n" +
+				"\nWarnings:\n  - spec.region is deprecated\n" +
+				"\n</details>\n",
+		},
+		"BackticksInDiff": {
+			reason: "Should use a fence longer than any backtick run in the diff so the block isn't closed early.",
+			diffs: map[string]*dt.ResourceDiff{
+				"added": {
+					DiffType:     dt.DiffTypeAdded,
+					ResourceName: "docs",
+					Gvk:          schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+					LineDiffs: []diffmatchpatch.Diff{
+						{Type: diffmatchpatch.DiffInsert, Text: "data: \"```\""},
+					},
+				},
+			},
+			wantStdout: tableHeader +
+				"| `docs` | ConfigMap | added | 1 | 0 |\n" +
+				"\nSummary: 1 added\n" +
+				"\n<details>\n<summary><code>ConfigMap/docs</code> added</summary>\n\n" +
+				"````diff\n+ data: \"```\"\n````\n" +
+				"\n</details>\n",
+		},
+		"ErrorsToStderr": {
+			reason: "Should write errors to stderr, keeping stdout a clean report.",
+			diffs:  map[string]*dt.ResourceDiff{},
+			errs: []dt.OutputError{
+				{ResourceID: "XBucket/my-xr", Message: "cannot find composition"},
+			},
+			wantStdout: NoChangesMessage + "\n",
+			wantStderr: "ERROR: XBucket/my-xr: cannot find composition\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatMarkdown
+			opts.Style = DiffStyleSideBySide
+			opts.Stdout = &stdout
+			opts.Stderr = &stderr
+
+			err := NewMarkdownDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(tt.diffs, tt.errs)
+			if err != nil {
+				t.Fatalf("\n%s\nRenderDiffs(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stdout, +got stdout:\n%s", tt.reason, diff)
+			}
+
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want stderr, +got stderr:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package renderer
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+)
+
+// NormalizationConfig is a set of per-kind normalization rules, usually loaded from a file, that
+// encode provider quirks the built-in diff cleanup doesn't know about. For example:
+//
+//	rules:
+//	- kinds: [Bucket.s3.aws.upbound.io]
+//	  ignorePath: spec.forProvider.tags[crossplane-kind]
+//	- kinds: [SecurityGroup]
+//	  keyedArray: {path: spec.forProvider.ingress, key: fromPort}
+//	- quantity: spec.forProvider.storage
+//	- kinds: [Instance.ec2.aws.upbound.io]
+//	  lateInit: spec.forProvider
+type NormalizationConfig struct {
+	Rules []NormalizationRule `json:"rules"`
+}
+
+// NormalizationRule applies one normalization to resources of the given kinds. Exactly one of
+// IgnorePath, KeyedArray, Quantity, and LateInit must be set. Paths are dot-separated;
+// IgnorePath also accepts the map key form of --ignore-paths.
+type NormalizationRule struct {
+	// Kinds limits the rule to these kinds, each Kind or Kind.group. Empty matches every kind.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// IgnorePath removes the field at this path from both sides of the diff.
+	IgnorePath string `json:"ignorePath,omitempty"`
+
+	// KeyedArray sorts the list at a path by a key field on both sides, so a provider that
+	// reorders list items doesn't produce a diff.
+	KeyedArray *KeyedArrayRule `json:"keyedArray,omitempty"`
+
+	// Quantity treats every value under this path as a resource quantity, so values that differ
+	// only in notation (1Gi vs 1073741824) don't diff.
+	Quantity string `json:"quantity,omitempty"`
+
+	// LateInit fills fields under this path that are set in the cluster but not rendered, so
+	// values a provider late-initializes don't show as removals.
+	LateInit string `json:"lateInit,omitempty"`
+}
+
+// KeyedArrayRule identifies a list whose items are matched by a key field rather than position.
+type KeyedArrayRule struct {
+	Path string `json:"path"`
+	Key  string `json:"key"`
+}
+
+// Validate returns an error if any rule is malformed.
+func (c *NormalizationConfig) Validate() error {
+	for i, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid normalization rule %d", i)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error unless exactly one rule type is set with all its fields.
+func (r *NormalizationRule) Validate() error {
+	set := 0
+
+	for _, ok := range []bool{r.IgnorePath != "", r.KeyedArray != nil, r.Quantity != "", r.LateInit != ""} {
+		if ok {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return errors.New("exactly one of ignorePath, keyedArray, quantity, or lateInit must be set")
+	}
+
+	if r.KeyedArray != nil && (r.KeyedArray.Path == "" || r.KeyedArray.Key == "") {
+		return errors.New("keyedArray requires both path and key")
+	}
+
+	return nil
+}
+
+// MatchesKind reports whether gvk matches any of the given kinds. A kind is either a bare Kind,
+// matching that kind in any group, or Kind.group.
+func MatchesKind(gvk schema.GroupVersionKind, kinds []string) bool {
+	for _, k := range kinds {
+		kind, group, qualified := strings.Cut(k, ".")
+		if kind == gvk.Kind && (!qualified || group == gvk.Group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyNormalizationRules applies the rules that match gvk to the cleaned current and desired
+// objects in place. Either object may be nil; rules that compare both sides are skipped unless
+// both are present. It returns a description of each change for logging.
+func applyNormalizationRules(rules []NormalizationRule, gvk schema.GroupVersionKind, current, desired *un.Unstructured) []string {
+	var applied []string
+
+	present := make([]*un.Unstructured, 0, 2)
+
+	for _, obj := range []*un.Unstructured{current, desired} {
+		if obj != nil {
+			present = append(present, obj)
+		}
+	}
+
+	for _, r := range rules {
+		if len(r.Kinds) > 0 && !MatchesKind(gvk, r.Kinds) {
+			continue
+		}
+
+		switch {
+		case r.IgnorePath != "":
+			for _, obj := range present {
+				if removeNestedPath(obj.Object, r.IgnorePath) {
+					applied = append(applied, "ignored path: "+r.IgnorePath)
+				}
+			}
+		case r.KeyedArray != nil:
+			for _, obj := range present {
+				if sortKeyedArray(obj.Object, r.KeyedArray.Path, r.KeyedArray.Key) {
+					applied = append(applied, fmt.Sprintf("sorted %s by %s", r.KeyedArray.Path, r.KeyedArray.Key))
+				}
+			}
+		case r.Quantity != "" && current != nil && desired != nil:
+			for _, p := range alignQuantitiesAt(current.Object, desired.Object, r.Quantity) {
+				applied = append(applied, "normalized quantity: "+p)
+			}
+		case r.LateInit != "" && current != nil && desired != nil:
+			for _, p := range fillLateInitAt(current.Object, desired.Object, r.LateInit) {
+				applied = append(applied, "late-initialized field: "+p)
+			}
+		}
+	}
+
+	return applied
+}
+
+// sortKeyedArray stably sorts the list at path by each item's key field, compared as text.
+// Items without the key sort last. It reports whether a list was found.
+func sortKeyedArray(obj map[string]any, path, key string) bool {
+	v, found, err := un.NestedFieldNoCopy(obj, strings.Split(path, ".")...)
+	if !found || err != nil {
+		return false
+	}
+
+	items, ok := v.([]any)
+	if !ok {
+		return false
+	}
+
+	keyOf := func(item any) (string, bool) {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return "", false
+		}
+
+		k, ok := m[key]
+
+		return fmt.Sprint(k), ok
+	}
+
+	slices.SortStableFunc(items, func(a, b any) int {
+		ak, aok := keyOf(a)
+		bk, bok := keyOf(b)
+
+		switch {
+		case aok && bok:
+			return cmp.Compare(ak, bk)
+		case aok:
+			return -1
+		case bok:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return true
+}
+
+// alignQuantitiesAt replaces, under path in desired, every value that is a quantity equal to the
+// current value but spelled differently with the current value. It returns the paths replaced.
+func alignQuantitiesAt(current, desired map[string]any, path string) []string {
+	parts := strings.Split(path, ".")
+
+	cv, cok, _ := un.NestedFieldNoCopy(current, parts...)
+	dv, dok, _ := un.NestedFieldNoCopy(desired, parts...)
+
+	if !cok || !dok {
+		return nil
+	}
+
+	aligned, paths := alignQuantities(cv, dv, path)
+	if len(paths) > 0 {
+		_ = un.SetNestedField(desired, aligned, parts...)
+	}
+
+	return paths
+}
+
+// alignQuantities walks current and desired in parallel, returning desired with every scalar that
+// parses to the same quantity as its current counterpart replaced by the current value.
+func alignQuantities(current, desired any, path string) (any, []string) {
+	var paths []string
+
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return desired, nil
+		}
+
+		for k, dv := range d {
+			cv, ok := c[k]
+			if !ok {
+				continue
+			}
+
+			var p []string
+
+			d[k], p = alignQuantities(cv, dv, path+"."+k)
+			paths = append(paths, p...)
+		}
+	case []any:
+		c, ok := current.([]any)
+		if !ok {
+			return desired, nil
+		}
+
+		for i := range min(len(c), len(d)) {
+			var p []string
+
+			d[i], p = alignQuantities(c[i], d[i], fmt.Sprintf("%s[%d]", path, i))
+			paths = append(paths, p...)
+		}
+	default:
+		if equality.Semantic.DeepEqual(current, desired) {
+			return desired, nil
+		}
+
+		cq, cok := parseQuantity(current)
+		dq, dok := parseQuantity(desired)
+
+		if cok && dok && cq.Cmp(dq) == 0 {
+			return current, []string{path}
+		}
+	}
+
+	return desired, paths
+}
+
+// fillLateInitAt copies into desired every field under path that current sets and desired
+// doesn't, recursing into maps. Lists are not merged. It returns the paths filled.
+func fillLateInitAt(current, desired map[string]any, path string) []string {
+	parts := strings.Split(path, ".")
+
+	cv, found, _ := un.NestedFieldNoCopy(current, parts...)
+	if !found {
+		return nil
+	}
+
+	dv, found, _ := un.NestedFieldNoCopy(desired, parts...)
+	if !found {
+		_ = un.SetNestedField(desired, cv, parts...)
+		return []string{path}
+	}
+
+	return fillLateInit(cv, dv, path)
+}
+
+// fillLateInit copies keys of current missing from desired into desired, recursing into maps
+// both sides hold.
+func fillLateInit(current, desired any, path string) []string {
+	c, cok := current.(map[string]any)
+	d, dok := desired.(map[string]any)
+
+	if !cok || !dok {
+		return nil
+	}
+
+	var paths []string
+
+	for k, cv := range c {
+		p := path + "." + k
+
+		dv, ok := d[k]
+		if !ok {
+			d[k] = runtime.DeepCopyJSONValue(cv)
+			paths = append(paths, p)
+
+			continue
+		}
+
+		paths = append(paths, fillLateInit(cv, dv, p)...)
+	}
+
+	return paths
+}
@@ -0,0 +1,155 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGenerateDiffWithOptions_NormalizationRules(t *testing.T) {
+	securityGroup := func(forProvider map[string]any) *un.Unstructured {
+		res := tu.NewResource("ec2.aws.upbound.io/v1beta1", "SecurityGroup", "sg").Build()
+		_ = un.SetNestedField(res.Object, forProvider, "spec", "forProvider")
+
+		return res
+	}
+
+	ingress := func(ports ...int64) []any {
+		rules := make([]any, 0, len(ports))
+		for _, p := range ports {
+			rules = append(rules, map[string]any{"fromPort": p, "protocol": "tcp"})
+		}
+
+		return rules
+	}
+
+	tests := map[string]struct {
+		reason       string
+		rules        []NormalizationRule
+		current      *un.Unstructured
+		desired      *un.Unstructured
+		wantType     types.DiffType
+		wantContains []string
+	}{
+		"IgnorePath": {
+			reason:   "A path ignored for the kind should not produce a diff.",
+			rules:    []NormalizationRule{{Kinds: []string{"SecurityGroup"}, IgnorePath: "spec.forProvider.tags"}},
+			current:  securityGroup(map[string]any{"region": "us-east-1", "tags": map[string]any{"team": "a"}}),
+			desired:  securityGroup(map[string]any{"region": "us-east-1", "tags": map[string]any{"team": "b"}}),
+			wantType: types.DiffTypeEqual,
+		},
+		"OtherKind": {
+			reason:       "A rule for another kind should not apply.",
+			rules:        []NormalizationRule{{Kinds: []string{"SecurityGroup.other.org"}, IgnorePath: "spec.forProvider.tags"}},
+			current:      securityGroup(map[string]any{"tags": map[string]any{"team": "a"}}),
+			desired:      securityGroup(map[string]any{"tags": map[string]any{"team": "b"}}),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"team: b"},
+		},
+		"KeyedArray": {
+			reason:   "A list reordered by the provider should not produce a diff when sorted by its key.",
+			rules:    []NormalizationRule{{KeyedArray: &KeyedArrayRule{Path: "spec.forProvider.ingress", Key: "fromPort"}}},
+			current:  securityGroup(map[string]any{"ingress": ingress(443, 80)}),
+			desired:  securityGroup(map[string]any{"ingress": ingress(80, 443)}),
+			wantType: types.DiffTypeEqual,
+		},
+		"Quantity": {
+			reason:   "Values under a quantity path should compare by quantity whatever their field name.",
+			rules:    []NormalizationRule{{Quantity: "spec.forProvider.volume"}},
+			current:  securityGroup(map[string]any{"volume": map[string]any{"size": "1Gi"}}),
+			desired:  securityGroup(map[string]any{"volume": map[string]any{"size": "1073741824"}}),
+			wantType: types.DiffTypeEqual,
+		},
+		"LateInit": {
+			reason: "Fields set only in the cluster under a late-init path should not show as removed, while real changes still do.",
+			rules:  []NormalizationRule{{LateInit: "spec.forProvider"}},
+			current: securityGroup(map[string]any{
+				"region":      "us-east-1",
+				"description": "Managed by Crossplane",
+				"vpc":         map[string]any{"id": "vpc-123", "cidr": "10.0.0.0/16"},
+			}),
+			desired:      securityGroup(map[string]any{"region": "us-west-2", "vpc": map[string]any{"id": "vpc-123"}}),
+			wantType:     types.DiffTypeModified,
+			wantContains: []string{"region: us-west-2"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.NormalizationRules = tt.rules
+
+			diff, err := GenerateDiffWithOptions(t.Context(), tt.current, tt.desired, tu.TestLogger(t, false), opts)
+			if err != nil {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): unexpected error: %v", tt.reason, err)
+			}
+
+			if diffStr := cmp.Diff(tt.wantType, diff.DiffType); diffStr != "" {
+				t.Fatalf("\n%s\nGenerateDiffWithOptions(...): -want type, +got type:\n%s", tt.reason, diffStr)
+			}
+
+			formatted := FormatDiff(diff.LineDiffs, opts)
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): diff does not contain %q:\n%s", tt.reason, want, formatted)
+				}
+			}
+
+			// A late-initialized field must never show up as a removal.
+			for _, d := range diff.LineDiffs {
+				if d.Type == diffmatchpatch.DiffDelete && strings.Contains(d.Text, "description") {
+					t.Errorf("\n%s\nGenerateDiffWithOptions(...): late-initialized field shown as removed:\n%s", tt.reason, formatted)
+				}
+			}
+
+			// Rules act on cleaned copies only.
+			if _, found, _ := un.NestedFieldNoCopy(tt.desired.Object, "spec", "forProvider", "description"); found {
+				t.Errorf("\n%s\nGenerateDiffWithOptions(...): rules modified the desired object", tt.reason)
+			}
+		})
+	}
+}
+
+func TestNormalizationRule_Validate(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		rule    NormalizationRule
+		wantErr bool
+	}{
+		"OneType": {
+			reason: "A rule with exactly one type should be valid.",
+			rule:   NormalizationRule{Kinds: []string{"Bucket"}, LateInit: "spec.forProvider"},
+		},
+		"NoType": {
+			reason:  "A rule with no type should be rejected.",
+			rule:    NormalizationRule{Kinds: []string{"Bucket"}},
+			wantErr: true,
+		},
+		"TwoTypes": {
+			reason:  "A rule with two types should be rejected.",
+			rule:    NormalizationRule{IgnorePath: "spec.a", Quantity: "spec.b"},
+			wantErr: true,
+		},
+		"KeyedArrayWithoutKey": {
+			reason:  "A keyedArray rule without a key should be rejected.",
+			rule:    NormalizationRule{KeyedArray: &KeyedArrayRule{Path: "spec.items"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("\n%s\nValidate(): error = %v, wantErr %t", tt.reason, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package renderer
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+)
+
+// Provenance records what a diff was computed against, so shared output can be traced back to
+// its source: the cluster or exported resources, the compositions the XRs resolved to, and when.
+type Provenance struct {
+	Server       string                  `json:"server,omitempty"`      // API server URL of the cluster diffed against
+	ObservedDir  string                  `json:"observedDir,omitempty"` // --observed-dir diffed against instead of a cluster
+	Compositions []CompositionProvenance `json:"compositions"`
+	Timestamp    time.Time               `json:"timestamp"`
+}
+
+// CompositionProvenance identifies a composition an XR was rendered with, and the revision it was
+// resolved from, if any.
+type CompositionProvenance struct {
+	Name     string          `json:"name"`
+	Revision *dt.RevisionRef `json:"revision,omitempty"`
+}
+
+// Record fills in the compositions the XRs among diffs were rendered with, each distinct
+// composition and revision once, sorted, and the time of the diff.
+func (p *Provenance) Record(diffs map[string]*dt.ResourceDiff, at time.Time) {
+	p.Compositions = []CompositionProvenance{}
+	p.Timestamp = at.UTC().Truncate(time.Second)
+
+	for _, diff := range diffs {
+		if diff.Composition == "" {
+			continue
+		}
+
+		c := CompositionProvenance{Name: diff.Composition, Revision: diff.Revision}
+		if !slices.ContainsFunc(p.Compositions, func(o CompositionProvenance) bool { return o.String() == c.String() }) {
+			p.Compositions = append(p.Compositions, c)
+		}
+	}
+
+	slices.SortFunc(p.Compositions, func(a, b CompositionProvenance) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+}
+
+// String formats the composition as its name, followed by the revision it was resolved from, as
+// XR headers name it.
+func (c CompositionProvenance) String() string {
+	if c.Revision == nil {
+		return c.Name
+	}
+
+	if c.Revision.Number == 0 {
+		return fmt.Sprintf("%s (revision %s)", c.Name, c.Revision.Name)
+	}
+
+	return fmt.Sprintf("%s (revision %s, #%d)", c.Name, c.Revision.Name, c.Revision.Number)
+}
+
+// String formats the provenance as an indented "Provenance:" block for human-readable output.
+func (p *Provenance) String() string {
+	var b strings.Builder
+
+	b.WriteString("Provenance:\n")
+
+	switch {
+	case p.ObservedDir != "":
+		fmt.Fprintf(&b, "  observed dir: %s\n", p.ObservedDir)
+	default:
+		fmt.Fprintf(&b, "  server: %s\n", p.Server)
+	}
+
+	names := make([]string, 0, len(p.Compositions))
+	for _, c := range p.Compositions {
+		names = append(names, c.String())
+	}
+
+	if len(names) > 0 {
+		fmt.Fprintf(&b, "  compositions: %s\n", strings.Join(names, ", "))
+	}
+
+	fmt.Fprintf(&b, "  timestamp: %s\n", p.Timestamp.Format(time.RFC3339))
+
+	return b.String()
+}
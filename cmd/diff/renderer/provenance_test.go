@@ -0,0 +1,105 @@
+package renderer
+
+import (
+	"testing"
+	"time"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProvenance_Record(t *testing.T) {
+	at := time.Date(2026, 1, 10, 12, 0, 0, 500, time.FixedZone("CET", 3600))
+	rev := &dt.RevisionRef{Name: "db-abc12", Number: 3}
+
+	tests := map[string]struct {
+		reason string
+		diffs  map[string]*dt.ResourceDiff
+		want   []CompositionProvenance
+	}{
+		"NoXRs": {
+			reason: "Diffs without a composition should record no compositions.",
+			diffs:  map[string]*dt.ResourceDiff{"b": {ResourceName: "my-bucket"}},
+			want:   []CompositionProvenance{},
+		},
+		"DistinctSorted": {
+			reason: "Each composition and revision should be recorded once, sorted.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Composition: "network"},
+				"b": {Composition: "database", Revision: rev},
+				"c": {Composition: "database", Revision: rev},
+				"d": {ResourceName: "my-bucket"},
+			},
+			want: []CompositionProvenance{{Name: "database", Revision: rev}, {Name: "network"}},
+		},
+		"SameCompositionDifferentRevisions": {
+			reason: "XRs rendered against different revisions of a composition should each be recorded.",
+			diffs: map[string]*dt.ResourceDiff{
+				"a": {Composition: "database", Revision: rev},
+				"b": {Composition: "database"},
+			},
+			want: []CompositionProvenance{{Name: "database"}, {Name: "database", Revision: rev}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &Provenance{Server: "https://127.0.0.1:6443"}
+			p.Record(tt.diffs, at)
+
+			if diff := cmp.Diff(tt.want, p.Compositions); diff != "" {
+				t.Errorf("\n%s\nRecord(...): -want compositions, +got compositions:\n%s", tt.reason, diff)
+			}
+
+			if want := time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC); !p.Timestamp.Equal(want) || p.Timestamp.Location() != time.UTC {
+				t.Errorf("\n%s\nRecord(...): want timestamp %s, got %s", tt.reason, want, p.Timestamp)
+			}
+		})
+	}
+}
+
+func TestProvenance_String(t *testing.T) {
+	at := time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		reason     string
+		provenance Provenance
+		want       string
+	}{
+		"Cluster": {
+			reason: "A cluster diff should name its server, compositions and their revisions, and timestamp.",
+			provenance: Provenance{
+				Server: "https://127.0.0.1:6443",
+				Compositions: []CompositionProvenance{
+					{Name: "database", Revision: &dt.RevisionRef{Name: "db-abc12", Number: 3}},
+					{Name: "network", Revision: &dt.RevisionRef{Name: "net-def34"}},
+					{Name: "storage"},
+				},
+				Timestamp: at,
+			},
+			want: "Provenance:\n" +
+				"  server: https://127.0.0.1:6443\n" +
+				"  compositions: database (revision db-abc12, #3), network (revision net-def34), storage\n" +
+				"  timestamp: 2026-01-10T11:00:00Z\n",
+		},
+		"ObservedDir": {
+			reason: "A diff against exported resources should name their directory, and omit compositions when there are none.",
+			provenance: Provenance{
+				ObservedDir:  "./cluster-export",
+				Compositions: []CompositionProvenance{},
+				Timestamp:    at,
+			},
+			want: "Provenance:\n" +
+				"  observed dir: ./cluster-export\n" +
+				"  timestamp: 2026-01-10T11:00:00Z\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.provenance.String()); diff != "" {
+				t.Errorf("\n%s\nString(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package renderer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	t "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DefaultDiffWidth is the width side-by-side diffs are laid out in when the terminal width is
+// unknown, e.g. when output isn't a terminal.
+const DefaultDiffWidth = 80
+
+// minSideBySideColumn is the narrowest a side-by-side column gets, however small the width.
+const minSideBySideColumn = 10
+
+// SideBySideDiffFormatter formats diffs as two columns, the current lines on the left and the
+// desired lines on the right, in the style of diff -y. Lines longer than a column are wrapped.
+type SideBySideDiffFormatter struct{}
+
+// sideBySideRow is one row of a side-by-side diff. A side without a line is marked absent
+// rather than empty, since an empty line is a valid line.
+type sideBySideRow struct {
+	Old, New       string
+	HasOld, HasNew bool
+	Changed        bool
+}
+
+// Format implements the DiffFormatter interface for SideBySideDiffFormatter. Changed rows are
+// marked "|" when both sides have a line, "<" when only the current side does, and ">" when only
+// the desired side does. Compact mode keeps ContextLines rows around each change, like
+// CompactDiffFormatter.
+func (f *SideBySideDiffFormatter) Format(diffs []diffmatchpatch.Diff, options DiffOptions) string {
+	rows := sideBySideRows(diffs)
+
+	width := options.Width
+	if width <= 0 {
+		width = DefaultDiffWidth
+	}
+
+	// Two columns around a " | " separator.
+	column := max((width-3)/2, minSideBySideColumn)
+
+	visible := make([]bool, len(rows))
+	for i := range rows {
+		visible[i] = !options.Compact
+	}
+
+	if options.Compact {
+		changed := false
+
+		for i, row := range rows {
+			if !row.Changed {
+				continue
+			}
+
+			changed = true
+
+			for j := max(0, i-options.ContextLines); j <= min(len(rows)-1, i+options.ContextLines); j++ {
+				visible[j] = true
+			}
+		}
+
+		if !changed {
+			return ""
+		}
+
+		// A single hidden row between changes is cheaper to show than to collapse.
+		for i := 1; i < len(rows)-1; i++ {
+			if !visible[i] && visible[i-1] && visible[i+1] {
+				visible[i] = true
+			}
+		}
+	}
+
+	var builder strings.Builder
+
+	printed, hidden := false, 0
+
+	for i, row := range rows {
+		if !visible[i] {
+			hidden++
+			continue
+		}
+
+		if printed && hidden > 0 {
+			builder.WriteString(chunkSeparator(options, hidden))
+			builder.WriteString("\n")
+		}
+
+		writeSideBySideRow(&builder, row, column, options.UseColors)
+
+		printed, hidden = true, 0
+	}
+
+	return builder.String()
+}
+
+// sideBySideRows pairs the lines of a diff into rows. Each run of deletions and insertions
+// between unchanged lines is lined up top to bottom, so a modified line sits next to its
+// replacement.
+func sideBySideRows(diffs []diffmatchpatch.Diff) []sideBySideRow {
+	var (
+		rows              []sideBySideRow
+		deleted, inserted []string
+	)
+
+	flush := func() {
+		for i := range max(len(deleted), len(inserted)) {
+			row := sideBySideRow{Changed: true}
+
+			if i < len(deleted) {
+				row.Old, row.HasOld = deleted[i], true
+			}
+
+			if i < len(inserted) {
+				row.New, row.HasNew = inserted[i], true
+			}
+
+			rows = append(rows, row)
+		}
+
+		deleted, inserted = nil, nil
+	}
+
+	for _, diff := range diffs {
+		lines := strings.Split(diff.Text, "\n")
+		if strings.HasSuffix(diff.Text, "\n") {
+			lines = lines[:len(lines)-1]
+		}
+
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			deleted = append(deleted, lines...)
+		case diffmatchpatch.DiffInsert:
+			inserted = append(inserted, lines...)
+		case diffmatchpatch.DiffEqual:
+			flush()
+
+			for _, line := range lines {
+				rows = append(rows, sideBySideRow{Old: line, New: line, HasOld: true, HasNew: true})
+			}
+		}
+	}
+
+	flush()
+
+	return rows
+}
+
+// writeSideBySideRow writes a row, wrapping each side at column runes. The marker is only
+// written on the first line of a wrapped row.
+func writeSideBySideRow(builder *strings.Builder, row sideBySideRow, column int, useColors bool) {
+	marker := " "
+
+	switch {
+	case !row.Changed:
+	case row.HasOld && row.HasNew:
+		marker = "|"
+	case row.HasOld:
+		marker = "<"
+	default:
+		marker = ">"
+	}
+
+	left, right := wrapRunes(row.Old, column), wrapRunes(row.New, column)
+
+	for i := range max(len(left), len(right)) {
+		var l, r string
+
+		if i < len(left) && row.HasOld {
+			l = left[i]
+		}
+
+		if i < len(right) && row.HasNew {
+			r = right[i]
+		}
+
+		padding := strings.Repeat(" ", column-utf8.RuneCountInString(l))
+
+		if useColors && row.Changed {
+			if l != "" {
+				l = t.ColorRed + l + t.ColorReset
+			}
+
+			if r != "" {
+				r = t.ColorGreen + r + t.ColorReset
+			}
+		}
+
+		line := l + padding + " " + marker + " " + r
+		builder.WriteString(strings.TrimRight(line, " "))
+		builder.WriteString("\n")
+
+		marker = " "
+	}
+}
+
+// wrapRunes splits s into pieces of at most width runes. An empty string is a single empty piece.
+func wrapRunes(s string, width int) []string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return []string{""}
+	}
+
+	pieces := make([]string, 0, len(runes)/width+1)
+
+	for len(runes) > width {
+		pieces = append(pieces, string(runes[:width]))
+		runes = runes[width:]
+	}
+
+	return append(pieces, string(runes))
+}
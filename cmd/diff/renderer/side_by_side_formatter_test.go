@@ -0,0 +1,100 @@
+package renderer
+
+import (
+	"testing"
+
+	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+func TestSideBySideDiffFormatter_Format(t *testing.T) {
+	modified := []diffmatchpatch.Diff{
+		{Type: diffmatchpatch.DiffEqual, Text: "spec:\n"},
+		{Type: diffmatchpatch.DiffDelete, Text: "  size: small\n  zone: a\n"},
+		{Type: diffmatchpatch.DiffInsert, Text: "  size: large\n"},
+		{Type: diffmatchpatch.DiffEqual, Text: "  region: us-east-1\n"},
+		{Type: diffmatchpatch.DiffInsert, Text: "  tier: gold\n"},
+	}
+
+	tests := map[string]struct {
+		reason  string
+		diffs   []diffmatchpatch.Diff
+		options func(*DiffOptions)
+		want    string
+	}{
+		"Columns": {
+			reason: "Changed lines should be paired up and marked |, < or >, with unchanged lines on both sides.",
+			diffs:  modified,
+			options: func(o *DiffOptions) {
+				o.Width = 43
+			},
+			want: "" +
+				"spec:                  spec:\n" +
+				"  size: small        |   size: large\n" +
+				"  zone: a            <\n" +
+				"  region: us-east-1      region: us-east-1\n" +
+				"                     >   tier: gold\n",
+		},
+		"Wrapped": {
+			reason: "Lines longer than a column should wrap, with the marker only on the first line.",
+			diffs: []diffmatchpatch.Diff{
+				{Type: diffmatchpatch.DiffDelete, Text: "abcdefghijklmnop\n"},
+				{Type: diffmatchpatch.DiffInsert, Text: "abcdefghijkl\n"},
+			},
+			options: func(o *DiffOptions) {
+				o.Width = 23
+			},
+			want: "" +
+				"abcdefghij | abcdefghij\n" +
+				"klmnop       kl\n",
+		},
+		"Compact": {
+			reason: "Compact mode should keep only the context rows around each change, counting the hidden ones.",
+			diffs: []diffmatchpatch.Diff{
+				{Type: diffmatchpatch.DiffEqual, Text: "a\nb\nc\n"},
+				{Type: diffmatchpatch.DiffDelete, Text: "d\n"},
+				{Type: diffmatchpatch.DiffEqual, Text: "e\nf\ng\nh\ni\n"},
+				{Type: diffmatchpatch.DiffInsert, Text: "j\n"},
+			},
+			options: func(o *DiffOptions) {
+				o.Width = 23
+				o.Compact = true
+				o.ContextLines = 1
+			},
+			want: "" +
+				"c            c\n" +
+				"d          <\n" +
+				"e            e\n" +
+				"... (3 unchanged lines)\n" +
+				"i            i\n" +
+				"           > j\n",
+		},
+		"Colored": {
+			reason: "Removed text should be red and added text green; padding stays outside the color codes.",
+			diffs: []diffmatchpatch.Diff{
+				{Type: diffmatchpatch.DiffDelete, Text: "old\n"},
+				{Type: diffmatchpatch.DiffInsert, Text: "new\n"},
+			},
+			options: func(o *DiffOptions) {
+				o.Width = 23
+				o.UseColors = true
+			},
+			want: dt.ColorRed + "old" + dt.ColorReset + "        | " + dt.ColorGreen + "new" + dt.ColorReset + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultDiffOptions()
+			opts.UseColors = false
+			opts.Style = DiffStyleSideBySide
+			tt.options(&opts)
+
+			got := FormatDiff(tt.diffs, opts)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("\n%s\nFormatDiff(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
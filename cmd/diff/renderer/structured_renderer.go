@@ -24,6 +24,23 @@ const (
 	OutputFormatJSON OutputFormat = "json"
 	// OutputFormatYAML outputs structured YAML.
 	OutputFormatYAML OutputFormat = "yaml"
+	// OutputFormatCSV outputs one CSV row per changed resource.
+	OutputFormatCSV OutputFormat = "csv"
+	// OutputFormatTextNoANSI is the diff format with no ANSI escape codes, whatever
+	// the color settings.
+	OutputFormatTextNoANSI OutputFormat = "text-no-ansi"
+	// OutputFormatDesired outputs the desired object of every resource as a multi-document
+	// YAML stream instead of a diff.
+	OutputFormatDesired OutputFormat = "desired"
+	// OutputFormatGitHub outputs a GitHub Actions annotation per changed resource ahead of the
+	// human-readable diff.
+	OutputFormatGitHub OutputFormat = "github"
+	// OutputFormatDOT outputs a Graphviz graph of the XRs and the resources they compose.
+	OutputFormatDOT OutputFormat = "dot"
+	// OutputFormatMarkdown outputs a Markdown report of the changes for a PR comment.
+	OutputFormatMarkdown OutputFormat = "markdown"
+	// OutputFormatJUnit outputs a JUnit XML report with a testcase per resource.
+	OutputFormatJUnit OutputFormat = "junit"
 )
 
 // XRStatus represents the processing status of an XR in composition diffs.
@@ -57,6 +74,10 @@ const (
 	// compositionUpdatePolicy with a compositionRevisionSelector that does not match the labels of
 	// the composition change being diffed. Such XRs would not select the resulting revision.
 	FilterReasonRevisionSelectorMismatch FilterReason = "revision_selector_mismatch"
+	// FilterReasonRetargeted indicates the XR uses the composition in the cluster, but the composition
+	// change retargets its compositeTypeRef to a different XR type. The XR would no longer match the
+	// composition and is left orphaned on its current revision.
+	FilterReasonRetargeted FilterReason = "retargeted"
 )
 
 // OutputError is an alias for dt.OutputError for convenience.
@@ -66,9 +87,28 @@ type OutputError = dt.OutputError
 // StructuredDiffOutput represents the structured output format for diffs.
 // Note: Only JSON tags are used because sigs.k8s.io/yaml uses JSON tags for YAML serialization.
 type StructuredDiffOutput struct {
-	Summary Summary          `json:"summary"`
-	Changes []ChangeDetail   `json:"changes"`
-	Errors  []dt.OutputError `json:"errors,omitempty"`
+	Summary           Summary               `json:"summary"`
+	Changes           []ChangeDetail        `json:"changes"`
+	RequiredResources []XRRequiredResources `json:"requiredResources,omitempty"` // with --show-external-resources
+	Errors            []dt.OutputError      `json:"errors,omitempty"`
+	Provenance        *Provenance           `json:"provenance,omitempty"`
+}
+
+// XRRequiredResources lists the required resources an XR's functions were given.
+type XRRequiredResources struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Name       string                `json:"name"`
+	Namespace  string                `json:"namespace,omitempty"`
+	Resources  []dt.RequiredResource `json:"resources"`
+}
+
+// StructuredSummaryOutput is the structured output of --summary-only: the counts of changes,
+// without the changes themselves.
+type StructuredSummaryOutput struct {
+	Summary    Summary          `json:"summary"`
+	Errors     []dt.OutputError `json:"errors,omitempty"`
+	Provenance *Provenance      `json:"provenance,omitempty"`
 }
 
 // Summary contains aggregated counts of changes.
@@ -80,12 +120,15 @@ type Summary struct {
 
 // ChangeDetail represents a single resource change.
 type ChangeDetail struct {
-	Type       string         `json:"type"`
-	APIVersion string         `json:"apiVersion"`
-	Kind       string         `json:"kind"`
-	Name       string         `json:"name"`
-	Namespace  string         `json:"namespace,omitempty"`
-	Diff       map[string]any `json:"diff"`
+	Type          string           `json:"type"`
+	APIVersion    string           `json:"apiVersion"`
+	Kind          string           `json:"kind"`
+	Name          string           `json:"name"`
+	Namespace     string           `json:"namespace,omitempty"`
+	Diff          map[string]any   `json:"diff"`
+	Warnings      []string         `json:"warnings,omitempty"`
+	RemovalReason dt.RemovalReason `json:"removalReason,omitempty"` // why a removed resource would be removed
+	Revision      *dt.RevisionRef  `json:"revision,omitempty"`      // the CompositionRevision an XR was rendered against
 }
 
 // CompDiffOutput is the top-level output for composition diffs (internal representation).
@@ -101,6 +144,9 @@ type CompositionDiff struct {
 	Name              string
 	Error             error            // per-composition error (nil if successful)
 	CompositionDiff   *dt.ResourceDiff // the actual composition diff (nil if unchanged)
+	RetargetedFrom    string           // XR type the cluster composition targets, when the change retargets it
+	StepsReordered    *StepReorder     // pipeline step order change, when the same steps run in a new order
+	Reconciliation    *ReconciliationSummary
 	AffectedResources AffectedResourcesSummary
 	ImpactAnalysis    []XRImpact
 }
@@ -120,6 +166,27 @@ func (c *CompositionDiff) HasChanges() bool {
 	return false
 }
 
+// StepReorder records a composition that runs the same pipeline steps as the cluster's, in a
+// different order.
+type StepReorder struct {
+	From []string `json:"from"` // step names in the cluster composition's order
+	To   []string `json:"to"`   // step names in the new composition's order
+}
+
+// ReconciliationSummary buckets the XRs using a composition by their effective update policy, i.e.
+// whether Crossplane will re-reconcile them against the changed composition once it is applied.
+type ReconciliationSummary struct {
+	// AutoUpdate counts XRs with an Automatic compositionUpdatePolicy that would select the
+	// resulting revision.
+	AutoUpdate int `json:"autoUpdate"`
+	// Manual counts XRs pinned to their current revision by a Manual compositionUpdatePolicy,
+	// whether or not --include-manual diffed them.
+	Manual int `json:"manual"`
+	// NotSelected counts Automatic XRs whose compositionRevisionSelector would not select the
+	// resulting revision.
+	NotSelected int `json:"notSelected"`
+}
+
 // AffectedResourcesSummary contains counts of affected resources by status.
 type AffectedResourcesSummary struct {
 	Total       int `json:"total"`
@@ -134,6 +201,9 @@ type AffectedResourcesSummary struct {
 	// FilteredByPolicy so the breakdown is visible even in default-discovery mode, where individual
 	// XR impacts are not surfaced.
 	FilteredBySelector int `json:"filteredBySelector,omitempty"`
+	// Orphaned counts XRs of the composition's old type that a compositeTypeRef change leaves behind
+	// (FilterReasonRetargeted).
+	Orphaned int `json:"orphaned,omitempty"`
 }
 
 // XRImpact represents the impact analysis for a single XR (internal).
@@ -148,6 +218,9 @@ type XRImpact struct {
 	// FilterDetail is an optional human-readable explanation for a filtered outcome (e.g. which
 	// selector failed to match which labels), surfaced to help users self-diagnose the exclusion.
 	FilterDetail string
+	// ManualPolicy is true for an XR with the Manual composition update policy that
+	// --include-manual let through to be diffed.
+	ManualPolicy bool
 	Error        error                       // store actual error, not string
 	Diffs        map[string]*dt.ResourceDiff // downstream diffs (nil if unchanged/error)
 }
@@ -165,16 +238,36 @@ type compositionDiffJSON struct {
 	Name               string                   `json:"name"`
 	Error              string                   `json:"error,omitempty"`
 	CompositionChanges *ChangeDetail            `json:"compositionChanges,omitempty"`
+	RetargetedFrom     string                   `json:"retargetedFrom,omitempty"`
+	StepsReordered     *StepReorder             `json:"stepsReordered,omitempty"`
+	Reconciliation     *ReconciliationSummary   `json:"reconciliation,omitempty"`
 	AffectedResources  AffectedResourcesSummary `json:"affectedResources"`
 	ImpactAnalysis     []xrImpactJSON           `json:"impactAnalysis"`
 }
 
+// compSummaryJSONOutput is the JSON schema for composition diffs under --summary-only.
+type compSummaryJSONOutput struct {
+	Compositions []compositionSummaryJSON `json:"compositions"`
+	Errors       []dt.OutputError         `json:"errors,omitempty"`
+}
+
+// compositionSummaryJSON holds the counts of one composition diff: whether the composition
+// changed, how its XRs are affected, and the downstream changes across those XRs.
+type compositionSummaryJSON struct {
+	Name              string                   `json:"name"`
+	Error             string                   `json:"error,omitempty"`
+	CompositionChange string                   `json:"compositionChange,omitempty"`
+	AffectedResources AffectedResourcesSummary `json:"affectedResources"`
+	DownstreamChanges Summary                  `json:"downstreamChanges"`
+}
+
 type xrImpactJSON struct {
 	corev1.ObjectReference `json:",inline"`
 
 	Status            XRStatus           `json:"status"`
 	FilterReason      FilterReason       `json:"filterReason,omitempty"`
 	FilterDetail      string             `json:"filterDetail,omitempty"`
+	ManualPolicy      bool               `json:"manualPolicy,omitempty"`
 	Error             string             `json:"error,omitempty"`
 	DownstreamChanges *DownstreamChanges `json:"downstreamChanges,omitempty"`
 }
@@ -208,6 +301,12 @@ func (r *StructuredDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff,
 
 	output := r.buildStructuredOutput(diffs)
 	output.Errors = errs
+	output.Provenance = r.opts.Provenance
+
+	var payload any = output
+	if r.opts.SummaryOnly {
+		payload = StructuredSummaryOutput{Summary: output.Summary, Errors: errs, Provenance: r.opts.Provenance}
+	}
 
 	var (
 		data []byte
@@ -216,10 +315,10 @@ func (r *StructuredDiffRenderer) RenderDiffs(diffs map[string]*dt.ResourceDiff,
 
 	switch r.opts.Format {
 	case OutputFormatJSON:
-		data, err = json.MarshalIndent(output, "", "  ")
+		data, err = json.MarshalIndent(payload, "", "  ")
 	case OutputFormatYAML:
-		data, err = sigsyaml.Marshal(output)
-	case OutputFormatDiff:
+		data, err = sigsyaml.Marshal(payload)
+	case OutputFormatDiff, OutputFormatTextNoANSI, OutputFormatCSV, OutputFormatDesired, OutputFormatGitHub, OutputFormatDOT, OutputFormatMarkdown, OutputFormatJUnit:
 		return errors.Errorf("unsupported output format for structured renderer: %s", r.opts.Format)
 	}
 
@@ -269,6 +368,17 @@ func (r *StructuredDiffRenderer) buildStructuredOutput(diffs map[string]*dt.Reso
 	})
 
 	for _, diff := range sortedDiffs {
+		// Required resources are listed whether or not the XR changed.
+		if len(diff.RequiredResources) > 0 {
+			output.RequiredResources = append(output.RequiredResources, XRRequiredResources{
+				APIVersion: diff.Gvk.GroupVersion().String(),
+				Kind:       diff.Gvk.Kind,
+				Name:       diff.ResourceName,
+				Namespace:  diff.Namespace,
+				Resources:  diff.RequiredResources,
+			})
+		}
+
 		// Skip equal resources
 		if diff.DiffType == dt.DiffTypeEqual {
 			continue
@@ -292,12 +402,15 @@ func (r *StructuredDiffRenderer) buildStructuredOutput(diffs map[string]*dt.Reso
 		// avoiding an empty namespace when the desired manifest omits it but the
 		// current cluster object has one.
 		change := ChangeDetail{
-			Type:       diff.DiffType.ToWord(),
-			APIVersion: diff.Gvk.GroupVersion().String(),
-			Kind:       diff.Gvk.Kind,
-			Name:       diff.ResourceName,
-			Namespace:  diff.Namespace,
-			Diff:       r.buildDiffDetail(diff),
+			Type:          diff.DiffType.ToWord(),
+			APIVersion:    diff.Gvk.GroupVersion().String(),
+			Kind:          diff.Gvk.Kind,
+			Name:          diff.ResourceName,
+			Namespace:     diff.Namespace,
+			Diff:          r.buildDiffDetail(diff),
+			Warnings:      diff.Warnings,
+			RemovalReason: diff.RemovalReason,
+			Revision:      diff.Revision,
 		}
 
 		output.Changes = append(output.Changes, change)
@@ -359,12 +472,15 @@ func compareStrings(a, b string) int {
 // the renderer performs no cleanup of its own.
 func resourceDiffToChangeDetail(diff *dt.ResourceDiff) *ChangeDetail {
 	change := &ChangeDetail{
-		Type:       diff.DiffType.ToWord(),
-		APIVersion: diff.Gvk.GroupVersion().String(),
-		Kind:       diff.Gvk.Kind,
-		Name:       diff.ResourceName,
-		Namespace:  diff.Namespace,
-		Diff:       make(map[string]any),
+		Type:          diff.DiffType.ToWord(),
+		APIVersion:    diff.Gvk.GroupVersion().String(),
+		Kind:          diff.Gvk.Kind,
+		Name:          diff.ResourceName,
+		Namespace:     diff.Namespace,
+		Diff:          make(map[string]any),
+		Warnings:      diff.Warnings,
+		RemovalReason: diff.RemovalReason,
+		Revision:      diff.Revision,
 	}
 
 	switch diff.DiffType {
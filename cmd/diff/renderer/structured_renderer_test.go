@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
@@ -82,8 +83,9 @@ func sharedDiffFixtures() []testDiffFixture {
 						Version: "v1alpha1",
 						Kind:    "XExample",
 					},
-					Current: dt.ResourceViews{Raw: modifiedCurrentResource, Clean: modifiedCurrentResource},
-					Desired: dt.ResourceViews{Raw: modifiedDesiredResource, Clean: modifiedDesiredResource},
+					Current:  dt.ResourceViews{Raw: modifiedCurrentResource, Clean: modifiedCurrentResource},
+					Desired:  dt.ResourceViews{Raw: modifiedDesiredResource, Clean: modifiedDesiredResource},
+					Revision: &dt.RevisionRef{Name: "xexamples.example.org-abc123", Number: 2},
 				},
 				"removed": {
 					DiffType:     dt.DiffTypeRemoved,
@@ -93,7 +95,8 @@ func sharedDiffFixtures() []testDiffFixture {
 						Version: "v1alpha1",
 						Kind:    "XNopResource",
 					},
-					Current: dt.ResourceViews{Raw: removedResource, Clean: removedResource},
+					Current:       dt.ResourceViews{Raw: removedResource, Clean: removedResource},
+					RemovalReason: dt.RemovalReasonNoLongerComposed,
 				},
 				"equal": {
 					DiffType:     dt.DiffTypeEqual,
@@ -133,6 +136,16 @@ func sharedDiffFixtures() []testDiffFixture {
 				if addedChange.Namespace != "default" {
 					t.Errorf("Expected Namespace 'default', got '%s'", addedChange.Namespace)
 				}
+
+				for _, change := range output.Changes {
+					if change.Type == dt.DiffTypeWordRemoved && change.RemovalReason != dt.RemovalReasonNoLongerComposed {
+						t.Errorf("Expected RemovalReason %q, got %q", dt.RemovalReasonNoLongerComposed, change.RemovalReason)
+					}
+
+					if change.Type == dt.DiffTypeWordModified && (change.Revision == nil || change.Revision.Name != "xexamples.example.org-abc123" || change.Revision.Number != 2) {
+						t.Errorf("Expected Revision xexamples.example.org-abc123 #2, got %v", change.Revision)
+					}
+				}
 			},
 		},
 		{
@@ -222,8 +235,8 @@ func TestStructuredDiffRenderer_RenderDiffs(t *testing.T) {
 					if err := sigsyaml.Unmarshal(buf.Bytes(), &output); err != nil {
 						t.Fatalf("Failed to parse YAML output: %v\nOutput: %s", err, buf.String())
 					}
-				case OutputFormatDiff:
-					t.Fatal("OutputFormatDiff should not be used with StructuredDiffRenderer")
+				case OutputFormatDiff, OutputFormatTextNoANSI, OutputFormatCSV:
+					t.Fatalf("%s should not be used with StructuredDiffRenderer", format)
 				}
 
 				// Verify summary
@@ -260,6 +273,107 @@ func TestStructuredDiffRenderer_RenderDiffs(t *testing.T) {
 	}
 }
 
+// TestStructuredDiffRenderer_SummaryOnly verifies that --summary-only writes only the summary
+// counts, computed from the diffs, and any errors.
+func TestStructuredDiffRenderer_SummaryOnly(t *testing.T) {
+	for _, fixture := range sharedDiffFixtures() {
+		t.Run(fixture.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatJSON
+			opts.SummaryOnly = true
+			opts.Stdout = &buf
+			opts.Stderr = &bytes.Buffer{}
+
+			if err := NewStructuredDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(fixture.diffs, fixture.errs); err != nil {
+				t.Fatalf("RenderDiffs() failed: %v", err)
+			}
+
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+			}
+
+			if _, ok := raw["changes"]; ok {
+				t.Errorf("summary-only output should not contain changes\nOutput: %s", buf.String())
+			}
+
+			var output StructuredSummaryOutput
+			if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+			}
+
+			if diff := cmp.Diff(fixture.expectedSummary, output.Summary); diff != "" {
+				t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+			}
+
+			if len(fixture.errs) > 0 {
+				if diff := cmp.Diff(fixture.errs, output.Errors); diff != "" {
+					t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// TestStructuredDiffRenderer_Provenance verifies that the provenance, when set, is included in
+// both full and summary-only output, and omitted otherwise.
+func TestStructuredDiffRenderer_Provenance(t *testing.T) {
+	provenance := &Provenance{
+		Server:       "https://127.0.0.1:6443",
+		Compositions: []CompositionProvenance{{Name: "database", Revision: &dt.RevisionRef{Name: "db-abc12", Number: 3}}},
+		Timestamp:    time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]struct {
+		reason      string
+		provenance  *Provenance
+		summaryOnly bool
+	}{
+		"Full": {
+			reason:     "Full output should include the provenance.",
+			provenance: provenance,
+		},
+		"SummaryOnly": {
+			reason:      "Summary-only output should include the provenance.",
+			provenance:  provenance,
+			summaryOnly: true,
+		},
+		"Unset": {
+			reason: "Output should omit the provenance when none is set.",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := DefaultDiffOptions()
+			opts.Format = OutputFormatJSON
+			opts.SummaryOnly = tt.summaryOnly
+			opts.Provenance = tt.provenance
+			opts.Stdout = &buf
+			opts.Stderr = &bytes.Buffer{}
+
+			if err := NewStructuredDiffRenderer(tu.TestLogger(t, false), opts).RenderDiffs(nil, nil); err != nil {
+				t.Fatalf("RenderDiffs() failed: %v", err)
+			}
+
+			var output struct {
+				Provenance *Provenance `json:"provenance"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+				t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+			}
+
+			if diff := cmp.Diff(tt.provenance, output.Provenance); diff != "" {
+				t.Errorf("\n%s\nRenderDiffs(...): -want provenance, +got provenance:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
 // TestStructuredDiffRenderer_RenderDiffs_ErrorsToStderr verifies that errors are
 // written to stderr for human visibility in addition to being included in the
 // structured output for machine parsing.
@@ -310,8 +424,8 @@ func TestStructuredDiffRenderer_RenderDiffs_ErrorsToStderr(t *testing.T) {
 				if err := sigsyaml.Unmarshal(stdout.Bytes(), &output); err != nil {
 					t.Fatalf("Failed to parse YAML output: %v\nOutput: %s", err, stdout.String())
 				}
-			case OutputFormatDiff:
-				t.Fatal("OutputFormatDiff should not be used with StructuredDiffRenderer")
+			case OutputFormatDiff, OutputFormatTextNoANSI, OutputFormatCSV:
+				t.Fatalf("%s should not be used with StructuredDiffRenderer", format)
 			}
 
 			if diff := cmp.Diff(errs, output.Errors); diff != "" {
@@ -523,8 +637,8 @@ func TestStructuredDiffRenderer_RespectsIgnorePaths(t *testing.T) {
 					if err := sigsyaml.Unmarshal(buf.Bytes(), &output); err != nil {
 						t.Fatalf("yaml.Unmarshal: %v\noutput: %s", err, buf.String())
 					}
-				case OutputFormatDiff:
-					t.Fatal("diff format not supported by structured renderer")
+				case OutputFormatDiff, OutputFormatTextNoANSI, OutputFormatCSV:
+					t.Fatalf("%s format not supported by structured renderer", format)
 				}
 
 				if diff := cmp.Diff(tc.wantSummary, output.Summary); diff != "" {
@@ -29,15 +29,51 @@ type ResourceViews struct {
 
 // ResourceDiff represents the diff for a specific resource.
 type ResourceDiff struct {
-	Gvk          schema.GroupVersionKind
-	Namespace    string
-	ResourceName string
-	DiffType     DiffType
-	LineDiffs    []diffmatchpatch.Diff
-	Current      ResourceViews // the resource's current (cluster) state, raw + clean
-	Desired      ResourceViews // the resource's desired (rendered) state, raw + clean
+	Gvk           schema.GroupVersionKind
+	Namespace     string
+	ResourceName  string
+	DiffType      DiffType
+	LineDiffs     []diffmatchpatch.Diff
+	Current       ResourceViews // the resource's current (cluster) state, raw + clean
+	Desired       ResourceViews // the resource's desired (rendered) state, raw + clean
+	Warnings      []string      // API server warnings returned by the dry-run apply, if recorded, and notes on how it was rendered
+	SourceFile    string        // input file of the top-level resource this diff came from, if known
+	Owner         string        // for a removed resource, diff key of the composed resource owning it, if not the XR
+	Composite     string        // diff key of the XR that composed this resource; empty for a top-level resource
+	RemovalReason RemovalReason // for a removed resource, why it would be removed
+	Revision      *RevisionRef  // for an XR, the CompositionRevision it was rendered against, if any
+	Composition   string        // for an XR, the name of the Composition it was rendered with
+
+	// RequiredResources are, for an XR, the resources its functions required, when recorded.
+	RequiredResources []RequiredResource
 }
 
+// RequiredResource identifies a resource supplied to a function that required it, and how the
+// requirement matched it.
+type RequiredResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Match      string `json:"match"` // "name", or "labels" followed by the labels, e.g. "labels team=a"
+}
+
+// RevisionRef identifies a CompositionRevision.
+type RevisionRef struct {
+	Name   string `json:"name"`
+	Number int64  `json:"number"`
+}
+
+// RemovalReason says why a resource would be removed.
+type RemovalReason string
+
+const (
+	// RemovalReasonNoLongerComposed indicates the resource's owner was rendered without it.
+	RemovalReasonNoLongerComposed RemovalReason = "no-longer-composed"
+	// RemovalReasonOwnerRemoved indicates the composed resource owning it would itself be removed.
+	RemovalReasonOwnerRemoved RemovalReason = "owner-removed"
+)
+
 // DiffType represents the type of diff (added, removed, modified).
 type DiffType string
 
@@ -218,6 +254,16 @@ func MakeDiffKey(apiVersion, kind, namespace, name string) string {
 	return fmt.Sprintf("%s/%s/%s/%s", apiVersion, kind, namespace, name)
 }
 
+// KindNameFromDiffKey returns the Kind/name part of a key made by MakeDiffKey.
+func KindNameFromDiffKey(key string) string {
+	parts := strings.Split(key, "/")
+	if len(parts) < 4 {
+		return key
+	}
+
+	return parts[len(parts)-3] + "/" + parts[len(parts)-1]
+}
+
 // MakeDiffKeyFromResource creates a unique key for a resource diff from an Unstructured resource.
 // This is a convenience wrapper around MakeDiffKey that extracts all fields from the resource.
 func MakeDiffKeyFromResource(res *un.Unstructured) string {
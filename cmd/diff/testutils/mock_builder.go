@@ -530,21 +530,21 @@ func (b *MockApplyClientBuilder) WithApply(fn func(context.Context, *un.Unstruct
 }
 
 // WithDryRunApply sets the DryRunApply behavior.
-func (b *MockApplyClientBuilder) WithDryRunApply(fn func(context.Context, *un.Unstructured, string) (*un.Unstructured, error)) *MockApplyClientBuilder {
+func (b *MockApplyClientBuilder) WithDryRunApply(fn func(context.Context, *un.Unstructured, string, bool) (*un.Unstructured, error)) *MockApplyClientBuilder {
 	b.mock.DryRunApplyFn = fn
 	return b
 }
 
 // WithSuccessfulDryRun sets DryRunApply to return the input resource.
 func (b *MockApplyClientBuilder) WithSuccessfulDryRun() *MockApplyClientBuilder {
-	return b.WithDryRunApply(func(_ context.Context, obj *un.Unstructured, _ string) (*un.Unstructured, error) {
+	return b.WithDryRunApply(func(_ context.Context, obj *un.Unstructured, _ string, _ bool) (*un.Unstructured, error) {
 		return obj, nil
 	})
 }
 
 // WithFailedDryRun sets DryRunApply to return an error.
 func (b *MockApplyClientBuilder) WithFailedDryRun(errMsg string) *MockApplyClientBuilder {
-	return b.WithDryRunApply(func(context.Context, *un.Unstructured, string) (*un.Unstructured, error) {
+	return b.WithDryRunApply(func(context.Context, *un.Unstructured, string, bool) (*un.Unstructured, error) {
 		return nil, errors.New(errMsg)
 	})
 }
@@ -689,6 +689,36 @@ func (b *MockCompositionClientBuilder) WithFindMatchingComposition(fn func(conte
 	return b
 }
 
+// WithFindMatchingCompositionAsOf sets the FindMatchingCompositionAsOf behavior.
+func (b *MockCompositionClientBuilder) WithFindMatchingCompositionAsOf(fn func(context.Context, *un.Unstructured, time.Time) (*xpextv1.Composition, error)) *MockCompositionClientBuilder {
+	b.mock.FindMatchingCompositionAsOfFn = fn
+	return b
+}
+
+// WithFindMatchingCompositionForRevision sets the FindMatchingCompositionForRevision behavior.
+func (b *MockCompositionClientBuilder) WithFindMatchingCompositionForRevision(fn func(context.Context, *un.Unstructured, string) (*xpextv1.Composition, error)) *MockCompositionClientBuilder {
+	b.mock.FindMatchingCompositionForRevisionFn = fn
+	return b
+}
+
+// WithFindMatchingCompositionAtLatestRevision sets the FindMatchingCompositionAtLatestRevision behavior.
+func (b *MockCompositionClientBuilder) WithFindMatchingCompositionAtLatestRevision(fn func(context.Context, *un.Unstructured) (*xpextv1.Composition, error)) *MockCompositionClientBuilder {
+	b.mock.FindMatchingCompositionAtLatestRevisionFn = fn
+	return b
+}
+
+// WithFindMatchingCompositionSkippingMissingRevisions sets the FindMatchingCompositionSkippingMissingRevisions behavior.
+func (b *MockCompositionClientBuilder) WithFindMatchingCompositionSkippingMissingRevisions(fn func(context.Context, *un.Unstructured) (*xpextv1.Composition, error)) *MockCompositionClientBuilder {
+	b.mock.FindMatchingCompositionSkippingMissingRevisionsFn = fn
+	return b
+}
+
+// WithExplainCompositionSelection sets the ExplainCompositionSelection behavior.
+func (b *MockCompositionClientBuilder) WithExplainCompositionSelection(fn func(context.Context, *un.Unstructured) (*dtypes.CompositionSelection, error)) *MockCompositionClientBuilder {
+	b.mock.ExplainCompositionSelectionFn = fn
+	return b
+}
+
 // WithSuccessfulCompositionMatch sets FindMatchingComposition to return a specific composition.
 func (b *MockCompositionClientBuilder) WithSuccessfulCompositionMatch(comp *xpextv1.Composition) *MockCompositionClientBuilder {
 	return b.WithFindMatchingComposition(func(context.Context, *un.Unstructured) (*xpextv1.Composition, error) {
@@ -722,7 +752,7 @@ func (b *MockCompositionClientBuilder) WithSuccessfulCompositionFetch(comp *xpex
 			return comp, nil
 		}
 
-		return nil, errors.New("composition not found")
+		return nil, errNoComposition(name)
 	})
 }
 
@@ -738,10 +768,16 @@ func (b *MockCompositionClientBuilder) WithSuccessfulCompositionFetches(comps []
 			return composition, nil
 		}
 
-		return nil, errors.New("composition not found")
+		return nil, errNoComposition(name)
 	})
 }
 
+// errNoComposition returns the NotFound error the composition client returns for a composition
+// that isn't in the cluster.
+func errNoComposition(name string) error {
+	return apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.crossplane.io", Resource: "compositions"}, name)
+}
+
 // WithFindComposites sets the FindComposites behavior.
 func (b *MockCompositionClientBuilder) WithFindComposites(fn func(context.Context, *un.Unstructured, dtypes.FindCompositesOptions) ([]*un.Unstructured, error)) *MockCompositionClientBuilder {
 	b.mock.FindCompositesFn = fn
@@ -956,6 +992,12 @@ func (b *MockEnvironmentClientBuilder) WithGetEnvironmentConfig(fn func(context.
 	return b
 }
 
+// WithGetEnvironmentConfigsBySelector sets the GetEnvironmentConfigsBySelector behavior.
+func (b *MockEnvironmentClientBuilder) WithGetEnvironmentConfigsBySelector(fn func(context.Context, metav1.LabelSelector) ([]*un.Unstructured, error)) *MockEnvironmentClientBuilder {
+	b.mock.GetEnvironmentConfigsBySelectorFn = fn
+	return b
+}
+
 // Build returns the built mock.
 func (b *MockEnvironmentClientBuilder) Build() *MockEnvironmentClient {
 	return b.mock
@@ -1108,14 +1150,30 @@ func (b *MockDefinitionClientBuilder) WithXRD(xrd *xpextv1.CompositeResourceDefi
 			return xrdObj, nil
 		}
 
-		return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+		return nil, errNoXRD("XR", gvk)
 	})
 }
 
+// errNoXRD returns the NotFound error the definition client returns when no XRD defines gvk as
+// the given type, XR or claim.
+func errNoXRD(typ string, gvk schema.GroupVersionKind) error {
+	err := apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.crossplane.io", Resource: "compositeresourcedefinitions"}, gvk.String())
+	err.ErrStatus.Message = fmt.Sprintf("no XRD found that defines %s type %s", typ, gvk.String())
+
+	return err
+}
+
 // WithXRDForXRNotFound sets GetXRDForXR to return not found error for any GVK.
 func (b *MockDefinitionClientBuilder) WithXRDForXRNotFound() *MockDefinitionClientBuilder {
 	return b.WithGetXRDForXR(func(_ context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error) {
-		return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+		return nil, errNoXRD("XR", gvk)
+	})
+}
+
+// WithXRDForClaimNotFound sets GetXRDForClaim to return not found error for any GVK.
+func (b *MockDefinitionClientBuilder) WithXRDForClaimNotFound() *MockDefinitionClientBuilder {
+	return b.WithGetXRDForClaim(func(_ context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error) {
+		return nil, errNoXRD("claim", gvk)
 	})
 }
 
@@ -1125,7 +1183,7 @@ func (b *MockDefinitionClientBuilder) WithXRDForXRNotFoundForGVK(notFoundGVK sch
 
 	return b.WithGetXRDForXR(func(ctx context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error) {
 		if gvk.Group == notFoundGVK.Group && gvk.Kind == notFoundGVK.Kind {
-			return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+			return nil, errNoXRD("XR", gvk)
 		}
 
 		// Fall through to existing function if set
@@ -1133,7 +1191,7 @@ func (b *MockDefinitionClientBuilder) WithXRDForXRNotFoundForGVK(notFoundGVK sch
 			return existingFn(ctx, gvk)
 		}
 
-		return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+		return nil, errNoXRD("XR", gvk)
 	})
 }
 
@@ -1158,7 +1216,7 @@ func (b *MockDefinitionClientBuilder) Build() *MockDefinitionClient {
 				return xrd, nil
 			}
 
-			return nil, errors.Errorf("no XRD found that defines XR type %s", gvk.String())
+			return nil, errNoXRD("XR", gvk)
 		}
 	}
 
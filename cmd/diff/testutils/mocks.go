@@ -3,6 +3,7 @@ package testutils
 import (
 	"context"
 	"io"
+	"time"
 
 	dt "github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer/types"
 	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
@@ -415,13 +416,15 @@ func (m *MockResourceClient) IsNamespacedResource(ctx context.Context, gvk schem
 
 // MockSchemaClient implements the kubernetes.SchemaClient interface.
 type MockSchemaClient struct {
-	InitializeFn       func(ctx context.Context) error
-	GetCRDFn           func(ctx context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error)
-	GetCRDByNameFn     func(name string) (*extv1.CustomResourceDefinition, error)
-	IsCRDRequiredFn    func(ctx context.Context, gvk schema.GroupVersionKind) bool
-	ValidateResourceFn func(ctx context.Context, resource *un.Unstructured) error
-	LoadCRDsFromXRDsFn func(ctx context.Context, xrds []*un.Unstructured) error
-	GetAllCRDsFn       func() []*extv1.CustomResourceDefinition
+	InitializeFn            func(ctx context.Context) error
+	GetCRDFn                func(ctx context.Context, gvk schema.GroupVersionKind) (*extv1.CustomResourceDefinition, error)
+	GetCRDByNameFn          func(name string) (*extv1.CustomResourceDefinition, error)
+	IsCRDRequiredFn         func(ctx context.Context, gvk schema.GroupVersionKind) bool
+	ValidateResourceFn      func(ctx context.Context, resource *un.Unstructured) error
+	LoadCRDsFromXRDsFn      func(ctx context.Context, xrds []*un.Unstructured) error
+	LoadCRDsFromLocalXRDsFn func(xrds []*un.Unstructured) error
+	AddLocalCRDsFn          func(crds []*extv1.CustomResourceDefinition)
+	GetAllCRDsFn            func() []*extv1.CustomResourceDefinition
 }
 
 // Initialize implements kubernetes.SchemaClient.
@@ -478,6 +481,22 @@ func (m *MockSchemaClient) LoadCRDsFromXRDs(ctx context.Context, xrds []*un.Unst
 	return nil
 }
 
+// LoadCRDsFromLocalXRDs implements kubernetes.SchemaClient.
+func (m *MockSchemaClient) LoadCRDsFromLocalXRDs(xrds []*un.Unstructured) error {
+	if m.LoadCRDsFromLocalXRDsFn != nil {
+		return m.LoadCRDsFromLocalXRDsFn(xrds)
+	}
+
+	return nil
+}
+
+// AddLocalCRDs implements kubernetes.SchemaClient.
+func (m *MockSchemaClient) AddLocalCRDs(crds []*extv1.CustomResourceDefinition) {
+	if m.AddLocalCRDsFn != nil {
+		m.AddLocalCRDsFn(crds)
+	}
+}
+
 // GetAllCRDs implements kubernetes.SchemaClient.
 func (m *MockSchemaClient) GetAllCRDs() []*extv1.CustomResourceDefinition {
 	if m.GetAllCRDsFn != nil {
@@ -491,7 +510,7 @@ func (m *MockSchemaClient) GetAllCRDs() []*extv1.CustomResourceDefinition {
 type MockApplyClient struct {
 	InitializeFn  func(ctx context.Context) error
 	ApplyFn       func(ctx context.Context, obj *un.Unstructured) (*un.Unstructured, error)
-	DryRunApplyFn func(ctx context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error)
+	DryRunApplyFn func(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error)
 }
 
 // Initialize implements kubernetes.ApplyClient.
@@ -513,9 +532,9 @@ func (m *MockApplyClient) Apply(ctx context.Context, obj *un.Unstructured) (*un.
 }
 
 // DryRunApply implements kubernetes.ApplyClient.
-func (m *MockApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string) (*un.Unstructured, error) {
+func (m *MockApplyClient) DryRunApply(ctx context.Context, obj *un.Unstructured, fieldOwner string, force bool) (*un.Unstructured, error) {
 	if m.DryRunApplyFn != nil {
-		return m.DryRunApplyFn(ctx, obj, fieldOwner)
+		return m.DryRunApplyFn(ctx, obj, fieldOwner, force)
 	}
 
 	return nil, errors.New("DryRunApply not implemented")
@@ -555,11 +574,17 @@ func (m *MockTypeConverter) GetResourceNameForGVK(ctx context.Context, gvk schem
 
 // MockCompositionClient implements the crossplane.CompositionClient interface.
 type MockCompositionClient struct {
-	InitializeFn              func(ctx context.Context) error
-	FindMatchingCompositionFn func(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error)
-	ListCompositionsFn        func(ctx context.Context) ([]*xpextv1.Composition, error)
-	GetCompositionFn          func(ctx context.Context, name string) (*xpextv1.Composition, error)
-	FindCompositesFn          func(ctx context.Context, comp *un.Unstructured, opts types.FindCompositesOptions) ([]*un.Unstructured, error)
+	InitializeFn                                      func(ctx context.Context) error
+	FindMatchingCompositionFn                         func(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error)
+	FindMatchingCompositionAsOfFn                     func(ctx context.Context, res *un.Unstructured, asOf time.Time) (*xpextv1.Composition, error)
+	FindMatchingCompositionForRevisionFn              func(ctx context.Context, res *un.Unstructured, revisionName string) (*xpextv1.Composition, error)
+	FindMatchingCompositionAtLatestRevisionFn         func(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error)
+	FindMatchingCompositionSkippingMissingRevisionsFn func(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error)
+	ExplainCompositionSelectionFn                     func(ctx context.Context, res *un.Unstructured) (*types.CompositionSelection, error)
+	ListCompositionsFn                                func(ctx context.Context) ([]*xpextv1.Composition, error)
+	GetCompositionFn                                  func(ctx context.Context, name string) (*xpextv1.Composition, error)
+	FindCompositesFn                                  func(ctx context.Context, comp *un.Unstructured, opts types.FindCompositesOptions) ([]*un.Unstructured, error)
+	AddLocalCompositionsFn                            func(comps []*xpextv1.Composition)
 }
 
 // Initialize implements crossplane.CompositionClient.
@@ -580,6 +605,51 @@ func (m *MockCompositionClient) FindMatchingComposition(ctx context.Context, res
 	return nil, errors.New("FindMatchingComposition not implemented")
 }
 
+// FindMatchingCompositionAsOf implements crossplane.CompositionClient.
+func (m *MockCompositionClient) FindMatchingCompositionAsOf(ctx context.Context, res *un.Unstructured, asOf time.Time) (*xpextv1.Composition, error) {
+	if m.FindMatchingCompositionAsOfFn != nil {
+		return m.FindMatchingCompositionAsOfFn(ctx, res, asOf)
+	}
+
+	return nil, errors.New("FindMatchingCompositionAsOf not implemented")
+}
+
+// FindMatchingCompositionForRevision implements crossplane.CompositionClient.
+func (m *MockCompositionClient) FindMatchingCompositionForRevision(ctx context.Context, res *un.Unstructured, revisionName string) (*xpextv1.Composition, error) {
+	if m.FindMatchingCompositionForRevisionFn != nil {
+		return m.FindMatchingCompositionForRevisionFn(ctx, res, revisionName)
+	}
+
+	return nil, errors.New("FindMatchingCompositionForRevision not implemented")
+}
+
+// FindMatchingCompositionAtLatestRevision implements crossplane.CompositionClient.
+func (m *MockCompositionClient) FindMatchingCompositionAtLatestRevision(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error) {
+	if m.FindMatchingCompositionAtLatestRevisionFn != nil {
+		return m.FindMatchingCompositionAtLatestRevisionFn(ctx, res)
+	}
+
+	return nil, errors.New("FindMatchingCompositionAtLatestRevision not implemented")
+}
+
+// FindMatchingCompositionSkippingMissingRevisions implements crossplane.CompositionClient.
+func (m *MockCompositionClient) FindMatchingCompositionSkippingMissingRevisions(ctx context.Context, res *un.Unstructured) (*xpextv1.Composition, error) {
+	if m.FindMatchingCompositionSkippingMissingRevisionsFn != nil {
+		return m.FindMatchingCompositionSkippingMissingRevisionsFn(ctx, res)
+	}
+
+	return nil, errors.New("FindMatchingCompositionSkippingMissingRevisions not implemented")
+}
+
+// ExplainCompositionSelection implements crossplane.CompositionClient.
+func (m *MockCompositionClient) ExplainCompositionSelection(ctx context.Context, res *un.Unstructured) (*types.CompositionSelection, error) {
+	if m.ExplainCompositionSelectionFn != nil {
+		return m.ExplainCompositionSelectionFn(ctx, res)
+	}
+
+	return nil, errors.New("ExplainCompositionSelection not implemented")
+}
+
 // ListCompositions implements crossplane.CompositionClient.
 func (m *MockCompositionClient) ListCompositions(ctx context.Context) ([]*xpextv1.Composition, error) {
 	if m.ListCompositionsFn != nil {
@@ -607,11 +677,19 @@ func (m *MockCompositionClient) FindComposites(ctx context.Context, comp *un.Uns
 	return nil, errors.New("FindComposites not implemented")
 }
 
+// AddLocalCompositions implements crossplane.CompositionClient.
+func (m *MockCompositionClient) AddLocalCompositions(comps []*xpextv1.Composition) {
+	if m.AddLocalCompositionsFn != nil {
+		m.AddLocalCompositionsFn(comps)
+	}
+}
+
 // MockFunctionClient implements the crossplane.FunctionClient interface.
 type MockFunctionClient struct {
 	InitializeFn               func(ctx context.Context) error
 	GetFunctionsFromPipelineFn func(comp *xpextv1.Composition) ([]pkgv1.Function, error)
 	ListFunctionsFn            func(ctx context.Context) ([]pkgv1.Function, error)
+	AddLocalFunctionsFn        func(fns []pkgv1.Function)
 }
 
 // Initialize implements crossplane.FunctionClient.
@@ -641,11 +719,20 @@ func (m *MockFunctionClient) ListFunctions(ctx context.Context) ([]pkgv1.Functio
 	return nil, errors.New("ListFunctions not implemented")
 }
 
+// AddLocalFunctions implements crossplane.FunctionClient.
+func (m *MockFunctionClient) AddLocalFunctions(fns []pkgv1.Function) {
+	if m.AddLocalFunctionsFn != nil {
+		m.AddLocalFunctionsFn(fns)
+	}
+}
+
 // MockEnvironmentClient implements the crossplane.EnvironmentClient interface.
 type MockEnvironmentClient struct {
-	InitializeFn            func(ctx context.Context) error
-	GetEnvironmentConfigsFn func(ctx context.Context) ([]*un.Unstructured, error)
-	GetEnvironmentConfigFn  func(ctx context.Context, name string) (*un.Unstructured, error)
+	InitializeFn                      func(ctx context.Context) error
+	GetEnvironmentConfigsFn           func(ctx context.Context) ([]*un.Unstructured, error)
+	GetEnvironmentConfigFn            func(ctx context.Context, name string) (*un.Unstructured, error)
+	GetEnvironmentConfigsBySelectorFn func(ctx context.Context, selector metav1.LabelSelector) ([]*un.Unstructured, error)
+	AddLocalEnvironmentConfigsFn      func(configs []*un.Unstructured)
 }
 
 // Initialize implements crossplane.EnvironmentClient.
@@ -675,6 +762,22 @@ func (m *MockEnvironmentClient) GetEnvironmentConfig(ctx context.Context, name s
 	return nil, errors.New("GetEnvironmentConfig not implemented")
 }
 
+// GetEnvironmentConfigsBySelector implements crossplane.EnvironmentClient.
+func (m *MockEnvironmentClient) GetEnvironmentConfigsBySelector(ctx context.Context, selector metav1.LabelSelector) ([]*un.Unstructured, error) {
+	if m.GetEnvironmentConfigsBySelectorFn != nil {
+		return m.GetEnvironmentConfigsBySelectorFn(ctx, selector)
+	}
+
+	return nil, errors.New("GetEnvironmentConfigsBySelector not implemented")
+}
+
+// AddLocalEnvironmentConfigs implements crossplane.EnvironmentClient.
+func (m *MockEnvironmentClient) AddLocalEnvironmentConfigs(configs []*un.Unstructured) {
+	if m.AddLocalEnvironmentConfigsFn != nil {
+		m.AddLocalEnvironmentConfigsFn(configs)
+	}
+}
+
 // MockDefinitionClient implements the crossplane.DefinitionClient interface.
 type MockDefinitionClient struct {
 	InitializeFn         func(ctx context.Context) error
@@ -683,6 +786,7 @@ type MockDefinitionClient struct {
 	GetXRDForXRFn        func(ctx context.Context, gvk schema.GroupVersionKind) (*un.Unstructured, error)
 	IsClaimResourceFn    func(ctx context.Context, resource *un.Unstructured) bool
 	GetCompositeSchemaFn func(ctx context.Context, gvk schema.GroupVersionKind) (cmp.Schema, error)
+	AddLocalXRDsFn       func(xrds []*un.Unstructured)
 }
 
 // Initialize implements crossplane.DefinitionClient.
@@ -739,6 +843,13 @@ func (m *MockDefinitionClient) GetCompositeSchema(ctx context.Context, gvk schem
 	return cmp.SchemaModern, errors.New("GetCompositeSchema not implemented")
 }
 
+// AddLocalXRDs implements crossplane.DefinitionClient.
+func (m *MockDefinitionClient) AddLocalXRDs(xrds []*un.Unstructured) {
+	if m.AddLocalXRDsFn != nil {
+		m.AddLocalXRDsFn(xrds)
+	}
+}
+
 // MockResourceTreeClient implements the crossplane.ResourceTreeClient interface.
 type MockResourceTreeClient struct {
 	InitializeFn      func(ctx context.Context) error
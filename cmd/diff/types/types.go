@@ -42,3 +42,17 @@ type FindCompositesOptions struct {
 	// caller derives "unmatched" from the diff between input refs and returned objects.
 	Refs []k8stypes.NamespacedName
 }
+
+// CompositionSelection describes the composition CompositionClient.ExplainCompositionSelection chose
+// for an XR or claim. Lives here for the same import-cycle reason as FindCompositesOptions.
+type CompositionSelection struct {
+	// Composition is the composition that would be used for rendering. When a revision was selected,
+	// this is the composition built from that revision.
+	Composition *apiextensionsv1.Composition
+	// RevisionName is the selected CompositionRevision, or empty when the Composition is used directly.
+	RevisionName string
+	// RevisionNumber is the selected revision's spec.revision, or zero when no revision was selected.
+	RevisionNumber int64
+	// Reasons explains, in order, how the composition and then the revision were chosen.
+	Reasons []string
+}
@@ -19,7 +19,10 @@ package versioncmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"runtime"
+	"runtime/debug"
 	"time"
 
 	"github.com/alecthomas/kong"
@@ -33,14 +36,22 @@ const (
 	errGetCrossplaneVersion = "unable to get crossplane version"
 )
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
 // fetchFunc fetches the Crossplane server version for a given REST config.
 // Exposed as a type so tests can substitute a stub on the Cmd struct.
 type fetchFunc func(ctx context.Context, cfg *rest.Config) (string, error)
 
 // Cmd represents the version command.
 type Cmd struct {
-	Client  bool            `env:""                                                          help:"If true, shows client version only (no server required)."`
-	Context kubecfg.Context `help:"Kubernetes context to use (defaults to current context)." name:"context"`
+	Client     bool            `env:""                                                                                    help:"If true, shows client version only (no server required)."`
+	Context    kubecfg.Context `help:"Kubernetes context to use (defaults to current context)."                           name:"context"`
+	Kubeconfig string          `help:"Path to the kubeconfig file to use (defaults to $KUBECONFIG, then ~/.kube/config)." name:"kubeconfig"                                               type:"path"`
+	InCluster  bool            `help:"Use the in-cluster ServiceAccount config, ignoring any kubeconfig."                 name:"in-cluster"`
+	Output     string          `default:"text"                                                                            enum:"text,json"                                                help:"Output format: text or json. JSON reports a null server version, rather than failing, when the cluster can't be reached." short:"o"`
 
 	fetch fetchFunc `kong:"-"` // test seam; nil means use FetchCrossplaneVersion.
 }
@@ -50,6 +61,15 @@ type Cmd struct {
 // honors the user's kubeconfig context.
 func (c *Cmd) GetKubeContext() kubecfg.Context { return c.Context }
 
+// GetKubeconfig implements kubecfg.KubeconfigProvider.
+func (c *Cmd) GetKubeconfig() string { return c.Kubeconfig }
+
+// GetInCluster implements kubecfg.InClusterProvider.
+func (c *Cmd) GetInCluster() bool { return c.InCluster }
+
+// GetUserAgent implements kubecfg.UserAgentProvider.
+func (c *Cmd) GetUserAgent() string { return kubecfg.DefaultUserAgent("version") }
+
 // BeforeApply binds the Cmd pointer as the kubecfg.Provider so that providers
 // resolved later (in Run) see the parsed --context value.
 func (c *Cmd) BeforeApply(ctx *kong.Context) error {
@@ -57,14 +77,82 @@ func (c *Cmd) BeforeApply(ctx *kong.Context) error {
 	return nil
 }
 
+// versionOutput is the JSON output of the version command.
+type versionOutput struct {
+	Client clientVersion  `json:"client"`
+	Server *serverVersion `json:"server"`
+}
+
+// clientVersion describes the crossplane-diff build.
+type clientVersion struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Commit    string `json:"commit,omitempty"`
+}
+
+// serverVersion describes the Crossplane installation in the cluster.
+type serverVersion struct {
+	Version string `json:"version"`
+}
+
 // Run runs the version command.
 func (c *Cmd) Run(k *kong.Context) error {
+	if c.Output == outputJSON {
+		return c.runJSON(k)
+	}
+
 	_, _ = fmt.Fprintln(k.Stdout, "Client Version: "+versioninfo.New().GetVersionString())
 
 	if c.Client {
 		return nil
 	}
 
+	vxp, err := c.serverVersion()
+	if err != nil {
+		return err
+	}
+
+	if vxp != "" {
+		_, _ = fmt.Fprintln(k.Stdout, "Server Version: "+vxp)
+	}
+
+	return nil
+}
+
+// runJSON prints the client and server versions as JSON. A server version that can't be
+// fetched is reported as null, with the reason on stderr, so the client version is always
+// available.
+func (c *Cmd) runJSON(k *kong.Context) error {
+	out := versionOutput{
+		Client: clientVersion{
+			Version:   versioninfo.New().GetVersionString(),
+			GoVersion: runtime.Version(),
+			Commit:    buildCommit(),
+		},
+	}
+
+	if !c.Client {
+		vxp, err := c.serverVersion()
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintln(k.Stderr, "Warning: "+err.Error())
+		case vxp != "":
+			out.Server = &serverVersion{Version: vxp}
+		}
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal version to JSON")
+	}
+
+	_, err = fmt.Fprintln(k.Stdout, string(b))
+
+	return errors.Wrap(err, "cannot write version")
+}
+
+// serverVersion fetches the Crossplane version running in the cluster.
+func (c *Cmd) serverVersion() (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -73,7 +161,7 @@ func (c *Cmd) Run(k *kong.Context) error {
 	// in-cluster only when no kubeconfig is available.
 	cfg, err := kubecfg.Provide(c)
 	if err != nil {
-		return errors.Wrap(err, errGetCrossplaneVersion)
+		return "", errors.Wrap(err, errGetCrossplaneVersion)
 	}
 
 	fetch := c.fetch
@@ -83,12 +171,25 @@ func (c *Cmd) Run(k *kong.Context) error {
 
 	vxp, err := fetch(ctx, cfg)
 	if err != nil {
-		return errors.Wrap(err, errGetCrossplaneVersion)
+		return "", errors.Wrap(err, errGetCrossplaneVersion)
 	}
 
-	if vxp != "" {
-		_, _ = fmt.Fprintln(k.Stdout, "Server Version: "+vxp)
+	return vxp, nil
+}
+
+// buildCommit returns the VCS revision the binary was built from, if the Go toolchain
+// recorded one.
+func buildCommit() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
 	}
 
-	return nil
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+
+	return ""
 }
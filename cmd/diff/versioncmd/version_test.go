@@ -19,7 +19,9 @@ package versioncmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -30,10 +32,11 @@ import (
 )
 
 // newKongCtx returns a kong.Context wired with an in-memory stdout buffer so
-// tests can assert command output without touching the real stdout.
+// tests can assert command output without touching the real stdout. Stderr is
+// discarded.
 func newKongCtx(buf *bytes.Buffer) *kong.Context {
 	return &kong.Context{
-		Kong: &kong.Kong{Stdout: buf},
+		Kong: &kong.Kong{Stdout: buf, Stderr: io.Discard},
 	}
 }
 
@@ -123,6 +126,63 @@ func TestCmd_Run_ServerFetchErrorIsWrapped(t *testing.T) {
 	}
 }
 
+func TestCmd_Run_JSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	cmd := &Cmd{
+		Output: outputJSON,
+		fetch: func(context.Context, *rest.Config) (string, error) {
+			return "v2.0.2", nil
+		},
+	}
+
+	withTempKubeconfig(t)
+
+	if err := cmd.Run(newKongCtx(&buf)); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	var got versionOutput
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output %q is not valid JSON: %v", buf.String(), err)
+	}
+
+	if got.Client.GoVersion == "" {
+		t.Errorf("client version %+v missing Go version", got.Client)
+	}
+
+	if got.Server == nil || got.Server.Version != "v2.0.2" {
+		t.Errorf("server version = %+v, want v2.0.2", got.Server)
+	}
+}
+
+func TestCmd_Run_JSONServerUnreachable(t *testing.T) {
+	// In JSON mode an unreachable cluster yields a null server version and a
+	// warning on stderr, rather than failing the command.
+	var stdout, stderr bytes.Buffer
+
+	cmd := &Cmd{
+		Output: outputJSON,
+		fetch: func(context.Context, *rest.Config) (string, error) {
+			return "", errors.New("boom")
+		},
+	}
+
+	withTempKubeconfig(t)
+
+	if err := cmd.Run(&kong.Context{Kong: &kong.Kong{Stdout: &stdout, Stderr: &stderr}}); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	if !strings.Contains(stdout.String(), `"server": null`) {
+		t.Errorf("output %q should report a null server version", stdout.String())
+	}
+
+	if !strings.Contains(stderr.String(), "boom") {
+		t.Errorf("stderr %q missing underlying cause 'boom'", stderr.String())
+	}
+}
+
 func TestCmd_Run_ServerVersionEmptyDoesNotPrint(t *testing.T) {
 	// If the fetcher returns an empty string without error, we should not
 	// print a bogus "Server Version: " line.
@@ -18,14 +18,23 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
 	dp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/diffprocessor"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/types"
 	ld "github.com/crossplane/cli/v2/cmd/crossplane/common/load"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
 )
 
 // XRCmd represents the XR diff command.
@@ -33,7 +42,76 @@ type XRCmd struct {
 	// Embed common fields
 	CommonCmdFields
 
-	Files []string `arg:"" help:"YAML files containing Crossplane resources to diff." optional:""`
+	Files []string `arg:"" help:"YAML files, directories, or glob patterns containing Crossplane resources to diff, or - to read a YAML stream from stdin." optional:""`
+
+	CompositionRevisionAsOf time.Time      `aliases:"revision-as-of"                                                                                                                                                                  help:"Render against the CompositionRevision that was current at this time (RFC3339, e.g. 2026-01-10T12:00:00Z) instead of the live Composition." name:"composition-revision-as-of" placeholder:"TIME"          xor:"composition-selection"`
+	CompositionMap          CompositionMap `help:"YAML file mapping resource kind to composition name (e.g. 'XDatabase: database-v2'). Overrides composition selection for those kinds, including nested XRs."                        name:"composition-map"                                                                                                                            placeholder:"PATH"                xor:"composition-selection"`
+	CompositionRevision     string         `help:"Render every input XR against this CompositionRevision instead of the one its update policy selects. The revision must belong to each XR's composition; nested XRs are unaffected." name:"composition-revision"                                                                                                                       placeholder:"NAME"                xor:"composition-selection"`
+
+	FilterKinds []string `help:"Only show diffs for resources of these kinds (Kind, Kind.group or group/Kind). The input XRs are always shown, and the full resource tree is still rendered and diffed." name:"filter-kind" placeholder:"KIND"`
+
+	FilterNamespace string `help:"Only show diffs for resources in this namespace. The full resource tree is still rendered and diffed." name:"filter-namespace" placeholder:"NAMESPACE"`
+
+	Inspect string `help:"Print the observed and desired objects of the resource KIND/NAME, as compared, instead of the diff." name:"inspect" placeholder:"KIND/NAME"`
+
+	OnlyChanged bool `help:"Hide resources with no changed lines from the output. Added and removed resources are always shown, as are the summary and section headers." name:"only-changed"`
+
+	Baseline BaselineFile `help:"JSON or YAML output of an earlier run (--output=json). Only show changes that are new or different since then, then list new, resolved and unchanged drift." name:"baseline" placeholder:"FILE"`
+
+	ShowProvenance bool `help:"Lead the diff with what it was computed against: the cluster's API server URL, the compositions and revisions the XRs were rendered with, and a timestamp. Always included in JSON and YAML output." name:"show-provenance"`
+
+	AllowManaged bool `help:"Diff input resources that aren't XRs or claims, such as managed resources created directly, with a dry-run apply instead of failing to find their composition." name:"allow-managed"`
+
+	ValidateOnly bool `help:"Only resolve each resource's composition and functions and validate it against its schema, without rendering. Writes a JSON array of {resource, ok, errors} results." name:"validate-only"`
+
+	WithImpact bool `help:"After diffing, also show the impact on every other XR using each input resource's composition, as the comp command does." name:"with-impact"`
+}
+
+// validateFlags returns an error if incompatible flags are set together.
+func (c *XRCmd) validateFlags() error {
+	if c.WithImpact && c.xrOnlyOutput() {
+		return errors.Errorf("--with-impact cannot be used with --output=%s", c.Output)
+	}
+
+	for _, k := range c.FilterKinds {
+		if strings.TrimSpace(k) == "" || strings.HasPrefix(k, "/") || strings.HasSuffix(k, "/") {
+			return errors.Errorf("invalid --filter-kind %q: expected Kind, Kind.group or group/Kind", k)
+		}
+	}
+
+	if c.Inspect != "" {
+		if kind, name, ok := strings.Cut(c.Inspect, "/"); !ok || kind == "" || name == "" {
+			return errors.Errorf("invalid --inspect %q: expected KIND/NAME", c.Inspect)
+		}
+
+		if c.WithImpact {
+			return errors.New("--inspect cannot be used with --with-impact")
+		}
+
+		if c.SummaryOnly {
+			return errors.New("--inspect cannot be used with --summary-only")
+		}
+	}
+
+	if c.LatestRevision && (c.CompositionRevision != "" || !c.CompositionRevisionAsOf.IsZero()) {
+		return errors.New("--latest-revision cannot be used with --composition-revision or --composition-revision-as-of")
+	}
+
+	if c.ValidateOnly {
+		switch {
+		case c.WithImpact:
+			return errors.New("--validate-only cannot be used with --with-impact")
+		case c.Inspect != "":
+			return errors.New("--validate-only cannot be used with --inspect")
+		case c.SummaryOnly:
+			return errors.New("--validate-only cannot be used with --summary-only")
+		case c.Output != "" && c.Output != string(renderer.OutputFormatDiff) &&
+			c.Output != string(renderer.OutputFormatTextNoANSI) && c.Output != string(renderer.OutputFormatJSON):
+			return errors.Errorf("--validate-only always writes JSON and cannot be used with --output=%s", c.Output)
+		}
+	}
+
+	return nil
 }
 
 // Help returns help instructions for the XR diff command.
@@ -62,13 +140,49 @@ Examples:
 
   # Show eventual state with function-sequencer (all stages, not just first).
   crossplane-diff xr xr.yaml --eventual-state
-`
+
+  # Only show the changes to resources in the team-a namespace.
+  crossplane-diff xr xr.yaml --filter-namespace=team-a
+
+  # Only show the changes to Buckets and to the XR itself.
+  crossplane-diff xr xr.yaml --filter-kind=Bucket.s3.aws.upbound.io
+
+  # Print the observed and desired objects that the diff of one resource compares.
+  crossplane-diff xr xr.yaml --inspect=Bucket/my-bucket
+
+  # Check that each resource resolves a composition and functions and matches its schema,
+  # without rendering, and print the results as JSON.
+  crossplane-diff xr xrs/ --validate-only
+
+  # Show the changes, then the impact on every other XR using the same composition(s).
+  crossplane-diff xr xr.yaml --with-impact
+
+  # Show the changes against the composition revision that was current at a point in time.
+  crossplane-diff xr xr.yaml --composition-revision-as-of=2026-01-10T12:00:00Z
+
+  # Render each kind in a directory of XRs against the composition named for it in comp-map.yaml.
+  crossplane-diff xr xrs/ --composition-map=comp-map.yaml
+
+  # Print the complete desired state as multi-document YAML, e.g. to pipe into kubectl.
+  crossplane-diff xr xr.yaml --output=desired | kubectl apply --dry-run=server -f -
+
+  # Summarize the changes as CSV, one row per changed resource, for review in a spreadsheet.
+  crossplane-diff xr xrs/ --output=csv > changes.csv
+` + exitCodeHelp()
 }
 
 // AfterApply implements kong's AfterApply method to bind command-specific dependencies.
 // AppContext is received via dependency injection - Kong resolves it through the provider chain:
 // ContextProvider (bound in CommonCmdFields.BeforeApply) -> provideRestConfig -> provideAppContext.
 func (c *XRCmd) AfterApply(ctx *kong.Context, log logging.Logger, appCtx *AppContext) error {
+	if err := c.validateFlags(); err != nil {
+		return err
+	}
+
+	if err := c.openOutputFile(ctx); err != nil {
+		return err
+	}
+
 	proc := makeDefaultXRProc(c, ctx, appCtx, log)
 
 	loader, err := makeDefaultXRLoader(c)
@@ -90,19 +204,60 @@ func makeDefaultXRProc(c *XRCmd, kongCtx *kong.Context, appCtx *AppContext, log
 		dp.WithStderr(kongCtx.Stderr),
 	)
 
+	if c.FilterNamespace != "" {
+		opts = append(opts, dp.WithFilterNamespace(c.FilterNamespace))
+	}
+
+	if len(c.FilterKinds) > 0 {
+		opts = append(opts, dp.WithFilterKinds(filterKinds(c.FilterKinds)))
+	}
+
+	if c.OnlyChanged {
+		opts = append(opts, dp.WithOnlyChanged(true))
+	}
+
+	if c.Baseline.Value != nil {
+		opts = append(opts, dp.WithBaseline(c.Baseline.Value))
+	}
+
+	if p := c.provenance(appCtx); p != nil {
+		opts = append(opts, dp.WithProvenance(p))
+	}
+
+	if c.Inspect != "" {
+		opts = append(opts, dp.WithInspect(c.Inspect))
+	}
+
+	if c.AllowManaged {
+		opts = append(opts, dp.WithAllowManaged(true))
+	}
+
+	if c.ValidateOnly {
+		opts = append(opts, dp.WithValidateOnly(true))
+	}
+
 	return dp.NewDiffProcessor(appCtx.K8sClients, appCtx.XpClients, opts...)
 }
 
+// provenance returns the provenance to record with the diff, which structured output always
+// includes and human-readable output only with --show-provenance, or nil.
+func (c *XRCmd) provenance(appCtx *AppContext) *renderer.Provenance {
+	if format := renderer.OutputFormat(c.Output); !c.ShowProvenance && format != renderer.OutputFormatJSON && format != renderer.OutputFormatYAML {
+		return nil
+	}
+
+	return &renderer.Provenance{Server: appCtx.Server, ObservedDir: appCtx.ObservedDir}
+}
+
 func makeDefaultXRLoader(c *XRCmd) (ld.Loader, error) {
-	return ld.NewCompositeLoader(c.Files)
+	return NewSourceFileLoader(c.Files)
 }
 
 // Run executes the XR diff command.
-func (c *XRCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, proc dp.DiffProcessor, loader ld.Loader, exitCode *ExitCode) error {
+func (c *XRCmd) Run(kongCtx *kong.Context, log logging.Logger, appCtx *AppContext, proc dp.DiffProcessor, loader ld.Loader, exitCode *ExitCode) error {
 	// the rest config here is provided by a function in main.go that's only invoked for commands that request it
 	// in their arguments.  that means we won't get "can't find kubeconfig" errors for cases where the config isn't asked for.
 
-	// TODO:  add a file output option
 	// TODO:  make sure namespacing works everywhere; what to do with the -n argument?
 	// TODO:  test for the case of applying a namespaced object inside a composition using fn-gotemplating inside fn-kubectl?
 	// TODO:  add test for new vs updated XRs with downstream fields plumbed from Status field
@@ -116,6 +271,17 @@ func (c *XRCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, pro
 	}
 	defer cancel()
 
+	if err := registerLocalDefinitions(appCtx, &c.CommonCmdFields, log); err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return err
+	}
+
+	defer func() {
+		if err := c.closeOutputFile(); err != nil {
+			log.Info("Failed to close output file", "error", err)
+		}
+	}()
+
 	// Cleanup any resources held by the processor (e.g., Docker containers)
 	defer func() {
 		// Use background context with timeout for cleanup instead of the command context.
@@ -137,13 +303,25 @@ func (c *XRCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, pro
 		return errors.Wrap(err, "cannot load resources")
 	}
 
+	resources, err = registerLocalXRDs(appCtx, resources, log)
+	if err != nil {
+		exitCode.Code = dp.ExitCodeToolError
+		return err
+	}
+
 	err = proc.Initialize(ctx)
 	if err != nil {
 		exitCode.Code = dp.ExitCodeToolError
 		return errors.Wrap(err, "cannot initialize diff processor")
 	}
 
-	hasDiffs, err := proc.PerformDiff(ctx, resources, appCtx.XpClients.Composition.FindMatchingComposition)
+	hasDiffs, err := proc.PerformDiff(ctx, resources, c.compositionProvider(appCtx, resources))
+
+	if c.WithImpact {
+		hasImpact, impactErr := c.diffImpact(ctx, kongCtx, log, appCtx, proc, resources)
+		hasDiffs = hasDiffs || hasImpact
+		err = errors.Join(err, impactErr)
+	}
 
 	// Determine exit code based on result
 	exitCode.Code = dp.DetermineExitCode(err, hasDiffs)
@@ -153,3 +331,176 @@ func (c *XRCmd) Run(_ *kong.Context, log logging.Logger, appCtx *AppContext, pro
 
 	return nil
 }
+
+// compositionProvider returns the composition lookup used for rendering. By default this is the live
+// composition match; with --composition-revision-as-of it is the revision that was current at that time,
+// and with --composition-revision it is the named revision for the input resources.
+func (c *XRCmd) compositionProvider(appCtx *AppContext, resources []*un.Unstructured) types.CompositionProvider {
+	match := c.matchComposition(appCtx.XpClients.Composition)
+
+	if len(c.CompositionMap.Value) > 0 {
+		return compositionMapProvider(appCtx.XpClients.Composition, c.CompositionMap.Value, match)
+	}
+
+	if c.CompositionRevision != "" {
+		return compositionRevisionProvider(appCtx.XpClients.Composition, c.CompositionRevision, resources, match)
+	}
+
+	if c.CompositionRevisionAsOf.IsZero() {
+		return match
+	}
+
+	asOf := c.CompositionRevisionAsOf
+
+	return func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+		return appCtx.XpClients.Composition.FindMatchingCompositionAsOf(ctx, res, asOf)
+	}
+}
+
+// matchComposition returns the live composition match, resolving revisions as --latest-revision and
+// --skip-missing-revisions say.
+func (c *XRCmd) matchComposition(client xp.CompositionClient) types.CompositionProvider {
+	switch {
+	case c.LatestRevision:
+		return client.FindMatchingCompositionAtLatestRevision
+	case c.SkipMissingRevisions:
+		return client.FindMatchingCompositionSkippingMissingRevisions
+	default:
+		return client.FindMatchingComposition
+	}
+}
+
+// compositionRevisionProvider renders each of the input resources against the named
+// CompositionRevision. Nested XRs, which may use other compositions, fall through to match.
+func compositionRevisionProvider(client xp.CompositionClient, revision string, inputs []*un.Unstructured, match types.CompositionProvider) types.CompositionProvider {
+	key := func(res *un.Unstructured) string {
+		return fmt.Sprintf("%s/%s/%s", res.GroupVersionKind().String(), res.GetNamespace(), res.GetName())
+	}
+
+	pinned := make(map[string]bool, len(inputs))
+	for _, res := range inputs {
+		pinned[key(res)] = true
+	}
+
+	return func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+		if !pinned[key(res)] {
+			return match(ctx, res)
+		}
+
+		return client.FindMatchingCompositionForRevision(ctx, res, revision)
+	}
+}
+
+// compositionMapProvider overrides composition selection for the kinds in kinds, using the named
+// composition from the cluster. Other kinds fall through to match.
+func compositionMapProvider(client xp.CompositionClient, kinds map[string]string, match types.CompositionProvider) types.CompositionProvider {
+	return func(ctx context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+		name, ok := kinds[res.GetKind()]
+		if !ok {
+			return match(ctx, res)
+		}
+
+		comp, err := client.GetComposition(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get composition %s mapped to kind %s by --composition-map", name, res.GetKind())
+		}
+
+		return comp, nil
+	}
+}
+
+// diffImpact runs composition impact analysis, as the comp command does, for each composition the
+// input resources resolve to. The XR processor is reused as the comp processor's peer so function
+// runtimes started for the XR diff are shared, and cleaned up with it.
+func (c *XRCmd) diffImpact(ctx context.Context, kongCtx *kong.Context, log logging.Logger, appCtx *AppContext, proc dp.DiffProcessor, resources []*un.Unstructured) (bool, error) {
+	comps, err := c.impactCompositions(ctx, log, appCtx, resources)
+	if err != nil {
+		return false, err
+	}
+
+	if len(comps) == 0 {
+		log.Debug("No compositions resolved for impact analysis")
+		return false, nil
+	}
+
+	// Structured output is written as two consecutive documents; only the human-readable diff gets
+	// a separator between the XR diff and the impact report.
+	if format := renderer.OutputFormat(c.Output); format != renderer.OutputFormatJSON && format != renderer.OutputFormatYAML {
+		if _, err := fmt.Fprint(kongCtx.Stdout, "\n"+strings.Repeat("=", 80)+"\n\n"); err != nil {
+			return false, errors.Wrap(err, "cannot write impact separator")
+		}
+	}
+
+	opts := defaultProcessorOptions(c.CommonCmdFields)
+	opts = append(opts,
+		dp.WithLogger(log),
+		dp.WithStdout(kongCtx.Stdout),
+		dp.WithStderr(kongCtx.Stderr),
+	)
+
+	compProc := dp.NewCompDiffProcessor(proc, appCtx.XpClients.Composition, opts...)
+
+	hasDiffs, err := compProc.DiffComposition(ctx, comps, "", nil)
+
+	return hasDiffs, errors.Wrap(err, "cannot analyze composition impact")
+}
+
+// impactCompositions returns the live cluster Composition for each distinct composition the input
+// resources resolve to, in first-seen order. Resources whose composition cannot be resolved are
+// skipped; the XR diff has already reported them.
+func (c *XRCmd) impactCompositions(ctx context.Context, log logging.Logger, appCtx *AppContext, resources []*un.Unstructured) ([]*un.Unstructured, error) {
+	find := c.compositionProvider(appCtx, resources)
+	seen := make(map[string]bool)
+
+	var comps []*un.Unstructured
+
+	for _, res := range resources {
+		resolved, err := find(ctx, res)
+		if err != nil {
+			log.Debug("Skipping impact analysis for resource without a composition",
+				"resource", fmt.Sprintf("%s/%s", res.GetKind(), res.GetName()),
+				"error", err)
+
+			continue
+		}
+
+		if seen[resolved.GetName()] {
+			continue
+		}
+
+		seen[resolved.GetName()] = true
+
+		live, err := appCtx.XpClients.Composition.GetComposition(ctx, resolved.GetName())
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get composition %s for impact analysis", resolved.GetName())
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert composition %s", live.GetName())
+		}
+
+		u := &un.Unstructured{Object: obj}
+		u.SetGroupVersionKind(apiextensionsv1.CompositionGroupVersionKind)
+
+		comps = append(comps, u)
+	}
+
+	return comps, nil
+}
+
+// filterKinds rewrites any group/Kind entries of --filter-kind as Kind.group, the form the
+// processor matches against.
+func filterKinds(kinds []string) []string {
+	out := make([]string, 0, len(kinds))
+
+	for _, k := range kinds {
+		if group, kind, ok := strings.Cut(k, "/"); ok {
+			k = kind + "." + group
+		}
+
+		out = append(out, k)
+	}
+
+	return out
+}
@@ -0,0 +1,532 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	xp "github.com/crossplane-contrib/crossplane-diff/cmd/diff/client/crossplane"
+	"github.com/crossplane-contrib/crossplane-diff/cmd/diff/renderer"
+	tu "github.com/crossplane-contrib/crossplane-diff/cmd/diff/testutils"
+	"github.com/google/go-cmp/cmp"
+	un "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/errors"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/v2/apiextensions/v1"
+)
+
+func TestXRCmd_CompositionRevisionAsOf(t *testing.T) {
+	asOf := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		args     []string
+		wantAsOf time.Time
+		wantComp string
+	}{
+		"NotSet": {
+			args:     []string{"xr", "<file>"},
+			wantComp: "live-comp",
+		},
+		"Set": {
+			args:     []string{"xr", "<file>", "--composition-revision-as-of=2026-01-10T12:00:00Z"},
+			wantAsOf: asOf,
+			wantComp: "revision-comp",
+		},
+		"Alias": {
+			args:     []string{"xr", "<file>", "--revision-as-of=2026-01-10T12:00:00Z"},
+			wantAsOf: asOf,
+			wantComp: "revision-comp",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+
+			if !c.XR.CompositionRevisionAsOf.Equal(tt.wantAsOf) {
+				t.Errorf("CompositionRevisionAsOf: want %v, got %v", tt.wantAsOf, c.XR.CompositionRevisionAsOf)
+			}
+
+			var gotAsOf time.Time
+
+			appCtx := &AppContext{XpClients: xp.Clients{Composition: tu.NewMockCompositionClient().
+				WithSuccessfulCompositionMatch(tu.NewComposition("live-comp").Build()).
+				WithFindMatchingCompositionAsOf(func(_ context.Context, _ *un.Unstructured, at time.Time) (*apiextensionsv1.Composition, error) {
+					gotAsOf = at
+					return tu.NewComposition("revision-comp").Build(), nil
+				}).
+				Build(),
+			}}
+
+			comp, err := c.XR.compositionProvider(appCtx, nil)(t.Context(), tu.NewResource("example.org/v1", "XR1", "my-xr").Build())
+			if err != nil {
+				t.Fatalf("unexpected provider error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantComp, comp.GetName()); diff != "" {
+				t.Errorf("composition provider: -want, +got:\n%s", diff)
+			}
+
+			if !gotAsOf.Equal(tt.wantAsOf) {
+				t.Errorf("FindMatchingCompositionAsOf time: want %v, got %v", tt.wantAsOf, gotAsOf)
+			}
+		})
+	}
+}
+
+func TestXRCmd_CompositionRevision(t *testing.T) {
+	input := tu.NewResource("example.org/v1", "XR1", "my-xr").Build()
+	nested := tu.NewResource("example.org/v1", "XNested", "my-xr-nested").Build()
+
+	tests := map[string]struct {
+		reason   string
+		args     []string
+		res      *un.Unstructured
+		wantComp string
+		wantRev  string
+	}{
+		"NotSet": {
+			reason:   "Without the flag, the live composition match should be used.",
+			args:     []string{"xr", "<file>"},
+			res:      input,
+			wantComp: "live-comp",
+		},
+		"InputResource": {
+			reason:   "An input resource should be rendered against the named revision.",
+			args:     []string{"xr", "<file>", "--composition-revision=live-comp-abc123"},
+			res:      input,
+			wantComp: "revision-comp",
+			wantRev:  "live-comp-abc123",
+		},
+		"NestedResource": {
+			reason:   "A nested XR, which may use another composition, should use the normal match.",
+			args:     []string{"xr", "<file>", "--composition-revision=live-comp-abc123"},
+			res:      nested,
+			wantComp: "live-comp",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if err != nil {
+				t.Fatalf("\n%s\nunexpected parse error: %v", tt.reason, err)
+			}
+
+			var gotRev string
+
+			appCtx := &AppContext{XpClients: xp.Clients{Composition: tu.NewMockCompositionClient().
+				WithSuccessfulCompositionMatch(tu.NewComposition("live-comp").Build()).
+				WithFindMatchingCompositionForRevision(func(_ context.Context, _ *un.Unstructured, revision string) (*apiextensionsv1.Composition, error) {
+					gotRev = revision
+					return tu.NewComposition("revision-comp").Build(), nil
+				}).
+				Build(),
+			}}
+
+			comp, err := c.XR.compositionProvider(appCtx, []*un.Unstructured{input})(t.Context(), tt.res)
+			if err != nil {
+				t.Fatalf("\n%s\nunexpected provider error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantComp, comp.GetName()); diff != "" {
+				t.Errorf("\n%s\ncomposition provider: -want, +got:\n%s", tt.reason, diff)
+			}
+
+			if gotRev != tt.wantRev {
+				t.Errorf("\n%s\nFindMatchingCompositionForRevision revision: want %q, got %q", tt.reason, tt.wantRev, gotRev)
+			}
+		})
+	}
+}
+
+func TestXRCmd_CompositionMap(t *testing.T) {
+	dir := t.TempDir()
+
+	mapFile := filepath.Join(dir, "comp-map.yaml")
+	if err := os.WriteFile(mapFile, []byte("XDatabase: database-v2\nXNetwork: missing-comp\n"), 0o600); err != nil {
+		t.Fatalf("write map file: %v", err)
+	}
+
+	badFile := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(badFile, []byte("XDatabase:\n  name: database-v2\n"), 0o600); err != nil {
+		t.Fatalf("write bad file: %v", err)
+	}
+
+	tests := map[string]struct {
+		reason       string
+		args         []string
+		kind         string
+		wantKinds    map[string]string
+		wantComp     string
+		wantErr      string
+		wantParseErr string
+	}{
+		"NotSet": {
+			reason:   "Without the flag the live composition match should be used.",
+			args:     []string{"xr", "<file>"},
+			kind:     "XDatabase",
+			wantComp: "live-comp",
+		},
+		"MappedKind": {
+			reason:    "A mapped kind should render against the named composition.",
+			args:      []string{"xr", "<file>", "--composition-map=" + mapFile},
+			kind:      "XDatabase",
+			wantKinds: map[string]string{"XDatabase": "database-v2", "XNetwork": "missing-comp"},
+			wantComp:  "database-v2",
+		},
+		"UnmappedKind": {
+			reason:    "A kind missing from the map should fall through to the live composition match.",
+			args:      []string{"xr", "<file>", "--composition-map=" + mapFile},
+			kind:      "XCache",
+			wantKinds: map[string]string{"XDatabase": "database-v2", "XNetwork": "missing-comp"},
+			wantComp:  "live-comp",
+		},
+		"MappedCompositionMissing": {
+			reason:    "A mapped composition that can't be fetched should be an error, not a silent fallback.",
+			args:      []string{"xr", "<file>", "--composition-map=" + mapFile},
+			kind:      "XNetwork",
+			wantKinds: map[string]string{"XDatabase": "database-v2", "XNetwork": "missing-comp"},
+			wantErr:   "cannot get composition missing-comp mapped to kind XNetwork",
+		},
+		"NotAKindMapping": {
+			reason:       "A file that isn't a flat kind-to-name mapping should be rejected.",
+			args:         []string{"xr", "<file>", "--composition-map=" + badFile},
+			wantParseErr: "expected a mapping of kind to composition name",
+		},
+		"WithRevisionAsOf": {
+			reason:       "The map and --composition-revision-as-of both select the composition, so they are exclusive.",
+			args:         []string{"xr", "<file>", "--composition-map=" + mapFile, "--composition-revision-as-of=2026-01-10T12:00:00Z"},
+			wantParseErr: "can't be used together",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if tt.wantParseErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantParseErr) {
+					t.Fatalf("%s\nwant parse error containing %q, got %v", tt.reason, tt.wantParseErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nunexpected parse error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantKinds, c.XR.CompositionMap.Value); diff != "" {
+				t.Errorf("%s\nCompositionMap.Value: -want, +got:\n%s", tt.reason, diff)
+			}
+
+			appCtx := &AppContext{XpClients: xp.Clients{Composition: tu.NewMockCompositionClient().
+				WithSuccessfulCompositionMatch(tu.NewComposition("live-comp").Build()).
+				WithSuccessfulCompositionFetch(tu.NewComposition("database-v2").Build()).
+				Build(),
+			}}
+
+			comp, err := c.XR.compositionProvider(appCtx, nil)(t.Context(), tu.NewResource("example.org/v1", tt.kind, "my-xr").Build())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("%s\nwant provider error containing %q, got %v", tt.reason, tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s\nunexpected provider error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantComp, comp.GetName()); diff != "" {
+				t.Errorf("%s\ncomposition provider: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestXRCmd_RevisionSelection(t *testing.T) {
+	tests := map[string]struct {
+		reason   string
+		args     []string
+		wantComp string
+	}{
+		"Default": {
+			reason:   "Without revision flags, the XR should use the live composition match.",
+			args:     []string{"xr", "<file>"},
+			wantComp: "live-comp",
+		},
+		"LatestRevision": {
+			reason:   "With --latest-revision, the XR should be matched at its composition's latest revision.",
+			args:     []string{"xr", "<file>", "--latest-revision"},
+			wantComp: "latest-comp",
+		},
+		"SkipMissingRevisions": {
+			reason:   "With --skip-missing-revisions, the XR should be matched skipping missing pinned revisions.",
+			args:     []string{"xr", "<file>", "--skip-missing-revisions"},
+			wantComp: "skipping-comp",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := parseArgs(t, tt.args...)
+			if err != nil {
+				t.Fatalf("%s\nunexpected parse error: %v", tt.reason, err)
+			}
+
+			appCtx := &AppContext{XpClients: xp.Clients{Composition: tu.NewMockCompositionClient().
+				WithSuccessfulCompositionMatch(tu.NewComposition("live-comp").Build()).
+				WithFindMatchingCompositionAtLatestRevision(func(context.Context, *un.Unstructured) (*apiextensionsv1.Composition, error) {
+					return tu.NewComposition("latest-comp").Build(), nil
+				}).
+				WithFindMatchingCompositionSkippingMissingRevisions(func(context.Context, *un.Unstructured) (*apiextensionsv1.Composition, error) {
+					return tu.NewComposition("skipping-comp").Build(), nil
+				}).
+				Build(),
+			}}
+
+			comp, err := c.XR.compositionProvider(appCtx, nil)(t.Context(), tu.NewResource("example.org/v1", "XR1", "my-xr").Build())
+			if err != nil {
+				t.Fatalf("%s\nunexpected provider error: %v", tt.reason, err)
+			}
+
+			if diff := cmp.Diff(tt.wantComp, comp.GetName()); diff != "" {
+				t.Errorf("%s\ncomposition provider: -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestXRCmd_ValidateFlags(t *testing.T) {
+	tests := map[string]struct {
+		reason  string
+		cmd     XRCmd
+		wantErr string
+	}{
+		"CSVOutput": {
+			reason: "CSV output on its own should be accepted.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "csv"}},
+		},
+		"ImpactWithDiffOutput": {
+			reason: "--with-impact should be accepted with the default output.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "diff"}, WithImpact: true},
+		},
+		"ImpactWithCSVOutput": {
+			reason:  "The impact report has no CSV form, so --with-impact should be rejected with --output=csv.",
+			cmd:     XRCmd{CommonCmdFields: CommonCmdFields{Output: "csv"}, WithImpact: true},
+			wantErr: "--with-impact cannot be used with --output=csv",
+		},
+		"DesiredOutput": {
+			reason: "Desired state output on its own should be accepted.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "desired"}},
+		},
+		"ImpactWithDesiredOutput": {
+			reason:  "The impact report has no desired state form, so --with-impact should be rejected with --output=desired.",
+			cmd:     XRCmd{CommonCmdFields: CommonCmdFields{Output: "desired"}, WithImpact: true},
+			wantErr: "--with-impact cannot be used with --output=desired",
+		},
+		"ImpactWithDOTOutput": {
+			reason:  "The impact report has no graph form, so --with-impact should be rejected with --output=dot.",
+			cmd:     XRCmd{CommonCmdFields: CommonCmdFields{Output: "dot"}, WithImpact: true},
+			wantErr: "--with-impact cannot be used with --output=dot",
+		},
+		"ImpactWithMarkdownOutput": {
+			reason:  "The impact report has no Markdown form, so --with-impact should be rejected with --output=markdown.",
+			cmd:     XRCmd{CommonCmdFields: CommonCmdFields{Output: "markdown"}, WithImpact: true},
+			wantErr: "--with-impact cannot be used with --output=markdown",
+		},
+		"ImpactWithJUnitOutput": {
+			reason:  "The impact report has no JUnit form, so --with-impact should be rejected with --output=junit.",
+			cmd:     XRCmd{CommonCmdFields: CommonCmdFields{Output: "junit"}, WithImpact: true},
+			wantErr: "--with-impact cannot be used with --output=junit",
+		},
+		"FilterKinds": {
+			reason: "--filter-kind should accept Kind, Kind.group and group/Kind entries.",
+			cmd:    XRCmd{FilterKinds: []string{"Bucket", "Bucket.s3.aws.upbound.io", "s3.aws.upbound.io/Bucket"}},
+		},
+		"FilterKindEmpty": {
+			reason:  "--filter-kind should reject an entry with no kind.",
+			cmd:     XRCmd{FilterKinds: []string{"s3.aws.upbound.io/"}},
+			wantErr: `invalid --filter-kind "s3.aws.upbound.io/"`,
+		},
+		"Inspect": {
+			reason: "--inspect should accept a KIND/NAME reference.",
+			cmd:    XRCmd{Inspect: "Bucket/my-bucket"},
+		},
+		"InspectWithoutName": {
+			reason:  "--inspect should reject a reference with no name.",
+			cmd:     XRCmd{Inspect: "Bucket"},
+			wantErr: `invalid --inspect "Bucket": expected KIND/NAME`,
+		},
+		"InspectWithImpact": {
+			reason:  "--inspect replaces the diff output, so it should be rejected with --with-impact.",
+			cmd:     XRCmd{Inspect: "Bucket/my-bucket", WithImpact: true},
+			wantErr: "--inspect cannot be used with --with-impact",
+		},
+		"InspectWithSummaryOnly": {
+			reason:  "--inspect prints whole objects, so it should be rejected with --summary-only.",
+			cmd:     XRCmd{Inspect: "Bucket/my-bucket", CommonCmdFields: CommonCmdFields{SummaryOnly: true}},
+			wantErr: "--inspect cannot be used with --summary-only",
+		},
+		"LatestRevisionWithCompositionRevision": {
+			reason:  "--latest-revision and --composition-revision each pick the revision, so they should be rejected together.",
+			cmd:     XRCmd{CompositionRevision: "comp-abc123", CommonCmdFields: CommonCmdFields{LatestRevision: true}},
+			wantErr: "--latest-revision cannot be used with --composition-revision or --composition-revision-as-of",
+		},
+		"ValidateOnly": {
+			reason: "--validate-only should be accepted with the default output format.",
+			cmd:    XRCmd{ValidateOnly: true, CommonCmdFields: CommonCmdFields{Output: "diff"}},
+		},
+		"ValidateOnlyWithImpact": {
+			reason:  "--validate-only doesn't diff, so it should be rejected with --with-impact.",
+			cmd:     XRCmd{ValidateOnly: true, WithImpact: true},
+			wantErr: "--validate-only cannot be used with --with-impact",
+		},
+		"ValidateOnlyWithSummaryOnly": {
+			reason:  "--validate-only doesn't diff, so there is nothing to summarize.",
+			cmd:     XRCmd{ValidateOnly: true, CommonCmdFields: CommonCmdFields{SummaryOnly: true}},
+			wantErr: "--validate-only cannot be used with --summary-only",
+		},
+		"ValidateOnlyWithYAMLOutput": {
+			reason:  "--validate-only always writes JSON, so it should reject --output=yaml.",
+			cmd:     XRCmd{ValidateOnly: true, CommonCmdFields: CommonCmdFields{Output: "yaml"}},
+			wantErr: "--validate-only always writes JSON and cannot be used with --output=yaml",
+		},
+		"ValidateOnlyWithDesiredOutput": {
+			reason:  "--validate-only renders nothing, so it should reject --output=desired.",
+			cmd:     XRCmd{ValidateOnly: true, CommonCmdFields: CommonCmdFields{Output: "desired"}},
+			wantErr: "--validate-only always writes JSON and cannot be used with --output=desired",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.cmd.validateFlags()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("%s\nunexpected error: %v", tt.reason, err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("%s\nwant error containing %q, got %v", tt.reason, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestXRCmd_ImpactCompositions(t *testing.T) {
+	shared := tu.NewComposition("shared-comp").WithCompositeTypeRef("example.org/v1", "XR1").Build()
+	other := tu.NewComposition("other-comp").WithCompositeTypeRef("example.org/v1", "XR2").Build()
+
+	byName := map[string]*apiextensionsv1.Composition{"shared-comp": shared, "other-comp": other}
+
+	compClient := tu.NewMockCompositionClient().
+		WithFindMatchingComposition(func(_ context.Context, res *un.Unstructured) (*apiextensionsv1.Composition, error) {
+			switch res.GetKind() {
+			case "XR1":
+				return shared, nil
+			case "XR2":
+				return other, nil
+			default:
+				return nil, errors.New("no composition found")
+			}
+		}).
+		WithGetComposition(func(_ context.Context, name string) (*apiextensionsv1.Composition, error) {
+			return byName[name], nil
+		}).
+		Build()
+
+	resources := []*un.Unstructured{
+		tu.NewResource("example.org/v1", "XR1", "a").Build(),
+		tu.NewResource("example.org/v1", "Unknown", "b").Build(),
+		tu.NewResource("example.org/v1", "XR1", "c").Build(),
+		tu.NewResource("example.org/v1", "XR2", "d").Build(),
+	}
+
+	c := &XRCmd{}
+
+	got, err := c.impactCompositions(t.Context(), tu.TestLogger(t, false), &AppContext{XpClients: xp.Clients{Composition: compClient}}, resources)
+	if err != nil {
+		t.Fatalf("impactCompositions(...): unexpected error: %v", err)
+	}
+
+	names := make([]string, 0, len(got))
+	for _, u := range got {
+		if u.GetKind() != "Composition" {
+			t.Errorf("impactCompositions(...): %s has kind %q, want Composition", u.GetName(), u.GetKind())
+		}
+
+		names = append(names, u.GetName())
+	}
+
+	// Each composition appears once, in first-seen order; unresolvable resources are skipped.
+	if diff := cmp.Diff([]string{"shared-comp", "other-comp"}, names); diff != "" {
+		t.Errorf("impactCompositions(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestXRCmd_Provenance(t *testing.T) {
+	appCtx := &AppContext{Server: "https://127.0.0.1:6443"}
+
+	tests := map[string]struct {
+		reason string
+		cmd    XRCmd
+		want   *renderer.Provenance
+	}{
+		"TextOutput": {
+			reason: "Human-readable output should have no provenance by default.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "diff"}},
+		},
+		"ShowProvenance": {
+			reason: "--show-provenance should record the provenance for human-readable output.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "diff"}, ShowProvenance: true},
+			want:   &renderer.Provenance{Server: "https://127.0.0.1:6443"},
+		},
+		"JSONOutput": {
+			reason: "JSON output should always record the provenance.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "json"}},
+			want:   &renderer.Provenance{Server: "https://127.0.0.1:6443"},
+		},
+		"YAMLOutput": {
+			reason: "YAML output should always record the provenance.",
+			cmd:    XRCmd{CommonCmdFields: CommonCmdFields{Output: "yaml"}},
+			want:   &renderer.Provenance{Server: "https://127.0.0.1:6443"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.cmd.provenance(appCtx)); diff != "" {
+				t.Errorf("\n%s\nprovenance(...): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}